@@ -0,0 +1,121 @@
+package app
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/auth"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/clients"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/config"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/grpc"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/health"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/http"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/lifecycle"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/utils"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/cache"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/dao"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/search"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/service"
+	goredis "github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// App owns the connections, DAOs, services and servers of the service. Each
+// one is built once from the config when its component starts and handed to
+// the handlers, DAOs and jobs that use it. The health checks, the Prometheus
+// metrics and the tracer provider (with tracing.ServiceName) stay process-wide,
+// so run one App per process.
+type App struct {
+	cfg     *config.Conf
+	exitSig chan os.Signal
+
+	DB                    *gorm.DB
+	Redis                 *goredis.Client
+	Clients               *clients.Clients
+	KafkaConn             *utils.KafkaConn
+	Writer                *utils.MyWriter
+	OrderDao              *dao.OrderDaoImpl
+	OrderProductDao       *dao.OrderProductDaoImpl
+	OrderLogDao           *dao.OrderLogDaoImpl
+	OrderExportJobDao     *dao.OrderExportJobDaoImpl
+	ShipmentDao           *dao.ShipmentDaoImpl
+	TrackingEventDao      *dao.TrackingEventDaoImpl
+	OrderEditDao          *dao.OrderEditDaoImpl
+	AccessAuditDao        *dao.AccessAuditDaoImpl
+	OrderAnalyticsDao     *dao.OrderAnalyticsDaoImpl
+	OrderSummaryDao       *dao.OrderSummaryDaoImpl
+	JobCheckpointDao      *dao.JobCheckpointDaoImpl
+	Authorizer            *auth.Authorizer
+	OrderStatsCache       *cache.OrderStatsCache
+	Carriers              utils.Carriers
+	OrderService          *service.OrderServiceImpl
+	SearchIndex           search.OrderIndex
+	OrderSearchService    *service.OrderSearchServiceImpl
+	OrderExportService    *service.OrderExportServiceImpl
+	OrderAnalyticsService *service.OrderAnalyticsServiceImpl
+	OrderSummaryService   *service.OrderSummaryServiceImpl
+	GrpcServer            *grpc.Server
+	HttpServer            *http.Server
+
+	lifecycle *lifecycle.Manager
+}
+
+// New wires the components in start order; nothing is connected until Start.
+// A server that fails after startup sends to exitSig.
+func New(cfg *config.Conf, exitSig chan os.Signal) *App {
+	var shutdownTimeout time.Duration
+	if cfg.ShutdownConfig != nil {
+		shutdownTimeout = time.Duration(cfg.ShutdownConfig.Timeout) * time.Second
+	}
+	a := &App{
+		cfg:       cfg,
+		exitSig:   exitSig,
+		lifecycle: lifecycle.NewManager(shutdownTimeout),
+	}
+	a.lifecycle.Add(a.tracingComponent())
+	a.lifecycle.Add(a.mysqlComponent())
+	a.lifecycle.Add(a.redisComponent())
+	a.lifecycle.Add(a.kafkaProducerComponent())
+	a.lifecycle.Add(a.clientsComponent())
+	a.lifecycle.Add(a.orderServiceComponent())
+	a.lifecycle.Add(a.exportServiceComponent())
+	a.lifecycle.Add(a.searchIndexComponent())
+	a.lifecycle.Add(a.orderLogConsumerComponent())
+	a.lifecycle.Add(a.searchIndexerConsumerComponent())
+	a.lifecycle.Add(a.searchBackfillJobComponent())
+	a.lifecycle.Add(a.orderStatsConsumerComponent())
+	a.lifecycle.Add(a.autoConfirmJobComponent())
+	a.lifecycle.Add(a.refundRetryJobComponent())
+	a.lifecycle.Add(a.dailySummaryJobComponent())
+	a.lifecycle.Add(a.grpcServerComponent())
+	a.lifecycle.Add(a.httpServerComponent())
+	return a
+}
+
+// Start starts every component and registers the readiness checks.
+func (a *App) Start() error {
+	if err := a.lifecycle.Start(); err != nil {
+		return err
+	}
+	a.registerHealthChecks()
+	return nil
+}
+
+// Stop stops the components in reverse order within the shutdown deadline,
+// so the servers drain first and the stores close last.
+func (a *App) Stop() error {
+	return a.lifecycle.Stop()
+}
+
+func (a *App) registerHealthChecks() {
+	if a.cfg.HealthConfig != nil {
+		health.SetCheckTimeout(time.Duration(a.cfg.HealthConfig.CheckTimeout) * time.Millisecond)
+	}
+	health.Register("mysql", func(ctx context.Context) error { return repository.Ping(ctx, a.DB) })
+	health.Register("redis", func(ctx context.Context) error { return a.Redis.Ping(ctx).Err() })
+	health.Register("kafka", a.KafkaConn.Ping)
+	health.Register("commodity_grpc", a.Clients.CheckProduct)
+	health.Register("payment_grpc", a.Clients.CheckPayment)
+}
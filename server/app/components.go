@@ -0,0 +1,477 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/auth"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/clients"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/config"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/export"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/grpc"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/health"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/http"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/http/api"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/http/router"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/lifecycle"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/metrics"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/consts"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/utils"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/cache"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/dao"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/dao/redis"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/search"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/service"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/tracing"
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+)
+
+const (
+	autoConfirmInterval       = 30 * time.Second
+	dailySummaryInterval      = 5 * time.Minute
+	refundRetryInterval       = time.Minute
+	serviceTokenTTL           = time.Minute
+	defaultOrderLogGroupID    = "consume_group_order_status_change"
+	defaultSearchIndexGroupID = "consume_group_order_search_index"
+	orderStatsGroupID         = "consume_group_order_stats"
+	defaultExportDir          = "./exports"
+)
+
+func (a *App) tracingComponent() lifecycle.Component {
+	return lifecycle.Component{
+		Name:  "tracing",
+		Start: func() error { return tracing.Init(a.cfg.TracingConfig) },
+		Stop:  func(ctx context.Context) error { tracing.Shutdown(ctx); return nil },
+	}
+}
+
+func (a *App) mysqlComponent() lifecycle.Component {
+	return lifecycle.Component{
+		Name: "mysql",
+		Start: func() error {
+			// 没有密钥时所有请求都无法认证，启动失败而不是带病运行
+			secret := os.Getenv(auth.SecretEnv)
+			if secret == "" {
+				return fmt.Errorf("%s environment variable is not set", auth.SecretEnv)
+			}
+			db, err := repository.NewDB(a.cfg.MySQLConfig)
+			if err != nil {
+				return err
+			}
+			a.DB = db
+			a.OrderDao = dao.NewOrderDao(db)
+			a.OrderProductDao = dao.NewOrderProductDao(db)
+			a.OrderLogDao = dao.NewOrderLogDao(db)
+			a.OrderExportJobDao = dao.NewOrderExportJobDao(db)
+			a.ShipmentDao = dao.NewShipmentDao(db)
+			a.TrackingEventDao = dao.NewTrackingEventDao(db)
+			a.OrderEditDao = dao.NewOrderEditDao(db)
+			a.AccessAuditDao = dao.NewAccessAuditDao(db)
+			a.OrderAnalyticsDao = dao.NewOrderAnalyticsDao(db)
+			a.OrderSummaryDao = dao.NewOrderSummaryDao(db)
+			a.JobCheckpointDao = dao.NewJobCheckpointDao(db)
+			var legacyMerchants map[int]int
+			if a.cfg.AuthConfig != nil {
+				legacyMerchants = a.cfg.AuthConfig.LegacyMerchants
+			}
+			a.Authorizer = auth.NewAuthorizer(secret, legacyMerchants, a.AccessAuditDao)
+			return nil
+		},
+		Stop: func(ctx context.Context) error { return repository.Close(a.DB) },
+	}
+}
+
+func (a *App) redisComponent() lifecycle.Component {
+	return lifecycle.Component{
+		Name: "redis",
+		Start: func() error {
+			client, err := redis.NewClient(a.cfg.RedisConfig)
+			if err != nil {
+				return err
+			}
+			a.Redis = client
+			return nil
+		},
+		Stop: func(ctx context.Context) error { return a.Redis.Close() },
+	}
+}
+
+// kafkaProducerComponent stops after everything that sends messages, and
+// flushes the buffered ones on the way out.
+func (a *App) kafkaProducerComponent() lifecycle.Component {
+	return lifecycle.Component{
+		Name: "kafka_producer",
+		Start: func() error {
+			conn, err := utils.NewKafkaConn(a.cfg.KafkaConfig)
+			if err != nil {
+				return err
+			}
+			if err := conn.BootstrapTopics(context.Background()); err != nil {
+				log.Logger.Errorf("kafka: bootstrap topics failed, err %s", err.Error())
+			}
+			a.KafkaConn = conn
+			a.Writer = utils.NewWriter(conn, a.cfg.KafkaConfig.Producer)
+			return nil
+		},
+		Stop: func(ctx context.Context) error { return a.Writer.Close() },
+	}
+}
+
+func (a *App) clientsComponent() lifecycle.Component {
+	return lifecycle.Component{
+		Name: "clients",
+		Start: func() error {
+			c, err := clients.NewClients(a.cfg, func() (string, error) {
+				return a.Authorizer.ServiceToken(serviceTokenTTL, auth.ScopeAddressesRead, auth.ScopeProductsRead)
+			}, metrics.GetRPCClientMetrics())
+			if err != nil {
+				return err
+			}
+			a.Clients = c
+			return nil
+		},
+		Stop: func(ctx context.Context) error { a.Clients.Close(); return nil },
+	}
+}
+
+func (a *App) orderServiceComponent() lifecycle.Component {
+	return lifecycle.Component{
+		Name: "order_service",
+		Start: func() error {
+			statsCache := cache.NewOrderStatsCache(
+				cache.NewRedisStatsStore(a.Redis),
+				a.OrderDao,
+				a.OrderSummaryDao,
+				utils.NewDistributedLock(a.Redis, cache.STATS_RECONCILE_LOCK_KEY, uuid.New().String(), cache.STATS_RECONCILE_LOCK_EXP),
+			)
+			a.OrderStatsCache = statsCache
+			a.Carriers = utils.NewCarriers(a.cfg.Carriers)
+			a.OrderService = service.NewOrderService(
+				a.OrderDao,
+				a.OrderProductDao,
+				a.OrderLogDao,
+				a.ShipmentDao,
+				a.TrackingEventDao,
+				a.OrderEditDao,
+				statsCache,
+				a.Clients.Product,
+				a.Clients.Payment,
+				a.Clients.AddressBook,
+				a.Clients.ProductMerchants,
+				a.Writer,
+				utils.NewDistributedLock(a.Redis, service.AUTO_CONFIRM_LOCK_KEY, uuid.New().String(), service.LOCK_EXP_TIME),
+				utils.NewDistributedLock(a.Redis, service.REFUND_RETRY_LOCK_KEY, uuid.New().String(), service.REFUND_RETRY_LOCK_EXP_TIME),
+				metrics.GetOrderMetrics(),
+				a.Carriers,
+				a.cfg.CommodityClient.ImageHosts,
+			)
+			a.OrderAnalyticsService = service.NewOrderAnalyticsService(a.OrderAnalyticsDao, a.OrderSummaryDao)
+			a.OrderSummaryService = service.NewOrderSummaryService(
+				a.OrderSummaryDao,
+				utils.NewDistributedLock(a.Redis, service.SUMMARY_LOCK_KEY, uuid.New().String(), service.SUMMARY_LOCK_EXP_TIME),
+				statsCache,
+			)
+			return nil
+		},
+	}
+}
+
+// exportServiceComponent runs the order export service. Stopping it cancels
+// the running export jobs, which are recorded as failed.
+func (a *App) exportServiceComponent() lifecycle.Component {
+	return lifecycle.Component{
+		Name: "export_service",
+		Start: func() error {
+			exportCfg := a.cfg.ExportConfig
+			if exportCfg == nil {
+				exportCfg = &config.ExportConfig{}
+			}
+			dir := exportCfg.Dir
+			if dir == "" {
+				dir = defaultExportDir
+			}
+			store, err := export.NewLocalStore(dir)
+			if err != nil {
+				return err
+			}
+			a.OrderExportService = service.NewOrderExportService(
+				a.OrderDao,
+				a.OrderProductDao,
+				a.OrderExportJobDao,
+				store,
+				service.ExportOptions{
+					BatchSize:   exportCfg.BatchSize,
+					MaxSyncRows: exportCfg.MaxSyncRows,
+					Workers:     exportCfg.Workers,
+					Retention:   time.Duration(exportCfg.Retention) * time.Hour,
+					JobTimeout:  time.Duration(exportCfg.JobTimeout) * time.Minute,
+				},
+			)
+			a.OrderExportService.Start()
+			return nil
+		},
+		Stop: func(ctx context.Context) error { return a.OrderExportService.Stop(ctx) },
+	}
+}
+
+// kafkaConsumerComponent runs a consumer of topics and reports it unhealthy
+// while it is retrying a failure. Stopping it finishes the message in hand and
+// commits its offset before the reader is closed.
+func (a *App) kafkaConsumerComponent(name string, groupID func() string, topics []string, handler func() utils.MessageHandler) lifecycle.Component {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	var consumer *utils.MyConsumer
+	return lifecycle.Component{
+		Name: name,
+		Start: func() error {
+			consumer = utils.NewConsumer(a.KafkaConn, groupID(), topics, handler(), metrics.GetKafkaConsumerMetrics())
+			health.Register(name, consumer.Check)
+			go func() {
+				defer close(done)
+				consumer.ConsumeMessage(ctx)
+			}()
+			return nil
+		},
+		Stop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+				return consumer.Close()
+			case <-stopCtx.Done():
+				return stopCtx.Err()
+			}
+		},
+	}
+}
+
+// orderLogConsumerComponent writes an order status log for every status change.
+func (a *App) orderLogConsumerComponent() lifecycle.Component {
+	return a.kafkaConsumerComponent("order_log_consumer",
+		func() string {
+			if consumerCfg := a.cfg.KafkaConfig.Consumer; consumerCfg != nil && consumerCfg.GroupID != "" {
+				return consumerCfg.GroupID
+			}
+			return defaultOrderLogGroupID
+		},
+		[]string{consts.TopicOrderStatusChanged},
+		func() utils.MessageHandler { return utils.NewOrderLogHandler(a.OrderLogDao) },
+	)
+}
+
+// searchIndexComponent builds the order search index for the configured engine.
+func (a *App) searchIndexComponent() lifecycle.Component {
+	return lifecycle.Component{
+		Name: "search_index",
+		Start: func() error {
+			engine := search.EngineMySQL
+			if a.cfg.SearchConfig != nil && a.cfg.SearchConfig.Engine != "" {
+				engine = a.cfg.SearchConfig.Engine
+			}
+			if engine != search.EngineMySQL {
+				return fmt.Errorf("unsupported search engine: %s", engine)
+			}
+			index, err := search.NewMySQLIndex(a.DB)
+			if err != nil {
+				return err
+			}
+			a.SearchIndex = index
+			a.OrderSearchService = service.NewOrderSearchService(index)
+			return nil
+		},
+	}
+}
+
+// searchIndexerConsumerComponent keeps the search index in sync with order events.
+func (a *App) searchIndexerConsumerComponent() lifecycle.Component {
+	return a.kafkaConsumerComponent("search_indexer_consumer",
+		func() string {
+			if a.cfg.SearchConfig != nil && a.cfg.SearchConfig.GroupID != "" {
+				return a.cfg.SearchConfig.GroupID
+			}
+			return defaultSearchIndexGroupID
+		},
+		[]string{consts.TopicOrderCreated, consts.TopicOrderStatusChanged, consts.TopicOrderCanceled, consts.TopicOrderUpdated},
+		func() utils.MessageHandler {
+			indexer := search.NewIndexer(a.SearchIndex, a.OrderDao, a.OrderProductDao)
+			// 所有订单事件都以订单号为 key
+			return func(ctx context.Context, msg kafka.Message) error {
+				return indexer.IndexOrder(ctx, string(msg.Key))
+			}
+		},
+	)
+}
+
+// searchBackfillJobComponent indexes, once at startup, the orders the search
+// index has not seen yet. Stopping it interrupts the backfill, which resumes
+// from its checkpoint on the next start.
+func (a *App) searchBackfillJobComponent() lifecycle.Component {
+	var cancel context.CancelFunc
+	done := make(chan struct{})
+	return lifecycle.Component{
+		Name: "search_backfill_job",
+		Start: func() error {
+			var ctx context.Context
+			ctx, cancel = context.WithCancel(context.Background())
+			backfiller := search.NewBackfiller(
+				search.NewIndexer(a.SearchIndex, a.OrderDao, a.OrderProductDao),
+				a.OrderDao,
+				a.JobCheckpointDao,
+				utils.NewDistributedLock(a.Redis, search.BACKFILL_LOCK_KEY, uuid.New().String(), search.BACKFILL_LOCK_EXP_TIME),
+			)
+			go func() {
+				defer close(done)
+				backfiller.Run(ctx)
+			}()
+
+			log.Logger.Info("Search backfill job started")
+			return nil
+		},
+		Stop: func(ctx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	}
+}
+
+// orderStatsConsumerComponent keeps the order stats in step with paid,
+// canceled, edited and refunded orders.
+func (a *App) orderStatsConsumerComponent() lifecycle.Component {
+	return a.kafkaConsumerComponent("order_stats_consumer",
+		func() string { return orderStatsGroupID },
+		[]string{consts.TopicOrderStatusChanged, consts.TopicOrderCanceled, consts.TopicOrderUpdated, consts.TopicOrderRefunded},
+		func() utils.MessageHandler { return cache.NewOrderStatsHandler(a.OrderStatsCache) },
+	)
+}
+
+// autoConfirmJobComponent runs the auto confirm job. Stopping it lets a run
+// that is already in progress finish.
+func (a *App) autoConfirmJobComponent() lifecycle.Component {
+	timer := utils.NewMyTimer(autoConfirmInterval)
+	return lifecycle.Component{
+		Name: "auto_confirm_job",
+		Start: func() error {
+			ctx := context.Background()
+
+			// 创建一个包装函数
+			task := func() {
+				a.OrderService.OrderAutoConfirm(ctx)
+			}
+
+			go timer.Start(ctx, task)
+
+			log.Logger.Info("Auto confirm job started")
+			return nil
+		},
+		Stop: func(ctx context.Context) error {
+			timer.Stop()
+			select {
+			case <-timer.Done():
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	}
+}
+
+// refundRetryJobComponent resends the refunds of order edits whose message was
+// not acknowledged; only the instance holding the lock runs each round. Stopping it lets a run that is already in progress finish.
+func (a *App) refundRetryJobComponent() lifecycle.Component {
+	timer := utils.NewMyTimer(refundRetryInterval)
+	return lifecycle.Component{
+		Name: "refund_retry_job",
+		Start: func() error {
+			ctx := context.Background()
+			task := func() {
+				a.OrderService.RetryPendingRefunds(ctx)
+			}
+
+			go timer.Start(ctx, task)
+
+			log.Logger.Info("Refund retry job started")
+			return nil
+		},
+		Stop: func(ctx context.Context) error {
+			timer.Stop()
+			select {
+			case <-timer.Done():
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	}
+}
+
+// dailySummaryJobComponent refreshes the daily order summary at startup, which
+// backfills it on the first run, and then periodically. Stopping it lets a
+// run that is already in progress finish.
+func (a *App) dailySummaryJobComponent() lifecycle.Component {
+	timer := utils.NewMyTimer(dailySummaryInterval)
+	return lifecycle.Component{
+		Name: "daily_summary_job",
+		Start: func() error {
+			ctx := context.Background()
+			task := func() {
+				a.OrderSummaryService.RefreshDailySummary(ctx)
+			}
+
+			go func() {
+				task()
+				timer.Start(ctx, task)
+			}()
+
+			log.Logger.Info("Daily summary job started")
+			return nil
+		},
+		Stop: func(ctx context.Context) error {
+			timer.Stop()
+			select {
+			case <-timer.Done():
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	}
+}
+
+func (a *App) grpcServerComponent() lifecycle.Component {
+	return lifecycle.Component{
+		Name: "grpc_server",
+		Start: func() error {
+			a.GrpcServer = grpc.NewServer(a.cfg.GrpcConfig, a.Authorizer)
+			return a.GrpcServer.Start(a.exitSig)
+		},
+		Stop: func(ctx context.Context) error { return a.GrpcServer.Shutdown(ctx) },
+	}
+}
+
+func (a *App) httpServerComponent() lifecycle.Component {
+	return lifecycle.Component{
+		Name: "http_server",
+		Start: func() error {
+			r := router.NewRouter(
+				api.NewOrderHandler(a.OrderService),
+				api.NewOrderSearchHandler(a.OrderSearchService),
+				api.NewOrderExportHandler(a.OrderExportService),
+				api.NewOrderAnalyticsHandler(a.OrderAnalyticsService),
+				api.NewTrackingWebhookHandler(a.OrderService, a.Carriers),
+				a.Authorizer,
+			)
+			a.HttpServer = http.NewServer(a.cfg.HttpConfig, r)
+			return a.HttpServer.Start(a.exitSig)
+		},
+		Stop: func(ctx context.Context) error { return a.HttpServer.Shutdown(ctx) },
+	}
+}
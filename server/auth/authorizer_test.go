@@ -0,0 +1,165 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/log"
+	daoMocks "github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/dao/mocks"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/model"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/golang/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func init() {
+	// 初始化测试用logger
+	logger, _ := zap.NewDevelopment()
+	log.Logger = logger.Sugar()
+}
+
+const testSecret = "secret"
+
+func sign(t *testing.T, c claims, secret string) string {
+	t.Helper()
+	if c.ExpiresAt == nil {
+		c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(time.Hour))
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, c).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return token
+}
+
+func TestAuthorizer_Authenticate(t *testing.T) {
+	a := NewAuthorizer(testSecret, nil, nil)
+	expired := claims{ID: 1, RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute))}}
+	tests := []struct {
+		name      string
+		token     string
+		wantRoles []string
+		wantErr   error
+	}{
+		{"legacy token is a customer", sign(t, claims{ID: 7}, testSecret), []string{RoleCustomer}, nil},
+		{"merchant with bearer prefix", "Bearer " + sign(t, claims{ID: 8, MerchantID: 3, Role: RoleMerchant}, testSecret), []string{RoleMerchant}, nil},
+		{"merchant without merchant_id", sign(t, claims{ID: 8, Role: RoleMerchant}, testSecret), nil, ErrUnauthenticated},
+		{"service without user", sign(t, claims{Roles: []string{RoleService}, Scope: "orders:read"}, testSecret), []string{RoleService}, nil},
+		{"customer without user", sign(t, claims{}, testSecret), nil, ErrUnauthenticated},
+		{"wrong secret", sign(t, claims{ID: 7}, "other"), nil, ErrUnauthenticated},
+		{"expired", sign(t, expired, testSecret), nil, ErrUnauthenticated},
+		{"missing", "", nil, ErrUnauthenticated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := a.Authenticate(tt.token)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Authenticate() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (len(p.Roles) != len(tt.wantRoles) || p.Roles[0] != tt.wantRoles[0]) {
+				t.Errorf("Authenticate() roles = %v, want %v", p.Roles, tt.wantRoles)
+			}
+		})
+	}
+}
+
+func TestAuthorizer_Authenticate_LegacyMerchant(t *testing.T) {
+	a := NewAuthorizer(testSecret, map[int]int{8: 3}, nil)
+
+	// 没有角色的旧令牌：配置中的商家用户同时是顾客和该商家的商家
+	p, err := a.Authenticate(sign(t, claims{ID: 8}, testSecret))
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if !p.HasRole(RoleCustomer) || !p.HasRole(RoleMerchant) || p.MerchantID != 3 {
+		t.Errorf("Authenticate() = %+v, want customer and merchant 3", p)
+	}
+
+	// 带角色的令牌按令牌中的角色处理
+	if p, err = a.Authenticate(sign(t, claims{ID: 8, Role: RoleCustomer}, testSecret)); err != nil || p.HasRole(RoleMerchant) {
+		t.Errorf("Authenticate() = %+v, %v, want customer only", p, err)
+	}
+	if p, err = a.Authenticate(sign(t, claims{ID: 9}, testSecret)); err != nil || p.HasRole(RoleMerchant) {
+		t.Errorf("Authenticate() = %+v, %v, want customer only", p, err)
+	}
+}
+
+func TestAuthorizer_ServiceToken(t *testing.T) {
+	token, err := NewAuthorizer(testSecret, nil, nil).ServiceToken(time.Minute, ScopeAddressesRead)
+	if err != nil {
+		t.Fatalf("ServiceToken() error = %v", err)
+	}
+	// 共享密钥的服务按服务令牌识别本服务
+	p, err := NewAuthorizer(testSecret, nil, nil).Authenticate(token)
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if !p.HasScope(ScopeAddressesRead) || p.HasScope(ScopeOrdersRead) {
+		t.Errorf("unexpected principal: %+v", p)
+	}
+
+	if _, err = NewAuthorizer("", nil, nil).ServiceToken(time.Minute); err == nil {
+		t.Error("ServiceToken() should fail without a secret")
+	}
+}
+
+func TestRule_Allows(t *testing.T) {
+	customer := &Principal{UserID: 1, Roles: []string{RoleCustomer}}
+	merchant := &Principal{UserID: 2, Roles: []string{RoleMerchant}}
+	reader := &Principal{Roles: []string{RoleService}, Scopes: []string{ScopeOrdersRead}}
+	// 非服务令牌的 scope 不生效
+	customerWithScope := &Principal{UserID: 1, Roles: []string{RoleCustomer}, Scopes: []string{ScopeOrdersRead}}
+
+	tests := []struct {
+		name string
+		rule Rule
+		p    *Principal
+		want bool
+	}{
+		{"merchant route, merchant", MerchantOnly, merchant, true},
+		{"merchant route, customer", MerchantOnly, customer, false},
+		{"merchant route, service", MerchantOnly, reader, false},
+		{"customer route, merchant", CustomerOnly, merchant, false},
+		{"read route, service", MerchantOrServiceRead, reader, true},
+		{"read route, customer scope", MerchantOrServiceRead, customerWithScope, false},
+		{"anonymous", CustomerOnly, nil, false},
+	}
+	for _, tt := range tests {
+		if got := tt.rule.Allows(tt.p); got != tt.want {
+			t.Errorf("%s: Allows() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAuthorizer_AuthorizeAuditsDenial(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuditDao := daoMocks.NewMockAccessAuditDao(ctrl)
+	a := NewAuthorizer(testSecret, nil, mockAuditDao)
+	target := Target{Channel: ChannelHTTP, Resource: "POST /order-ms/v1/merchant/orders/list", ClientIP: "10.0.0.1", RequestID: "req-1"}
+	customer := &Principal{UserID: 1, Roles: []string{RoleCustomer}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel() // 请求已取消时仍要写入审计记录
+	mockAuditDao.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, record *model.AccessAuditLog) (int, error) {
+			if ctx.Err() != nil {
+				t.Errorf("audit context should not be canceled: %v", ctx.Err())
+			}
+			if record.UserID != 1 || record.Roles != RoleCustomer || record.Resource != target.Resource || record.RequestID != "req-1" {
+				t.Errorf("unexpected audit record: %+v", record)
+			}
+			return 1, nil
+		})
+	if err := a.Authorize(ctx, customer, MerchantOnly, target); !errors.Is(err, ErrAccessDenied) {
+		t.Errorf("Authorize() error = %v, want %v", err, ErrAccessDenied)
+	}
+
+	// 允许的请求不写审计记录
+	if err := a.Authorize(ctx, customer, CustomerOnly, target); err != nil {
+		t.Errorf("Authorize() error = %v", err)
+	}
+}
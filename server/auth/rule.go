@@ -0,0 +1,42 @@
+package auth
+
+import "strings"
+
+// Rule grants access to callers holding any of its roles, or to service
+// callers holding any of its scopes.
+type Rule struct {
+	Roles  []string
+	Scopes []string
+}
+
+var (
+	MerchantOnly = Rule{Roles: []string{RoleMerchant}}
+	CustomerOnly = Rule{Roles: []string{RoleCustomer}}
+	ServiceOnly  = Rule{Roles: []string{RoleService}}
+
+	// MerchantOrServiceRead 商家或持有 orders:read 的内部服务，用于只读的商家接口
+	MerchantOrServiceRead = Rule{Roles: []string{RoleMerchant}, Scopes: []string{ScopeOrdersRead}}
+)
+
+func (r Rule) Allows(p *Principal) bool {
+	for _, role := range r.Roles {
+		if p.HasRole(role) {
+			return true
+		}
+	}
+	for _, scope := range r.Scopes {
+		if p.HasScope(scope) {
+			return true
+		}
+	}
+	return false
+}
+
+// String 用于审计记录中的拒绝原因
+func (r Rule) String() string {
+	s := "requires role " + strings.Join(r.Roles, "|")
+	if len(r.Scopes) > 0 {
+		s += " or service scope " + strings.Join(r.Scopes, "|")
+	}
+	return s
+}
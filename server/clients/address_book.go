@@ -0,0 +1,99 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/metrics"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/types"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// AddressBookClient reads saved addresses from the user service. The user
+// service's client library has no address API, so it calls the REST path set
+// in the config, which answers with the same {status, data, error} envelope as
+// this service. Every call carries a short-lived service token signed with the
+// shared JWT secret.
+type AddressBookClient struct {
+	baseURL string
+	path    string
+	client  *http.Client
+	token   TokenFunc
+	metrics *metrics.RPCClientMetrics
+}
+
+// TokenFunc returns a service token for a call to another service.
+type TokenFunc func() (string, error)
+
+func NewAddressBookClient(baseURL, path string, timeout time.Duration, token TokenFunc, rpcMetrics *metrics.RPCClientMetrics) *AddressBookClient {
+	return &AddressBookClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		path:    path,
+		client:  &http.Client{Timeout: timeout},
+		token:   token,
+		metrics: rpcMetrics,
+	}
+}
+
+type addressResponse struct {
+	Status int                 `json:"status"`
+	Data   *types.SavedAddress `json:"data"`
+	Error  string              `json:"error"`
+}
+
+// GetAddress returns the address addressID saved by userID. ok is false when
+// the user has no such address.
+func (c *AddressBookClient) GetAddress(ctx context.Context, userID, addressID int) (addr *types.SavedAddress, ok bool, err error) {
+	if c.baseURL == "" || c.path == "" {
+		return nil, false, errors.New("user service address book is not configured")
+	}
+	token, err := c.token()
+	if err != nil {
+		return nil, false, fmt.Errorf("sign service token: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+fmt.Sprintf(c.path, userID, addressID), nil)
+	if err != nil {
+		return nil, false, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	start := time.Now()
+	resp, err := c.client.Do(req)
+	code := "error"
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+	c.metrics.CallDone("user", "GetAddress", code, start)
+	if err != nil {
+		return nil, false, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, false, nil
+	default:
+		return nil, false, fmt.Errorf("get address %d: unexpected status %s", addressID, resp.Status)
+	}
+	var body addressResponse
+	if err = json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, false, fmt.Errorf("get address %d: decode response: %w", addressID, err)
+	}
+	if body.Status != 0 {
+		return nil, false, fmt.Errorf("get address %d: status %d, %s", addressID, body.Status, body.Error)
+	}
+	// 地址不属于该用户时按不存在处理
+	if body.Data == nil || body.Data.UserID != userID {
+		return nil, false, nil
+	}
+	return body.Data, true, nil
+}
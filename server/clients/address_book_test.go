@@ -0,0 +1,58 @@
+package clients
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAddressBookClient_GetAddress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer service-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/user-ms/v1/internal/users/123/addresses/7":
+			_, _ = w.Write([]byte(`{"status":0,"data":{"id":7,"user_id":123,"line1":"1 Orchard Road","city":"Singapore","postal_code":"238823","country":"SG"}}`))
+		case "/user-ms/v1/internal/users/456/addresses/7":
+			// 用户服务未按用户过滤时，客户端仍按不存在处理
+			_, _ = w.Write([]byte(`{"status":0,"data":{"id":7,"user_id":123,"line1":"1 Orchard Road"}}`))
+		case "/user-ms/v1/internal/users/123/addresses/8":
+			_, _ = w.Write([]byte(`{"status":50000,"error":"db down"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := NewAddressBookClient(server.URL+"/", "/user-ms/v1/internal/users/%d/addresses/%d", time.Second,
+		func() (string, error) { return "service-token", nil }, nil)
+	ctx := context.Background()
+
+	addr, ok, err := client.GetAddress(ctx, 123, 7)
+	if err != nil || !ok {
+		t.Fatalf("GetAddress() = %v, %v", ok, err)
+	}
+	if addr.ID != 7 || addr.Line1 != "1 Orchard Road" || addr.PostalCode != "238823" || addr.Country != "SG" {
+		t.Errorf("GetAddress() = %+v", addr)
+	}
+
+	for _, ids := range [][2]int{{123, 9}, {456, 7}} {
+		if _, ok, err = client.GetAddress(ctx, ids[0], ids[1]); err != nil || ok {
+			t.Errorf("GetAddress(%d, %d) = %v, %v, want not found", ids[0], ids[1], ok, err)
+		}
+	}
+
+	if _, _, err = client.GetAddress(ctx, 123, 8); err == nil {
+		t.Error("GetAddress() should fail when the user service returns an error status")
+	}
+
+	// 未配置接口路径时不请求用户服务
+	unconfigured := NewAddressBookClient(server.URL, "", time.Second, func() (string, error) { return "service-token", nil }, nil)
+	if _, _, err = unconfigured.GetAddress(ctx, 123, 7); err == nil {
+		t.Error("GetAddress() should fail when the address path is not configured")
+	}
+}
@@ -1,8 +1,141 @@
 package clients
 
-import "github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/config"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
 
-func InitAllClients(cfg *config.Conf) {
-	_ = InitProductClient(cfg.CommodityClient)
-	InitPaymentClient(cfg.PaymentClient)
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/common/productpb"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/config"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/metrics"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-payment-mservice/common/paymentpb"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// Clients holds the gRPC clients of the downstream services and their connections.
+type Clients struct {
+	Product          productpb.ProductServiceClient
+	Payment          paymentpb.PaymentServiceClient
+	AddressBook      *AddressBookClient
+	ProductMerchants *ProductMerchantClient
+
+	productConn *grpc.ClientConn
+	paymentConn *grpc.ClientConn
+}
+
+// NewClients dials the downstream services. token signs the service tokens of
+// the REST calls, and rpcMetrics records the latency of every call.
+func NewClients(cfg *config.Conf, token TokenFunc, rpcMetrics *metrics.RPCClientMetrics) (*Clients, error) {
+	productConn, err := newClientConn("commodity", cfg.CommodityClient.Host, cfg.CommodityClient.Port, rpcMetrics)
+	if err != nil {
+		return nil, fmt.Errorf("init product client failed: %w", err)
+	}
+	paymentConn, err := newClientConn("payment", cfg.PaymentClient.Host, cfg.PaymentClient.Port, rpcMetrics)
+	if err != nil {
+		_ = productConn.Close()
+		return nil, fmt.Errorf("init payment client failed: %w", err)
+	}
+	log.Logger.Infoln("NewClients: success")
+	return &Clients{
+		Product:          productpb.NewProductServiceClient(productConn),
+		Payment:          paymentpb.NewPaymentServiceClient(paymentConn),
+		AddressBook:      newAddressBookClient(cfg.UserClient, token, rpcMetrics),
+		ProductMerchants: newProductMerchantClient(cfg.CommodityClient, token, rpcMetrics),
+		productConn:      productConn,
+		paymentConn:      paymentConn,
+	}, nil
+}
+
+const (
+	defaultUserClientTimeout      = 2 * time.Second
+	defaultCommodityClientTimeout = 2 * time.Second
+)
+
+func newAddressBookClient(cfg *config.UserClient, token TokenFunc, rpcMetrics *metrics.RPCClientMetrics) *AddressBookClient {
+	if cfg == nil {
+		cfg = &config.UserClient{}
+	}
+	path := cfg.AddressPath
+	if path != "" && strings.Count(path, "%d") != 2 {
+		log.Logger.Errorf("NewClients: address_path %q must contain the user ID and address ID as %%d", path)
+		path = ""
+	}
+	if cfg.BaseURL == "" || path == "" {
+		log.Logger.Warn("NewClients: user service address book is not configured, saved addresses are unavailable")
+	}
+	timeout := time.Duration(cfg.Timeout) * time.Millisecond
+	if timeout <= 0 {
+		timeout = defaultUserClientTimeout
+	}
+	return NewAddressBookClient(cfg.BaseURL, path, timeout, token, rpcMetrics)
+}
+
+func newProductMerchantClient(cfg *config.CommodityClient, token TokenFunc, rpcMetrics *metrics.RPCClientMetrics) *ProductMerchantClient {
+	if cfg == nil {
+		cfg = &config.CommodityClient{}
+	}
+	if cfg.BaseURL == "" || cfg.MerchantPath == "" {
+		log.Logger.Error("NewClients: product service merchant lookup is not configured, orders cannot be placed")
+	}
+	timeout := time.Duration(cfg.Timeout) * time.Millisecond
+	if timeout <= 0 {
+		timeout = defaultCommodityClientTimeout
+	}
+	return NewProductMerchantClient(cfg.BaseURL, cfg.MerchantPath, timeout, token, rpcMetrics)
+}
+
+// newClientConn dials a downstream service with the same options as the
+// service client libraries, plus tracing so spans propagate over gRPC and
+// latency metrics labeled with service.
+func newClientConn(service string, host string, port int, rpcMetrics *metrics.RPCClientMetrics) (*grpc.ClientConn, error) {
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(1024 * 1024)),
+		grpc.WithDefaultCallOptions(grpc.MaxCallSendMsgSize(1024 * 1024)),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+		grpc.WithUnaryInterceptor(metricsInterceptor(service, rpcMetrics)),
+	}
+	return grpc.NewClient(fmt.Sprintf("%s:%d", host, port), opts...)
+}
+
+func (c *Clients) Close() {
+	for _, conn := range []*grpc.ClientConn{c.productConn, c.paymentConn} {
+		if conn == nil {
+			continue
+		}
+		if err := conn.Close(); err != nil {
+			log.Logger.Errorf("Clients.Close: close conn %s failed, err %s", conn.Target(), err.Error())
+		}
+	}
+}
+
+func (c *Clients) CheckProduct(ctx context.Context) error {
+	return checkConnState(c.productConn)
+}
+
+func (c *Clients) CheckPayment(ctx context.Context) error {
+	return checkConnState(c.paymentConn)
+}
+
+// checkConnState fails when the connection is broken. Idle connections are
+// asked to connect, since grpc.NewClient only dials on first use.
+func checkConnState(conn *grpc.ClientConn) error {
+	if conn == nil {
+		return errors.New("grpc client is not initialized")
+	}
+	switch state := conn.GetState(); state {
+	case connectivity.Idle:
+		conn.Connect()
+		return nil
+	case connectivity.TransientFailure, connectivity.Shutdown:
+		return fmt.Errorf("grpc connection to %s is %s", conn.Target(), state)
+	default:
+		return nil
+	}
 }
@@ -0,0 +1,21 @@
+package clients
+
+import (
+	"context"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/metrics"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// metricsInterceptor records the latency of every outbound unary call,
+// labeled by downstream service, method and gRPC status code.
+func metricsInterceptor(service string, rpcMetrics *metrics.RPCClientMetrics) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		rpcMetrics.CallDone(service, method, status.Code(err).String(), start)
+		return err
+	}
+}
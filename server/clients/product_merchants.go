@@ -0,0 +1,94 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/metrics"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// ProductMerchantClient looks up which merchant sells each product. The
+// product service's gRPC API has no merchant, so it calls the REST path set in
+// the config with the product IDs as a comma-separated ids query. The answer
+// uses the same {status, data, error} envelope as this service, with data
+// mapping product ID to merchant ID. Every call carries a short-lived service
+// token signed with the shared JWT secret.
+type ProductMerchantClient struct {
+	baseURL string
+	path    string
+	client  *http.Client
+	token   TokenFunc
+	metrics *metrics.RPCClientMetrics
+}
+
+func NewProductMerchantClient(baseURL, path string, timeout time.Duration, token TokenFunc, rpcMetrics *metrics.RPCClientMetrics) *ProductMerchantClient {
+	return &ProductMerchantClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		path:    path,
+		client:  &http.Client{Timeout: timeout},
+		token:   token,
+		metrics: rpcMetrics,
+	}
+}
+
+type productMerchantsResponse struct {
+	Status int           `json:"status"`
+	Data   map[int64]int `json:"data"`
+	Error  string        `json:"error"`
+}
+
+// GetProductMerchants returns the merchant ID of each product in productIDs.
+// Products the product service doesn't know are missing from the result.
+func (c *ProductMerchantClient) GetProductMerchants(ctx context.Context, productIDs []int64) (merchants map[int64]int, err error) {
+	if c.baseURL == "" || c.path == "" {
+		return nil, errors.New("product service merchant lookup is not configured")
+	}
+	token, err := c.token()
+	if err != nil {
+		return nil, fmt.Errorf("sign service token: %w", err)
+	}
+	ids := make([]string, len(productIDs))
+	for i, id := range productIDs {
+		ids[i] = strconv.FormatInt(id, 10)
+	}
+	query := url.Values{"ids": {strings.Join(ids, ",")}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+c.path+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	start := time.Now()
+	resp, err := c.client.Do(req)
+	code := "error"
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+	c.metrics.CallDone("commodity", "GetProductMerchants", code, start)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("get product merchants: unexpected status %s", resp.Status)
+	}
+	var body productMerchantsResponse
+	if err = json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("get product merchants: decode response: %w", err)
+	}
+	if body.Status != 0 {
+		return nil, fmt.Errorf("get product merchants: status %d, %s", body.Status, body.Error)
+	}
+	return body.Data, nil
+}
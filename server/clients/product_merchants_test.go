@@ -0,0 +1,47 @@
+package clients
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestProductMerchantClient_GetProductMerchants(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer service-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Query().Get("ids") {
+		case "1,2":
+			_, _ = w.Write([]byte(`{"status":0,"data":{"1":7,"2":1}}`))
+		default:
+			_, _ = w.Write([]byte(`{"status":50000,"error":"db down"}`))
+		}
+	}))
+	defer server.Close()
+
+	token := func() (string, error) { return "service-token", nil }
+	client := NewProductMerchantClient(server.URL+"/", "/commodity-ms/v1/internal/products/merchants", time.Second, token, nil)
+	ctx := context.Background()
+
+	merchants, err := client.GetProductMerchants(ctx, []int64{1, 2})
+	if err != nil {
+		t.Fatalf("GetProductMerchants() error = %v", err)
+	}
+	if len(merchants) != 2 || merchants[1] != 7 || merchants[2] != 1 {
+		t.Errorf("GetProductMerchants() = %v", merchants)
+	}
+
+	if _, err = client.GetProductMerchants(ctx, []int64{3}); err == nil {
+		t.Error("GetProductMerchants() should fail when the product service returns an error status")
+	}
+
+	// 未配置接口路径时不请求商品服务
+	unconfigured := NewProductMerchantClient(server.URL, "", time.Second, token, nil)
+	if _, err = unconfigured.GetProductMerchants(ctx, []int64{1}); err == nil {
+		t.Error("GetProductMerchants() should fail when the merchant path is not configured")
+	}
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"strings"
 
 	"github.com/spf13/viper"
 )
@@ -9,14 +10,29 @@ import (
 var Config = &Conf{}
 
 type Conf struct {
-	GrpcConfig      *GrpcConfig      `mapstructure:"grpc"`
-	LogConfig       *LogConfig       `mapstructure:"log"`
-	HttpConfig      *HttpConfig      `mapstructure:"http"`
-	MySQLConfig     *MySQL           `mapstructure:"mysql"`
-	CommodityClient *CommodityClient `mapstructure:"commodityClient"`
-	PaymentClient   *PaymentClient   `mapstruct:"paymentClient"`
-	KafkaConfig     *KafkaConfig     `mapstructure:"kafka"`
-	RedisConfig     *RedisConfig     `mapstructure:"redis"`
+	GrpcConfig      *GrpcConfig               `mapstructure:"grpc"`
+	LogConfig       *LogConfig                `mapstructure:"log"`
+	HttpConfig      *HttpConfig               `mapstructure:"http"`
+	MySQLConfig     *MySQL                    `mapstructure:"mysql"`
+	CommodityClient *CommodityClient          `mapstructure:"commodityClient"`
+	PaymentClient   *PaymentClient            `mapstruct:"paymentClient"`
+	UserClient      *UserClient               `mapstructure:"userClient"`
+	KafkaConfig     *KafkaConfig              `mapstructure:"kafka"`
+	RedisConfig     *RedisConfig              `mapstructure:"redis"`
+	TracingConfig   *TracingConfig            `mapstructure:"tracing"`
+	HealthConfig    *HealthConfig             `mapstructure:"health"`
+	ShutdownConfig  *ShutdownConfig           `mapstructure:"shutdown"`
+	SearchConfig    *SearchConfig             `mapstructure:"search"`
+	ExportConfig    *ExportConfig             `mapstructure:"export"`
+	AuthConfig      *AuthConfig               `mapstructure:"auth"`
+	Carriers        map[string]*CarrierConfig `mapstructure:"carriers"` // keyed by carrier code
+}
+
+// AuthConfig configures token authentication; the signing secret is read from
+// JWT_SECRET. LegacyMerchants maps user ID to merchant ID for merchant users
+// whose tokens have no role claim yet.
+type AuthConfig struct {
+	LegacyMerchants map[int]int `mapstructure:"legacy_merchants"`
 }
 
 type RedisConfig struct {
@@ -29,9 +45,16 @@ type HttpConfig struct {
 	Port int    `mapstructure:"port"`
 }
 
+// LogConfig configures the logger. Format is "console" or "json"; the
+// rotation settings apply to FilePath (MaxSize in MB, MaxAge in days).
 type LogConfig struct {
-	Level    string `mapstructure:"level"`
-	FilePath string `mapstructure:"file_path"`
+	Level      string `mapstructure:"level"`
+	FilePath   string `mapstructure:"file_path"`
+	Format     string `mapstructure:"format"`
+	MaxSize    int    `mapstructure:"max_size"`
+	MaxBackups int    `mapstructure:"max_backups"`
+	MaxAge     int    `mapstructure:"max_age"`
+	Compress   bool   `mapstructure:"compress"`
 }
 
 type GrpcConfig struct {
@@ -49,9 +72,18 @@ type MySQL struct {
 	DBName   string `mapstructure:"dbName"`
 }
 
+// CommodityClient is the product service. Host and Port address its gRPC API.
+// BaseURL and MerchantPath address the REST API that returns the merchant of
+// each product; orders can't be placed while they are empty. Timeout is in
+// milliseconds. ImageHosts are the host names of the catalog image CDN; order
+// items only keep https images on these hosts.
 type CommodityClient struct {
-	Host string `mapstructure:"host"`
-	Port int    `mapstructure:"port"`
+	Host         string   `mapstructure:"host"`
+	Port         int      `mapstructure:"port"`
+	BaseURL      string   `mapstructure:"base_url"`
+	MerchantPath string   `mapstructure:"merchant_path"`
+	Timeout      int      `mapstructure:"timeout"`
+	ImageHosts   []string `mapstructure:"image_hosts"`
 }
 
 type PaymentClient struct {
@@ -59,9 +91,108 @@ type PaymentClient struct {
 	Port int    `mapstructure:"port"`
 }
 
+// UserClient is the REST API of the user service, which serves the address
+// book. AddressPath is the address API path with the user ID and address ID as
+// %d verbs; saved addresses are unavailable while it is empty. Timeout is in
+// milliseconds.
+type UserClient struct {
+	BaseURL     string `mapstructure:"base_url"`
+	AddressPath string `mapstructure:"address_path"`
+	Timeout     int    `mapstructure:"timeout"`
+}
+
 type KafkaConfig struct {
-	Host string `mapstructure:"host"`
-	Port int    `mapstructure:"port"`
+	Host     string                 `mapstructure:"host"` // deprecated, use Brokers
+	Port     int                    `mapstructure:"port"` // deprecated, use Brokers
+	Brokers  []string               `mapstructure:"brokers"`
+	SASL     *KafkaSASL             `mapstructure:"sasl"`
+	TLS      *KafkaTLS              `mapstructure:"tls"`
+	Topics   map[string]*KafkaTopic `mapstructure:"topics"` // keyed by logical topic, see consts.Topic*
+	Consumer *KafkaConsumer         `mapstructure:"consumer"`
+	Producer *KafkaProducer         `mapstructure:"producer"`
+}
+
+// KafkaSASL enables SASL authentication when Mechanism is set.
+// Supported mechanisms: PLAIN, SCRAM-SHA-256, SCRAM-SHA-512.
+type KafkaSASL struct {
+	Mechanism string `mapstructure:"mechanism"`
+	Username  string `mapstructure:"username"`
+	Password  string `mapstructure:"password"`
+}
+
+type KafkaTLS struct {
+	Enabled            bool   `mapstructure:"enabled"`
+	CAFile             string `mapstructure:"ca_file"`
+	CertFile           string `mapstructure:"cert_file"`
+	KeyFile            string `mapstructure:"key_file"`
+	InsecureSkipVerify bool   `mapstructure:"insecure_skip_verify"`
+}
+
+// KafkaTopic is the physical topic behind a logical topic, plus the settings
+// used to create it at startup.
+type KafkaTopic struct {
+	Name              string `mapstructure:"name"`
+	Partitions        int    `mapstructure:"partitions"`
+	ReplicationFactor int    `mapstructure:"replication_factor"`
+}
+
+type KafkaConsumer struct {
+	GroupID string `mapstructure:"group_id"`
+}
+
+// KafkaProducer controls how messages are delivered to the broker.
+// Mode is one of "sync", "async" or "batch"; timeouts are in milliseconds.
+type KafkaProducer struct {
+	Mode         string `mapstructure:"mode"`
+	BufferSize   int    `mapstructure:"buffer_size"`
+	BatchSize    int    `mapstructure:"batch_size"`
+	BatchTimeout int    `mapstructure:"batch_timeout"`
+	WriteTimeout int    `mapstructure:"write_timeout"`
+}
+
+// TracingConfig configures OpenTelemetry tracing.
+// Exporter is "otlp" (gRPC to Endpoint) or "stdout" for local runs.
+type TracingConfig struct {
+	Enabled     bool    `mapstructure:"enabled"`
+	ServiceName string  `mapstructure:"service_name"`
+	Exporter    string  `mapstructure:"exporter"`
+	Endpoint    string  `mapstructure:"endpoint"`
+	Insecure    bool    `mapstructure:"insecure"`
+	SampleRatio float64 `mapstructure:"sample_ratio"`
+}
+
+type HealthConfig struct {
+	CheckTimeout int `mapstructure:"check_timeout"` // 单项依赖检查超时, ms
+}
+
+type ShutdownConfig struct {
+	Timeout int `mapstructure:"timeout"` // 优雅停机总时限, s
+}
+
+// SearchConfig selects the order search engine and the consumer group that feeds it.
+type SearchConfig struct {
+	Engine  string `mapstructure:"engine"`   // 目前仅支持 mysql
+	GroupID string `mapstructure:"group_id"` // 索引消费组
+}
+
+// ExportConfig configures order exports. Async export files are kept in Dir,
+// which must be shared between replicas.
+type ExportConfig struct {
+	Dir         string `mapstructure:"dir"`
+	BatchSize   int    `mapstructure:"batch_size"`    // 每批读取的订单数
+	MaxSyncRows int    `mapstructure:"max_sync_rows"` // 同步导出的订单数上限，超过需使用异步导出
+	Workers     int    `mapstructure:"workers"`       // 同时运行的异步导出任务数
+	Retention   int    `mapstructure:"retention"`     // 导出文件保留时长, h
+	JobTimeout  int    `mapstructure:"job_timeout"`   // 异步导出任务从创建到完成的最长时间, min
+}
+
+// CarrierConfig describes a carrier. TrackingURL is a template in which
+// {tracking_no} is replaced by the tracking number. WebhookSecret signs the
+// carrier's tracking webhooks and is read from CARRIER_<CODE>_WEBHOOK_SECRET.
+type CarrierConfig struct {
+	Name          string `mapstructure:"name"`
+	TrackingURL   string `mapstructure:"tracking_url"`
+	WebhookSecret string `mapstructure:"-"`
 }
 
 var UseLocalConfig = false
@@ -91,4 +222,16 @@ func Init() {
 	} else {
 		panic("MYSQL_PASSWORD environment variable is not set")
 	}
+	kafkaPassword := os.Getenv("KAFKA_SASL_PASSWORD")
+	if kafkaPassword != "" && Config.KafkaConfig.SASL != nil {
+		Config.KafkaConfig.SASL.Password = kafkaPassword
+	}
+	for i, host := range Config.CommodityClient.ImageHosts {
+		Config.CommodityClient.ImageHosts[i] = strings.ToLower(strings.TrimSpace(host))
+	}
+	for code, carrier := range Config.Carriers {
+		if carrier != nil {
+			carrier.WebhookSecret = os.Getenv("CARRIER_" + strings.ToUpper(code) + "_WEBHOOK_SECRET")
+		}
+	}
 }
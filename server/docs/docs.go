@@ -15,9 +15,83 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/customer/checkouts/{checkout_no}": {
+            "get": {
+                "description": "根据结算单号查询一次下单拆分出的全部子订单详情",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Order"
+                ],
+                "summary": "用户侧查询结算单",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "结算单号",
+                        "name": "checkout_no",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/types.CheckoutDetail"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
         "/customer/orders": {
             "post": {
-                "description": "创建一个新订单",
+                "description": "创建一个新订单。购物车中不同商家的商品拆分为不同子订单并一次支付，返回结算单号；只有一个商家时结算单号即订单号。\n收货地址可使用地址簿中的地址（address_id）或结构化地址（shipping_address），下单时的地址作为快照保存在订单上",
                 "consumes": [
                     "application/json"
                 ],
@@ -46,18 +120,54 @@ const docTemplate = `{
                             "$ref": "#/definitions/api.Response"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/api.Response"
                         }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
                     }
                 }
             }
         },
         "/customer/orders/list": {
             "post": {
-                "description": "根据userID查询订单列表，支持分页，支持根据时间筛选",
+                "description": "根据userID查询订单列表，支持偏移分页和游标分页（cursor），支持按时间、状态和商品名称筛选。每个订单返回商品件数和缩略图",
                 "consumes": [
                     "application/json"
                 ],
@@ -104,6 +214,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/api.Response"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -160,6 +282,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/api.Response"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -212,31 +346,26 @@ const docTemplate = `{
                             "$ref": "#/definitions/api.Response"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/api.Response"
                         }
-                    }
-                }
-            }
-        },
-        "/merchant/order-stats": {
-            "get": {
-                "description": "get Order Stats",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Order"
-                ],
-                "summary": "get Order Stats",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/api.Response"
                         }
@@ -250,9 +379,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/merchant/orders/list": {
+        "/customer/orders/{order_no}/reorder": {
             "post": {
-                "description": "根据条件查询订单列表，支持分页",
+                "description": "按当前价格和库存重新生成订单的购物车，下架或无库存的商品标记为不可购买，库存不足时按库存数量。返回的 order_item_list 可直接用于创建订单",
                 "consumes": [
                     "application/json"
                 ],
@@ -262,16 +391,14 @@ const docTemplate = `{
                 "tags": [
                     "Order"
                 ],
-                "summary": "查询订单列表",
+                "summary": "再次购买",
                 "parameters": [
                     {
-                        "description": "查询条件",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.ListOrderRequest"
-                        }
+                        "type": "string",
+                        "description": "订单号",
+                        "name": "order_no",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -286,7 +413,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/types.ListOrderResponse"
+                                            "$ref": "#/definitions/types.ReorderQuote"
                                         }
                                     }
                                 }
@@ -299,6 +426,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/api.Response"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -308,26 +453,59 @@ const docTemplate = `{
                 }
             }
         },
-        "/merchant/orders/{order_no}": {
+        "/healthz": {
             "get": {
-                "description": "根据订单号查询订单详情，包括订单基本信息、商品列表和状态日志",
-                "consumes": [
+                "description": "进程存活即返回200，不检查依赖",
+                "produces": [
                     "application/json"
                 ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "存活探针",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/merchant/analytics/countries": {
+            "get": {
+                "description": "按收货国家统计已支付订单数、销售额、用户数和销售额占比",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Order"
+                    "Analytics"
                 ],
-                "summary": "查询订单详情",
+                "summary": "国家分布",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "订单号",
-                        "name": "order_no",
-                        "in": "path",
+                        "description": "起始日期（含），格式 2006-01-02",
+                        "name": "start_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "结束日期（含），格式 2006-01-02",
+                        "name": "end_date",
+                        "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA 时区，如 Asia/Singapore，默认服务器时区",
+                        "name": "tz",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -342,7 +520,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/types.OrderDetail"
+                                            "$ref": "#/definitions/types.CountryBreakdownResponse"
                                         }
                                     }
                                 }
@@ -355,8 +533,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/api.Response"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/api.Response"
                         }
@@ -370,35 +554,55 @@ const docTemplate = `{
                 }
             }
         },
-        "/merchant/orders/{order_no}/ship": {
-            "patch": {
-                "description": "商家标记订单为已发货状态，并添加物流单号",
-                "consumes": [
-                    "application/json"
-                ],
+        "/merchant/analytics/funnel": {
+            "get": {
+                "description": "统计范围内下单的订单到达下单、支付、发货、收货各阶段的数量和比例，以及取消率",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Order"
+                    "Analytics"
                 ],
-                "summary": "商家发货",
+                "summary": "订单转化漏斗",
                 "parameters": [
                     {
-                        "description": "发货信息",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.ShipOrderRequest"
-                        }
+                        "type": "string",
+                        "description": "起始日期（含），格式 2006-01-02",
+                        "name": "start_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "结束日期（含），格式 2006-01-02",
+                        "name": "end_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA 时区，如 Asia/Singapore，默认服务器时区",
+                        "name": "tz",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/types.StatusFunnelResponse"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
@@ -407,6 +611,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/api.Response"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -415,287 +631,2775 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "api.Response": {
-            "type": "object",
-            "properties": {
-                "data": {},
+        },
+        "/merchant/analytics/sales": {
+            "get": {
+                "description": "按日、周（周一开始）或月统计已支付订单数和销售额，周期按 tz 时区划分，没有订单的周期返回 0。范围最长 366 天",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Analytics"
+                ],
+                "summary": "销售趋势",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "起始日期（含），格式 2006-01-02",
+                        "name": "start_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "结束日期（含），格式 2006-01-02",
+                        "name": "end_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA 时区，如 Asia/Singapore，默认服务器时区",
+                        "name": "tz",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "统计周期：day（默认）/ week / month",
+                        "name": "interval",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/types.SalesSeriesResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/merchant/analytics/summary": {
+            "get": {
+                "description": "统计范围内的复购率和支付到首次发货的平均时长",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Analytics"
+                ],
+                "summary": "复购率与发货时效",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "起始日期（含），格式 2006-01-02",
+                        "name": "start_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "结束日期（含），格式 2006-01-02",
+                        "name": "end_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA 时区，如 Asia/Singapore，默认服务器时区",
+                        "name": "tz",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/types.AnalyticsSummary"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/merchant/analytics/top-products": {
+            "get": {
+                "description": "按销售额或销量返回已支付订单中排名靠前的商品",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Analytics"
+                ],
+                "summary": "热销商品",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "起始日期（含），格式 2006-01-02",
+                        "name": "start_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "结束日期（含），格式 2006-01-02",
+                        "name": "end_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA 时区，如 Asia/Singapore，默认服务器时区",
+                        "name": "tz",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "排序：revenue（默认）/ quantity",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "返回数量，默认10，最大100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/types.TopProductsResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/merchant/order-stats": {
+            "get": {
+                "description": "get Order Stats, merchants only see the stats of their own orders",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Order"
+                ],
+                "summary": "get Order Stats",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/merchant/orders/export": {
+            "post": {
+                "description": "按与订单列表相同的筛选条件导出订单及商品明细，每个商品一行，格式为 CSV 或 XLSX。默认同步流式下载；async=true 时创建异步任务，返回任务状态，完成后通过 download_url 下载",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/octet-stream",
+                    "application/json"
+                ],
+                "tags": [
+                    "Order"
+                ],
+                "summary": "商家导出订单",
+                "parameters": [
+                    {
+                        "description": "导出条件",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.ExportOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "导出文件",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/types.ExportJobInfo"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/merchant/orders/export/jobs/{job_id}": {
+            "get": {
+                "description": "查询异步导出任务状态，完成后返回下载链接",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Order"
+                ],
+                "summary": "查询导出任务",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "任务编号",
+                        "name": "job_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/types.ExportJobInfo"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/merchant/orders/export/jobs/{job_id}/download": {
+            "get": {
+                "description": "下载已完成的异步导出任务文件，支持 Range 断点续传",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "Order"
+                ],
+                "summary": "下载导出文件",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "任务编号",
+                        "name": "job_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "导出文件",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "410": {
+                        "description": "Gone",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/merchant/orders/list": {
+            "post": {
+                "description": "根据条件查询订单列表：多状态、金额范围、收货人、商品、物流单号、支付/发货时间筛选，任意字段排序，支持偏移分页和游标分页（cursor），返回符合条件的总数",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Order"
+                ],
+                "summary": "查询订单列表",
+                "parameters": [
+                    {
+                        "description": "查询条件",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.ListOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/types.ListOrderResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/merchant/orders/search": {
+            "get": {
+                "description": "按订单号、收货人姓名/电话/地址、商品名、备注、物流单号全文检索，返回命中高亮及状态、国家、月份分面",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Order"
+                ],
+                "summary": "商家全文检索订单",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "关键词，多个词之间为 AND，每个词至少2个字符",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "订单状态",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "收货国家",
+                        "name": "country",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "下单月份，格式 2006-01",
+                        "name": "month",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "分页限制，默认20，最大100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "分页偏移",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/types.OrderSearchResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/merchant/orders/ship/batch": {
+            "post": {
+                "description": "按快递清单批量发货。JSON 传 items；或以 multipart/form-data 上传 CSV 文件（字段 file，表头包含 order_no、tracking_no，可选 carrier），也可直接以 text/csv 作为请求体；CSV 最多 500 行、不超过 1MB。逐行按订单状态校验，返回逐行结果；all_or_nothing 为 true 时任意一行失败则全部不发货",
+                "consumes": [
+                    "application/json",
+                    "multipart/form-data",
+                    "text/plain"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Order"
+                ],
+                "summary": "商家批量发货",
+                "parameters": [
+                    {
+                        "description": "发货清单（JSON）",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/types.BatchShipRequest"
+                        }
+                    },
+                    {
+                        "type": "file",
+                        "description": "发货清单（CSV）",
+                        "name": "file",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "CSV 上传时使用：任意一行失败则全部不发货",
+                        "name": "all_or_nothing",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/types.BatchShipResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/merchant/orders/{order_no}": {
+            "get": {
+                "description": "根据订单号查询订单详情，包括订单基本信息、商品列表和状态日志",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Order"
+                ],
+                "summary": "查询订单详情",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "订单号",
+                        "name": "order_no",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/types.OrderDetail"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "发货前（已创建、已付款）修改收货人、电话、收货地址、备注或删除商品，未传的字段不修改。删除商品后重新计算运费和税，运费不高于原运费；已付款订单退还差额。每次修改记录字段修改前后的值",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Order"
+                ],
+                "summary": "商家修改订单",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "订单号",
+                        "name": "order_no",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "修改内容",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.EditOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/types.EditOrderResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/merchant/orders/{order_no}/audits": {
+            "get": {
+                "description": "按修改时间顺序返回订单的修改记录，包括操作人、原因、退款金额及各字段修改前后的值",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Order"
+                ],
+                "summary": "商家查询订单修改记录",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "订单号",
+                        "name": "order_no",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/types.OrderAuditEntry"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/merchant/orders/{order_no}/ship": {
+            "patch": {
+                "description": "商家发出订单的全部未发货商品，订单变为已发货。承运商和物流单号必填，物流回调按二者匹配包裹",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Order"
+                ],
+                "summary": "商家发货",
+                "parameters": [
+                    {
+                        "description": "发货信息",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.ShipOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/merchant/orders/{order_no}/shipments": {
+            "post": {
+                "description": "为订单创建一个包裹（承运商、物流单号、重量、面单地址及包裹内商品和数量），支持一单多包裹。items 为空时发出全部未发货商品；全部商品发出后订单变为已发货，否则为部分发货",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Order"
+                ],
+                "summary": "商家创建包裹",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "订单号",
+                        "name": "order_no",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "包裹信息",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.CreateShipmentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/types.ShipmentDetail"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "检查MySQL、Redis、Kafka及下游gRPC连接，启动中或关闭中返回503",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "就绪探针",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/health.Report"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/health.Report"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/carriers/{carrier}/tracking": {
+            "post": {
+                "description": "承运商推送包裹的物流轨迹。请求需带 X-Webhook-Timestamp（unix 秒）和 X-Webhook-Signature（以承运商密钥对 \"\u003ctimestamp\u003e.\u003cbody\u003e\" 计算的 HMAC-SHA256，hex 编码），时间戳与服务器时间相差不得超过 5 分钟。重复推送的事件只保存一次；包裹签收后，自动确认收货的等待期从签收时间起算",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "承运商物流轨迹回调",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "承运商编码",
+                        "name": "carrier",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "unix 秒",
+                        "name": "X-Webhook-Timestamp",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "HMAC-SHA256 签名",
+                        "name": "X-Webhook-Signature",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "物流轨迹",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.TrackingWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.Response"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "api.Response": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "error": {
+                    "description": "错误详情，用于排查",
+                    "type": "string"
+                },
+                "fields": {
+                    "description": "参数校验失败的字段",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/errs.FieldError"
+                    }
+                },
+                "msg": {
+                    "description": "面向用户的提示，按 Accept-Language 本地化",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "0 表示成功，否则为错误码，见 errs.Code",
+                    "type": "integer"
+                }
+            }
+        },
+        "errs.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "message": {
+                    "description": "本地化的提示",
+                    "type": "string"
+                },
+                "param": {
+                    "description": "规则参数，如 gt=0 中的 0",
+                    "type": "string"
+                },
+                "rule": {
+                    "description": "未通过的校验规则，如 required、gt、phone",
+                    "type": "string"
+                }
+            }
+        },
+        "health.CheckResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "latency_ms": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "health.Report": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/health.CheckResult"
+                    }
+                },
+                "ready": {
+                    "type": "boolean"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.Address": {
+            "type": "object",
+            "required": [
+                "city",
+                "country",
+                "line1"
+            ],
+            "properties": {
+                "city": {
+                    "description": "城市",
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "country": {
+                    "description": "ISO 3166-1 两位国家代码，如 SG",
+                    "type": "string"
+                },
+                "line1": {
+                    "description": "街道、门牌号",
+                    "type": "string",
+                    "maxLength": 120
+                },
+                "line2": {
+                    "description": "楼层、单元号等",
+                    "type": "string",
+                    "maxLength": 120
+                },
+                "postal_code": {
+                    "description": "邮政编码，如 018956、SW1A 1AA",
+                    "type": "string"
+                },
+                "state": {
+                    "description": "州/省",
+                    "type": "string",
+                    "maxLength": 64
+                }
+            }
+        },
+        "types.AnalyticsSummary": {
+            "type": "object",
+            "properties": {
+                "avg_time_to_ship_hours": {
+                    "description": "支付到首次发货的平均小时数",
+                    "type": "number"
+                },
+                "customers": {
+                    "description": "有已支付订单的用户数",
+                    "type": "integer"
+                },
+                "repeat_customer_rate": {
+                    "description": "复购率",
+                    "type": "number"
+                },
+                "repeat_customers": {
+                    "description": "其中下单两次及以上的用户数",
+                    "type": "integer"
+                },
+                "shipped_orders": {
+                    "description": "已发货订单数",
+                    "type": "integer"
+                }
+            }
+        },
+        "types.BatchShipItem": {
+            "type": "object",
+            "properties": {
+                "carrier": {
+                    "description": "承运商，可为空",
+                    "type": "string"
+                },
+                "order_no": {
+                    "type": "string"
+                },
+                "tracking_no": {
+                    "description": "物流单号",
+                    "type": "string"
+                }
+            }
+        },
+        "types.BatchShipRequest": {
+            "type": "object",
+            "required": [
+                "items"
+            ],
+            "properties": {
+                "all_or_nothing": {
+                    "description": "任意一行失败则全部不发货",
+                    "type": "boolean"
+                },
+                "items": {
+                    "description": "各行的字段在发货时逐行校验，结果按行返回",
+                    "type": "array",
+                    "maxItems": 500,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/types.BatchShipItem"
+                    }
+                }
+            }
+        },
+        "types.BatchShipResponse": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "integer"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.BatchShipRowResult"
+                    }
+                },
+                "succeeded": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.BatchShipRowResult": {
+            "type": "object",
+            "properties": {
+                "carrier": {
+                    "type": "string"
+                },
+                "error": {
+                    "description": "失败原因",
+                    "type": "string"
+                },
+                "order_no": {
+                    "type": "string"
+                },
+                "result": {
+                    "description": "shipped / failed / skipped（all_or_nothing 时因其他行失败未执行）",
+                    "type": "string"
+                },
+                "row": {
+                    "description": "行号，从 1 开始，CSV 不含表头",
+                    "type": "integer"
+                },
+                "tracking_no": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.CheckoutDetail": {
+            "type": "object",
+            "properties": {
+                "checkout_no": {
+                    "description": "结算单号",
+                    "type": "string"
+                },
+                "create_time": {
+                    "description": "下单时间",
+                    "type": "string"
+                },
+                "orders": {
+                    "description": "各商家的子订单",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.OrderDetail"
+                    }
+                },
+                "total_amount": {
+                    "description": "各子订单总金额之和",
+                    "type": "integer"
+                }
+            }
+        },
+        "types.ConfirmOrderRequest": {
+            "type": "object",
+            "properties": {
+                "order_no": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.CountryBreakdownResponse": {
+            "type": "object",
+            "properties": {
+                "countries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.CountrySales"
+                    }
+                }
+            }
+        },
+        "types.CountrySales": {
+            "type": "object",
+            "properties": {
+                "country": {
+                    "type": "string"
+                },
+                "customers": {
+                    "type": "integer"
+                },
+                "orders": {
+                    "type": "integer"
+                },
+                "revenue": {
+                    "type": "integer"
+                },
+                "share": {
+                    "description": "销售额占比",
+                    "type": "number"
+                }
+            }
+        },
+        "types.CreateShipmentRequest": {
+            "type": "object",
+            "required": [
+                "items",
+                "tracking_no"
+            ],
+            "properties": {
+                "carrier_code": {
+                    "description": "承运商编码",
+                    "type": "string",
+                    "maxLength": 32
+                },
+                "items": {
+                    "description": "包裹内商品，为空时发出全部未发货商品",
+                    "type": "array",
+                    "maxItems": 100,
+                    "items": {
+                        "$ref": "#/definitions/types.ShipmentItemRequest"
+                    }
+                },
+                "label_url": {
+                    "description": "面单地址",
+                    "type": "string",
+                    "maxLength": 512
+                },
+                "tracking_no": {
+                    "description": "物流单号",
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "weight": {
+                    "description": "包裹重量，克",
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "types.CustomerListOrderRequest": {
+            "type": "object",
+            "properties": {
+                "count_mode": {
+                    "description": "总数统计方式：exact（默认）/ estimate",
+                    "type": "string"
+                },
+                "cursor": {
+                    "description": "游标分页：上一页返回的 next_cursor",
+                    "type": "string"
+                },
+                "end_time": {
+                    "description": "创建时间结束范围",
+                    "type": "string"
+                },
+                "limit": {
+                    "description": "分页限制",
+                    "type": "integer"
+                },
+                "offset": {
+                    "description": "分页偏移，传 cursor 时忽略",
+                    "type": "integer"
+                },
+                "product_name": {
+                    "description": "商品名称关键字",
+                    "type": "string",
+                    "maxLength": 128
+                },
+                "start_time": {
+                    "description": "创建时间开始范围",
+                    "type": "string"
+                },
+                "statuses": {
+                    "description": "订单状态多选",
+                    "type": "array",
+                    "maxItems": 6,
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "types.EditOrderRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "description": "修改原因，记入审计日志",
+                    "type": "string",
+                    "maxLength": 256
+                },
+                "receiver_first_name": {
+                    "description": "收货人姓名，不传表示不修改，下同",
+                    "type": "string",
+                    "maxLength": 64,
+                    "minLength": 1
+                },
+                "receiver_last_name": {
+                    "description": "收货人姓名",
+                    "type": "string",
+                    "maxLength": 64,
+                    "minLength": 1
+                },
+                "receiver_phone": {
+                    "description": "收货人电话，E.164 格式",
+                    "type": "string"
+                },
+                "remark": {
+                    "description": "备注，传空字符串清空",
+                    "type": "string",
+                    "maxLength": 256
+                },
+                "remove_item_ids": {
+                    "description": "要删除的订单商品ID，即订单详情 order_items 中的 id",
+                    "type": "array",
+                    "maxItems": 100,
+                    "uniqueItems": true,
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "shipping_address": {
+                    "description": "收货地址，整体替换",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.Address"
+                        }
+                    ]
+                }
+            }
+        },
+        "types.EditOrderResponse": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "description": "本次修改的字段",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.FieldChange"
+                    }
+                },
+                "order": {
+                    "description": "修改后的订单",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.OrderDetail"
+                        }
+                    ]
+                },
+                "refund_amount": {
+                    "description": "本次修改退还的金额",
+                    "type": "integer"
+                },
+                "refund_status": {
+                    "description": "退款消息发送状态：pending 待重试，sent 已送达；没有退款时为空",
+                    "type": "string"
+                }
+            }
+        },
+        "types.ExportJobInfo": {
+            "type": "object",
+            "properties": {
+                "create_time": {
+                    "description": "创建时间",
+                    "type": "string"
+                },
+                "download_url": {
+                    "description": "下载链接，仅 succeeded 时返回",
+                    "type": "string"
+                },
                 "error": {
+                    "description": "失败原因",
+                    "type": "string"
+                },
+                "expire_time": {
+                    "description": "文件过期时间",
+                    "type": "string"
+                },
+                "finish_time": {
+                    "description": "完成时间",
+                    "type": "string"
+                },
+                "format": {
+                    "type": "string"
+                },
+                "job_id": {
+                    "type": "string"
+                },
+                "row_count": {
+                    "description": "导出行数，每个订单商品一行",
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "pending / running / succeeded / failed / expired",
+                    "type": "string"
+                }
+            }
+        },
+        "types.ExportOrderRequest": {
+            "type": "object",
+            "properties": {
+                "async": {
+                    "description": "异步导出：立即返回任务，完成后通过下载链接获取文件",
+                    "type": "boolean"
+                },
+                "count_mode": {
+                    "description": "总数统计方式：exact（默认）/ estimate",
+                    "type": "string",
+                    "enum": [
+                        "exact",
+                        "estimate"
+                    ]
+                },
+                "cursor": {
+                    "description": "游标分页：上一页返回的 next_cursor，按 pay_time / delivery_time 排序时不支持",
+                    "type": "string"
+                },
+                "delivery_end_time": {
+                    "description": "发货时间结束范围",
+                    "type": "string"
+                },
+                "delivery_start_time": {
+                    "description": "发货时间开始范围",
+                    "type": "string"
+                },
+                "end_time": {
+                    "description": "创建时间结束范围",
+                    "type": "string"
+                },
+                "format": {
+                    "description": "导出格式：csv（默认）/ xlsx",
+                    "type": "string",
+                    "enum": [
+                        "csv",
+                        "xlsx"
+                    ]
+                },
+                "limit": {
+                    "description": "分页限制",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "logistics_no": {
+                    "description": "物流单号",
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "max_amount": {
+                    "description": "总金额上限（含）",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "min_amount": {
+                    "description": "总金额下限（含）",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "offset": {
+                    "description": "分页偏移，传 cursor 时忽略",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "order_no": {
+                    "description": "订单号筛选",
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "order_status": {
+                    "description": "订单状态筛选",
+                    "type": "integer",
+                    "enum": [
+                        1,
+                        2,
+                        3,
+                        4,
+                        5,
+                        6
+                    ]
+                },
+                "pay_end_time": {
+                    "description": "支付时间结束范围",
+                    "type": "string"
+                },
+                "pay_start_time": {
+                    "description": "支付时间开始范围",
+                    "type": "string"
+                },
+                "product_id": {
+                    "description": "包含该商品的订单",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "product_name": {
+                    "description": "包含名称中有该关键字的商品的订单",
+                    "type": "string",
+                    "maxLength": 128
+                },
+                "receiver_country": {
+                    "description": "收货人国家",
+                    "type": "string"
+                },
+                "receiver_name": {
+                    "description": "收货人姓名前缀，\"名 姓\" 时分别匹配",
+                    "type": "string",
+                    "maxLength": 128
+                },
+                "receiver_phone": {
+                    "description": "收货人电话前缀",
+                    "type": "string",
+                    "maxLength": 16
+                },
+                "sort_by": {
+                    "description": "排序字段：create_time（默认）/ pay_time / delivery_time / total_amount / status",
+                    "type": "string",
+                    "enum": [
+                        "create_time",
+                        "pay_time",
+                        "delivery_time",
+                        "total_amount",
+                        "status"
+                    ]
+                },
+                "sort_order": {
+                    "description": "排序方向：desc（默认）/ asc",
+                    "type": "string"
+                },
+                "start_time": {
+                    "description": "创建时间开始范围",
+                    "type": "string"
+                },
+                "statuses": {
+                    "description": "订单状态多选",
+                    "type": "array",
+                    "maxItems": 6,
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "user_id": {
+                    "description": "用户ID筛选",
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "types.FacetBucket": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "展示名称，仅状态分面",
+                    "type": "string"
+                },
+                "value": {
+                    "description": "筛选时传入的值",
+                    "type": "string"
+                }
+            }
+        },
+        "types.FieldChange": {
+            "type": "object",
+            "properties": {
+                "after": {
+                    "description": "修改后的值，删除商品时为 null"
+                },
+                "before": {
+                    "description": "修改前的值"
+                },
+                "field": {
+                    "description": "字段，如 receiver_phone、shipping_address.city、items[id=3]",
+                    "type": "string"
+                }
+            }
+        },
+        "types.FunnelStage": {
+            "type": "object",
+            "properties": {
+                "orders": {
+                    "description": "到达该阶段的订单数",
+                    "type": "integer"
+                },
+                "rate": {
+                    "description": "占下单数的比例",
+                    "type": "number"
+                },
+                "stage": {
+                    "description": "placed / paid / shipped / delivered",
+                    "type": "string"
+                }
+            }
+        },
+        "types.ListOrderRequest": {
+            "type": "object",
+            "properties": {
+                "count_mode": {
+                    "description": "总数统计方式：exact（默认）/ estimate",
+                    "type": "string",
+                    "enum": [
+                        "exact",
+                        "estimate"
+                    ]
+                },
+                "cursor": {
+                    "description": "游标分页：上一页返回的 next_cursor，按 pay_time / delivery_time 排序时不支持",
+                    "type": "string"
+                },
+                "delivery_end_time": {
+                    "description": "发货时间结束范围",
+                    "type": "string"
+                },
+                "delivery_start_time": {
+                    "description": "发货时间开始范围",
+                    "type": "string"
+                },
+                "end_time": {
+                    "description": "创建时间结束范围",
+                    "type": "string"
+                },
+                "limit": {
+                    "description": "分页限制",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "logistics_no": {
+                    "description": "物流单号",
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "max_amount": {
+                    "description": "总金额上限（含）",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "min_amount": {
+                    "description": "总金额下限（含）",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "offset": {
+                    "description": "分页偏移，传 cursor 时忽略",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "order_no": {
+                    "description": "订单号筛选",
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "order_status": {
+                    "description": "订单状态筛选",
+                    "type": "integer",
+                    "enum": [
+                        1,
+                        2,
+                        3,
+                        4,
+                        5,
+                        6
+                    ]
+                },
+                "pay_end_time": {
+                    "description": "支付时间结束范围",
+                    "type": "string"
+                },
+                "pay_start_time": {
+                    "description": "支付时间开始范围",
+                    "type": "string"
+                },
+                "product_id": {
+                    "description": "包含该商品的订单",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "product_name": {
+                    "description": "包含名称中有该关键字的商品的订单",
+                    "type": "string",
+                    "maxLength": 128
+                },
+                "receiver_country": {
+                    "description": "收货人国家",
+                    "type": "string"
+                },
+                "receiver_name": {
+                    "description": "收货人姓名前缀，\"名 姓\" 时分别匹配",
+                    "type": "string",
+                    "maxLength": 128
+                },
+                "receiver_phone": {
+                    "description": "收货人电话前缀",
+                    "type": "string",
+                    "maxLength": 16
+                },
+                "sort_by": {
+                    "description": "排序字段：create_time（默认）/ pay_time / delivery_time / total_amount / status",
+                    "type": "string",
+                    "enum": [
+                        "create_time",
+                        "pay_time",
+                        "delivery_time",
+                        "total_amount",
+                        "status"
+                    ]
+                },
+                "sort_order": {
+                    "description": "排序方向：desc（默认）/ asc",
+                    "type": "string"
+                },
+                "start_time": {
+                    "description": "创建时间开始范围",
+                    "type": "string"
+                },
+                "statuses": {
+                    "description": "订单状态多选",
+                    "type": "array",
+                    "maxItems": 6,
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "user_id": {
+                    "description": "用户ID筛选",
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "types.ListOrderResponse": {
+            "type": "object",
+            "properties": {
+                "has_more": {
+                    "description": "是否还有下一页",
+                    "type": "boolean"
+                },
+                "next_cursor": {
+                    "description": "下一页游标，没有更多时为空",
+                    "type": "string"
+                },
+                "orders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.OrderInfoInList"
+                    }
+                },
+                "total": {
+                    "description": "符合条件的订单总数",
+                    "type": "integer"
+                },
+                "total_estimated": {
+                    "description": "total 是否为估算值",
+                    "type": "boolean"
+                }
+            }
+        },
+        "types.OrderAuditEntry": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "操作，目前只有 edit",
+                    "type": "string"
+                },
+                "changes": {
+                    "description": "字段修改前后的值",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.FieldChange"
+                    }
+                },
+                "create_time": {
+                    "description": "修改时间",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "operator_id": {
+                    "description": "修改订单的用户",
+                    "type": "integer"
+                },
+                "reason": {
+                    "description": "修改原因",
+                    "type": "string"
+                },
+                "refund_amount": {
+                    "description": "本次修改退还的金额",
+                    "type": "integer"
+                },
+                "refund_status": {
+                    "description": "退款消息发送状态：pending 待重试，sent 已送达；没有退款时为空",
+                    "type": "string"
+                }
+            }
+        },
+        "types.OrderDetail": {
+            "type": "object",
+            "properties": {
+                "address_id": {
+                    "description": "地址簿中的地址，0 表示下单时填写",
+                    "type": "integer"
+                },
+                "arrived_time": {
+                    "description": "承运商报告全部包裹签收的时间",
+                    "type": "string"
+                },
+                "carrier": {
+                    "description": "承运商",
+                    "type": "string"
+                },
+                "checkout_no": {
+                    "description": "结算单号",
+                    "type": "string"
+                },
+                "confirm_time": {
+                    "description": "收货确认时间",
+                    "type": "string"
+                },
+                "create_time": {
+                    "description": "创建时间",
+                    "type": "string"
+                },
+                "delivery_time": {
+                    "description": "发货时间",
+                    "type": "string"
+                },
+                "logistics_no": {
+                    "description": "物流单号",
+                    "type": "string"
+                },
+                "merchant_id": {
+                    "description": "商家",
+                    "type": "integer"
+                },
+                "order_items": {
+                    "description": "订单商品列表",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.OrderItemDetail"
+                    }
+                },
+                "order_no": {
+                    "description": "基本订单信息",
                     "type": "string"
                 },
-                "msg": {
+                "pay_amount": {
+                    "description": "实际支付金额",
+                    "type": "integer"
+                },
+                "pay_time": {
+                    "description": "支付时间",
+                    "type": "string"
+                },
+                "receiver_address": {
+                    "description": "收货地址",
+                    "type": "string"
+                },
+                "receiver_country": {
+                    "description": "收货人国家",
+                    "type": "string"
+                },
+                "receiver_first_name": {
+                    "description": "收货信息",
+                    "type": "string"
+                },
+                "receiver_last_name": {
+                    "description": "收货人姓名",
+                    "type": "string"
+                },
+                "receiver_phone": {
+                    "description": "收货人电话",
+                    "type": "string"
+                },
+                "receiver_zip_code": {
+                    "description": "已废弃，使用 shipping_address.postal_code",
+                    "type": "integer"
+                },
+                "refund_amount": {
+                    "description": "已退款金额，发货前删除商品时退还",
+                    "type": "integer"
+                },
+                "remark": {
+                    "description": "其他信息",
                     "type": "string"
                 },
+                "shipments": {
+                    "description": "包裹列表",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.ShipmentDetail"
+                    }
+                },
+                "shipping_address": {
+                    "description": "下单时的收货地址快照，不随地址簿修改变化",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.Address"
+                        }
+                    ]
+                },
+                "shipping_fee": {
+                    "description": "运费",
+                    "type": "integer"
+                },
                 "status": {
+                    "description": "订单状态",
+                    "type": "integer"
+                },
+                "status_logs": {
+                    "description": "订单状态变更日志",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.OrderStatusLogDetail"
+                    }
+                },
+                "status_name": {
+                    "description": "订单状态名称",
+                    "type": "string"
+                },
+                "tax": {
+                    "description": "税费",
+                    "type": "integer"
+                },
+                "total_amount": {
+                    "description": "总金额",
+                    "type": "integer"
+                },
+                "update_time": {
+                    "description": "更新时间",
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "下单用户",
                     "type": "integer"
                 }
             }
         },
-        "types.ConfirmOrderRequest": {
+        "types.OrderInfo": {
             "type": "object",
+            "required": [
+                "order_item_list",
+                "receiver_first_name",
+                "receiver_last_name",
+                "receiver_phone"
+            ],
             "properties": {
-                "order_no": {
+                "address_id": {
+                    "description": "地址簿中的地址，优先于 shipping_address",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "order_item_list": {
+                    "description": "订单商品列表，商品不能重复",
+                    "type": "array",
+                    "maxItems": 100,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/types.OrderItemInfo"
+                    }
+                },
+                "receiver_address": {
+                    "description": "收货地址；已废弃，使用 shipping_address",
+                    "type": "string",
+                    "maxLength": 256
+                },
+                "receiver_country": {
+                    "description": "收货人国家，ISO 3166-1 两位代码；已废弃，使用 shipping_address",
+                    "type": "string"
+                },
+                "receiver_first_name": {
+                    "description": "收货人姓名",
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "receiver_last_name": {
+                    "description": "收货人姓名",
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "receiver_phone": {
+                    "description": "收货人电话，E.164 格式，如 +6591234567",
                     "type": "string"
+                },
+                "receiver_zip_code": {
+                    "description": "收货人邮政编码；已废弃，整数无法保存字母和前导零",
+                    "type": "integer"
+                },
+                "remark": {
+                    "description": "备注",
+                    "type": "string",
+                    "maxLength": 256
+                },
+                "shipping_address": {
+                    "description": "结构化收货地址，优先于 receiver_address 等字段",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.Address"
+                        }
+                    ]
                 }
             }
         },
-        "types.CustomerListOrderRequest": {
+        "types.OrderInfoInList": {
             "type": "object",
             "properties": {
-                "end_time": {
-                    "description": "创建时间结束范围",
+                "checkout_no": {
+                    "description": "结算单号，同一次下单的子订单相同",
                     "type": "string"
                 },
-                "limit": {
-                    "description": "分页限制",
+                "create_time": {
+                    "type": "string"
+                },
+                "item_count": {
+                    "description": "商品总件数，仅用户侧列表返回",
                     "type": "integer"
                 },
-                "offset": {
-                    "description": "分页偏移",
+                "merchant_id": {
+                    "description": "商家",
                     "type": "integer"
                 },
-                "start_time": {
-                    "description": "创建时间开始范围",
+                "order_no": {
+                    "type": "string"
+                },
+                "receiver_first_name": {
+                    "description": "收货人姓名",
+                    "type": "string"
+                },
+                "receiver_last_name": {
+                    "description": "收货人姓名",
+                    "type": "string"
+                },
+                "receiver_phone": {
+                    "description": "收货人电话",
+                    "type": "string"
+                },
+                "status": {
                     "type": "string"
+                },
+                "thumbnail": {
+                    "description": "首个商品的图片，仅用户侧列表返回",
+                    "type": "string"
+                },
+                "total_amount": {
+                    "type": "integer"
                 }
             }
         },
-        "types.ListOrderRequest": {
+        "types.OrderItemDetail": {
             "type": "object",
             "properties": {
-                "end_time": {
-                    "description": "创建时间结束范围",
+                "create_time": {
+                    "description": "创建时间",
                     "type": "string"
                 },
-                "limit": {
-                    "description": "分页限制",
+                "id": {
+                    "description": "订单商品ID",
                     "type": "integer"
                 },
-                "offset": {
-                    "description": "分页偏移",
+                "image_url": {
+                    "description": "商品图片",
+                    "type": "string"
+                },
+                "price": {
+                    "description": "商品单价",
                     "type": "integer"
                 },
-                "order_no": {
-                    "description": "订单号筛选",
+                "product_id": {
+                    "description": "商品ID",
+                    "type": "integer"
+                },
+                "product_name": {
+                    "description": "商品名称",
                     "type": "string"
                 },
-                "order_status": {
-                    "description": "订单状态筛选",
+                "quantity": {
+                    "description": "商品数量",
                     "type": "integer"
                 },
-                "start_time": {
-                    "description": "创建时间开始范围",
+                "shipped_quantity": {
+                    "description": "已发货数量",
+                    "type": "integer"
+                },
+                "total_price": {
+                    "description": "商品总价",
+                    "type": "integer"
+                },
+                "update_time": {
+                    "description": "更新时间",
+                    "type": "string"
+                }
+            }
+        },
+        "types.OrderItemInfo": {
+            "type": "object",
+            "properties": {
+                "image_url": {
+                    "description": "商品图片，用作订单列表缩略图；只接受商品目录 CDN 上的 https 地址",
+                    "type": "string",
+                    "maxLength": 512
+                },
+                "merchant_id": {
+                    "description": "商品所属商家，由服务端按商品目录确定，传入时必须一致；不同商家的商品拆分为不同子订单",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "price": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "product_name": {
+                    "type": "string",
+                    "maxLength": 128
+                },
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.OrderSearchHit": {
+            "type": "object",
+            "properties": {
+                "create_time": {
+                    "type": "string"
+                },
+                "highlights": {
+                    "description": "字段 -\u003e 命中片段，命中词以 \u003cem\u003e\u003c/em\u003e 包裹",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "order_no": {
+                    "type": "string"
+                },
+                "receiver_country": {
+                    "type": "string"
+                },
+                "receiver_name": {
+                    "type": "string"
+                },
+                "score": {
+                    "description": "相关度",
+                    "type": "number"
+                },
+                "status": {
                     "type": "string"
                 },
+                "total_amount": {
+                    "type": "integer"
+                },
                 "user_id": {
-                    "description": "用户ID筛选",
                     "type": "integer"
                 }
             }
         },
-        "types.ListOrderResponse": {
+        "types.OrderSearchResponse": {
+            "type": "object",
+            "properties": {
+                "facets": {
+                    "description": "status / country / month",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "$ref": "#/definitions/types.FacetBucket"
+                        }
+                    }
+                },
+                "hits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.OrderSearchHit"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.OrderStatusLogDetail": {
+            "type": "object",
+            "properties": {
+                "create_time": {
+                    "description": "变更时间",
+                    "type": "string"
+                },
+                "current_status": {
+                    "description": "当前状态",
+                    "type": "integer"
+                },
+                "id": {
+                    "description": "日志ID",
+                    "type": "integer"
+                },
+                "remark": {
+                    "description": "备注",
+                    "type": "string"
+                },
+                "status_name": {
+                    "description": "状态名称",
+                    "type": "string"
+                }
+            }
+        },
+        "types.ProductSales": {
             "type": "object",
             "properties": {
                 "orders": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/types.OrderInfoInList"
-                    }
+                    "description": "包含该商品的订单数",
+                    "type": "integer"
                 },
-                "total": {
+                "product_id": {
+                    "type": "integer"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "description": "销量",
+                    "type": "integer"
+                },
+                "revenue": {
+                    "description": "销售额",
                     "type": "integer"
                 }
             }
         },
-        "types.OrderDetail": {
+        "types.ReorderItem": {
             "type": "object",
             "properties": {
-                "confirm_time": {
-                    "description": "收货确认时间",
-                    "type": "string"
-                },
-                "create_time": {
-                    "description": "创建时间",
-                    "type": "string"
-                },
-                "delivery_time": {
-                    "description": "发货时间",
-                    "type": "string"
-                },
-                "logistics_no": {
-                    "description": "物流单号",
-                    "type": "string"
-                },
-                "order_items": {
-                    "description": "订单商品列表",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/types.OrderItemDetail"
-                    }
+                "available": {
+                    "description": "是否可购买",
+                    "type": "boolean"
                 },
-                "order_no": {
-                    "description": "基本订单信息",
+                "image_url": {
                     "type": "string"
                 },
-                "pay_amount": {
-                    "description": "实际支付金额",
+                "merchant_id": {
                     "type": "integer"
                 },
-                "pay_time": {
-                    "description": "支付时间",
-                    "type": "string"
-                },
-                "receiver_address": {
-                    "description": "收货地址",
-                    "type": "string"
+                "order_price": {
+                    "description": "原订单价格",
+                    "type": "integer"
                 },
-                "receiver_country": {
-                    "description": "收货人国家",
-                    "type": "string"
+                "order_quantity": {
+                    "description": "原订单数量",
+                    "type": "integer"
                 },
-                "receiver_first_name": {
-                    "description": "收货信息",
-                    "type": "string"
+                "price": {
+                    "description": "当前价格",
+                    "type": "integer"
                 },
-                "receiver_last_name": {
-                    "description": "收货人姓名",
-                    "type": "string"
+                "product_id": {
+                    "type": "integer"
                 },
-                "receiver_phone": {
-                    "description": "收货人电话",
+                "product_name": {
                     "type": "string"
                 },
-                "receiver_zip_code": {
-                    "description": "收货人邮政编码",
+                "quantity": {
+                    "description": "可购买数量，库存不足时少于原数量",
                     "type": "integer"
                 },
-                "remark": {
-                    "description": "其他信息",
+                "reason": {
+                    "description": "不可购买或数量减少的原因：unavailable / out_of_stock / insufficient_stock",
                     "type": "string"
-                },
-                "shipping_fee": {
-                    "description": "运费",
+                }
+            }
+        },
+        "types.ReorderQuote": {
+            "type": "object",
+            "properties": {
+                "item_total": {
+                    "description": "商品总价，按当前价格",
                     "type": "integer"
                 },
-                "status": {
-                    "description": "订单状态",
-                    "type": "integer"
+                "items": {
+                    "description": "原订单的每个商品及其当前情况",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.ReorderItem"
+                    }
                 },
-                "status_logs": {
-                    "description": "订单状态变更日志",
+                "order_item_list": {
+                    "description": "可购买的商品，可直接用于创建订单",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/types.OrderStatusLogDetail"
+                        "$ref": "#/definitions/types.OrderItemInfo"
                     }
                 },
-                "status_name": {
-                    "description": "订单状态名称",
+                "shipping_fee": {
+                    "description": "运费，按商家分别计算后合计",
+                    "type": "integer"
+                },
+                "source_order_no": {
+                    "description": "原订单号",
                     "type": "string"
                 },
                 "tax": {
-                    "description": "税费",
+                    "description": "税",
                     "type": "integer"
                 },
                 "total_amount": {
-                    "description": "总金额",
+                    "description": "应付总额",
+                    "type": "integer"
+                }
+            }
+        },
+        "types.SalesPoint": {
+            "type": "object",
+            "properties": {
+                "orders": {
+                    "description": "已支付订单数",
                     "type": "integer"
                 },
-                "update_time": {
-                    "description": "更新时间",
+                "period": {
+                    "description": "周期标识：日、周为起始日期 2006-01-02，月为 2006-01",
                     "type": "string"
                 },
-                "user_id": {
-                    "description": "下单用户",
+                "revenue": {
+                    "description": "销售额",
                     "type": "integer"
+                },
+                "start": {
+                    "description": "周期起始时间",
+                    "type": "string"
                 }
             }
         },
-        "types.OrderInfo": {
+        "types.SalesSeriesResponse": {
             "type": "object",
             "properties": {
-                "order_item_list": {
-                    "description": "订单商品列表",
+                "interval": {
+                    "type": "string"
+                },
+                "points": {
+                    "description": "范围内每个周期一个点，没有订单的周期为 0",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/types.OrderItemInfo"
+                        "$ref": "#/definitions/types.SalesPoint"
                     }
                 },
-                "receiver_address": {
-                    "description": "收货地址",
-                    "type": "string"
-                },
-                "receiver_country": {
-                    "description": "收货人国家",
-                    "type": "string"
-                },
-                "receiver_first_name": {
-                    "description": "收货人姓名",
-                    "type": "string"
-                },
-                "receiver_last_name": {
-                    "description": "收货人姓名",
-                    "type": "string"
-                },
-                "receiver_phone": {
-                    "description": "收货人电话",
-                    "type": "string"
+                "total_orders": {
+                    "type": "integer"
                 },
-                "receiver_zip_code": {
-                    "description": "收货人邮政编码",
+                "total_revenue": {
                     "type": "integer"
                 },
-                "remark": {
-                    "description": "备注",
+                "tz": {
                     "type": "string"
                 }
             }
         },
-        "types.OrderInfoInList": {
+        "types.ShipOrderRequest": {
             "type": "object",
+            "required": [
+                "carrier_code",
+                "tracking_no"
+            ],
             "properties": {
-                "create_time": {
+                "carrier_code": {
+                    "description": "承运商编码，见配置 carriers",
+                    "type": "string",
+                    "maxLength": 32
+                },
+                "tracking_no": {
+                    "type": "string",
+                    "maxLength": 64
+                }
+            }
+        },
+        "types.ShipmentDetail": {
+            "type": "object",
+            "properties": {
+                "carrier_code": {
+                    "description": "承运商编码",
                     "type": "string"
                 },
-                "order_no": {
+                "carrier_name": {
+                    "description": "承运商名称",
                     "type": "string"
                 },
-                "receiver_first_name": {
-                    "description": "收货人姓名",
+                "create_time": {
+                    "description": "发货时间",
                     "type": "string"
                 },
-                "receiver_last_name": {
-                    "description": "收货人姓名",
+                "delivered_time": {
+                    "description": "承运商报告的签收时间",
                     "type": "string"
                 },
-                "receiver_phone": {
-                    "description": "收货人电话",
+                "id": {
+                    "description": "包裹ID",
+                    "type": "integer"
+                },
+                "items": {
+                    "description": "包裹内商品",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.ShipmentItemDetail"
+                    }
+                },
+                "label_url": {
+                    "description": "面单地址",
                     "type": "string"
                 },
-                "status": {
+                "tracking_events": {
+                    "description": "物流轨迹，仅用户查询订单详情时返回",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.TrackingEventDetail"
+                    }
+                },
+                "tracking_no": {
+                    "description": "物流单号",
                     "type": "string"
                 },
-                "total_amount": {
+                "tracking_url": {
+                    "description": "物流查询链接，承运商未配置模板时为空",
+                    "type": "string"
+                },
+                "weight": {
+                    "description": "包裹重量，克",
                     "type": "integer"
                 }
             }
         },
-        "types.OrderItemDetail": {
+        "types.ShipmentItemDetail": {
             "type": "object",
             "properties": {
-                "create_time": {
-                    "description": "创建时间",
-                    "type": "string"
-                },
-                "id": {
+                "order_product_id": {
                     "description": "订单商品ID",
                     "type": "integer"
                 },
-                "price": {
-                    "description": "商品单价",
-                    "type": "integer"
-                },
                 "product_id": {
                     "description": "商品ID",
                     "type": "integer"
@@ -705,65 +3409,117 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "quantity": {
-                    "description": "商品数量",
+                    "description": "本包裹内的数量",
+                    "type": "integer"
+                }
+            }
+        },
+        "types.ShipmentItemRequest": {
+            "type": "object",
+            "properties": {
+                "order_product_id": {
+                    "description": "订单商品ID，即订单详情 order_items 中的 id",
                     "type": "integer"
                 },
-                "total_price": {
-                    "description": "商品总价",
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.StatusFunnelResponse": {
+            "type": "object",
+            "properties": {
+                "cancel_rate": {
+                    "description": "取消订单占下单数的比例",
+                    "type": "number"
+                },
+                "canceled": {
+                    "description": "取消的订单数",
                     "type": "integer"
                 },
-                "update_time": {
-                    "description": "更新时间",
+                "stages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.FunnelStage"
+                    }
+                },
+                "status_counts": {
+                    "description": "各状态的订单数",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer",
+                        "format": "int64"
+                    }
+                }
+            }
+        },
+        "types.TopProductsResponse": {
+            "type": "object",
+            "properties": {
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.ProductSales"
+                    }
+                },
+                "sort_by": {
                     "type": "string"
                 }
             }
         },
-        "types.OrderItemInfo": {
+        "types.TrackingEventDetail": {
             "type": "object",
             "properties": {
-                "price": {
-                    "type": "integer"
+                "description": {
+                    "description": "描述",
+                    "type": "string"
                 },
-                "product_id": {
-                    "type": "integer"
+                "event_time": {
+                    "description": "发生时间",
+                    "type": "string"
                 },
-                "product_name": {
+                "location": {
+                    "description": "地点",
                     "type": "string"
                 },
-                "quantity": {
-                    "type": "integer"
+                "status": {
+                    "description": "轨迹状态：in_transit, out_for_delivery, delivered, exception",
+                    "type": "string"
                 }
             }
         },
-        "types.OrderStatusLogDetail": {
+        "types.TrackingEventRequest": {
             "type": "object",
             "properties": {
-                "create_time": {
-                    "description": "变更时间",
+                "description": {
+                    "description": "描述",
                     "type": "string"
                 },
-                "current_status": {
-                    "description": "当前状态",
-                    "type": "integer"
-                },
-                "id": {
-                    "description": "日志ID",
-                    "type": "integer"
+                "location": {
+                    "description": "地点",
+                    "type": "string"
                 },
-                "remark": {
-                    "description": "备注",
+                "status": {
+                    "description": "轨迹状态：in_transit, out_for_delivery, delivered, exception",
                     "type": "string"
                 },
-                "status_name": {
-                    "description": "状态名称",
+                "time": {
+                    "description": "发生时间，RFC 3339",
                     "type": "string"
                 }
             }
         },
-        "types.ShipOrderRequest": {
+        "types.TrackingWebhookRequest": {
             "type": "object",
             "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.TrackingEventRequest"
+                    }
+                },
                 "tracking_no": {
+                    "description": "物流单号",
                     "type": "string"
                 }
             }
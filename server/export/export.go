@@ -0,0 +1,132 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strings"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/errs"
+	"github.com/xuri/excelize/v2"
+)
+
+// 导出格式
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+const xlsxSheet = "Orders"
+
+var ErrUnsupportedFormat = errs.New(errs.CodeUnsupportedFormat, "unsupported export format")
+
+// RowWriter writes a table row by row. Close flushes whatever is still
+// buffered; nothing may be written after it.
+type RowWriter interface {
+	WriteRow(row []string) error
+	Close() error
+}
+
+// IsFormat reports whether format is a supported export format.
+func IsFormat(format string) bool {
+	return format == FormatCSV || format == FormatXLSX
+}
+
+// ContentType returns the MIME type of format.
+func ContentType(format string) string {
+	if format == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// NewRowWriter returns a RowWriter that writes format to w.
+func NewRowWriter(format string, w io.Writer) (RowWriter, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w)
+	case FormatXLSX:
+		return newXLSXWriter(w)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+// escapeFormula prefixes a value that a spreadsheet would run as a formula,
+// such as a remark of "=HYPERLINK(...)", with a quote so it stays text.
+func escapeFormula(v string) string {
+	if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+		return "'" + v
+	}
+	return v
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func newCSVWriter(w io.Writer) (*csvWriter, error) {
+	// UTF-8 BOM，Excel 打开时才能正确识别中文
+	if _, err := w.Write([]byte("\xEF\xBB\xBF")); err != nil {
+		return nil, err
+	}
+	return &csvWriter{w: csv.NewWriter(w)}, nil
+}
+
+func (c *csvWriter) WriteRow(row []string) error {
+	// csv.Writer 自带缓冲，写满后才落到下层 writer
+	escaped := make([]string, len(row))
+	for i, v := range row {
+		escaped[i] = escapeFormula(v)
+	}
+	return c.w.Write(escaped)
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// xlsxWriter streams rows into a single sheet. excelize keeps the sheet in
+// memory only up to a threshold and spills the rest to a temporary file; the
+// workbook is written to w on Close.
+type xlsxWriter struct {
+	w      io.Writer
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	row    int
+}
+
+func newXLSXWriter(w io.Writer) (*xlsxWriter, error) {
+	file := excelize.NewFile()
+	if err := file.SetSheetName(file.GetSheetName(0), xlsxSheet); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	stream, err := file.NewStreamWriter(xlsxSheet)
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return &xlsxWriter{w: w, file: file, stream: stream}, nil
+}
+
+func (x *xlsxWriter) WriteRow(row []string) error {
+	x.row++
+	cell, err := excelize.CoordinatesToCellName(1, x.row)
+	if err != nil {
+		return err
+	}
+	values := make([]interface{}, len(row))
+	for i, v := range row {
+		values[i] = escapeFormula(v)
+	}
+	return x.stream.SetRow(cell, values)
+}
+
+func (x *xlsxWriter) Close() error {
+	defer x.file.Close()
+	if err := x.stream.Flush(); err != nil {
+		return err
+	}
+	return x.file.Write(x.w)
+}
@@ -0,0 +1,64 @@
+package export
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestCSVWriter(t *testing.T) {
+	var buf bytes.Buffer
+	rw, err := NewRowWriter(FormatCSV, &buf)
+	if err != nil {
+		t.Fatalf("NewRowWriter() error = %v", err)
+	}
+	_ = rw.WriteRow([]string{"order_no", "remark"})
+	_ = rw.WriteRow([]string{"ORD1", "fragile, handle with care"})
+	_ = rw.WriteRow([]string{"ORD2", "=HYPERLINK(\"http://evil\")"})
+	if err = rw.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// 以公式字符开头的值加单引号前缀，表格软件按文本显示
+	want := "\xEF\xBB\xBForder_no,remark\nORD1,\"fragile, handle with care\"\nORD2,\"'=HYPERLINK(\"\"http://evil\"\")\"\n"
+	if buf.String() != want {
+		t.Errorf("csv = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestXLSXWriter(t *testing.T) {
+	var buf bytes.Buffer
+	rw, err := NewRowWriter(FormatXLSX, &buf)
+	if err != nil {
+		t.Fatalf("NewRowWriter() error = %v", err)
+	}
+	_ = rw.WriteRow([]string{"order_no", "product_name"})
+	_ = rw.WriteRow([]string{"ORD1", "陶瓷杯"})
+	_ = rw.WriteRow([]string{"ORD2", "@SUM(A1:A2)"})
+	if err = rw.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	f, err := excelize.OpenReader(&buf)
+	if err != nil {
+		t.Fatalf("OpenReader() error = %v", err)
+	}
+	defer f.Close()
+	rows, err := f.GetRows(xlsxSheet)
+	if err != nil {
+		t.Fatalf("GetRows() error = %v", err)
+	}
+	want := [][]string{{"order_no", "product_name"}, {"ORD1", "陶瓷杯"}, {"ORD2", "'@SUM(A1:A2)"}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows = %v, want %v", rows, want)
+	}
+}
+
+func TestNewRowWriter_UnsupportedFormat(t *testing.T) {
+	if _, err := NewRowWriter("pdf", &bytes.Buffer{}); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("expected ErrUnsupportedFormat, got %v", err)
+	}
+}
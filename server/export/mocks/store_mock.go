@@ -0,0 +1,79 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./store.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	io "io"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockFileStore is a mock of FileStore interface.
+type MockFileStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockFileStoreMockRecorder
+}
+
+// MockFileStoreMockRecorder is the mock recorder for MockFileStore.
+type MockFileStoreMockRecorder struct {
+	mock *MockFileStore
+}
+
+// NewMockFileStore creates a new mock instance.
+func NewMockFileStore(ctrl *gomock.Controller) *MockFileStore {
+	mock := &MockFileStore{ctrl: ctrl}
+	mock.recorder = &MockFileStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFileStore) EXPECT() *MockFileStoreMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockFileStore) Create(name string) (io.WriteCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", name)
+	ret0, _ := ret[0].(io.WriteCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockFileStoreMockRecorder) Create(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockFileStore)(nil).Create), name)
+}
+
+// Open mocks base method.
+func (m *MockFileStore) Open(name string) (io.ReadSeekCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Open", name)
+	ret0, _ := ret[0].(io.ReadSeekCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Open indicates an expected call of Open.
+func (mr *MockFileStoreMockRecorder) Open(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Open", reflect.TypeOf((*MockFileStore)(nil).Open), name)
+}
+
+// Remove mocks base method.
+func (m *MockFileStore) Remove(name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Remove", name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Remove indicates an expected call of Remove.
+func (mr *MockFileStoreMockRecorder) Remove(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockFileStore)(nil).Remove), name)
+}
@@ -0,0 +1,60 @@
+package export
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// mockgen -source=./store.go -destination=./mocks/store_mock.go -package=mocks
+
+var ErrFileNotFound = errors.New("export file not found")
+
+// FileStore keeps the files produced by async export jobs until they are
+// downloaded or expire. LocalStore is built in; object storage can be plugged
+// in by implementing this interface.
+type FileStore interface {
+	Create(name string) (io.WriteCloser, error)
+	Open(name string) (io.ReadSeekCloser, error)
+	Remove(name string) error
+}
+
+// LocalStore stores export files in a directory. When the service runs with
+// more than one replica the directory must be on a shared volume, because the
+// download may be served by another replica than the one running the job.
+type LocalStore struct {
+	dir string
+}
+
+func NewLocalStore(dir string) (*LocalStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &LocalStore{dir: dir}, nil
+}
+
+func (s *LocalStore) Create(name string) (io.WriteCloser, error) {
+	return os.Create(s.path(name))
+}
+
+func (s *LocalStore) Open(name string) (io.ReadSeekCloser, error) {
+	f, err := os.Open(s.path(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrFileNotFound
+	}
+	return f, err
+}
+
+func (s *LocalStore) Remove(name string) error {
+	err := os.Remove(s.path(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// path keeps name inside the store directory.
+func (s *LocalStore) path(name string) string {
+	return filepath.Join(s.dir, filepath.Base(name))
+}
@@ -0,0 +1,67 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/common/demopb"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	authorizationKey = "authorization"
+	requestIDKey     = "x-request-id"
+)
+
+// methodRules 每个 gRPC 方法的访问规则，未列出的方法一律拒绝
+var methodRules = map[string]auth.Rule{
+	demopb.DemoService_SayHello_FullMethodName: auth.ServiceOnly,
+}
+
+// AuthInterceptor authenticates the bearer token in the "authorization"
+// metadata and checks it against the rule of the called method.
+func AuthInterceptor(authorizer *auth.Authorizer, rules map[string]auth.Rule) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		target := auth.Target{Channel: auth.ChannelGRPC, Resource: info.FullMethod}
+		if values := md.Get(requestIDKey); len(values) > 0 {
+			target.RequestID = values[0]
+		}
+		if p, ok := peer.FromContext(ctx); ok {
+			target.ClientIP = p.Addr.String()
+		}
+
+		var token string
+		if values := md.Get(authorizationKey); len(values) > 0 {
+			token = values[0]
+		}
+		principal, err := authorizer.Authenticate(token)
+		if err != nil {
+			return nil, toStatus(err)
+		}
+		rule, ok := rules[info.FullMethod]
+		if !ok {
+			return nil, toStatus(authorizer.Deny(ctx, principal, target, "no access rule for method"))
+		}
+		if err = authorizer.Authorize(ctx, principal, rule, target); err != nil {
+			return nil, toStatus(err)
+		}
+		return handler(ctx, req)
+	}
+}
+
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, auth.ErrUnauthenticated):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, auth.ErrAccessDenied):
+		return status.Error(codes.PermissionDenied, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
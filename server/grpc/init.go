@@ -1,38 +1,73 @@
 package grpc
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"os"
 	"time"
 
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/common/demopb"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/auth"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/config"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/log"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
 )
 
-func Init(exitSig chan os.Signal) {
-	ipPort := fmt.Sprintf("%s:%d", config.Config.GrpcConfig.Host, config.Config.GrpcConfig.Port)
-	listener, err := net.Listen("tcp", ipPort)
-	if err != nil {
-		log.Logger.Fatalf("Failed to listen: %v", err)
-		exitSig <- os.Interrupt
-		return
-	}
+type Server struct {
+	addr       string
+	grpcServer *grpc.Server
+}
+
+func NewServer(cfg *config.GrpcConfig, authorizer *auth.Authorizer) *Server {
 	// Set up gRPC options for timeout and connection pooling
 	opts := []grpc.ServerOption{
-		grpc.ConnectionTimeout(time.Duration(config.Config.GrpcConfig.ConnectTimeout) * time.Second), // Set a connection timeout
-		grpc.MaxConcurrentStreams(uint32(config.Config.GrpcConfig.MaxPoolSize)),                      // Set maximum concurrent streams
+		grpc.ConnectionTimeout(time.Duration(cfg.ConnectTimeout) * time.Second), // Set a connection timeout
+		grpc.MaxConcurrentStreams(uint32(cfg.MaxPoolSize)),                      // Set maximum concurrent streams
 		grpc.MaxRecvMsgSize(1024 * 1024), // Set maximum receive message size (1MB here)
 		grpc.MaxSendMsgSize(1024 * 1024), // Set maximum send message size (1MB here)
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.UnaryInterceptor(AuthInterceptor(authorizer, methodRules)),
 	}
 	grpcServer := grpc.NewServer(opts...)
 	demopb.RegisterDemoServiceServer(grpcServer, &DemoService{})
+	return &Server{
+		addr:       fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+		grpcServer: grpcServer,
+	}
+}
+
+// Start binds the listen address and serves in the background. A bind error
+// is returned; a serve error after startup is reported through exitSig.
+func (s *Server) Start(exitSig chan os.Signal) error {
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("listen on %s failed: %w", s.addr, err)
+	}
+	log.Logger.Infof("Server is running on %s", s.addr)
+	go func() {
+		if err := s.grpcServer.Serve(listener); err != nil {
+			log.Logger.Errorf("Failed to serve: %v", err)
+			exitSig <- os.Interrupt
+		}
+	}()
+	return nil
+}
 
-	log.Logger.Infof("Server is running on %s", ipPort)
-	if err := grpcServer.Serve(listener); err != nil {
-		log.Logger.Fatal("Failed to serve: %v", err)
-		exitSig <- os.Interrupt
+// Shutdown waits for in-flight RPCs to finish, and force-closes the
+// remaining connections once ctx expires.
+func (s *Server) Shutdown(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.grpcServer.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.grpcServer.Stop()
+		return ctx.Err()
 	}
 }
@@ -0,0 +1,136 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+
+	defaultCheckTimeout = 2 * time.Second
+)
+
+// CheckFunc reports whether a dependency is usable; it must honour ctx.
+type CheckFunc func(ctx context.Context) error
+
+type CheckResult struct {
+	Status    string `json:"status"`
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+type Report struct {
+	Ready  bool                    `json:"ready"`
+	Reason string                  `json:"reason,omitempty"`
+	Checks map[string]*CheckResult `json:"checks"`
+}
+
+type namedCheck struct {
+	name  string
+	check CheckFunc
+}
+
+var (
+	mu           sync.RWMutex
+	checks       []namedCheck
+	checkTimeout = defaultCheckTimeout
+
+	// ready is false while the service is starting and again once shutdown begins
+	ready        atomic.Bool
+	shuttingDown atomic.Bool
+)
+
+// Register adds a readiness check. Checks run concurrently, each under its own timeout.
+func Register(name string, check CheckFunc) {
+	mu.Lock()
+	defer mu.Unlock()
+	checks = append(checks, namedCheck{name: name, check: check})
+}
+
+// SetCheckTimeout sets the per-check timeout; non-positive values keep the default.
+func SetCheckTimeout(timeout time.Duration) {
+	if timeout <= 0 {
+		return
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	checkTimeout = timeout
+}
+
+// MarkReady is called once startup has finished.
+func MarkReady() {
+	ready.Store(true)
+}
+
+// MarkShuttingDown flips readiness off so the load balancer stops routing
+// new traffic while in-flight requests drain.
+func MarkShuttingDown() {
+	shuttingDown.Store(true)
+	ready.Store(false)
+}
+
+// Check runs every registered check and builds the readiness report.
+func Check(ctx context.Context) *Report {
+	mu.RLock()
+	registered := append([]namedCheck(nil), checks...)
+	timeout := checkTimeout
+	mu.RUnlock()
+
+	report := &Report{Ready: true, Checks: make(map[string]*CheckResult, len(registered))}
+	var (
+		wg       sync.WaitGroup
+		resultMu sync.Mutex
+	)
+	for _, c := range registered {
+		wg.Add(1)
+		go func(c namedCheck) {
+			defer wg.Done()
+			result := runCheck(ctx, c.check, timeout)
+			resultMu.Lock()
+			report.Checks[c.name] = result
+			resultMu.Unlock()
+		}(c)
+	}
+	wg.Wait()
+
+	for _, result := range report.Checks {
+		if result.Status != StatusUp {
+			report.Ready = false
+			report.Reason = "dependency check failed"
+		}
+	}
+	if shuttingDown.Load() {
+		report.Ready = false
+		report.Reason = "shutting down"
+	} else if !ready.Load() {
+		report.Ready = false
+		report.Reason = "starting"
+	}
+	return report
+}
+
+func runCheck(ctx context.Context, check CheckFunc, timeout time.Duration) *CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	errCh := make(chan error, 1)
+	go func() { errCh <- check(ctx) }()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	result := &CheckResult{Status: StatusUp, LatencyMs: time.Since(start).Milliseconds()}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
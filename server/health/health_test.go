@@ -0,0 +1,67 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func resetForTest() {
+	checks = nil
+	checkTimeout = defaultCheckTimeout
+	ready.Store(false)
+	shuttingDown.Store(false)
+}
+
+func TestCheck_NotReadyWhileStarting(t *testing.T) {
+	resetForTest()
+	Register("ok", func(ctx context.Context) error { return nil })
+
+	report := Check(context.Background())
+	if report.Ready || report.Reason != "starting" {
+		t.Errorf("Expected not ready while starting, got: %+v", report)
+	}
+
+	MarkReady()
+	report = Check(context.Background())
+	if !report.Ready {
+		t.Errorf("Expected ready, got: %+v", report)
+	}
+	if report.Checks["ok"].Status != StatusUp {
+		t.Errorf("Expected check up, got: %+v", report.Checks["ok"])
+	}
+}
+
+func TestCheck_FailedAndSlowChecks(t *testing.T) {
+	resetForTest()
+	SetCheckTimeout(20 * time.Millisecond)
+	MarkReady()
+	Register("mysql", func(ctx context.Context) error { return errors.New("connection refused") })
+	Register("kafka", func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	})
+
+	report := Check(context.Background())
+	if report.Ready {
+		t.Errorf("Expected not ready, got: %+v", report)
+	}
+	if report.Checks["mysql"].Error != "connection refused" {
+		t.Errorf("Expected mysql error, got: %+v", report.Checks["mysql"])
+	}
+	if report.Checks["kafka"].Status != StatusDown {
+		t.Errorf("Expected kafka check to time out, got: %+v", report.Checks["kafka"])
+	}
+}
+
+func TestCheck_NotReadyWhenShuttingDown(t *testing.T) {
+	resetForTest()
+	MarkReady()
+	MarkShuttingDown()
+
+	report := Check(context.Background())
+	if report.Ready || report.Reason != "shutting down" {
+		t.Errorf("Expected not ready during shutdown, got: %+v", report)
+	}
+}
@@ -1,33 +1,39 @@
 package api
 
 import (
+	"errors"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/errs"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/validate"
 	"github.com/gin-gonic/gin"
 )
 
+// Response.Status 取值：成功为 SUCCESS，失败为 errs.Code 错误码，前三位为 HTTP 状态码
 const (
-	SUCCESS = 0
-	ERROR   = 500
+	SUCCESS = int(errs.CodeOK)
+	ERROR   = int(errs.CodeInternal)
 )
 
-var MsgFlags = map[int]string{
-	SUCCESS: "ok",
+// GetMsg 获取状态码对应信息，按请求的 Accept-Language 返回中文或英文
+func GetMsg(ctx *gin.Context, code int) string {
+	return errs.Message(errs.Code(code), lang(ctx))
 }
 
-// GetMsg 获取状态码对应信息
-func GetMsg(code int) string {
-	msg, ok := MsgFlags[code]
-	if ok {
-		return msg
+func lang(ctx *gin.Context) string {
+	if ctx == nil {
+		return errs.LangEN
 	}
-	return MsgFlags[ERROR]
+	return errs.Lang(ctx.GetHeader("Accept-Language"))
 }
 
 // Response 基础序列化器
 type Response struct {
-	Status int         `json:"status"`
+	Status int         `json:"status"` // 0 表示成功，否则为错误码，见 errs.Code
 	Data   interface{} `json:"data"`
-	Msg    string      `json:"msg"`
-	Error  string      `json:"error"`
+	Msg    string      `json:"msg"`   // 面向用户的提示，按 Accept-Language 本地化
+	Error  string      `json:"error"` // 错误详情，用于排查
+
+	Fields []*errs.FieldError `json:"fields,omitempty"` // 参数校验失败的字段
 }
 
 // RespSuccess 带data成功返回
@@ -40,25 +46,58 @@ func RespSuccess(ctx *gin.Context, data interface{}, code ...int) *Response {
 	r := &Response{
 		Status: status,
 		Data:   data,
-		Msg:    GetMsg(status),
+		Msg:    GetMsg(ctx, status),
 	}
 
 	return r
 }
 
-// RespError 错误返回
+// RespError 错误返回，未指定 code 时使用 err 对应的错误码
 func RespError(ctx *gin.Context, err error, code ...int) *Response {
-	status := ERROR
+	status := int(errs.From(err).Code)
 	if code != nil {
 		status = code[0]
 	}
 
 	r := &Response{
 		Status: status,
-		Msg:    GetMsg(status),
+		Msg:    GetMsg(ctx, status),
 		Data:   nil,
 		Error:  err.Error(),
+		Fields: localizeFields(ctx, errs.From(err).Fields),
 	}
 
 	return r
 }
+
+// localizeFields 按请求语言返回字段错误的副本
+func localizeFields(ctx *gin.Context, fields []*errs.FieldError) []*errs.FieldError {
+	if len(fields) == 0 {
+		return nil
+	}
+	l := lang(ctx)
+	localized := make([]*errs.FieldError, 0, len(fields))
+	for _, f := range fields {
+		c := *f
+		c.Message = f.Localize(l)
+		localized = append(localized, &c)
+	}
+	return localized
+}
+
+// RespondError 按 err 的错误码写入对应的 HTTP 状态和错误返回
+func RespondError(ctx *gin.Context, err error) {
+	ctx.JSON(errs.From(err).Code.HTTPStatus(), RespError(ctx, err))
+}
+
+// badRequest 将未分类的错误（如请求绑定失败）归为参数错误，字段校验失败时带上各字段的错误
+func badRequest(err error) error {
+	var e *errs.Error
+	if errors.As(err, &e) {
+		return err
+	}
+	if fields, ok := validate.FieldErrors(err); ok {
+		return errs.ErrValidation.WithFields(fields)
+	}
+	return errs.ErrInvalidParam.Wrap(err)
+}
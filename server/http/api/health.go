@@ -0,0 +1,36 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/health"
+	"github.com/gin-gonic/gin"
+)
+
+// Healthz godoc
+// @Summary 存活探针
+// @Description 进程存活即返回200，不检查依赖
+// @Tags Health
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Router /healthz [get]
+func Healthz(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"status": health.StatusUp})
+}
+
+// Readyz godoc
+// @Summary 就绪探针
+// @Description 检查MySQL、Redis、Kafka及下游gRPC连接，启动中或关闭中返回503
+// @Tags Health
+// @Produce json
+// @Success 200 {object} health.Report
+// @Failure 503 {object} health.Report
+// @Router /readyz [get]
+func Readyz(ctx *gin.Context) {
+	report := health.Check(ctx.Request.Context())
+	if !report.Ready {
+		ctx.JSON(http.StatusServiceUnavailable, report)
+		return
+	}
+	ctx.JSON(http.StatusOK, report)
+}
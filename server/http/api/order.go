@@ -1,35 +1,52 @@
 package api
 
 import (
-	"errors"
 	"net/http"
 
-	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/consts"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/errs"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/types"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/utils"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/service"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 )
 
+// OrderHandler serves the order endpoints with the order service built by the app.
+type OrderHandler struct {
+	orderService *service.OrderServiceImpl
+}
+
+func NewOrderHandler(orderService *service.OrderServiceImpl) *OrderHandler {
+	return &OrderHandler{orderService: orderService}
+}
+
 // CreateOrder godoc
 // @Summary 创建订单
-// @Description 创建一个新订单
+// @Description 创建一个新订单。购物车中不同商家的商品拆分为不同子订单并一次支付，返回结算单号；只有一个商家时结算单号即订单号。
+// @Description 收货地址可使用地址簿中的地址（address_id）或结构化地址（shipping_address），下单时的地址作为快照保存在订单上
 // @Tags Order
 // @Accept json
 // @Produce json
 // @Param order body types.OrderInfo true "订单信息"
 // @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 401 {object} Response
+// @Failure 403 {object} Response
+// @Failure 404 {object} Response
+// @Failure 422 {object} Response
+// @Failure 502 {object} Response
 // @Failure 500 {object} Response
 // @Router /customer/orders [post]
-func CreateOrder(ctx *gin.Context) {
+func (h *OrderHandler) CreateOrder(ctx *gin.Context) {
 	var req types.OrderInfo
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, RespError(ctx, err))
+		RespondError(ctx, badRequest(err))
 		return
 	}
 	userId := ctx.Value("userID").(int)
-	orderNo, err := service.GetOrderServiceInstance().CreateOrder(ctx, req, userId)
+	orderNo, err := h.orderService.CreateOrder(ctx, req, userId)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, RespError(ctx, err))
+		RespondError(ctx, err)
 		return
 	}
 
@@ -38,19 +55,21 @@ func CreateOrder(ctx *gin.Context) {
 
 // ListOrders godoc
 // @Summary 查询订单列表
-// @Description 根据条件查询订单列表，支持分页
+// @Description 根据条件查询订单列表：多状态、金额范围、收货人、商品、物流单号、支付/发货时间筛选，任意字段排序，支持偏移分页和游标分页（cursor），返回符合条件的总数
 // @Tags Order
 // @Accept json
 // @Produce json
 // @Param request body types.ListOrderRequest true "查询条件"
 // @Success 200 {object} Response{data=types.ListOrderResponse}
 // @Failure 400 {object} Response
+// @Failure 401 {object} Response
+// @Failure 403 {object} Response
 // @Failure 500 {object} Response
 // @Router /merchant/orders/list [post]
-func ListOrders(ctx *gin.Context) {
+func (h *OrderHandler) ListOrders(ctx *gin.Context) {
 	var req types.ListOrderRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, RespError(ctx, err))
+		RespondError(ctx, badRequest(err))
 		return
 	}
 
@@ -61,10 +80,11 @@ func ListOrders(ctx *gin.Context) {
 	if req.Limit > 100 {
 		req.Limit = 100 // 最大每页100条
 	}
+	req.MerchantID = ctx.Value("merchantID").(int)
 
-	resp, err := service.GetOrderServiceInstance().ListOrders(ctx, req)
+	resp, err := h.orderService.ListOrders(ctx, req)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, RespError(ctx, err))
+		RespondError(ctx, err)
 		return
 	}
 
@@ -80,19 +100,22 @@ func ListOrders(ctx *gin.Context) {
 // @Param order_no path string true "订单号"
 // @Success 200 {object} Response{data=types.OrderDetail}
 // @Failure 400 {object} Response
+// @Failure 401 {object} Response
+// @Failure 403 {object} Response
 // @Failure 404 {object} Response
 // @Failure 500 {object} Response
 // @Router /merchant/orders/{order_no} [get]
-func GetOrderDetail(ctx *gin.Context) {
+func (h *OrderHandler) GetOrderDetail(ctx *gin.Context) {
 	orderNo := ctx.Param("order_no")
 	if orderNo == "" {
-		ctx.JSON(http.StatusBadRequest, RespError(ctx, errors.New("订单号不能为空")))
+		RespondError(ctx, errs.ErrInvalidParam.Detailf("订单号不能为空"))
 		return
 	}
 
-	detail, err := service.GetOrderServiceInstance().GetOrderDetail(ctx, orderNo)
+	merchantID := ctx.Value("merchantID").(int)
+	detail, err := h.orderService.MerchantGetOrderDetail(ctx, orderNo, merchantID)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, RespError(ctx, err))
+		RespondError(ctx, err)
 		return
 	}
 
@@ -101,19 +124,21 @@ func GetOrderDetail(ctx *gin.Context) {
 
 // CustomerListOrders godoc
 // @Summary 用户侧查询订单列表
-// @Description 根据userID查询订单列表，支持分页，支持根据时间筛选
+// @Description 根据userID查询订单列表，支持偏移分页和游标分页（cursor），支持按时间、状态和商品名称筛选。每个订单返回商品件数和缩略图
 // @Tags Order
 // @Accept json
 // @Produce json
 // @Param request body types.CustomerListOrderRequest true "查询条件"
 // @Success 200 {object} Response{data=types.ListOrderResponse}
 // @Failure 400 {object} Response
+// @Failure 401 {object} Response
+// @Failure 403 {object} Response
 // @Failure 500 {object} Response
 // @Router /customer/orders/list [post]
-func CustomerListOrders(ctx *gin.Context) {
+func (h *OrderHandler) CustomerListOrders(ctx *gin.Context) {
 	var req types.CustomerListOrderRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, RespError(ctx, err))
+		RespondError(ctx, badRequest(err))
 		return
 	}
 
@@ -126,15 +151,9 @@ func CustomerListOrders(ctx *gin.Context) {
 	}
 
 	userID := ctx.Value("userID").(int)
-	resp, err := service.GetOrderServiceInstance().ListOrders(ctx, types.ListOrderRequest{
-		UserID:    userID,
-		StartTime: req.StartTime,
-		EndTime:   req.EndTime,
-		Limit:     req.Limit,
-		Offset:    req.Offset,
-	})
+	resp, err := h.orderService.CustomerListOrders(ctx, userID, req)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, RespError(ctx, err))
+		RespondError(ctx, err)
 		return
 	}
 
@@ -150,63 +169,309 @@ func CustomerListOrders(ctx *gin.Context) {
 // @Param order_no path string true "订单号"
 // @Success 200 {object} Response{data=types.OrderDetail}
 // @Failure 400 {object} Response
+// @Failure 401 {object} Response
+// @Failure 403 {object} Response
 // @Failure 404 {object} Response
 // @Failure 500 {object} Response
 // @Router /customer/orders/{order_no} [get]
-func CustomerGetOrderDetail(ctx *gin.Context) {
+func (h *OrderHandler) CustomerGetOrderDetail(ctx *gin.Context) {
 	orderNo := ctx.Param("order_no")
 	if orderNo == "" {
-		ctx.JSON(http.StatusBadRequest, RespError(ctx, errors.New("订单号不能为空")))
+		RespondError(ctx, errs.ErrInvalidParam.Detailf("订单号不能为空"))
 		return
 	}
 
 	userID := ctx.Value("userID").(int)
-	detail, err := service.GetOrderServiceInstance().CustomerGetOrderDetail(ctx, orderNo, userID)
+	detail, err := h.orderService.CustomerGetOrderDetail(ctx, orderNo, userID)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, RespError(ctx, err))
+		RespondError(ctx, err)
 		return
 	}
 
 	ctx.JSON(http.StatusOK, RespSuccess(ctx, detail))
 }
 
+// CustomerGetCheckout godoc
+// @Summary 用户侧查询结算单
+// @Description 根据结算单号查询一次下单拆分出的全部子订单详情
+// @Tags Order
+// @Accept json
+// @Produce json
+// @Param checkout_no path string true "结算单号"
+// @Success 200 {object} Response{data=types.CheckoutDetail}
+// @Failure 400 {object} Response
+// @Failure 401 {object} Response
+// @Failure 403 {object} Response
+// @Failure 404 {object} Response
+// @Failure 500 {object} Response
+// @Router /customer/checkouts/{checkout_no} [get]
+func (h *OrderHandler) CustomerGetCheckout(ctx *gin.Context) {
+	checkoutNo := ctx.Param("checkout_no")
+	if checkoutNo == "" {
+		RespondError(ctx, errs.ErrInvalidParam.Detailf("结算单号不能为空"))
+		return
+	}
+
+	userID := ctx.Value("userID").(int)
+	detail, err := h.orderService.CustomerGetCheckout(ctx, checkoutNo, userID)
+	if err != nil {
+		RespondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, RespSuccess(ctx, detail))
+}
+
+// CustomerReorder godoc
+// @Summary 再次购买
+// @Description 按当前价格和库存重新生成订单的购物车，下架或无库存的商品标记为不可购买，库存不足时按库存数量。返回的 order_item_list 可直接用于创建订单
+// @Tags Order
+// @Accept json
+// @Produce json
+// @Param order_no path string true "订单号"
+// @Success 200 {object} Response{data=types.ReorderQuote}
+// @Failure 400 {object} Response
+// @Failure 401 {object} Response
+// @Failure 403 {object} Response
+// @Failure 404 {object} Response
+// @Failure 500 {object} Response
+// @Router /customer/orders/{order_no}/reorder [post]
+func (h *OrderHandler) CustomerReorder(ctx *gin.Context) {
+	orderNo := ctx.Param("order_no")
+	if orderNo == "" {
+		RespondError(ctx, errs.ErrInvalidParam.Detailf("订单号不能为空"))
+		return
+	}
+
+	userID := ctx.Value("userID").(int)
+	quote, err := h.orderService.CustomerReorder(ctx, orderNo, userID)
+	if err != nil {
+		RespondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, RespSuccess(ctx, quote))
+}
+
 // ShipOrder godoc
 // @Summary 商家发货
-// @Description 商家标记订单为已发货状态，并添加物流单号
+// @Description 商家发出订单的全部未发货商品，订单变为已发货。承运商和物流单号必填，物流回调按二者匹配包裹
 // @Tags Order
 // @Accept json
 // @Produce json
 // @Param request body types.ShipOrderRequest true "发货信息"
 // @Success 200 {object} Response
 // @Failure 400 {object} Response
+// @Failure 401 {object} Response
+// @Failure 403 {object} Response
+// @Failure 404 {object} Response
+// @Failure 409 {object} Response
 // @Failure 500 {object} Response
 // @Router /merchant/orders/{order_no}/ship [patch]
-func ShipOrder(ctx *gin.Context) {
+func (h *OrderHandler) ShipOrder(ctx *gin.Context) {
 	orderNo := ctx.Param("order_no")
 	if orderNo == "" {
-		ctx.JSON(http.StatusBadRequest, RespError(ctx, errors.New("订单号不能为空")))
+		RespondError(ctx, errs.ErrInvalidParam.Detailf("订单号不能为空"))
 		return
 	}
 
 	var req types.ShipOrderRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, RespError(ctx, err))
+		RespondError(ctx, badRequest(err))
 		return
 	}
 
-	if req.TrackingNo == "" {
-		ctx.JSON(http.StatusBadRequest, RespError(ctx, errors.New("物流单号不能为空")))
+	// 调用 service 层更新订单状态为已发货
+	merchantID := ctx.Value("merchantID").(int)
+	err := h.orderService.MerchantShipOrder(ctx, orderNo, merchantID, req.CarrierCode, req.TrackingNo)
+	if err != nil {
+		RespondError(ctx, err)
 		return
 	}
 
-	// 调用 service 层更新订单状态为已发货
-	err := service.GetOrderServiceInstance().UpdateOrderStatus(ctx, orderNo, consts.SHIPPED, req.TrackingNo) // 3 表示 SHIPPED
+	ctx.JSON(http.StatusOK, RespSuccess(ctx, "订单发货成功"))
+}
+
+// BatchShipOrders godoc
+// @Summary 商家批量发货
+// @Description 按快递清单批量发货。JSON 传 items；或以 multipart/form-data 上传 CSV 文件（字段 file，表头包含 order_no、tracking_no，可选 carrier），也可直接以 text/csv 作为请求体；CSV 最多 500 行、不超过 1MB。逐行按订单状态校验，返回逐行结果；all_or_nothing 为 true 时任意一行失败则全部不发货
+// @Tags Order
+// @Accept json
+// @Accept mpfd
+// @Accept plain
+// @Produce json
+// @Param request body types.BatchShipRequest false "发货清单（JSON）"
+// @Param file formData file false "发货清单（CSV）"
+// @Param all_or_nothing query bool false "CSV 上传时使用：任意一行失败则全部不发货"
+// @Success 200 {object} Response{data=types.BatchShipResponse}
+// @Failure 400 {object} Response
+// @Failure 401 {object} Response
+// @Failure 403 {object} Response
+// @Failure 500 {object} Response
+// @Router /merchant/orders/ship/batch [post]
+func (h *OrderHandler) BatchShipOrders(ctx *gin.Context) {
+	var req types.BatchShipRequest
+	switch ctx.ContentType() {
+	case gin.MIMEMultipartPOSTForm, "text/csv":
+		items, err := parseShipmentUpload(ctx)
+		if err != nil {
+			RespondError(ctx, badRequest(err))
+			return
+		}
+		req.Items = items
+		req.AllOrNothing = ctx.Query("all_or_nothing") == "true" || ctx.PostForm("all_or_nothing") == "true"
+		// CSV 清单不经过 ShouldBind，按同样的规则校验
+		if err = binding.Validator.ValidateStruct(&req); err != nil {
+			RespondError(ctx, badRequest(err))
+			return
+		}
+	default:
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			RespondError(ctx, badRequest(err))
+			return
+		}
+	}
+
+	req.MerchantID = ctx.Value("merchantID").(int)
+	resp, err := h.orderService.BatchShipOrders(ctx, req)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, RespError(ctx, err))
+		RespondError(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, RespSuccess(ctx, "订单发货成功"))
+	ctx.JSON(http.StatusOK, RespSuccess(ctx, resp))
+}
+
+// maxShipmentUploadBytes caps the request body of a CSV manifest upload,
+// ample for service.MaxBatchShipItems rows.
+const maxShipmentUploadBytes = 1 << 20
+
+// parseShipmentUpload reads the CSV manifest from the "file" form field or,
+// for text/csv requests, from the body. The body is capped at
+// maxShipmentUploadBytes and reading stops after service.MaxBatchShipItems rows.
+func parseShipmentUpload(ctx *gin.Context) ([]*types.BatchShipItem, error) {
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxShipmentUploadBytes)
+	if ctx.ContentType() == "text/csv" {
+		return utils.ParseShipmentCSV(ctx.Request.Body, service.MaxBatchShipItems)
+	}
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		return nil, err
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return utils.ParseShipmentCSV(file, service.MaxBatchShipItems)
+}
+
+// CreateShipment godoc
+// @Summary 商家创建包裹
+// @Description 为订单创建一个包裹（承运商、物流单号、重量、面单地址及包裹内商品和数量），支持一单多包裹。items 为空时发出全部未发货商品；全部商品发出后订单变为已发货，否则为部分发货
+// @Tags Order
+// @Accept json
+// @Produce json
+// @Param order_no path string true "订单号"
+// @Param request body types.CreateShipmentRequest true "包裹信息"
+// @Success 200 {object} Response{data=types.ShipmentDetail}
+// @Failure 400 {object} Response
+// @Failure 401 {object} Response
+// @Failure 403 {object} Response
+// @Failure 404 {object} Response
+// @Failure 409 {object} Response
+// @Failure 500 {object} Response
+// @Router /merchant/orders/{order_no}/shipments [post]
+func (h *OrderHandler) CreateShipment(ctx *gin.Context) {
+	orderNo := ctx.Param("order_no")
+	if orderNo == "" {
+		RespondError(ctx, errs.ErrInvalidParam.Detailf("订单号不能为空"))
+		return
+	}
+
+	var req types.CreateShipmentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		RespondError(ctx, badRequest(err))
+		return
+	}
+
+	req.MerchantID = ctx.Value("merchantID").(int)
+	detail, err := h.orderService.CreateShipment(ctx, orderNo, req)
+	if err != nil {
+		RespondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, RespSuccess(ctx, detail))
+}
+
+// EditOrder godoc
+// @Summary 商家修改订单
+// @Description 发货前（已创建、已付款）修改收货人、电话、收货地址、备注或删除商品，未传的字段不修改。删除商品后重新计算运费和税，运费不高于原运费；已付款订单退还差额。每次修改记录字段修改前后的值
+// @Tags Order
+// @Accept json
+// @Produce json
+// @Param order_no path string true "订单号"
+// @Param request body types.EditOrderRequest true "修改内容"
+// @Success 200 {object} Response{data=types.EditOrderResponse}
+// @Failure 400 {object} Response
+// @Failure 401 {object} Response
+// @Failure 403 {object} Response
+// @Failure 404 {object} Response
+// @Failure 409 {object} Response
+// @Failure 500 {object} Response
+// @Router /merchant/orders/{order_no} [patch]
+func (h *OrderHandler) EditOrder(ctx *gin.Context) {
+	orderNo := ctx.Param("order_no")
+	if orderNo == "" {
+		RespondError(ctx, errs.ErrInvalidParam.Detailf("订单号不能为空"))
+		return
+	}
+
+	var req types.EditOrderRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		RespondError(ctx, badRequest(err))
+		return
+	}
+
+	merchantID := ctx.Value("merchantID").(int)
+	operatorID := ctx.Value("userID").(int)
+	resp, err := h.orderService.MerchantEditOrder(ctx, orderNo, merchantID, operatorID, req)
+	if err != nil {
+		RespondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, RespSuccess(ctx, resp))
+}
+
+// GetOrderAudits godoc
+// @Summary 商家查询订单修改记录
+// @Description 按修改时间顺序返回订单的修改记录，包括操作人、原因、退款金额及各字段修改前后的值
+// @Tags Order
+// @Produce json
+// @Param order_no path string true "订单号"
+// @Success 200 {object} Response{data=[]types.OrderAuditEntry}
+// @Failure 400 {object} Response
+// @Failure 401 {object} Response
+// @Failure 403 {object} Response
+// @Failure 404 {object} Response
+// @Failure 500 {object} Response
+// @Router /merchant/orders/{order_no}/audits [get]
+func (h *OrderHandler) GetOrderAudits(ctx *gin.Context) {
+	orderNo := ctx.Param("order_no")
+	if orderNo == "" {
+		RespondError(ctx, errs.ErrInvalidParam.Detailf("订单号不能为空"))
+		return
+	}
+
+	entries, err := h.orderService.MerchantGetOrderAudits(ctx, orderNo, ctx.Value("merchantID").(int))
+	if err != nil {
+		RespondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, RespSuccess(ctx, entries))
 }
 
 // ConfirmOrder godoc
@@ -218,19 +483,24 @@ func ShipOrder(ctx *gin.Context) {
 // @Param request body types.ConfirmOrderRequest true "确认收货信息"
 // @Success 200 {object} Response
 // @Failure 400 {object} Response
+// @Failure 401 {object} Response
+// @Failure 403 {object} Response
+// @Failure 404 {object} Response
+// @Failure 409 {object} Response
 // @Failure 500 {object} Response
 // @Router /customer/orders/{order_no}/confirm [patch]
-func ConfirmOrder(ctx *gin.Context) {
+func (h *OrderHandler) ConfirmOrder(ctx *gin.Context) {
 	orderNo := ctx.Param("order_no")
 	if orderNo == "" {
-		ctx.JSON(http.StatusBadRequest, RespError(ctx, errors.New("订单号不能为空")))
+		RespondError(ctx, errs.ErrInvalidParam.Detailf("订单号不能为空"))
 		return
 	}
 
-	// 调用 service 层更新订单状态为已收货
-	err := service.GetOrderServiceInstance().UpdateOrderStatus(ctx, orderNo, consts.DELIVERED, "")
+	// 只能确认自己的订单
+	userID := ctx.Value("userID").(int)
+	err := h.orderService.CustomerConfirmOrder(ctx, orderNo, userID)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, RespError(ctx, err))
+		RespondError(ctx, err)
 		return
 	}
 
@@ -239,17 +509,20 @@ func ConfirmOrder(ctx *gin.Context) {
 
 // GetOrderStats godoc
 // @Summary get Order Stats
-// @Description get Order Stats
+// @Description get Order Stats, merchants only see the stats of their own orders
 // @Tags Order
 // @Accept json
 // @Produce json
 // @Success 200 {object} Response
+// @Failure 401 {object} Response
+// @Failure 403 {object} Response
 // @Failure 500 {object} Response
 // @Router /merchant/order-stats [get]
-func GetOrderStats(ctx *gin.Context) {
-	stats, err := service.GetOrderServiceInstance().GetOrderStats(ctx)
+func (h *OrderHandler) GetOrderStats(ctx *gin.Context) {
+	merchantID := ctx.Value("merchantID").(int)
+	stats, err := h.orderService.GetOrderStats(ctx, merchantID)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, RespError(ctx, err))
+		RespondError(ctx, err)
 		return
 	}
 	ctx.JSON(http.StatusOK, RespSuccess(ctx, stats))
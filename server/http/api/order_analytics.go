@@ -0,0 +1,177 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/types"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/service"
+	"github.com/gin-gonic/gin"
+)
+
+// OrderAnalyticsHandler serves the merchant analytics endpoints. Every
+// endpoint takes a start_date/end_date range interpreted in the tz time zone.
+type OrderAnalyticsHandler struct {
+	analyticsService service.OrderAnalyticsService
+}
+
+func NewOrderAnalyticsHandler(analyticsService service.OrderAnalyticsService) *OrderAnalyticsHandler {
+	return &OrderAnalyticsHandler{analyticsService: analyticsService}
+}
+
+// GetSalesSeries godoc
+// @Summary 销售趋势
+// @Description 按日、周（周一开始）或月统计已支付订单数和销售额，周期按 tz 时区划分，没有订单的周期返回 0。范围最长 366 天
+// @Tags Analytics
+// @Produce json
+// @Param start_date query string true "起始日期（含），格式 2006-01-02"
+// @Param end_date query string true "结束日期（含），格式 2006-01-02"
+// @Param tz query string false "IANA 时区，如 Asia/Singapore，默认服务器时区"
+// @Param interval query string false "统计周期：day（默认）/ week / month"
+// @Success 200 {object} Response{data=types.SalesSeriesResponse}
+// @Failure 400 {object} Response
+// @Failure 401 {object} Response
+// @Failure 403 {object} Response
+// @Failure 500 {object} Response
+// @Router /merchant/analytics/sales [get]
+func (h *OrderAnalyticsHandler) GetSalesSeries(ctx *gin.Context) {
+	var req types.SalesSeriesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		RespondError(ctx, badRequest(err))
+		return
+	}
+	req.MerchantID = ctx.Value("merchantID").(int)
+
+	resp, err := h.analyticsService.GetSalesSeries(ctx, req)
+	if err != nil {
+		RespondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, RespSuccess(ctx, resp))
+}
+
+// GetTopProducts godoc
+// @Summary 热销商品
+// @Description 按销售额或销量返回已支付订单中排名靠前的商品
+// @Tags Analytics
+// @Produce json
+// @Param start_date query string true "起始日期（含），格式 2006-01-02"
+// @Param end_date query string true "结束日期（含），格式 2006-01-02"
+// @Param tz query string false "IANA 时区，如 Asia/Singapore，默认服务器时区"
+// @Param sort_by query string false "排序：revenue（默认）/ quantity"
+// @Param limit query int false "返回数量，默认10，最大100"
+// @Success 200 {object} Response{data=types.TopProductsResponse}
+// @Failure 400 {object} Response
+// @Failure 401 {object} Response
+// @Failure 403 {object} Response
+// @Failure 500 {object} Response
+// @Router /merchant/analytics/top-products [get]
+func (h *OrderAnalyticsHandler) GetTopProducts(ctx *gin.Context) {
+	var req types.TopProductsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		RespondError(ctx, badRequest(err))
+		return
+	}
+	req.MerchantID = ctx.Value("merchantID").(int)
+
+	resp, err := h.analyticsService.GetTopProducts(ctx, req)
+	if err != nil {
+		RespondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, RespSuccess(ctx, resp))
+}
+
+// GetStatusFunnel godoc
+// @Summary 订单转化漏斗
+// @Description 统计范围内下单的订单到达下单、支付、发货、收货各阶段的数量和比例，以及取消率
+// @Tags Analytics
+// @Produce json
+// @Param start_date query string true "起始日期（含），格式 2006-01-02"
+// @Param end_date query string true "结束日期（含），格式 2006-01-02"
+// @Param tz query string false "IANA 时区，如 Asia/Singapore，默认服务器时区"
+// @Success 200 {object} Response{data=types.StatusFunnelResponse}
+// @Failure 400 {object} Response
+// @Failure 401 {object} Response
+// @Failure 403 {object} Response
+// @Failure 500 {object} Response
+// @Router /merchant/analytics/funnel [get]
+func (h *OrderAnalyticsHandler) GetStatusFunnel(ctx *gin.Context) {
+	var req types.AnalyticsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		RespondError(ctx, badRequest(err))
+		return
+	}
+	req.MerchantID = ctx.Value("merchantID").(int)
+
+	resp, err := h.analyticsService.GetStatusFunnel(ctx, req)
+	if err != nil {
+		RespondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, RespSuccess(ctx, resp))
+}
+
+// GetSummary godoc
+// @Summary 复购率与发货时效
+// @Description 统计范围内的复购率和支付到首次发货的平均时长
+// @Tags Analytics
+// @Produce json
+// @Param start_date query string true "起始日期（含），格式 2006-01-02"
+// @Param end_date query string true "结束日期（含），格式 2006-01-02"
+// @Param tz query string false "IANA 时区，如 Asia/Singapore，默认服务器时区"
+// @Success 200 {object} Response{data=types.AnalyticsSummary}
+// @Failure 400 {object} Response
+// @Failure 401 {object} Response
+// @Failure 403 {object} Response
+// @Failure 500 {object} Response
+// @Router /merchant/analytics/summary [get]
+func (h *OrderAnalyticsHandler) GetSummary(ctx *gin.Context) {
+	var req types.AnalyticsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		RespondError(ctx, badRequest(err))
+		return
+	}
+	req.MerchantID = ctx.Value("merchantID").(int)
+
+	resp, err := h.analyticsService.GetSummary(ctx, req)
+	if err != nil {
+		RespondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, RespSuccess(ctx, resp))
+}
+
+// GetCountryBreakdown godoc
+// @Summary 国家分布
+// @Description 按收货国家统计已支付订单数、销售额、用户数和销售额占比
+// @Tags Analytics
+// @Produce json
+// @Param start_date query string true "起始日期（含），格式 2006-01-02"
+// @Param end_date query string true "结束日期（含），格式 2006-01-02"
+// @Param tz query string false "IANA 时区，如 Asia/Singapore，默认服务器时区"
+// @Success 200 {object} Response{data=types.CountryBreakdownResponse}
+// @Failure 400 {object} Response
+// @Failure 401 {object} Response
+// @Failure 403 {object} Response
+// @Failure 500 {object} Response
+// @Router /merchant/analytics/countries [get]
+func (h *OrderAnalyticsHandler) GetCountryBreakdown(ctx *gin.Context) {
+	var req types.AnalyticsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		RespondError(ctx, badRequest(err))
+		return
+	}
+	req.MerchantID = ctx.Value("merchantID").(int)
+
+	resp, err := h.analyticsService.GetCountryBreakdown(ctx, req)
+	if err != nil {
+		RespondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, RespSuccess(ctx, resp))
+}
@@ -0,0 +1,176 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/export"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/consts"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/types"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/service"
+	"github.com/gin-gonic/gin"
+)
+
+// exportDownloadURL is the download endpoint of an async export job, see router.
+const exportDownloadURL = "/order-ms/v1/merchant/orders/export/jobs/%s/download"
+
+// OrderExportHandler serves merchant order exports.
+type OrderExportHandler struct {
+	exportService service.OrderExportService
+}
+
+func NewOrderExportHandler(exportService service.OrderExportService) *OrderExportHandler {
+	return &OrderExportHandler{exportService: exportService}
+}
+
+// ExportOrders godoc
+// @Summary 商家导出订单
+// @Description 按与订单列表相同的筛选条件导出订单及商品明细，每个商品一行，格式为 CSV 或 XLSX。默认同步流式下载；async=true 时创建异步任务，返回任务状态，完成后通过 download_url 下载
+// @Tags Order
+// @Accept json
+// @Produce octet-stream
+// @Produce json
+// @Param request body types.ExportOrderRequest true "导出条件"
+// @Success 200 {file} file "导出文件"
+// @Success 202 {object} Response{data=types.ExportJobInfo}
+// @Failure 400 {object} Response
+// @Failure 401 {object} Response
+// @Failure 403 {object} Response
+// @Failure 500 {object} Response
+// @Router /merchant/orders/export [post]
+func (h *OrderExportHandler) ExportOrders(ctx *gin.Context) {
+	var req types.ExportOrderRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		RespondError(ctx, badRequest(err))
+		return
+	}
+	if req.Format == "" {
+		req.Format = export.FormatCSV
+	}
+	req.MerchantID = ctx.Value("merchantID").(int)
+
+	if req.Async {
+		userID := ctx.Value("userID").(int)
+		job, err := h.exportService.CreateExportJob(ctx, req, userID)
+		if err != nil {
+			RespondError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusAccepted, RespSuccess(ctx, withDownloadURL(job)))
+		return
+	}
+
+	w := &attachmentWriter{
+		ctx:         ctx,
+		fileName:    fmt.Sprintf("orders-%s.%s", time.Now().Format("20060102150405"), req.Format),
+		contentType: export.ContentType(req.Format),
+	}
+	_, err := h.exportService.ExportOrders(ctx, req, w)
+	if err == nil {
+		return
+	}
+	if !w.started {
+		RespondError(ctx, err)
+		return
+	}
+	// 文件已开始下发，只能中断连接，让客户端下载失败而不是得到看似完整的残缺文件
+	log.FromContext(ctx).Errorf("ExportOrders: export aborted, err: %s", err.Error())
+	w.abort()
+}
+
+// GetExportJob godoc
+// @Summary 查询导出任务
+// @Description 查询异步导出任务状态，完成后返回下载链接
+// @Tags Order
+// @Produce json
+// @Param job_id path string true "任务编号"
+// @Success 200 {object} Response{data=types.ExportJobInfo}
+// @Failure 401 {object} Response
+// @Failure 403 {object} Response
+// @Failure 404 {object} Response
+// @Failure 500 {object} Response
+// @Router /merchant/orders/export/jobs/{job_id} [get]
+func (h *OrderExportHandler) GetExportJob(ctx *gin.Context) {
+	userID := ctx.Value("userID").(int)
+	job, err := h.exportService.GetExportJob(ctx, ctx.Param("job_id"), userID)
+	if err != nil {
+		RespondError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, RespSuccess(ctx, withDownloadURL(job)))
+}
+
+// DownloadExport godoc
+// @Summary 下载导出文件
+// @Description 下载已完成的异步导出任务文件，支持 Range 断点续传
+// @Tags Order
+// @Produce octet-stream
+// @Param job_id path string true "任务编号"
+// @Success 200 {file} file "导出文件"
+// @Failure 401 {object} Response
+// @Failure 403 {object} Response
+// @Failure 404 {object} Response
+// @Failure 409 {object} Response
+// @Failure 410 {object} Response
+// @Failure 500 {object} Response
+// @Router /merchant/orders/export/jobs/{job_id}/download [get]
+func (h *OrderExportHandler) DownloadExport(ctx *gin.Context) {
+	userID := ctx.Value("userID").(int)
+	file, job, err := h.exportService.OpenExportFile(ctx, ctx.Param("job_id"), userID)
+	if err != nil {
+		RespondError(ctx, err)
+		return
+	}
+	defer file.Close()
+
+	fileName := fmt.Sprintf("orders-%s.%s", job.FinishTime.Format("20060102150405"), job.Format)
+	ctx.Header("Content-Type", export.ContentType(job.Format))
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, fileName))
+	http.ServeContent(ctx.Writer, ctx.Request, fileName, job.FinishTime, file)
+}
+
+func withDownloadURL(job *types.ExportJobInfo) *types.ExportJobInfo {
+	if job.Status == consts.ExportJobSucceeded {
+		job.DownloadURL = fmt.Sprintf(exportDownloadURL, job.JobID)
+	}
+	return job
+}
+
+// attachmentWriter sets the download headers on the first write, so an error
+// returned before any row is written can still be answered with JSON.
+type attachmentWriter struct {
+	ctx         *gin.Context
+	fileName    string
+	contentType string
+	started     bool
+}
+
+func (w *attachmentWriter) Write(p []byte) (int, error) {
+	if !w.started {
+		w.started = true
+		w.ctx.Header("Content-Type", w.contentType)
+		w.ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, w.fileName))
+		w.ctx.Status(http.StatusOK)
+	}
+	return w.ctx.Writer.Write(p)
+}
+
+// abort closes the connection without ending the response body, so the client
+// sees a failed download. gin's Recovery would swallow http.ErrAbortHandler and
+// end the response normally, and gin refuses to hijack a written response, so
+// the underlying connection is hijacked and closed instead.
+func (w *attachmentWriter) abort() {
+	w.ctx.Abort()
+	rw := http.ResponseWriter(w.ctx.Writer)
+	if unwrapper, ok := rw.(interface{ Unwrap() http.ResponseWriter }); ok {
+		rw = unwrapper.Unwrap()
+	}
+	conn, _, err := http.NewResponseController(rw).Hijack()
+	if err != nil {
+		log.FromContext(w.ctx).Errorf("ExportOrders: hijack connection failed, err: %s", err.Error())
+		return
+	}
+	_ = conn.Close()
+}
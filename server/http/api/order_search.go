@@ -0,0 +1,60 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/types"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/service"
+	"github.com/gin-gonic/gin"
+)
+
+// OrderSearchHandler serves full-text order search.
+type OrderSearchHandler struct {
+	searchService service.OrderSearchService
+}
+
+func NewOrderSearchHandler(searchService service.OrderSearchService) *OrderSearchHandler {
+	return &OrderSearchHandler{searchService: searchService}
+}
+
+// SearchOrders godoc
+// @Summary 商家全文检索订单
+// @Description 按订单号、收货人姓名/电话/地址、商品名、备注、物流单号全文检索，返回命中高亮及状态、国家、月份分面
+// @Tags Order
+// @Produce json
+// @Param q query string true "关键词，多个词之间为 AND，每个词至少2个字符"
+// @Param status query int false "订单状态"
+// @Param country query string false "收货国家"
+// @Param month query string false "下单月份，格式 2006-01"
+// @Param limit query int false "分页限制，默认20，最大100"
+// @Param offset query int false "分页偏移"
+// @Success 200 {object} Response{data=types.OrderSearchResponse}
+// @Failure 400 {object} Response
+// @Failure 401 {object} Response
+// @Failure 403 {object} Response
+// @Failure 500 {object} Response
+// @Router /merchant/orders/search [get]
+func (h *OrderSearchHandler) SearchOrders(ctx *gin.Context) {
+	var req types.OrderSearchRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		RespondError(ctx, badRequest(err))
+		return
+	}
+
+	// 设置默认分页参数
+	if req.Limit <= 0 {
+		req.Limit = 20 // 默认每页20条
+	}
+	if req.Limit > 100 {
+		req.Limit = 100 // 最大每页100条
+	}
+	req.MerchantID = ctx.Value("merchantID").(int)
+
+	resp, err := h.searchService.SearchOrders(ctx, req)
+	if err != nil {
+		RespondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, RespSuccess(ctx, resp))
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/errs"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/types"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/utils"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/service"
+	"github.com/gin-gonic/gin"
+)
+
+// maxWebhookBodySize limits the body read before the signature is checked.
+const maxWebhookBodySize = 1 << 20
+
+var errCarrierNotFound = errs.New(errs.CodeCarrierNotFound, "carrier does not accept webhooks")
+
+// TrackingWebhookHandler receives tracking updates pushed by carriers. The
+// requests are not authenticated by JWT but signed with the carrier's secret.
+type TrackingWebhookHandler struct {
+	orderService *service.OrderServiceImpl
+	carriers     utils.Carriers
+}
+
+func NewTrackingWebhookHandler(orderService *service.OrderServiceImpl, carriers utils.Carriers) *TrackingWebhookHandler {
+	return &TrackingWebhookHandler{orderService: orderService, carriers: carriers}
+}
+
+// ReceiveTracking godoc
+// @Summary 承运商物流轨迹回调
+// @Description 承运商推送包裹的物流轨迹。请求需带 X-Webhook-Timestamp（unix 秒）和 X-Webhook-Signature（以承运商密钥对 "<timestamp>.<body>" 计算的 HMAC-SHA256，hex 编码），时间戳与服务器时间相差不得超过 5 分钟。重复推送的事件只保存一次；包裹签收后，自动确认收货的等待期从签收时间起算
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param carrier path string true "承运商编码"
+// @Param X-Webhook-Timestamp header string true "unix 秒"
+// @Param X-Webhook-Signature header string true "HMAC-SHA256 签名"
+// @Param request body types.TrackingWebhookRequest true "物流轨迹"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 401 {object} Response
+// @Failure 404 {object} Response
+// @Failure 500 {object} Response
+// @Router /webhooks/carriers/{carrier}/tracking [post]
+func (h *TrackingWebhookHandler) ReceiveTracking(ctx *gin.Context) {
+	carrier := ctx.Param("carrier")
+	secret, ok := h.carriers.WebhookSecret(carrier)
+	if !ok {
+		RespondError(ctx, errCarrierNotFound.Detailf("%s", carrier))
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(ctx.Request.Body, maxWebhookBodySize))
+	if err != nil {
+		RespondError(ctx, badRequest(err))
+		return
+	}
+	err = utils.VerifyWebhookSignature(secret, ctx.GetHeader(utils.WebhookTimestampHeader),
+		ctx.GetHeader(utils.WebhookSignatureHeader), body, time.Now())
+	if err != nil {
+		RespondError(ctx, err)
+		return
+	}
+
+	var req types.TrackingWebhookRequest
+	if err = json.Unmarshal(body, &req); err != nil {
+		RespondError(ctx, badRequest(err))
+		return
+	}
+	if err = h.orderService.RecordTrackingEvents(ctx, carrier, req); err != nil {
+		RespondError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, RespSuccess(ctx, "ok"))
+}
@@ -1,20 +1,47 @@
 package http
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net"
+	nethttp "net/http"
 	"os"
 
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/config"
-	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/http/router"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/log"
 )
 
-func Init(exitSig chan os.Signal) {
-	r := router.NewRouter()
-	log.Logger.Infof("Cerami Craft ItemService start...")
-	err := r.Run(fmt.Sprintf("%s:%d", config.Config.HttpConfig.Host, config.Config.HttpConfig.Port))
+type Server struct {
+	addr   string
+	server *nethttp.Server
+}
+
+func NewServer(cfg *config.HttpConfig, handler nethttp.Handler) *Server {
+	return &Server{
+		addr:   fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+		server: &nethttp.Server{Handler: handler},
+	}
+}
+
+// Start binds the listen address and serves in the background. A bind error
+// is returned; a serve error after startup is reported through exitSig.
+func (s *Server) Start(exitSig chan os.Signal) error {
+	listener, err := net.Listen("tcp", s.addr)
 	if err != nil {
-		log.Logger.Fatalf("Failed to run server: %v", err)
-		exitSig <- os.Interrupt
+		return fmt.Errorf("listen on %s failed: %w", s.addr, err)
 	}
+	log.Logger.Infof("Cerami Craft ItemService start...")
+	go func() {
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, nethttp.ErrServerClosed) {
+			log.Logger.Errorf("Failed to run server: %v", err)
+			exitSig <- os.Interrupt
+		}
+	}()
+	return nil
+}
+
+// Shutdown stops accepting new connections and waits for in-flight requests until ctx expires.
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
 }
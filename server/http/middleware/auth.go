@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/auth"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/http/api"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	AuthCookie    = "auth-token" // 用户服务登录后写入的 cookie
+	UserIDKey     = "userID"
+	MerchantIDKey = "merchantID" // 商家令牌所属商家；其他调用方为 0，不限商家
+	PrincipalKey  = "principal"
+)
+
+// Authenticate reads the JWT from the auth-token cookie or the Authorization
+// header, and stores the caller and its user ID in the context.
+func Authenticate(authorizer *auth.Authorizer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, _ := c.Cookie(AuthCookie)
+		if token == "" {
+			token = c.GetHeader("Authorization")
+		}
+		principal, err := authorizer.Authenticate(token)
+		if err != nil {
+			api.RespondError(c, err)
+			c.Abort()
+			return
+		}
+		c.Set(PrincipalKey, principal)
+		c.Set(UserIDKey, principal.UserID)
+		c.Set(MerchantIDKey, principal.MerchantID)
+		c.Next()
+	}
+}
+
+// Require lets the request through only when the caller satisfies rule;
+// denied requests are audited. It must run after Authenticate.
+func Require(authorizer *auth.Authorizer, rule auth.Rule) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, _ := c.Value(PrincipalKey).(*auth.Principal)
+		err := authorizer.Authorize(c, principal, rule, auth.Target{
+			Channel:   auth.ChannelHTTP,
+			Resource:  c.Request.Method + " " + c.FullPath(),
+			ClientIP:  c.ClientIP(),
+			RequestID: c.GetString(RequestIDKey),
+		})
+		if err != nil {
+			api.RespondError(c, err)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
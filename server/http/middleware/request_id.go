@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/log"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	RequestIDHeader = "X-Request-ID"
+	RequestIDKey    = "requestID"
+)
+
+// RequestID tags every request with an ID, taken from the X-Request-ID header
+// or generated, echoes it in the response and stores a child logger carrying
+// request_id (and trace_id when traced) in the request context.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" {
+			requestID = uuid.New().String()
+		}
+		c.Set(RequestIDKey, requestID)
+		c.Header(RequestIDHeader, requestID)
+
+		logger := log.Logger.With("request_id", requestID)
+		if spanCtx := trace.SpanContextFromContext(c.Request.Context()); spanCtx.HasTraceID() {
+			logger = logger.With("trace_id", spanCtx.TraceID().String())
+		}
+		c.Request = c.Request.WithContext(log.WithContext(c.Request.Context(), logger))
+
+		c.Next()
+	}
+}
@@ -2,22 +2,39 @@ package router
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/auth"
 	_ "github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/docs"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/http/api"
+	orderMiddleware "github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/http/middleware"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/metrics"
-	"github.com/NUS-ISS-Agile-Team/ceramicraft-user-mservice/common/middleware"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/validate"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/tracing"
 	swaggerFiles "github.com/swaggo/files"
 	gs "github.com/swaggo/gin-swagger"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
 const (
 	serviceURIPrefix = "/order-ms/v1"
 )
 
-func NewRouter() *gin.Engine {
+func NewRouter(orderHandler *api.OrderHandler, searchHandler *api.OrderSearchHandler, exportHandler *api.OrderExportHandler,
+	analyticsHandler *api.OrderAnalyticsHandler, webhookHandler *api.TrackingWebhookHandler, authorizer *auth.Authorizer) *gin.Engine {
 	r := gin.Default()
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		if err := validate.Register(v); err != nil {
+			panic(err)
+		}
+	}
+	// handlers pass *gin.Context down as context.Context; fall back to the
+	// request context so the span started by otelgin reaches the service layer
+	r.ContextWithFallback = true
+	r.Use(otelgin.Middleware(tracing.ServiceName))
+	r.Use(orderMiddleware.RequestID())
 
 	basicGroup := r.Group(serviceURIPrefix)
 	{
@@ -33,23 +50,48 @@ func NewRouter() *gin.Engine {
 				"message": "pong",
 			})
 		})
+		basicGroup.GET("/healthz", api.Healthz) // liveness
+		basicGroup.GET("/readyz", api.Readyz)   // readiness
+
+		// carrier callbacks are signed with the carrier's secret instead of a JWT
+		basicGroup.POST("/webhooks/carriers/:carrier/tracking", webhookHandler.ReceiveTracking)
+
+		// read-only merchant routes are also open to internal services holding orders:read
+		merchantOnly := orderMiddleware.Require(authorizer, auth.MerchantOnly)
+		merchantRead := orderMiddleware.Require(authorizer, auth.MerchantOrServiceRead)
+		customerOnly := orderMiddleware.Require(authorizer, auth.CustomerOnly)
 
 		merchantGroup := basicGroup.Group("/merchant")
 		{
-			merchantGroup.Use(middleware.AuthMiddleware())
-			merchantGroup.POST("/orders/list", api.ListOrders)
-			merchantGroup.GET("/orders/:order_no", api.GetOrderDetail)   // get order detail
-			merchantGroup.PATCH("/orders/:order_no/ship", api.ShipOrder) // ship order
-			merchantGroup.GET("/order-stats", api.GetOrderStats)         // get order stats
+			merchantGroup.Use(orderMiddleware.Authenticate(authorizer))
+			merchantGroup.POST("/orders/list", merchantRead, orderHandler.ListOrders)
+			merchantGroup.GET("/orders/search", merchantRead, searchHandler.SearchOrders)  // full-text search
+			merchantGroup.POST("/orders/export", merchantOnly, exportHandler.ExportOrders) // export orders
+			merchantGroup.GET("/orders/export/jobs/:job_id", merchantOnly, exportHandler.GetExportJob)
+			merchantGroup.GET("/orders/export/jobs/:job_id/download", merchantOnly, exportHandler.DownloadExport)
+			merchantGroup.GET("/orders/:order_no", merchantRead, orderHandler.GetOrderDetail)            // get order detail
+			merchantGroup.PATCH("/orders/:order_no", merchantOnly, orderHandler.EditOrder)               // edit order before shipping
+			merchantGroup.GET("/orders/:order_no/audits", merchantRead, orderHandler.GetOrderAudits)     // order edit history
+			merchantGroup.PATCH("/orders/:order_no/ship", merchantOnly, orderHandler.ShipOrder)          // ship order
+			merchantGroup.POST("/orders/ship/batch", merchantOnly, orderHandler.BatchShipOrders)         // ship orders from a manifest
+			merchantGroup.POST("/orders/:order_no/shipments", merchantOnly, orderHandler.CreateShipment) // create a parcel
+			merchantGroup.GET("/order-stats", merchantRead, orderHandler.GetOrderStats)                  // get order stats
+			merchantGroup.GET("/analytics/sales", merchantRead, analyticsHandler.GetSalesSeries)
+			merchantGroup.GET("/analytics/top-products", merchantRead, analyticsHandler.GetTopProducts)
+			merchantGroup.GET("/analytics/funnel", merchantRead, analyticsHandler.GetStatusFunnel)
+			merchantGroup.GET("/analytics/summary", merchantRead, analyticsHandler.GetSummary)
+			merchantGroup.GET("/analytics/countries", merchantRead, analyticsHandler.GetCountryBreakdown)
 		}
 
 		customerGroup := basicGroup.Group("/customer")
 		{
-			customerGroup.Use(middleware.AuthMiddleware())
-			customerGroup.POST("/orders", api.CreateOrder) // create order
-			customerGroup.POST("/orders/list", api.CustomerListOrders)
-			customerGroup.GET("/orders/:order_no", api.CustomerGetOrderDetail) // get order detail
-			customerGroup.PATCH("/orders/:order_no/confirm", api.ConfirmOrder) // confirm order
+			customerGroup.Use(orderMiddleware.Authenticate(authorizer), customerOnly)
+			customerGroup.POST("/orders", orderHandler.CreateOrder) // create order
+			customerGroup.POST("/orders/list", orderHandler.CustomerListOrders)
+			customerGroup.GET("/orders/:order_no", orderHandler.CustomerGetOrderDetail)    // get order detail
+			customerGroup.PATCH("/orders/:order_no/confirm", orderHandler.ConfirmOrder)    // confirm order
+			customerGroup.POST("/orders/:order_no/reorder", orderHandler.CustomerReorder)  // buy again
+			customerGroup.GET("/checkouts/:checkout_no", orderHandler.CustomerGetCheckout) // get all sub-orders of a checkout
 		}
 	}
 	return r
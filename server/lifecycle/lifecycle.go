@@ -0,0 +1,104 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/log"
+)
+
+const (
+	defaultShutdownTimeout = 30 * time.Second
+
+	// minStopTimeout 关闭超时后，剩余组件各自仍有的最短关闭时间
+	minStopTimeout = 2 * time.Second
+)
+
+// Component is one part of the application with a start and stop step.
+// Start must not block: long running loops go into their own goroutine.
+// Either function may be nil.
+type Component struct {
+	Name  string
+	Start func() error
+	Stop  func(ctx context.Context) error
+}
+
+// Manager starts components in the order they were added and stops the
+// started ones in reverse order, so servers stop taking traffic before the
+// jobs, producers and stores they depend on are closed.
+type Manager struct {
+	components      []Component
+	started         []Component
+	shutdownTimeout time.Duration
+	minStopTimeout  time.Duration
+}
+
+func NewManager(shutdownTimeout time.Duration) *Manager {
+	if shutdownTimeout <= 0 {
+		shutdownTimeout = defaultShutdownTimeout
+	}
+	return &Manager{shutdownTimeout: shutdownTimeout, minStopTimeout: minStopTimeout}
+}
+
+func (m *Manager) Add(component Component) {
+	m.components = append(m.components, component)
+}
+
+// Start starts every component in order. If one fails, the components
+// already started are stopped and the error is returned.
+func (m *Manager) Start() error {
+	for _, component := range m.components {
+		if component.Start != nil {
+			log.Logger.Infof("lifecycle: starting %s", component.Name)
+			if err := component.Start(); err != nil {
+				err = fmt.Errorf("start %s failed: %w", component.Name, err)
+				log.Logger.Error(err.Error())
+				_ = m.Stop()
+				return err
+			}
+		}
+		m.started = append(m.started, component)
+	}
+	log.Logger.Info("lifecycle: all components started")
+	return nil
+}
+
+// Stop stops the started components in reverse order. Each component gets
+// what is left of the shutdown deadline, but never less than minStopTimeout,
+// so a component that hangs past the deadline is abandoned while the ones
+// after it still flush and close on a best-effort budget.
+func (m *Manager) Stop() error {
+	deadline := time.Now().Add(m.shutdownTimeout)
+
+	var errs []error
+	for i := len(m.started) - 1; i >= 0; i-- {
+		component := m.started[i]
+		if component.Stop == nil {
+			continue
+		}
+		log.Logger.Infof("lifecycle: stopping %s", component.Name)
+		if err := stopWithDeadline(max(time.Until(deadline), m.minStopTimeout), component); err != nil {
+			err = fmt.Errorf("stop %s failed: %w", component.Name, err)
+			log.Logger.Error(err.Error())
+			errs = append(errs, err)
+		}
+	}
+	m.started = nil
+	log.Logger.Info("lifecycle: shutdown finished")
+	return errors.Join(errs...)
+}
+
+func stopWithDeadline(timeout time.Duration, component Component) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	errCh := make(chan error, 1)
+	go func() { errCh <- component.Stop(ctx) }()
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,93 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/log"
+	"go.uber.org/zap"
+)
+
+func init() {
+	logger, _ := zap.NewDevelopment()
+	log.Logger = logger.Sugar()
+}
+
+func recordingComponent(name string, events *[]string, startErr error) Component {
+	return Component{
+		Name: name,
+		Start: func() error {
+			*events = append(*events, "start "+name)
+			return startErr
+		},
+		Stop: func(ctx context.Context) error {
+			*events = append(*events, "stop "+name)
+			return nil
+		},
+	}
+}
+
+func TestManager_StartAndStopOrder(t *testing.T) {
+	var events []string
+	m := NewManager(time.Second)
+	m.Add(recordingComponent("db", &events, nil))
+	m.Add(recordingComponent("kafka", &events, nil))
+	m.Add(recordingComponent("http", &events, nil))
+
+	if err := m.Start(); err != nil {
+		t.Fatalf("Expected no error, got: %s", err.Error())
+	}
+	if err := m.Stop(); err != nil {
+		t.Fatalf("Expected no error, got: %s", err.Error())
+	}
+
+	expected := []string{"start db", "start kafka", "start http", "stop http", "stop kafka", "stop db"}
+	if !reflect.DeepEqual(events, expected) {
+		t.Errorf("Expected %v, got: %v", expected, events)
+	}
+}
+
+func TestManager_StartFailureStopsStartedComponents(t *testing.T) {
+	var events []string
+	m := NewManager(time.Second)
+	m.Add(recordingComponent("db", &events, nil))
+	m.Add(recordingComponent("kafka", &events, errors.New("broker down")))
+	m.Add(recordingComponent("http", &events, nil))
+
+	if err := m.Start(); err == nil {
+		t.Fatalf("Expected start error, got nil")
+	}
+
+	expected := []string{"start db", "start kafka", "stop db"}
+	if !reflect.DeepEqual(events, expected) {
+		t.Errorf("Expected %v, got: %v", expected, events)
+	}
+}
+
+func TestManager_StopDeadline(t *testing.T) {
+	var remaining time.Duration
+	m := NewManager(20 * time.Millisecond)
+	m.minStopTimeout = 50 * time.Millisecond
+	m.Add(Component{Name: "db", Stop: func(ctx context.Context) error {
+		deadline, _ := ctx.Deadline()
+		remaining = time.Until(deadline)
+		return nil
+	}})
+	m.Add(Component{Name: "stuck", Stop: func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	}})
+
+	_ = m.Start()
+	err := m.Stop()
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got: %v", err)
+	}
+	// 关闭超时后仍给后面的组件留出最短关闭时间
+	if remaining <= 0 {
+		t.Errorf("Expected components after the deadline to get a best-effort budget, got: %v", remaining)
+	}
+}
@@ -0,0 +1,25 @@
+package log
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+type loggerKey struct{}
+
+// WithContext returns a copy of ctx carrying logger.
+func WithContext(ctx context.Context, logger *zap.SugaredLogger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext returns the request-scoped logger stored by WithContext,
+// or the global Logger when ctx has none.
+func FromContext(ctx context.Context) *zap.SugaredLogger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(loggerKey{}).(*zap.SugaredLogger); ok && logger != nil {
+			return logger
+		}
+	}
+	return Logger
+}
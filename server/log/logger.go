@@ -8,6 +8,12 @@ import (
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/config"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+const (
+	FormatConsole = "console"
+	FormatJSON    = "json"
 )
 
 var (
@@ -42,6 +48,10 @@ func getLogLevel() zapcore.Level {
 
 func getEncoder() zapcore.Encoder {
 	encoderConfig := zap.NewProductionEncoderConfig()
+	if config.Config.LogConfig.Format == FormatJSON {
+		encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+		return zapcore.NewJSONEncoder(encoderConfig)
+	}
 	encoderConfig.EncodeTime = zapcore.TimeEncoderOfLayout("2006-01-02 15:04:05")
 	encoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
 	return zapcore.NewConsoleEncoder(encoderConfig)
@@ -61,9 +71,12 @@ func getLogWriter() zapcore.WriteSyncer {
 	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
 		panic(fmt.Sprintf("Failed to create directories: %v", err))
 	}
-	file, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
-	if err != nil {
-		panic(err)
-	}
-	return zapcore.AddSync(file)
+	// lumberjack rotates the file by size and prunes old files by count and age
+	return zapcore.AddSync(&lumberjack.Logger{
+		Filename:   logPath,
+		MaxSize:    config.Config.LogConfig.MaxSize,
+		MaxBackups: config.Config.LogConfig.MaxBackups,
+		MaxAge:     config.Config.LogConfig.MaxAge,
+		Compress:   config.Config.LogConfig.Compress,
+	})
 }
@@ -1,22 +1,15 @@
 package main
 
 import (
-	"context"
 	"os"
 	"os/signal"
 	"syscall"
-	"time"
 
-	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/clients"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/app"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/config"
-	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/grpc"
-	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/http"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/health"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/log"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/metrics"
-	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/utils"
-	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository"
-	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/service"
-	userUtils "github.com/NUS-ISS-Agile-Team/ceramicraft-user-mservice/common/utils"
 )
 
 var (
@@ -30,31 +23,20 @@ var (
 func main() {
 	config.Init()
 	log.InitLogger()
-	repository.Init()
-	userUtils.InitJwtSecret()
-	utils.InitKafka()
-	clients.InitAllClients(config.Config)
 	metrics.RegisterMetrics()
-	go grpc.Init(sigCh)
-	go http.Init(sigCh)
-	go utils.GetReader().ConsumeMessage(context.Background())
-	startAutoConfirmJob(context.Background(), service.GetOrderServiceInstance())
-	// listen terminage signal
+
+	// listen terminage signal before starting, so a signal during startup is
+	// kept in sigCh and leads to a graceful stop instead of killing the process
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	application := app.New(config.Config, sigCh)
+	if err := application.Start(); err != nil {
+		log.Logger.Fatalf("failed to start: %v", err)
+	}
+	health.MarkReady()
 	sig := <-sigCh // Block until signal is received
 	log.Logger.Infof("Received signal: %v, shutting down...", sig)
-	utils.CloseKafka()
-}
-
-func startAutoConfirmJob(ctx context.Context, orderService *service.OrderServiceImpl) {
-    timer := utils.NewMyTimer(30 * time.Second)
-    
-    // 创建一个包装函数
-    task := func() {
-        orderService.OrderAutoConfirm(ctx)
-    }
-    
-    go timer.Start(ctx, task)
-    
-    log.Logger.Info("Auto confirm job started")
+	health.MarkShuttingDown()
+	if err := application.Stop(); err != nil {
+		log.Logger.Errorf("shutdown finished with errors: %v", err)
+	}
 }
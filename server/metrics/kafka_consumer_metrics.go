@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// KafkaConsumerMetrics groups the metrics of the Kafka consumers.
+// A nil *KafkaConsumerMetrics is valid and records nothing.
+type KafkaConsumerMetrics struct {
+	ConsumeLag          *prometheus.HistogramVec
+	ConsumerLag         *prometheus.GaugeVec
+	ConsumeSkippedTotal *prometheus.CounterVec
+}
+
+// NewKafkaConsumerMetrics creates the consumer metrics and registers them to
+// reg; a nil reg creates the collectors without registering them.
+func NewKafkaConsumerMetrics(reg prometheus.Registerer) *KafkaConsumerMetrics {
+	m := &KafkaConsumerMetrics{
+		// Kafka 消费延迟：消息时间戳到被消费的时间
+		ConsumeLag: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "order_service_kafka_consume_lag_milliseconds",
+				Help:    "Histogram of time from message timestamp to consumption in milliseconds.(订单服务Kafka消费延迟ms)",
+				Buckets: []float64{10, 50, 100, 250, 500, 1000, 5000, 10000, 60000},
+			},
+			[]string{"topic"},
+		),
+		// Kafka 消费组积压消息数
+		ConsumerLag: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "order_service_kafka_consumer_lag_messages",
+				Help: "Number of messages the consumer group is behind the partition head.(订单服务Kafka消费积压数)",
+			},
+			[]string{"topic"},
+		),
+		// 多次重试仍处理失败、被跳过的 Kafka 消息数
+		ConsumeSkippedTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "order_service_kafka_consume_skipped_total",
+				Help: "Total number of Kafka messages skipped after the handler kept failing.(订单服务Kafka消费失败跳过数)",
+			},
+			[]string{"topic"},
+		),
+	}
+	if reg != nil {
+		reg.MustRegister(m.ConsumeLag, m.ConsumerLag, m.ConsumeSkippedTotal)
+	}
+	return m
+}
+
+// Fetched records the consume lag of a message produced at msgTime and the
+// consumer group lag behind the partition head.
+func (m *KafkaConsumerMetrics) Fetched(topic string, msgTime time.Time, groupLag int64) {
+	if m == nil {
+		return
+	}
+	m.ConsumeLag.WithLabelValues(topic).Observe(float64(time.Since(msgTime).Milliseconds()))
+	m.ConsumerLag.WithLabelValues(topic).Set(float64(groupLag))
+}
+
+func (m *KafkaConsumerMetrics) Skipped(topic string) {
+	if m == nil {
+		return
+	}
+	m.ConsumeSkippedTotal.WithLabelValues(topic).Inc()
+}
@@ -30,8 +30,69 @@ var (
 		},
 		[]string{"method", "path", "status"},
 	)
+
+	// Kafka 消息投递结果（success / failure / dropped）
+	KafkaProduceTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "order_service_kafka_produce_total",
+			Help: "Total number of Kafka messages produced, by topic and result.(订单服务Kafka消息投递总数)",
+		},
+		[]string{"topic", "result"},
+	)
+
+	// Kafka 消息投递耗时
+	KafkaProduceDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "order_service_kafka_produce_duration_milliseconds",
+			Help:    "Histogram of Kafka message delivery latency in milliseconds.(订单服务Kafka消息投递耗时ms)",
+			Buckets: []float64{1, 5, 10, 25, 50, 100, 250, 500, 1000, 5000},
+		},
+		[]string{"topic"},
+	)
+
+	// Kafka 生产延迟：SendMsg 到 broker 确认的时间（异步模式包含排队时间）
+	KafkaProduceLag = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "order_service_kafka_produce_lag_milliseconds",
+			Help:    "Histogram of time from SendMsg to broker ack in milliseconds, including buffering.(订单服务Kafka生产延迟ms)",
+			Buckets: []float64{1, 5, 10, 25, 50, 100, 250, 500, 1000, 5000, 10000},
+		},
+		[]string{"topic"},
+	)
+
+	orderMetrics         *OrderMetrics
+	kafkaConsumerMetrics *KafkaConsumerMetrics
+	rpcClientMetrics     *RPCClientMetrics
 )
 
+// RegisterMetrics registers every collector of the service to the default
+// prometheus registry, which is what /metrics serves.
 func RegisterMetrics() {
-	prometheus.MustRegister(HttpRequestsTotal, HttpRequestDuration, HttpRequestsErrors)
+	Register(prometheus.DefaultRegisterer)
+}
+
+// Register registers every collector of the service to reg.
+func Register(reg prometheus.Registerer) {
+	reg.MustRegister(
+		HttpRequestsTotal, HttpRequestDuration, HttpRequestsErrors,
+		KafkaProduceTotal, KafkaProduceDuration, KafkaProduceLag,
+	)
+	orderMetrics = NewOrderMetrics(reg)
+	kafkaConsumerMetrics = NewKafkaConsumerMetrics(reg)
+	rpcClientMetrics = NewRPCClientMetrics(reg)
+}
+
+// GetOrderMetrics returns the order metrics created by Register, nil before that.
+func GetOrderMetrics() *OrderMetrics {
+	return orderMetrics
+}
+
+// GetKafkaConsumerMetrics returns the consumer metrics created by Register, nil before that.
+func GetKafkaConsumerMetrics() *KafkaConsumerMetrics {
+	return kafkaConsumerMetrics
+}
+
+// GetRPCClientMetrics returns the downstream call metrics created by Register, nil before that.
+func GetRPCClientMetrics() *RPCClientMetrics {
+	return rpcClientMetrics
 }
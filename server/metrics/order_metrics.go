@@ -0,0 +1,147 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// 下单结果
+const (
+	OrderOutcomeSuccess           = "success"
+	OrderOutcomeStockInsufficient = "stock_insufficient"
+	OrderOutcomePaymentFailed     = "payment_failed"
+	OrderOutcomeError             = "error"
+)
+
+// 支付失败原因
+const (
+	PaymentFailureRPCError = "rpc_error"
+	PaymentFailureDeclined = "declined"
+)
+
+// OrderMetrics groups the business metrics of the order pipeline.
+// A nil *OrderMetrics is valid and records nothing, so service tests that
+// don't care about metrics can leave it unset.
+type OrderMetrics struct {
+	OrdersCreatedTotal             *prometheus.CounterVec
+	PaymentFailuresTotal           *prometheus.CounterVec
+	StockInsufficientTotal         prometheus.Counter
+	StatusTransitionsTotal         *prometheus.CounterVec
+	AutoConfirmBatchSize           prometheus.Histogram
+	AutoConfirmDuration            prometheus.Histogram
+	AutoConfirmLockContentionTotal prometheus.Counter
+}
+
+// NewOrderMetrics creates the order metrics and registers them to reg.
+// Pass prometheus.NewRegistry() in tests to keep them isolated; a nil reg
+// creates the collectors without registering them.
+func NewOrderMetrics(reg prometheus.Registerer) *OrderMetrics {
+	m := &OrderMetrics{
+		// 下单总数（按结果）
+		OrdersCreatedTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "order_service_orders_created_total",
+				Help: "Total number of create order attempts, by outcome.(下单总数)",
+			},
+			[]string{"outcome"},
+		),
+		// 支付失败（按原因）
+		PaymentFailuresTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "order_service_payment_failures_total",
+				Help: "Total number of failed payments, by reason.(支付失败总数)",
+			},
+			[]string{"reason"},
+		),
+		// 库存不足拒单
+		StockInsufficientTotal: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Name: "order_service_stock_insufficient_total",
+				Help: "Total number of orders rejected for insufficient stock.(库存不足拒单总数)",
+			},
+		),
+		// 订单状态流转
+		StatusTransitionsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "order_service_status_transitions_total",
+				Help: "Total number of order status transitions, by from/to status.(订单状态流转总数)",
+			},
+			[]string{"from", "to"},
+		),
+		// 自动确认收货每批订单数
+		AutoConfirmBatchSize: prometheus.NewHistogram(
+			prometheus.HistogramOpts{
+				Name:    "order_service_auto_confirm_batch_size",
+				Help:    "Number of orders confirmed per auto-confirm run.(自动确认收货每批订单数)",
+				Buckets: []float64{0, 1, 5, 10, 50, 100, 500, 1000},
+			},
+		),
+		// 自动确认收货耗时
+		AutoConfirmDuration: prometheus.NewHistogram(
+			prometheus.HistogramOpts{
+				Name:    "order_service_auto_confirm_duration_milliseconds",
+				Help:    "Histogram of auto-confirm run latency in milliseconds.(自动确认收货耗时ms)",
+				Buckets: []float64{10, 50, 100, 250, 500, 1000, 2500, 5000, 10000},
+			},
+		),
+		// 自动确认收货抢锁失败
+		AutoConfirmLockContentionTotal: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Name: "order_service_auto_confirm_lock_contention_total",
+				Help: "Total number of auto-confirm runs skipped because another instance held the lock.(自动确认收货抢锁失败总数)",
+			},
+		),
+	}
+	if reg != nil {
+		reg.MustRegister(
+			m.OrdersCreatedTotal, m.PaymentFailuresTotal, m.StockInsufficientTotal,
+			m.StatusTransitionsTotal, m.AutoConfirmBatchSize, m.AutoConfirmDuration,
+			m.AutoConfirmLockContentionTotal,
+		)
+	}
+	return m
+}
+
+func (m *OrderMetrics) OrderCreated(outcome string) {
+	if m == nil {
+		return
+	}
+	m.OrdersCreatedTotal.WithLabelValues(outcome).Inc()
+}
+
+func (m *OrderMetrics) PaymentFailed(reason string) {
+	if m == nil {
+		return
+	}
+	m.PaymentFailuresTotal.WithLabelValues(reason).Inc()
+}
+
+func (m *OrderMetrics) StockInsufficient() {
+	if m == nil {
+		return
+	}
+	m.StockInsufficientTotal.Inc()
+}
+
+func (m *OrderMetrics) StatusTransition(from, to string) {
+	if m == nil {
+		return
+	}
+	m.StatusTransitionsTotal.WithLabelValues(from, to).Inc()
+}
+
+func (m *OrderMetrics) AutoConfirmDone(batchSize int, start time.Time) {
+	if m == nil {
+		return
+	}
+	m.AutoConfirmBatchSize.Observe(float64(batchSize))
+	m.AutoConfirmDuration.Observe(float64(time.Since(start).Milliseconds()))
+}
+
+func (m *OrderMetrics) AutoConfirmLockContention() {
+	if m == nil {
+		return
+	}
+	m.AutoConfirmLockContentionTotal.Inc()
+}
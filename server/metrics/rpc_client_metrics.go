@@ -0,0 +1,41 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// RPCClientMetrics groups the metrics of the calls to downstream services.
+// A nil *RPCClientMetrics is valid and records nothing.
+type RPCClientMetrics struct {
+	ClientDuration *prometheus.HistogramVec
+}
+
+// NewRPCClientMetrics creates the downstream call metrics and registers them
+// to reg; a nil reg creates the collectors without registering them.
+func NewRPCClientMetrics(reg prometheus.Registerer) *RPCClientMetrics {
+	m := &RPCClientMetrics{
+		// 下游 RPC 调用耗时（商品服务、支付服务、用户服务）
+		ClientDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "order_service_rpc_client_duration_milliseconds",
+				Help:    "Histogram of outbound gRPC call latency in milliseconds.(订单服务下游RPC耗时ms)",
+				Buckets: []float64{5, 10, 25, 50, 100, 250, 500, 1000, 2000, 5000},
+			},
+			[]string{"service", "method", "code"},
+		),
+	}
+	if reg != nil {
+		reg.MustRegister(m.ClientDuration)
+	}
+	return m
+}
+
+// CallDone records a call to service started at start, labeled by its result code.
+func (m *RPCClientMetrics) CallDone(service, method, code string, start time.Time) {
+	if m == nil {
+		return
+	}
+	m.ClientDuration.WithLabelValues(service, method, code).Observe(float64(time.Since(start).Milliseconds()))
+}
@@ -0,0 +1,10 @@
+package consts
+
+// 导出任务状态
+const (
+	ExportJobPending   = "pending"
+	ExportJobRunning   = "running"
+	ExportJobSucceeded = "succeeded"
+	ExportJobFailed    = "failed"
+	ExportJobExpired   = "expired" // 文件已过保留期被清理
+)
@@ -0,0 +1,10 @@
+package consts
+
+// logical kafka topics, mapped to physical topic names by config kafka.topics
+const (
+	TopicOrderCreated       = "order_created"
+	TopicOrderStatusChanged = "order_status_changed"
+	TopicOrderCanceled      = "order_canceled"
+	TopicOrderUpdated       = "order_updated"  // 商家在发货前修改订单
+	TopicOrderRefunded      = "order_refunded" // 请求支付服务部分退款
+)
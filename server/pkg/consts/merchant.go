@@ -0,0 +1,5 @@
+package consts
+
+// DefaultMerchantID is the CeramiCraft store itself, the seller of every order
+// placed before independent studios joined and of cart items without a merchant.
+const DefaultMerchantID = 1
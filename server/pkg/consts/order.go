@@ -7,4 +7,5 @@ const (
 	SHIPPED
 	DELIVERED
 	CANCELED
+	PARTIALLY_SHIPPED // 部分发货，追加在末尾以保持已有状态值不变
 )
\ No newline at end of file
@@ -0,0 +1,7 @@
+package consts
+
+// 修改订单产生的退款消息发送状态
+const (
+	RefundPending = "pending" // 已记录，消息尚未确认送达，由重试任务补发
+	RefundSent    = "sent"
+)
@@ -0,0 +1,9 @@
+package consts
+
+// 物流轨迹状态，承运商回调需映射到这些值
+const (
+	TrackingInTransit      = "in_transit"
+	TrackingOutForDelivery = "out_for_delivery"
+	TrackingDelivered      = "delivered"
+	TrackingException      = "exception"
+)
@@ -0,0 +1,132 @@
+// Package errs defines the domain errors of the order service. Each error has
+// a stable Code that clients can rely on; the first three digits of the code
+// are the HTTP status it is served with.
+package errs
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+type Code int
+
+// 通用错误码
+const (
+	CodeInvalidParam  Code = 40000
+	CodeUnauthorized  Code = 40100
+	CodeForbidden     Code = 40300
+	CodeNotFound      Code = 40400
+	CodeConflict      Code = 40900
+	CodeGone          Code = 41000
+	CodeUnprocessable Code = 42200
+	CodeInternal      Code = 50000
+	CodeUpstream      Code = 50200
+)
+
+// 业务错误码
+const (
+	CodeInvalidCursor        Code = 40001
+	CodeInvalidSort          Code = 40002
+	CodeInvalidSearchQuery   Code = 40003
+	CodeUnsupportedFormat    Code = 40004
+	CodeExportTooLarge       Code = 40005
+	CodeInvalidShipment      Code = 40006
+	CodeInvalidShipBatch     Code = 40007
+	CodeInvalidTrackingEvent Code = 40008
+	CodeInvalidDateRange     Code = 40010
+	CodeInvalidAddress       Code = 40011
+	CodeInvalidOrderEdit     Code = 40012
+	CodeInvalidOrderItem     Code = 40013
+
+	CodeInvalidSignature Code = 40101
+	CodeWebhookExpired   Code = 40102
+
+	CodeOrderForbidden Code = 40301
+
+	CodeOrderNotFound     Code = 40401
+	CodeShipmentNotFound  Code = 40402
+	CodeExportJobNotFound Code = 40403
+	CodeCarrierNotFound   Code = 40404
+	CodeAddressNotFound   Code = 40405
+
+	CodeInvalidStatusTransition Code = 40901
+	CodeOrderStatusChanged      Code = 40902
+	CodeOrderNotShippable       Code = 40903
+	CodeExportNotReady          Code = 40904
+	CodeOrderNotEditable        Code = 40905
+
+	CodeExportExpired Code = 41001
+
+	CodeInsufficientStock Code = 42201
+	CodePaymentDeclined   Code = 42202
+
+	CodeProductService Code = 50201
+	CodePaymentService Code = 50202
+	CodeUserService    Code = 50203
+)
+
+// HTTPStatus is the HTTP status the code is served with.
+func (c Code) HTTPStatus() int {
+	return int(c) / 100
+}
+
+// Error is a domain error. Errors compare equal under errors.Is when their
+// codes match, so details added with Detailf or Wrap keep them matching the
+// sentinel they were made from.
+type Error struct {
+	Code    Code
+	Message string        // 英文描述，写入日志和 Response.Error
+	Fields  []*FieldError // 参数校验失败的字段
+	cause   error
+}
+
+func New(code Code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+func (e *Error) Error() string {
+	if e.cause != nil {
+		return e.Message + ": " + e.cause.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// Detailf returns a copy of e with details appended to the message.
+func (e *Error) Detailf(format string, args ...interface{}) *Error {
+	return &Error{Code: e.Code, Message: e.Message + ": " + fmt.Sprintf(format, args...), Fields: e.Fields, cause: e.cause}
+}
+
+// Wrap returns a copy of e caused by err.
+func (e *Error) Wrap(err error) *Error {
+	return &Error{Code: e.Code, Message: e.Message, Fields: e.Fields, cause: err}
+}
+
+var (
+	ErrInvalidParam = New(CodeInvalidParam, "invalid parameter")
+	ErrNotFound     = New(CodeNotFound, "record not found")
+	ErrInternal     = New(CodeInternal, "internal error")
+)
+
+// From returns the domain error in err's chain. A missing record becomes
+// ErrNotFound and any other error ErrInternal.
+func From(err error) *Error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound.Wrap(err)
+	}
+	return ErrInternal.Wrap(err)
+}
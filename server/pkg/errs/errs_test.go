@@ -0,0 +1,88 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+var errTestNotFound = New(CodeOrderNotFound, "order not found")
+
+func TestError_IsAndFrom(t *testing.T) {
+	detailed := errTestNotFound.Detailf("order no %s", "ORD1")
+	if !errors.Is(detailed, errTestNotFound) {
+		t.Error("detailed error should match its sentinel")
+	}
+	if errors.Is(detailed, ErrNotFound) {
+		t.Error("errors with different codes should not match")
+	}
+
+	wrapped := fmt.Errorf("GetOrderDetail: %w", errTestNotFound.Wrap(gorm.ErrRecordNotFound))
+	if got := From(wrapped); got.Code != CodeOrderNotFound || got.Code.HTTPStatus() != http.StatusNotFound {
+		t.Errorf("From() = %+v", got)
+	}
+	if !errors.Is(wrapped, gorm.ErrRecordNotFound) {
+		t.Error("Wrap should keep the cause in the chain")
+	}
+	if got := From(gorm.ErrRecordNotFound); got.Code != CodeNotFound {
+		t.Errorf("missing record should map to CodeNotFound, got %d", got.Code)
+	}
+	if got := From(errors.New("boom")); got.Code != CodeInternal || got.Error() != "internal error: boom" {
+		t.Errorf("unknown error should map to CodeInternal, got %+v", got)
+	}
+}
+
+func TestMessage(t *testing.T) {
+	tests := []struct {
+		code Code
+		lang string
+		want string
+	}{
+		{CodeOrderNotFound, LangZH, "订单不存在。"},
+		{CodeOrderNotFound, "fr", "The order does not exist."},
+		{Code(40999), LangEN, "The request conflicts with the current state."},
+		{Code(12345), LangZH, "服务器内部错误，请稍后重试。"},
+	}
+	for _, tt := range tests {
+		if got := Message(tt.code, tt.lang); got != tt.want {
+			t.Errorf("Message(%d, %s) = %s, want %s", tt.code, tt.lang, got, tt.want)
+		}
+	}
+}
+
+func TestLang(t *testing.T) {
+	tests := map[string]string{
+		"":                        LangEN,
+		"zh-CN,zh;q=0.9,en;q=0.8": LangZH,
+		"en-US,en;q=0.9":          LangEN,
+		"fr-FR, zh-TW;q=0.5":      LangZH,
+	}
+	for header, want := range tests {
+		if got := Lang(header); got != want {
+			t.Errorf("Lang(%q) = %s, want %s", header, got, want)
+		}
+	}
+}
+
+func TestWithFields(t *testing.T) {
+	err := ErrValidation.WithFields([]*FieldError{
+		NewFieldError("order_item_list", "min", "1", true),
+		NewFieldError("quantity", "gt", "0", false),
+	})
+	if !errors.Is(err, ErrValidation) || err.Code.HTTPStatus() != http.StatusBadRequest {
+		t.Fatalf("WithFields() = %v, want a validation error", err)
+	}
+	want := "request validation failed: order_item_list: must have at least 1 characters or items; quantity: must be greater than 0"
+	if err.Error() != want {
+		t.Errorf("Error() = %s, want %s", err.Error(), want)
+	}
+	if got := From(fmt.Errorf("bind: %w", err)).Fields; len(got) != 2 {
+		t.Errorf("From() lost the fields: %v", got)
+	}
+	if got := err.Fields[1].Localize(LangZH); got != "必须大于 0" {
+		t.Errorf("Localize() = %s", got)
+	}
+}
@@ -0,0 +1,86 @@
+package errs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CodeValidationFailed is returned with the field errors of a request that
+// fails validation.
+const CodeValidationFailed Code = 40009
+
+var ErrValidation = New(CodeValidationFailed, "request validation failed")
+
+// FieldError describes why one request field is invalid. Field is the JSON
+// path of the field, e.g. order_item_list[0].quantity.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`            // 未通过的校验规则，如 required、gt、phone
+	Param   string `json:"param,omitempty"` // 规则参数，如 gt=0 中的 0
+	Message string `json:"message"`         // 本地化的提示
+
+	length bool // min/max 等规则作用于字符串长度或列表元素个数
+}
+
+// NewFieldError creates a field error; length tells that size rules apply to
+// the length of a string or the number of items of a list.
+func NewFieldError(field, rule, param string, length bool) *FieldError {
+	f := &FieldError{Field: field, Rule: rule, Param: param, length: length}
+	f.Message = f.Localize(LangEN)
+	return f
+}
+
+// Localize returns the message of the field error in lang.
+func (f *FieldError) Localize(lang string) string {
+	key := f.Rule
+	if f.length {
+		if _, ok := fieldMessages[key+"_len"]; ok {
+			key += "_len"
+		}
+	}
+	msgs, ok := fieldMessages[key]
+	if !ok {
+		msgs = fieldMessages["invalid"]
+	}
+	msg, ok := msgs[lang]
+	if !ok {
+		msg = msgs[LangEN]
+	}
+	if strings.Contains(msg, "%s") {
+		msg = fmt.Sprintf(msg, f.Param)
+	}
+	return msg
+}
+
+// WithFields returns a copy of e carrying the field errors.
+func (e *Error) WithFields(fields []*FieldError) *Error {
+	parts := make([]string, 0, len(fields))
+	for _, f := range fields {
+		parts = append(parts, f.Field+": "+f.Message)
+	}
+	return &Error{Code: e.Code, Message: e.Message + ": " + strings.Join(parts, "; "), cause: e.cause, Fields: fields}
+}
+
+// fieldMessages 校验规则的提示，_len 后缀用于字符串长度和列表元素个数
+var fieldMessages = map[string]map[string]string{
+	"required": {LangEN: "is required", LangZH: "不能为空"},
+	"min":      {LangEN: "must be at least %s", LangZH: "不能小于 %s"},
+	"min_len":  {LangEN: "must have at least %s characters or items", LangZH: "长度或数量不能少于 %s"},
+	"max":      {LangEN: "must be at most %s", LangZH: "不能大于 %s"},
+	"max_len":  {LangEN: "must have at most %s characters or items", LangZH: "长度或数量不能超过 %s"},
+	"gt":       {LangEN: "must be greater than %s", LangZH: "必须大于 %s"},
+	"gte":      {LangEN: "must be at least %s", LangZH: "不能小于 %s"},
+	"lt":       {LangEN: "must be less than %s", LangZH: "必须小于 %s"},
+	"lte":      {LangEN: "must be at most %s", LangZH: "不能大于 %s"},
+	"oneof":    {LangEN: "must be one of: %s", LangZH: "必须是以下值之一：%s"},
+	"unique":   {LangEN: "must not contain duplicates", LangZH: "不能有重复项"},
+	"distinct": {LangEN: "must not contain duplicate %s", LangZH: "%s 不能重复"},
+	"gtefield": {LangEN: "must not be earlier or smaller than %s", LangZH: "不能早于或小于 %s"},
+	"url":      {LangEN: "must be a valid URL", LangZH: "必须是合法的 URL"},
+	"datetime": {LangEN: "must be in the format %s", LangZH: "格式必须为 %s"},
+	"phone":    {LangEN: "must be an E.164 phone number such as +6591234567", LangZH: "必须是 E.164 格式的电话号码，如 +6591234567"},
+	"country":  {LangEN: "must be an ISO 3166-1 alpha-2 country code such as SG", LangZH: "必须是 ISO 3166-1 两位国家代码，如 SG"},
+	"postcode": {LangEN: "is not a valid postal code of the country", LangZH: "不符合所在国家的邮政编码格式"},
+	"type":     {LangEN: "must be of type %s", LangZH: "类型必须为 %s"},
+	"invalid":  {LangEN: "is invalid", LangZH: "不合法"},
+}
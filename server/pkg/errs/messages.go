@@ -0,0 +1,97 @@
+package errs
+
+import "strings"
+
+// 支持的语言
+const (
+	LangEN = "en"
+	LangZH = "zh"
+)
+
+// CodeOK is the code of a successful response.
+const CodeOK Code = 0
+
+// messages 每个错误码面向用户的提示，未列出的错误码使用同一 HTTP 状态的通用提示
+var messages = map[Code]map[string]string{
+	CodeOK: {LangEN: "ok", LangZH: "成功"},
+
+	CodeInvalidParam:  {LangEN: "Invalid request parameters.", LangZH: "请求参数不正确。"},
+	CodeUnauthorized:  {LangEN: "Authentication failed.", LangZH: "认证失败。"},
+	CodeForbidden:     {LangEN: "You are not allowed to do this.", LangZH: "没有操作权限。"},
+	CodeNotFound:      {LangEN: "The record does not exist.", LangZH: "记录不存在。"},
+	CodeConflict:      {LangEN: "The request conflicts with the current state.", LangZH: "请求与当前状态冲突。"},
+	CodeGone:          {LangEN: "The resource is no longer available.", LangZH: "资源已失效。"},
+	CodeUnprocessable: {LangEN: "The request cannot be processed.", LangZH: "请求无法处理。"},
+	CodeInternal:      {LangEN: "Internal server error, please try again later.", LangZH: "服务器内部错误，请稍后重试。"},
+	CodeUpstream:      {LangEN: "A dependent service is unavailable, please try again later.", LangZH: "依赖的服务暂不可用，请稍后重试。"},
+
+	CodeInvalidCursor:        {LangEN: "The page cursor is invalid.", LangZH: "分页游标无效。"},
+	CodeInvalidSort:          {LangEN: "The sort option is invalid.", LangZH: "排序条件无效。"},
+	CodeInvalidSearchQuery:   {LangEN: "The search keywords are invalid.", LangZH: "搜索关键词无效。"},
+	CodeUnsupportedFormat:    {LangEN: "The export format is not supported.", LangZH: "不支持的导出格式。"},
+	CodeExportTooLarge:       {LangEN: "Too many orders to download directly, please use an async export.", LangZH: "订单过多，请使用异步导出。"},
+	CodeInvalidShipment:      {LangEN: "The shipment is invalid.", LangZH: "包裹信息不正确。"},
+	CodeInvalidShipBatch:     {LangEN: "The shipping manifest is invalid.", LangZH: "发货清单不正确。"},
+	CodeInvalidTrackingEvent: {LangEN: "The tracking update is invalid.", LangZH: "物流轨迹不正确。"},
+	CodeValidationFailed:     {LangEN: "Some fields are invalid.", LangZH: "部分字段不正确。"},
+	CodeInvalidDateRange:     {LangEN: "The date range is invalid.", LangZH: "日期范围不正确。"},
+	CodeInvalidAddress:       {LangEN: "The shipping address is invalid.", LangZH: "收货地址不正确。"},
+	CodeInvalidOrderEdit:     {LangEN: "The order change is invalid.", LangZH: "订单修改内容不正确。"},
+	CodeInvalidOrderItem:     {LangEN: "An item in the order is invalid.", LangZH: "订单中的商品不正确。"},
+
+	CodeInvalidSignature: {LangEN: "The signature is invalid.", LangZH: "签名无效。"},
+	CodeWebhookExpired:   {LangEN: "The request has expired.", LangZH: "请求已过期。"},
+
+	CodeOrderForbidden: {LangEN: "The order does not belong to you.", LangZH: "该订单不属于当前用户。"},
+
+	CodeOrderNotFound:     {LangEN: "The order does not exist.", LangZH: "订单不存在。"},
+	CodeShipmentNotFound:  {LangEN: "The shipment does not exist.", LangZH: "包裹不存在。"},
+	CodeExportJobNotFound: {LangEN: "The export job does not exist.", LangZH: "导出任务不存在。"},
+	CodeCarrierNotFound:   {LangEN: "The carrier is not supported.", LangZH: "不支持该承运商。"},
+	CodeAddressNotFound:   {LangEN: "The saved address does not exist.", LangZH: "收货地址不存在。"},
+
+	CodeInvalidStatusTransition: {LangEN: "The order cannot move to this status.", LangZH: "订单当前状态不允许该操作。"},
+	CodeOrderStatusChanged:      {LangEN: "The order was changed by someone else, please refresh.", LangZH: "订单已被修改，请刷新后重试。"},
+	CodeOrderNotShippable:       {LangEN: "The order cannot be shipped in its current status.", LangZH: "订单当前状态不能发货。"},
+	CodeExportNotReady:          {LangEN: "The export is not finished yet.", LangZH: "导出尚未完成。"},
+	CodeOrderNotEditable:        {LangEN: "The order can no longer be changed.", LangZH: "订单当前状态不能修改。"},
+
+	CodeExportExpired: {LangEN: "The export file has expired.", LangZH: "导出文件已过期。"},
+
+	CodeInsufficientStock: {LangEN: "Some products are out of stock.", LangZH: "部分商品库存不足。"},
+	CodePaymentDeclined:   {LangEN: "The payment was declined.", LangZH: "支付失败。"},
+
+	CodeProductService: {LangEN: "The product service is unavailable, please try again later.", LangZH: "商品服务暂不可用，请稍后重试。"},
+	CodePaymentService: {LangEN: "The payment service is unavailable, please try again later.", LangZH: "支付服务暂不可用，请稍后重试。"},
+	CodeUserService:    {LangEN: "The user service is unavailable, please try again later.", LangZH: "用户服务暂不可用，请稍后重试。"},
+}
+
+// Message returns the user-facing message of the code in lang, falling back
+// to the generic message of its HTTP status and then to English.
+func Message(code Code, lang string) string {
+	msgs, ok := messages[code]
+	if !ok {
+		msgs, ok = messages[Code(code.HTTPStatus()*100)]
+	}
+	if !ok {
+		msgs = messages[CodeInternal]
+	}
+	if msg, ok := msgs[lang]; ok {
+		return msg
+	}
+	return msgs[LangEN]
+}
+
+// Lang picks the supported language from an Accept-Language header.
+func Lang(acceptLanguage string) string {
+	for _, tag := range strings.Split(acceptLanguage, ",") {
+		tag = strings.ToLower(strings.TrimSpace(strings.SplitN(tag, ";", 2)[0]))
+		switch {
+		case strings.HasPrefix(tag, LangZH):
+			return LangZH
+		case strings.HasPrefix(tag, LangEN):
+			return LangEN
+		}
+	}
+	return LangEN
+}
@@ -0,0 +1,41 @@
+package types
+
+import "strings"
+
+// Address 结构化收货地址。邮政编码以字符串保存，保留字母和前导零，按 Country 校验
+type Address struct {
+	Line1      string `json:"line1" binding:"required,max=120"`       // 街道、门牌号
+	Line2      string `json:"line2" binding:"max=120"`                // 楼层、单元号等
+	City       string `json:"city" binding:"required,max=64"`         // 城市
+	State      string `json:"state" binding:"max=64"`                 // 州/省
+	PostalCode string `json:"postal_code" binding:"postcode=Country"` // 邮政编码，如 018956、SW1A 1AA
+	Country    string `json:"country" binding:"required,country"`     // ISO 3166-1 两位国家代码，如 SG
+}
+
+// Street joins the address lines, e.g. "1 Orchard Road, #10-01".
+func (a Address) Street() string {
+	return joinNonEmpty(a.Line1, a.Line2)
+}
+
+// String formats the address on one line, e.g.
+// "1 Orchard Road, #10-01, Singapore, 238823, SG".
+func (a Address) String() string {
+	return joinNonEmpty(a.Line1, a.Line2, a.City, a.State, a.PostalCode, a.Country)
+}
+
+func joinNonEmpty(parts ...string) string {
+	nonEmpty := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part != "" {
+			nonEmpty = append(nonEmpty, part)
+		}
+	}
+	return strings.Join(nonEmpty, ", ")
+}
+
+// SavedAddress 用户服务地址簿中的地址
+type SavedAddress struct {
+	ID     int `json:"id"`
+	UserID int `json:"user_id"`
+	Address
+}
@@ -4,32 +4,40 @@ import "time"
 
 // OrderInfo service layer input
 type OrderInfo struct {
-	ReceiverFirstName string           `json:"receiver_first_name"` // 收货人姓名
-	ReceiverLastName  string           `json:"receiver_last_name"`  // 收货人姓名
-	ReceiverPhone     string           `json:"receiver_phone"`      // 收货人电话
-	ReceiverAddress   string           `json:"receiver_address"`    // 收货地址
-	ReceiverCountry   string           `json:"receiver_country"`    // 收货人国家
-	ReceiverZipCode   int              `json:"receiver_zip_code"`   // 收货人邮政编码
-	Remark            string           `json:"remark"`              // 备注
-	OrderItemList     []*OrderItemInfo `json:"order_item_list"`     // 订单商品列表
+	ReceiverFirstName string           `json:"receiver_first_name" binding:"required,max=64"`                                               // 收货人姓名
+	ReceiverLastName  string           `json:"receiver_last_name" binding:"required,max=64"`                                                // 收货人姓名
+	ReceiverPhone     string           `json:"receiver_phone" binding:"required,phone"`                                                     // 收货人电话，E.164 格式，如 +6591234567
+	AddressID         int              `json:"address_id" binding:"gte=0"`                                                                  // 地址簿中的地址，优先于 shipping_address
+	ShippingAddress   *Address         `json:"shipping_address"`                                                                            // 结构化收货地址，优先于 receiver_address 等字段
+	ReceiverAddress   string           `json:"receiver_address" binding:"required_without_all=AddressID ShippingAddress,max=256"`           // 收货地址；已废弃，使用 shipping_address
+	ReceiverCountry   string           `json:"receiver_country" binding:"required_without_all=AddressID ShippingAddress,omitempty,country"` // 收货人国家，ISO 3166-1 两位代码；已废弃，使用 shipping_address
+	ReceiverZipCode   int              `json:"receiver_zip_code" binding:"postcode=ReceiverCountry"`                                        // 收货人邮政编码；已废弃，整数无法保存字母和前导零
+	Remark            string           `json:"remark" binding:"max=256"`                                                                    // 备注
+	OrderItemList     []*OrderItemInfo `json:"order_item_list" binding:"required,min=1,max=100,distinct=ProductID,dive,required"`           // 订单商品列表，商品不能重复
 }
 
 type OrderItemInfo struct {
-	ProductID   int    `json:"product_id"`
-	ProductName string `json:"product_name"`
-	Quantity    int    `json:"quantity"`
-	Price       int    `json:"price"`
+	ProductID   int    `json:"product_id" binding:"gt=0"`
+	MerchantID  int    `json:"merchant_id" binding:"gte=0"` // 商品所属商家，由服务端按商品目录确定，传入时必须一致；不同商家的商品拆分为不同子订单
+	ProductName string `json:"product_name" binding:"max=128"`
+	ImageURL    string `json:"image_url" binding:"omitempty,url,max=512"` // 商品图片，用作订单列表缩略图；只接受商品目录 CDN 上的 https 地址
+	Quantity    int    `json:"quantity" binding:"gt=0"`
+	Price       int    `json:"price" binding:"gte=0"`
 }
 
 type OrderMessage struct {
 	UserID            int              `json:"user_id"`             // 下单用户
 	OrderID           string           `json:"order_id"`            // 订单ID
+	MerchantID        int              `json:"merchant_id"`         // 商家
+	CheckoutNo        string           `json:"checkout_no"`         // 结算单号
 	ReceiverFirstName string           `json:"receiver_first_name"` // 收货人姓名
 	ReceiverLastName  string           `json:"receiver_last_name"`  // 收货人姓名
 	ReceiverPhone     string           `json:"receiver_phone"`      // 收货人电话
+	AddressID         int              `json:"address_id"`          // 地址簿中的地址，0 表示下单时填写
+	ShippingAddress   Address          `json:"shipping_address"`    // 收货地址快照
 	ReceiverAddress   string           `json:"receiver_address"`    // 收货地址
 	ReceiverCountry   string           `json:"receiver_country"`    // 收货人国家
-	ReceiverZipCode   int              `json:"receiver_zip_code"`   // 收货人邮政编码
+	ReceiverZipCode   int              `json:"receiver_zip_code"`   // 已废弃，使用 shipping_address.postal_code
 	Remark            string           `json:"remark"`              // 备注
 	OrderItemList     []*OrderItemInfo `json:"order_item_list"`
 }
@@ -44,32 +52,59 @@ type OrderStatusChangedMessage struct {
 // list order
 type OrderInfoInList struct {
 	OrderNo           string    `json:"order_no"`
+	CheckoutNo        string    `json:"checkout_no"`         // 结算单号，同一次下单的子订单相同
+	MerchantID        int       `json:"merchant_id"`         // 商家
 	ReceiverFirstName string    `json:"receiver_first_name"` // 收货人姓名
 	ReceiverLastName  string    `json:"receiver_last_name"`  // 收货人姓名
 	ReceiverPhone     string    `json:"receiver_phone"`      // 收货人电话
 	CreateTime        time.Time `json:"create_time"`
 	TotalAmount       int       `json:"total_amount"`
 	Status            string    `json:"status"`
+	ItemCount         int       `json:"item_count,omitempty"` // 商品总件数，仅用户侧列表返回
+	Thumbnail         string    `json:"thumbnail,omitempty"`  // 首个商品的图片，仅用户侧列表返回
 }
 
 type ListOrderRequest struct {
-	UserID      int       `json:"user_id"`      // 用户ID筛选
-	OrderStatus int       `json:"order_status"` // 订单状态筛选
-	StartTime   time.Time `json:"start_time"`   // 创建时间开始范围
-	EndTime     time.Time `json:"end_time"`     // 创建时间结束范围
-	OrderNo     string    `json:"order_no"`     // 订单号筛选
-	Limit       int       `json:"limit"`        // 分页限制
-	Offset      int       `json:"offset"`       // 分页偏移
+	MerchantID        int       `json:"-"`                                                                                        // 商家范围，由调用方的 JWT 决定，0 表示不限
+	UserID            int       `json:"user_id" binding:"gte=0"`                                                                  // 用户ID筛选
+	OrderStatus       int       `json:"order_status" binding:"omitempty,oneof=1 2 3 4 5 6"`                                       // 订单状态筛选
+	Statuses          []int     `json:"statuses" binding:"max=6,dive,oneof=1 2 3 4 5 6"`                                          // 订单状态多选
+	StartTime         time.Time `json:"start_time"`                                                                               // 创建时间开始范围
+	EndTime           time.Time `json:"end_time" binding:"omitempty,gtefield=StartTime"`                                          // 创建时间结束范围
+	OrderNo           string    `json:"order_no" binding:"max=64"`                                                                // 订单号筛选
+	MinAmount         *int      `json:"min_amount" binding:"omitempty,gte=0"`                                                     // 总金额下限（含）
+	MaxAmount         *int      `json:"max_amount" binding:"omitempty,gte=0"`                                                     // 总金额上限（含）
+	ReceiverName      string    `json:"receiver_name" binding:"max=128"`                                                          // 收货人姓名前缀，"名 姓" 时分别匹配
+	ReceiverPhone     string    `json:"receiver_phone" binding:"max=16"`                                                          // 收货人电话前缀
+	ReceiverCountry   string    `json:"receiver_country" binding:"omitempty,country"`                                             // 收货人国家
+	ProductID         int       `json:"product_id" binding:"gte=0"`                                                               // 包含该商品的订单
+	ProductName       string    `json:"product_name" binding:"max=128"`                                                           // 包含名称中有该关键字的商品的订单
+	LogisticsNo       string    `json:"logistics_no" binding:"max=64"`                                                            // 物流单号
+	PayStartTime      time.Time `json:"pay_start_time"`                                                                           // 支付时间开始范围
+	PayEndTime        time.Time `json:"pay_end_time" binding:"omitempty,gtefield=PayStartTime"`                                   // 支付时间结束范围
+	DeliveryStartTime time.Time `json:"delivery_start_time"`                                                                      // 发货时间开始范围
+	DeliveryEndTime   time.Time `json:"delivery_end_time" binding:"omitempty,gtefield=DeliveryStartTime"`                         // 发货时间结束范围
+	SortBy            string    `json:"sort_by" binding:"omitempty,oneof=create_time pay_time delivery_time total_amount status"` // 排序字段：create_time（默认）/ pay_time / delivery_time / total_amount / status
+	SortOrder         string    `json:"sort_order"`                                                                               // 排序方向：desc（默认）/ asc
+	Limit             int       `json:"limit" binding:"gte=0"`                                                                    // 分页限制
+	Offset            int       `json:"offset" binding:"gte=0"`                                                                   // 分页偏移，传 cursor 时忽略
+	Cursor            string    `json:"cursor"`                                                                                   // 游标分页：上一页返回的 next_cursor，按 pay_time / delivery_time 排序时不支持
+	CountMode         string    `json:"count_mode" binding:"omitempty,oneof=exact estimate"`                                      // 总数统计方式：exact（默认）/ estimate
 }
 
 type ListOrderResponse struct {
-	Orders []*OrderInfoInList `json:"orders"`
-	Total  int                `json:"total"`
+	Orders         []*OrderInfoInList `json:"orders"`
+	Total          int                `json:"total"`           // 符合条件的订单总数
+	TotalEstimated bool               `json:"total_estimated"` // total 是否为估算值
+	NextCursor     string             `json:"next_cursor"`     // 下一页游标，没有更多时为空
+	HasMore        bool               `json:"has_more"`        // 是否还有下一页
 }
 
 type OrderDetail struct {
 	// 基本订单信息
 	OrderNo      string    `json:"order_no"`      // 订单编号
+	CheckoutNo   string    `json:"checkout_no"`   // 结算单号
+	MerchantID   int       `json:"merchant_id"`   // 商家
 	UserID       int       `json:"user_id"`       // 下单用户
 	Status       int       `json:"status"`        // 订单状态
 	StatusName   string    `json:"status_name"`   // 订单状态名称
@@ -81,36 +116,76 @@ type OrderDetail struct {
 	CreateTime   time.Time `json:"create_time"`   // 创建时间
 	UpdateTime   time.Time `json:"update_time"`   // 更新时间
 	DeliveryTime time.Time `json:"delivery_time"` // 发货时间
+	ArrivedTime  time.Time `json:"arrived_time"`  // 承运商报告全部包裹签收的时间
 	ConfirmTime  time.Time `json:"confirm_time"`  // 收货确认时间
+	RefundAmount int       `json:"refund_amount"` // 已退款金额，发货前删除商品时退还
 
 	// 收货信息
-	ReceiverFirstName string `json:"receiver_first_name"` // 收货人姓名
-	ReceiverLastName  string `json:"receiver_last_name"`  // 收货人姓名
-	ReceiverPhone     string `json:"receiver_phone"`      // 收货人电话
-	ReceiverAddress   string `json:"receiver_address"`    // 收货地址
-	ReceiverCountry   string `json:"receiver_country"`    // 收货人国家
-	ReceiverZipCode   int    `json:"receiver_zip_code"`   // 收货人邮政编码
+	ReceiverFirstName string  `json:"receiver_first_name"` // 收货人姓名
+	ReceiverLastName  string  `json:"receiver_last_name"`  // 收货人姓名
+	ReceiverPhone     string  `json:"receiver_phone"`      // 收货人电话
+	AddressID         int     `json:"address_id"`          // 地址簿中的地址，0 表示下单时填写
+	ShippingAddress   Address `json:"shipping_address"`    // 下单时的收货地址快照，不随地址簿修改变化
+	ReceiverAddress   string  `json:"receiver_address"`    // 收货地址
+	ReceiverCountry   string  `json:"receiver_country"`    // 收货人国家
+	ReceiverZipCode   int     `json:"receiver_zip_code"`   // 已废弃，使用 shipping_address.postal_code
 
 	// 其他信息
 	Remark      string `json:"remark"`       // 备注
 	LogisticsNo string `json:"logistics_no"` // 物流单号
+	Carrier     string `json:"carrier"`      // 承运商
 
 	// 订单商品列表
 	OrderItems []*OrderItemDetail `json:"order_items"`
 
 	// 订单状态变更日志
 	StatusLogs []*OrderStatusLogDetail `json:"status_logs"`
+
+	// 包裹列表
+	Shipments []*ShipmentDetail `json:"shipments"`
 }
 
 type OrderItemDetail struct {
 	ID          int       `json:"id"`           // 订单商品ID
 	ProductID   int       `json:"product_id"`   // 商品ID
 	ProductName string    `json:"product_name"` // 商品名称
+	ImageURL    string    `json:"image_url"`    // 商品图片
 	Price       int       `json:"price"`        // 商品单价
 	Quantity    int       `json:"quantity"`     // 商品数量
 	TotalPrice  int       `json:"total_price"`  // 商品总价
 	CreateTime  time.Time `json:"create_time"`  // 创建时间
 	UpdateTime  time.Time `json:"update_time"`  // 更新时间
+
+	ShippedQuantity int `json:"shipped_quantity"` // 已发货数量
+}
+
+type ShipmentDetail struct {
+	ID          int                   `json:"id"`           // 包裹ID
+	CarrierCode string                `json:"carrier_code"` // 承运商编码
+	CarrierName string                `json:"carrier_name"` // 承运商名称
+	TrackingNo  string                `json:"tracking_no"`  // 物流单号
+	TrackingURL string                `json:"tracking_url"` // 物流查询链接，承运商未配置模板时为空
+	Weight      int                   `json:"weight"`       // 包裹重量，克
+	LabelURL    string                `json:"label_url"`    // 面单地址
+	CreateTime  time.Time             `json:"create_time"`  // 发货时间
+	Items       []*ShipmentItemDetail `json:"items"`        // 包裹内商品
+
+	DeliveredTime  time.Time              `json:"delivered_time"`            // 承运商报告的签收时间
+	TrackingEvents []*TrackingEventDetail `json:"tracking_events,omitempty"` // 物流轨迹，仅用户查询订单详情时返回
+}
+
+type TrackingEventDetail struct {
+	Status      string    `json:"status"`      // 轨迹状态：in_transit, out_for_delivery, delivered, exception
+	Description string    `json:"description"` // 描述
+	Location    string    `json:"location"`    // 地点
+	EventTime   time.Time `json:"event_time"`  // 发生时间
+}
+
+type ShipmentItemDetail struct {
+	OrderProductID int    `json:"order_product_id"` // 订单商品ID
+	ProductID      int    `json:"product_id"`       // 商品ID
+	ProductName    string `json:"product_name"`     // 商品名称
+	Quantity       int    `json:"quantity"`         // 本包裹内的数量
 }
 
 type OrderStatusLogDetail struct {
@@ -122,14 +197,165 @@ type OrderStatusLogDetail struct {
 }
 
 type CustomerListOrderRequest struct {
-	StartTime time.Time `json:"start_time"` // 创建时间开始范围
-	EndTime   time.Time `json:"end_time"`   // 创建时间结束范围
-	Limit     int       `json:"limit"`      // 分页限制
-	Offset    int       `json:"offset"`     // 分页偏移
+	Statuses    []int     `json:"statuses" binding:"max=6,dive,oneof=1 2 3 4 5 6"` // 订单状态多选
+	ProductName string    `json:"product_name" binding:"max=128"`                  // 商品名称关键字
+	StartTime   time.Time `json:"start_time"`                                      // 创建时间开始范围
+	EndTime     time.Time `json:"end_time"`                                        // 创建时间结束范围
+	Limit       int       `json:"limit"`                                           // 分页限制
+	Offset      int       `json:"offset"`                                          // 分页偏移，传 cursor 时忽略
+	Cursor      string    `json:"cursor"`                                          // 游标分页：上一页返回的 next_cursor
+	CountMode   string    `json:"count_mode"`                                      // 总数统计方式：exact（默认）/ estimate
+}
+
+// buy again
+type ReorderQuote struct {
+	SourceOrderNo string           `json:"source_order_no"` // 原订单号
+	Items         []*ReorderItem   `json:"items"`           // 原订单的每个商品及其当前情况
+	OrderItemList []*OrderItemInfo `json:"order_item_list"` // 可购买的商品，可直接用于创建订单
+	ItemTotal     int              `json:"item_total"`      // 商品总价，按当前价格
+	ShippingFee   int              `json:"shipping_fee"`    // 运费，按商家分别计算后合计
+	Tax           int              `json:"tax"`             // 税
+	TotalAmount   int              `json:"total_amount"`    // 应付总额
+}
+
+type ReorderItem struct {
+	ProductID     int    `json:"product_id"`
+	MerchantID    int    `json:"merchant_id"`
+	ProductName   string `json:"product_name"`
+	ImageURL      string `json:"image_url"`
+	Quantity      int    `json:"quantity"`       // 可购买数量，库存不足时少于原数量
+	OrderQuantity int    `json:"order_quantity"` // 原订单数量
+	Price         int    `json:"price"`          // 当前价格
+	OrderPrice    int    `json:"order_price"`    // 原订单价格
+	Available     bool   `json:"available"`      // 是否可购买
+	Reason        string `json:"reason"`         // 不可购买或数量减少的原因：unavailable / out_of_stock / insufficient_stock
 }
 
 type ShipOrderRequest struct {
+	CarrierCode string `json:"carrier_code" binding:"required,max=32"` // 承运商编码，见配置 carriers
+	TrackingNo  string `json:"tracking_no" binding:"required,max=64"`
+}
+
+// shipment
+type CreateShipmentRequest struct {
+	MerchantID  int                    `json:"-"`                                                             // 商家范围，由调用方的 JWT 决定
+	CarrierCode string                 `json:"carrier_code" binding:"max=32"`                                 // 承运商编码
+	TrackingNo  string                 `json:"tracking_no" binding:"required,max=64"`                         // 物流单号
+	Weight      int                    `json:"weight" binding:"gte=0"`                                        // 包裹重量，克
+	LabelURL    string                 `json:"label_url" binding:"omitempty,url,max=512"`                     // 面单地址
+	Items       []*ShipmentItemRequest `json:"items" binding:"max=100,distinct=OrderProductID,dive,required"` // 包裹内商品，为空时发出全部未发货商品
+}
+
+type ShipmentItemRequest struct {
+	OrderProductID int `json:"order_product_id" binding:"gt=0"` // 订单商品ID，即订单详情 order_items 中的 id
+	Quantity       int `json:"quantity" binding:"gt=0"`
+}
+
+// order edit
+type EditOrderRequest struct {
+	ReceiverFirstName *string  `json:"receiver_first_name" binding:"omitempty,min=1,max=64"` // 收货人姓名，不传表示不修改，下同
+	ReceiverLastName  *string  `json:"receiver_last_name" binding:"omitempty,min=1,max=64"`  // 收货人姓名
+	ReceiverPhone     *string  `json:"receiver_phone" binding:"omitempty,phone"`             // 收货人电话，E.164 格式
+	ShippingAddress   *Address `json:"shipping_address"`                                     // 收货地址，整体替换
+	Remark            *string  `json:"remark" binding:"omitempty,max=256"`                   // 备注，传空字符串清空
+	RemoveItemIDs     []int    `json:"remove_item_ids" binding:"max=100,unique,dive,gt=0"`   // 要删除的订单商品ID，即订单详情 order_items 中的 id
+	Reason            string   `json:"reason" binding:"required,max=256"`                    // 修改原因，记入审计日志
+}
+
+// FieldChange 订单一个字段修改前后的值
+type FieldChange struct {
+	Field  string `json:"field"`  // 字段，如 receiver_phone、shipping_address.city、items[id=3]
+	Before any    `json:"before"` // 修改前的值
+	After  any    `json:"after"`  // 修改后的值，删除商品时为 null
+}
+
+type EditOrderResponse struct {
+	Order        *OrderDetail   `json:"order"`         // 修改后的订单
+	Changes      []*FieldChange `json:"changes"`       // 本次修改的字段
+	RefundAmount int            `json:"refund_amount"` // 本次修改退还的金额
+	RefundStatus string         `json:"refund_status"` // 退款消息发送状态：pending 待重试，sent 已送达；没有退款时为空
+}
+
+type OrderAuditEntry struct {
+	ID           int            `json:"id"`
+	OperatorID   int            `json:"operator_id"`   // 修改订单的用户
+	Action       string         `json:"action"`        // 操作，目前只有 edit
+	Reason       string         `json:"reason"`        // 修改原因
+	Changes      []*FieldChange `json:"changes"`       // 字段修改前后的值
+	RefundAmount int            `json:"refund_amount"` // 本次修改退还的金额
+	RefundStatus string         `json:"refund_status"` // 退款消息发送状态：pending 待重试，sent 已送达；没有退款时为空
+	CreateTime   time.Time      `json:"create_time"`   // 修改时间
+}
+
+// OrderUpdatedMessage 订单内容被商家修改，状态不变
+type OrderUpdatedMessage struct {
+	OrderNo      string         `json:"order_no"`
+	MerchantID   int            `json:"merchant_id"`
+	OperatorID   int            `json:"operator_id"`
+	TotalAmount  int            `json:"total_amount"`  // 修改后的总金额
+	RefundAmount int            `json:"refund_amount"` // 本次修改退还的金额
+	Changes      []*FieldChange `json:"changes"`
+}
+
+// OrderRefundMessage 请求支付服务向顾客退还部分货款。消息可能重复投递，按 refund_id 去重
+type OrderRefundMessage struct {
+	RefundID   int    `json:"refund_id"` // 退款编号，即订单修改记录的 ID
+	OrderNo    string `json:"order_no"`
+	CheckoutNo string `json:"checkout_no"` // 结算单号，即支付时的 biz_id
+	UserID     int    `json:"user_id"`
+	MerchantID int    `json:"merchant_id"`
+	Amount     int    `json:"amount"` // 退款金额
+	Reason     string `json:"reason"`
+}
+
+// tracking webhook
+type TrackingWebhookRequest struct {
+	TrackingNo string                  `json:"tracking_no"` // 物流单号
+	Events     []*TrackingEventRequest `json:"events"`
+}
+
+type TrackingEventRequest struct {
+	Status      string    `json:"status"`      // 轨迹状态：in_transit, out_for_delivery, delivered, exception
+	Description string    `json:"description"` // 描述
+	Location    string    `json:"location"`    // 地点
+	Time        time.Time `json:"time"`        // 发生时间，RFC 3339
+}
+
+// batch ship
+type BatchShipItem struct {
+	OrderNo    string `json:"order_no"`
+	Carrier    string `json:"carrier"`     // 承运商，可为空
+	TrackingNo string `json:"tracking_no"` // 物流单号
+}
+
+type BatchShipRequest struct {
+	MerchantID   int              `json:"-"`                                      // 商家范围，由调用方的 JWT 决定
+	AllOrNothing bool             `json:"all_or_nothing"`                         // 任意一行失败则全部不发货
+	Items        []*BatchShipItem `json:"items" binding:"required,min=1,max=500"` // 各行的字段在发货时逐行校验，结果按行返回
+}
+
+type BatchShipRowResult struct {
+	Row        int    `json:"row"` // 行号，从 1 开始，CSV 不含表头
+	OrderNo    string `json:"order_no"`
+	Carrier    string `json:"carrier"`
 	TrackingNo string `json:"tracking_no"`
+	Result     string `json:"result"` // shipped / failed / skipped（all_or_nothing 时因其他行失败未执行）
+	Error      string `json:"error"`  // 失败原因
+}
+
+type BatchShipResponse struct {
+	Total     int                   `json:"total"`
+	Succeeded int                   `json:"succeeded"`
+	Failed    int                   `json:"failed"`
+	Results   []*BatchShipRowResult `json:"results"`
+}
+
+// checkout
+type CheckoutDetail struct {
+	CheckoutNo  string         `json:"checkout_no"`  // 结算单号
+	TotalAmount int            `json:"total_amount"` // 各子订单总金额之和
+	CreateTime  time.Time      `json:"create_time"`  // 下单时间
+	Orders      []*OrderDetail `json:"orders"`       // 各商家的子订单
 }
 
 type ConfirmOrderRequest struct {
@@ -147,3 +373,137 @@ type OrderStats struct {
 	TotalCustomers   int `json:"total_customers"`
 	AvgSalesPerOrder int `json:"avg_sales_per_order"`
 }
+
+// order search
+type OrderSearchRequest struct {
+	MerchantID int    `form:"-"`                                            // 商家范围，由调用方的 JWT 决定
+	Q          string `form:"q" binding:"required,max=256"`                 // 关键词：订单号、收货人、电话、地址、商品名、备注、物流单号
+	Status     int    `form:"status" binding:"omitempty,oneof=1 2 3 4 5 6"` // 订单状态筛选
+	Country    string `form:"country" binding:"omitempty,country"`          // 收货国家筛选
+	Month      string `form:"month" binding:"omitempty,datetime=2006-01"`   // 下单月份筛选，格式 2006-01
+	Limit      int    `form:"limit" binding:"gte=0"`                        // 分页限制
+	Offset     int    `form:"offset" binding:"gte=0"`                       // 分页偏移
+}
+
+type OrderSearchHit struct {
+	OrderNo         string            `json:"order_no"`
+	UserID          int               `json:"user_id"`
+	Status          string            `json:"status"`
+	TotalAmount     int               `json:"total_amount"`
+	ReceiverName    string            `json:"receiver_name"`
+	ReceiverCountry string            `json:"receiver_country"`
+	CreateTime      time.Time         `json:"create_time"`
+	Score           float64           `json:"score"`      // 相关度
+	Highlights      map[string]string `json:"highlights"` // 字段 -> 命中片段，命中词以 <em></em> 包裹
+}
+
+type FacetBucket struct {
+	Value string `json:"value"`          // 筛选时传入的值
+	Name  string `json:"name,omitempty"` // 展示名称，仅状态分面
+	Count int64  `json:"count"`
+}
+
+type OrderSearchResponse struct {
+	Hits   []*OrderSearchHit        `json:"hits"`
+	Total  int64                    `json:"total"`
+	Facets map[string][]FacetBucket `json:"facets"` // status / country / month
+}
+
+// order export
+type ExportOrderRequest struct {
+	ListOrderRequest        // 筛选与排序条件，分页字段忽略
+	Format           string `json:"format" binding:"omitempty,oneof=csv xlsx"` // 导出格式：csv（默认）/ xlsx
+	Async            bool   `json:"async"`                                     // 异步导出：立即返回任务，完成后通过下载链接获取文件
+}
+
+type ExportJobInfo struct {
+	JobID       string    `json:"job_id"`
+	Format      string    `json:"format"`
+	Status      string    `json:"status"`       // pending / running / succeeded / failed / expired
+	RowCount    int       `json:"row_count"`    // 导出行数，每个订单商品一行
+	Error       string    `json:"error"`        // 失败原因
+	CreateTime  time.Time `json:"create_time"`  // 创建时间
+	FinishTime  time.Time `json:"finish_time"`  // 完成时间
+	ExpireTime  time.Time `json:"expire_time"`  // 文件过期时间
+	DownloadURL string    `json:"download_url"` // 下载链接，仅 succeeded 时返回
+}
+
+// order analytics
+type AnalyticsRequest struct {
+	MerchantID int    `form:"-"`                                                 // 商家范围，由调用方的 JWT 决定
+	StartDate  string `form:"start_date" binding:"required,datetime=2006-01-02"` // 起始日期（含），按 tz 时区解释
+	EndDate    string `form:"end_date" binding:"required,datetime=2006-01-02"`   // 结束日期（含），按 tz 时区解释
+	TZ         string `form:"tz" binding:"omitempty,timezone"`                   // IANA 时区，如 Asia/Singapore，默认服务器时区
+}
+
+type SalesSeriesRequest struct {
+	AnalyticsRequest
+	Interval string `form:"interval" binding:"omitempty,oneof=day week month"` // 统计周期：day（默认）/ week（周一开始）/ month
+}
+
+type SalesPoint struct {
+	Period  string    `json:"period"`  // 周期标识：日、周为起始日期 2006-01-02，月为 2006-01
+	Start   time.Time `json:"start"`   // 周期起始时间
+	Orders  int64     `json:"orders"`  // 已支付订单数
+	Revenue int64     `json:"revenue"` // 销售额
+}
+
+type SalesSeriesResponse struct {
+	Interval     string        `json:"interval"`
+	TZ           string        `json:"tz"`
+	TotalOrders  int64         `json:"total_orders"`
+	TotalRevenue int64         `json:"total_revenue"`
+	Points       []*SalesPoint `json:"points"` // 范围内每个周期一个点，没有订单的周期为 0
+}
+
+type TopProductsRequest struct {
+	AnalyticsRequest
+	SortBy string `form:"sort_by" binding:"omitempty,oneof=revenue quantity"` // 排序：revenue（默认）/ quantity
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`            // 返回数量，默认 10
+}
+
+type ProductSales struct {
+	ProductID   int    `json:"product_id"`
+	ProductName string `json:"product_name"`
+	Quantity    int64  `json:"quantity"` // 销量
+	Revenue     int64  `json:"revenue"`  // 销售额
+	Orders      int64  `json:"orders"`   // 包含该商品的订单数
+}
+
+type TopProductsResponse struct {
+	SortBy   string          `json:"sort_by"`
+	Products []*ProductSales `json:"products"`
+}
+
+type FunnelStage struct {
+	Stage  string  `json:"stage"`  // placed / paid / shipped / delivered
+	Orders int64   `json:"orders"` // 到达该阶段的订单数
+	Rate   float64 `json:"rate"`   // 占下单数的比例
+}
+
+type StatusFunnelResponse struct {
+	Stages       []*FunnelStage   `json:"stages"`
+	Canceled     int64            `json:"canceled"`      // 取消的订单数
+	CancelRate   float64          `json:"cancel_rate"`   // 取消订单占下单数的比例
+	StatusCounts map[string]int64 `json:"status_counts"` // 各状态的订单数
+}
+
+type AnalyticsSummary struct {
+	Customers          int64   `json:"customers"`              // 有已支付订单的用户数
+	RepeatCustomers    int64   `json:"repeat_customers"`       // 其中下单两次及以上的用户数
+	RepeatCustomerRate float64 `json:"repeat_customer_rate"`   // 复购率
+	ShippedOrders      int64   `json:"shipped_orders"`         // 已发货订单数
+	AvgTimeToShipHours float64 `json:"avg_time_to_ship_hours"` // 支付到首次发货的平均小时数
+}
+
+type CountrySales struct {
+	Country   string  `json:"country"`
+	Orders    int64   `json:"orders"`
+	Revenue   int64   `json:"revenue"`
+	Customers int64   `json:"customers"`
+	Share     float64 `json:"share"` // 销售额占比
+}
+
+type CountryBreakdownResponse struct {
+	Countries []*CountrySales `json:"countries"`
+}
@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/config"
+)
+
+// trackingNoPlaceholder is replaced by the tracking number in a carrier's
+// tracking URL template.
+const trackingNoPlaceholder = "{tracking_no}"
+
+type Carrier struct {
+	Name          string
+	TrackingURL   string // 查询链接模板，{tracking_no} 会被替换为物流单号
+	WebhookSecret string // 物流回调签名密钥，为空时不接受该承运商的回调
+}
+
+// Carriers maps a carrier code to its display name and tracking URL template.
+type Carriers map[string]Carrier
+
+func NewCarriers(cfg map[string]*config.CarrierConfig) Carriers {
+	carriers := make(Carriers, len(cfg))
+	for code, c := range cfg {
+		if c == nil {
+			continue
+		}
+		carriers[NormalizeCarrierCode(code)] = Carrier{Name: c.Name, TrackingURL: c.TrackingURL, WebhookSecret: c.WebhookSecret}
+	}
+	return carriers
+}
+
+// NormalizeCarrierCode lower-cases the code, matching how the config keys are read.
+func NormalizeCarrierCode(code string) string {
+	return strings.ToLower(strings.TrimSpace(code))
+}
+
+// Name returns the display name of the carrier, or the code itself when the
+// carrier is not configured.
+func (c Carriers) Name(code string) string {
+	if carrier, ok := c[NormalizeCarrierCode(code)]; ok && carrier.Name != "" {
+		return carrier.Name
+	}
+	return code
+}
+
+// TrackingURL builds the tracking page URL of a parcel; it is empty when the
+// carrier has no template.
+func (c Carriers) TrackingURL(code, trackingNo string) string {
+	carrier, ok := c[NormalizeCarrierCode(code)]
+	if !ok || carrier.TrackingURL == "" || trackingNo == "" {
+		return ""
+	}
+	return strings.ReplaceAll(carrier.TrackingURL, trackingNoPlaceholder, url.QueryEscape(trackingNo))
+}
+
+// WebhookSecret returns the secret the carrier signs its tracking webhooks with.
+func (c Carriers) WebhookSecret(code string) (string, bool) {
+	carrier, ok := c[NormalizeCarrierCode(code)]
+	return carrier.WebhookSecret, ok && carrier.WebhookSecret != ""
+}
@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/config"
+)
+
+func TestCarriers(t *testing.T) {
+	carriers := NewCarriers(map[string]*config.CarrierConfig{
+		"dhl": {Name: "DHL Express", TrackingURL: "https://www.dhl.com/track?id={tracking_no}"},
+		"ups": {Name: "UPS"},
+	})
+
+	if got := carriers.TrackingURL("DHL", "JD 001"); got != "https://www.dhl.com/track?id=JD+001" {
+		t.Errorf("TrackingURL() = %s", got)
+	}
+	if got := carriers.TrackingURL("ups", "1Z"); got != "" {
+		t.Errorf("expected no URL without template, got %s", got)
+	}
+	if got := carriers.Name("dhl"); got != "DHL Express" {
+		t.Errorf("Name() = %s", got)
+	}
+	if got := carriers.Name("local"); got != "local" {
+		t.Errorf("expected unknown carrier to keep its code, got %s", got)
+	}
+}
@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/errs"
+)
+
+var ErrInvalidCursor = errs.New(errs.CodeInvalidCursor, "invalid cursor")
+
+type orderCursor struct {
+	SortBy string `json:"s,omitempty"`
+	Value  int64  `json:"v"` // 时间字段为 unix nano
+	ID     int    `json:"id"`
+}
+
+// EncodeOrderCursor turns the sort value and id of the last order on a page
+// into an opaque cursor for the next page.
+func EncodeOrderCursor(sortBy string, value int64, id int) string {
+	raw, _ := json.Marshal(orderCursor{SortBy: sortBy, Value: value, ID: id})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeOrderCursor parses a cursor returned by EncodeOrderCursor.
+func DecodeOrderCursor(cursor string) (sortBy string, value int64, id int, err error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", 0, 0, ErrInvalidCursor
+	}
+	var c orderCursor
+	if err = json.Unmarshal(raw, &c); err != nil || c.ID <= 0 {
+		return "", 0, 0, ErrInvalidCursor
+	}
+	return c.SortBy, c.Value, c.ID, nil
+}
@@ -0,0 +1,24 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestOrderCursor_RoundTrip(t *testing.T) {
+	sortBy, value, id, err := DecodeOrderCursor(EncodeOrderCursor("total_amount", 1999, 42))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %s", err.Error())
+	}
+	if sortBy != "total_amount" || value != 1999 || id != 42 {
+		t.Errorf("Expected (total_amount, 1999, 42), got: (%s, %d, %d)", sortBy, value, id)
+	}
+}
+
+func TestDecodeOrderCursor_Invalid(t *testing.T) {
+	for _, cursor := range []string{"not-base64!", "bm90IGpzb24", "e30"} {
+		if _, _, _, err := DecodeOrderCursor(cursor); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("Expected ErrInvalidCursor for %q, got: %v", cursor, err)
+		}
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"errors"
 	"time"
 
-	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/dao/redis"
 	goredis "github.com/redis/go-redis/v9"
 )
 
@@ -22,16 +21,16 @@ type Locker interface {
 
 // DistributedLock represents a Redis-based distributed lock
 type DistributedLock struct {
+	client     *goredis.Client
 	key        string
 	value      string
 	expiration time.Duration
 }
 
 var (
-	MyDistributedLock *DistributedLock
-	ErrLockFailed     = errors.New("failed to acquire lock")
-	ErrUnlockFailed   = errors.New("failed to release lock")
-	ErrLockNotHeld    = errors.New("lock not held by this instance")
+	ErrLockFailed   = errors.New("failed to acquire lock")
+	ErrUnlockFailed = errors.New("failed to release lock")
+	ErrLockNotHeld  = errors.New("lock not held by this instance")
 )
 
 // Lua script for atomic unlock (only unlock if the lock is held by this instance)
@@ -43,17 +42,18 @@ else
 end
 `
 
-// GetDistributedLock creates a new distributed lock instance
+// NewDistributedLock creates a new distributed lock instance
+// client: the Redis client holding the lock
 // key: the lock key in Redis
 // value: unique identifier for this lock holder (e.g., UUID or instance ID)
 // expiration: lock TTL to prevent deadlock if holder crashes
-func GetDistributedLock(key string, value string, expiration time.Duration) *DistributedLock {
-	MyDistributedLock = &DistributedLock{
+func NewDistributedLock(client *goredis.Client, key string, value string, expiration time.Duration) *DistributedLock {
+	return &DistributedLock{
+		client:     client,
 		key:        key,
 		value:      value,
 		expiration: expiration,
 	}
-	return MyDistributedLock
 }
 
 // Lock attempts to acquire the distributed lock using SET NX EX
@@ -62,7 +62,7 @@ func (l *DistributedLock) Lock(ctx context.Context) error {
 	// SET key value NX EX expiration
 	// NX: only set if key does not exist
 	// EX: set expiration time in seconds
-	result, err := redis.RedisClient.SetNX(ctx, l.key, l.value, l.expiration).Result()
+	result, err := l.client.SetNX(ctx, l.key, l.value, l.expiration).Result()
 	if err != nil {
 		return err
 	}
@@ -78,7 +78,7 @@ func (l *DistributedLock) Lock(ctx context.Context) error {
 // Only succeeds if the lock is held by this instance (value matches)
 func (l *DistributedLock) Unlock(ctx context.Context) error {
 	script := goredis.NewScript(unlockScript)
-	result, err := script.Run(ctx, redis.RedisClient, []string{l.key}, l.value).Result()
+	result, err := script.Run(ctx, l.client, []string{l.key}, l.value).Result()
 	if err != nil {
 		return err
 	}
@@ -13,8 +13,16 @@ var (
 
 // GenerateOrderID 生成唯一订单号，格式 No-20251004-163102-001
 func GenerateOrderID() string {
+	return generateID("No-")
+}
+
+// GenerateCheckoutNo 生成多商家结算单号，格式 Co-20251004-163102-001
+func GenerateCheckoutNo() string {
+	return generateID("Co-")
+}
+
+func generateID(prefix string) string {
 	now := time.Now()
-	prefix := "No-"
 	timeStr := now.Format("20060102-150405") // 年月日-时分秒
 	key := now.Format("20060102150405")      // 用于计数的秒级key
 
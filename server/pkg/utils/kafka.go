@@ -2,143 +2,485 @@ package utils
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/config"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/metrics"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/types"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/dao"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/tracing"
 	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Writer interface {
 	SendMsg(ctx context.Context, topic, key, value string) error
+	// SendMsgSync waits for the broker to acknowledge the message whatever the
+	// producer mode, for messages the caller must know were delivered.
+	SendMsgSync(ctx context.Context, topic, key, value string) error
 }
 
 type MyWriter struct {
-	kafkaWriter *kafka.Writer
+	kafkaWriter  *kafka.Writer
+	conn         *KafkaConn
+	mode         string
+	writeTimeout time.Duration
+	batchSize    int
+	batchTimeout time.Duration
+
+	// msgCh buffers messages in async and batch mode, drained by run
+	msgCh  chan kafka.Message
+	mu     sync.RWMutex
+	closed bool
+	wg     sync.WaitGroup
 }
 
+const (
+	ProducerModeSync  = "sync"
+	ProducerModeAsync = "async"
+	ProducerModeBatch = "batch"
+
+	defaultBufferSize   = 1000
+	defaultBatchSize    = 100
+	defaultBatchTimeout = 10 * time.Millisecond
+	defaultWriteTimeout = 5 * time.Second
+
+	// 消费失败时按指数退避重试
+	defaultConsumeAttempts   = 5
+	defaultConsumeBackoff    = 200 * time.Millisecond
+	defaultConsumeMaxBackoff = 30 * time.Second
+)
+
 var (
-	writer     *MyWriter
-	writerOnce sync.Once
-	reader     *MyConsumer
-	readerOnce sync.Once
+	ErrProducerBufferFull = errors.New("kafka producer buffer is full")
+	ErrProducerClosed     = errors.New("kafka producer is closed")
 )
 
+// MessageHandler handles one consumed message. A message whose handler keeps
+// returning an error is retried with backoff and then skipped, so handlers
+// should return an error only for failures worth retrying.
+type MessageHandler func(ctx context.Context, msg kafka.Message) error
+
 type MyConsumer struct {
-	r           *kafka.Reader
-	orderLogDao dao.OrderLogDao
-}
+	r           kafkaReader
+	handler     MessageHandler
+	maxAttempts int
+	backoff     time.Duration
+	maxBackoff  time.Duration
+	metrics     *metrics.KafkaConsumerMetrics
 
-func InitKafka() {
-	initKafkaWriter()
-	initKafkaReader()
+	// failure 最近一次读取、处理或提交失败的原因，成功后清空；作为健康检查结果
+	failure atomic.Pointer[error]
 }
 
-func CloseKafka() {
-	closeKafkaWriter()
-	closeKafkaReader()
+// kafkaReader is the part of *kafka.Reader the consumer uses.
+type kafkaReader interface {
+	FetchMessage(ctx context.Context) (kafka.Message, error)
+	CommitMessages(ctx context.Context, msgs ...kafka.Message) error
+	Stats() kafka.ReaderStats
+	Close() error
 }
 
-func initKafkaWriter() {
-	writerOnce.Do(func() {
-		brokerAddr := fmt.Sprintf("%s:%d", config.Config.KafkaConfig.Host, config.Config.KafkaConfig.Port)
-		kafkaWriter := &kafka.Writer{
-			Addr:                   kafka.TCP(brokerAddr),
-			Balancer:               &kafka.LeastBytes{},
-			RequiredAcks:           kafka.RequireAll,
-			Async:                  false,
-			AllowAutoTopicCreation: true,
-		}
-		writer = &MyWriter{
-			kafkaWriter: kafkaWriter,
+// NewWriter creates the producer. In async and batch mode it starts the
+// goroutine draining the buffer; Close flushes it.
+func NewWriter(conn *KafkaConn, cfg *config.KafkaProducer) *MyWriter {
+	if cfg == nil {
+		cfg = &config.KafkaProducer{}
+	}
+	w := &MyWriter{
+		conn:         conn,
+		mode:         cfg.Mode,
+		writeTimeout: time.Duration(cfg.WriteTimeout) * time.Millisecond,
+		batchSize:    cfg.BatchSize,
+		batchTimeout: time.Duration(cfg.BatchTimeout) * time.Millisecond,
+	}
+	if w.mode == "" {
+		w.mode = ProducerModeAsync
+	}
+	if w.writeTimeout <= 0 {
+		w.writeTimeout = defaultWriteTimeout
+	}
+	if w.batchSize <= 0 {
+		w.batchSize = defaultBatchSize
+	}
+	if w.batchTimeout <= 0 {
+		w.batchTimeout = defaultBatchTimeout
+	}
+	w.kafkaWriter = &kafka.Writer{
+		Addr:                   kafka.TCP(conn.brokers...),
+		Transport:              conn.transport(),
+		Balancer:               &kafka.LeastBytes{},
+		RequiredAcks:           kafka.RequireAll,
+		Async:                  false,
+		AllowAutoTopicCreation: false,
+		BatchSize:              w.batchSize,
+		BatchTimeout:           w.batchTimeout,
+	}
+	if w.mode != ProducerModeSync {
+		bufferSize := cfg.BufferSize
+		if bufferSize <= 0 {
+			bufferSize = defaultBufferSize
 		}
-	})
+		w.msgCh = make(chan kafka.Message, bufferSize)
+		w.wg.Add(1)
+		go w.run()
+	}
+	return w
+}
+
+// SendMsg delivers a message according to the producer mode.
+// In sync mode the write happens on a context detached from ctx, so a finished
+// HTTP request does not cancel it, and the broker error is returned to the caller.
+// In async and batch mode the message is buffered and ErrProducerBufferFull is
+// returned instead of blocking when the buffer is full.
+func (myWriter *MyWriter) SendMsg(ctx context.Context, topic, key, value string) error {
+	return myWriter.send(ctx, topic, key, value, myWriter.sendMsg)
+}
+
+// SendMsgSync writes the message on a context detached from ctx and returns the
+// broker error, bypassing the buffer in async and batch mode.
+func (myWriter *MyWriter) SendMsgSync(ctx context.Context, topic, key, value string) error {
+	return myWriter.send(ctx, topic, key, value, myWriter.writeSync)
+}
+
+func (myWriter *MyWriter) send(ctx context.Context, topic, key, value string, send func(context.Context, kafka.Message) error) error {
+	msg := kafka.Message{
+		Topic: myWriter.conn.topicName(topic), // 逻辑 topic 映射为实际 topic
+		Key:   []byte(key),
+		Value: []byte(value),
+		Time:  time.Now(), // 用于计算生产延迟
+	}
+	ctx, span := tracing.Tracer().Start(ctx, "kafka.produce "+msg.Topic,
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "kafka"),
+			attribute.String("messaging.destination.name", msg.Topic),
+			attribute.String("messaging.kafka.message.key", key),
+		))
+	defer span.End()
+	otel.GetTextMapPropagator().Inject(ctx, tracing.NewKafkaHeaderCarrier(&msg.Headers))
+
+	err := send(ctx, msg)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
+
+func (myWriter *MyWriter) sendMsg(ctx context.Context, msg kafka.Message) error {
+	if myWriter.mode == ProducerModeSync {
+		return myWriter.writeSync(ctx, msg)
+	}
+
+	myWriter.mu.RLock()
+	defer myWriter.mu.RUnlock()
+	if myWriter.closed {
+		metrics.KafkaProduceTotal.WithLabelValues(msg.Topic, "dropped").Inc()
+		return ErrProducerClosed
+	}
+	select {
+	case myWriter.msgCh <- msg:
+		return nil
+	default:
+		metrics.KafkaProduceTotal.WithLabelValues(msg.Topic, "dropped").Inc()
+		log.Logger.Errorf("SendMsg: buffer full, drop message, topic %s, key %s", msg.Topic, string(msg.Key))
+		return ErrProducerBufferFull
+	}
 }
 
-func closeKafkaWriter() {
-	if writer != nil && writer.kafkaWriter != nil {
-		if err := writer.kafkaWriter.Close(); err != nil {
-			log.Logger.Errorf("CloseKafkaWriter: failed to close writer, err %s", err.Error())
+func (myWriter *MyWriter) writeSync(ctx context.Context, msg kafka.Message) error {
+	writeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), myWriter.writeTimeout)
+	defer cancel()
+	return myWriter.write(writeCtx, msg)
+}
+
+// Close stops accepting messages, flushes everything still buffered and closes
+// the underlying kafka writer. Closing a closed writer does nothing.
+func (myWriter *MyWriter) Close() error {
+	myWriter.mu.Lock()
+	if myWriter.closed {
+		myWriter.mu.Unlock()
+		return nil
+	}
+	myWriter.closed = true
+	if myWriter.msgCh != nil {
+		close(myWriter.msgCh)
+	}
+	myWriter.mu.Unlock()
+	myWriter.wg.Wait()
+	return myWriter.kafkaWriter.Close()
+}
+
+// run drains the buffer. Every write waits up to the kafka writer's
+// BatchTimeout unless it fills a batch, so messages are always written in
+// batches: async mode writes whatever is buffered right away, batch mode
+// waits for batchSize messages or batchTimeout.
+func (myWriter *MyWriter) run() {
+	defer myWriter.wg.Done()
+	if myWriter.mode != ProducerModeBatch {
+		batch := make([]kafka.Message, 0, myWriter.batchSize)
+		for msg := range myWriter.msgCh {
+			batch = myWriter.drain(append(batch[:0], msg))
+			myWriter.writeWithTimeout(batch...)
+		}
+		return
+	}
+
+	batch := make([]kafka.Message, 0, myWriter.batchSize)
+	ticker := time.NewTicker(myWriter.batchTimeout)
+	defer ticker.Stop()
+	for {
+		select {
+		case msg, ok := <-myWriter.msgCh:
+			if !ok {
+				myWriter.writeWithTimeout(batch...)
+				return
+			}
+			batch = append(batch, msg)
+			if len(batch) >= myWriter.batchSize {
+				myWriter.writeWithTimeout(batch...)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				myWriter.writeWithTimeout(batch...)
+				batch = batch[:0]
+			}
 		}
 	}
 }
 
-func (myWriter *MyWriter) SendMsg(ctx context.Context, topic, key, value string) error {
-	go func() {
-		err := myWriter.kafkaWriter.WriteMessages(ctx, kafka.Message{
-			Topic: topic, // 这里可以覆盖默认 topic
-			Key:   []byte(key),
-			Value: []byte(value),
-		})
-		if err != nil {
-			log.Logger.Errorf("SendMsg: failed, err %s", err.Error())
+// drain appends the messages already buffered to batch, up to batchSize,
+// without waiting for more.
+func (myWriter *MyWriter) drain(batch []kafka.Message) []kafka.Message {
+	for len(batch) < myWriter.batchSize {
+		select {
+		case msg, ok := <-myWriter.msgCh:
+			if !ok {
+				return batch
+			}
+			batch = append(batch, msg)
+		default:
+			return batch
 		}
-	}()
-	return nil
+	}
+	return batch
 }
 
-func GetWriter() *MyWriter {
-	return writer
+func (myWriter *MyWriter) writeWithTimeout(msgs ...kafka.Message) {
+	if len(msgs) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), myWriter.writeTimeout)
+	defer cancel()
+	if err := myWriter.write(ctx, msgs...); err != nil {
+		log.Logger.Errorf("SendMsg: failed, err %s", err.Error())
+	}
 }
 
-func initKafkaReader() {
-	brokerAddr := fmt.Sprintf("%s:%d", config.Config.KafkaConfig.Host, config.Config.KafkaConfig.Port)
-	readerOnce.Do(func() {
-		kafkaReader := kafka.NewReader(kafka.ReaderConfig{
-			Brokers:   []string{brokerAddr},
-			GroupID:   "consume_group_order_status_change",
-			Topic:     "order_status_changed",
-			Partition: 0,
-			MaxBytes:  10e6,
-		})
-		reader = &MyConsumer{
-			r:           kafkaReader,
-			orderLogDao: dao.GetOrderLogDao(),
+func (myWriter *MyWriter) write(ctx context.Context, msgs ...kafka.Message) error {
+	start := time.Now()
+	err := myWriter.kafkaWriter.WriteMessages(ctx, msgs...)
+	duration := float64(time.Since(start).Milliseconds())
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	for _, msg := range msgs {
+		metrics.KafkaProduceTotal.WithLabelValues(msg.Topic, result).Inc()
+		metrics.KafkaProduceDuration.WithLabelValues(msg.Topic).Observe(duration)
+		if err == nil && !msg.Time.IsZero() {
+			metrics.KafkaProduceLag.WithLabelValues(msg.Topic).Observe(float64(time.Since(msg.Time).Milliseconds()))
 		}
-	})
+	}
+	return err
 }
 
-func closeKafkaReader() {
-	if reader != nil && reader.r != nil {
-		if err := reader.r.Close(); err != nil {
-			log.Logger.Errorf("failed to close reader: %s", err.Error())
+// NewConsumer creates a reader of the given logical topics in consumer group
+// groupID, and passes every message to handler.
+func NewConsumer(conn *KafkaConn, groupID string, topics []string, handler MessageHandler, consumerMetrics *metrics.KafkaConsumerMetrics) *MyConsumer {
+	readerConfig := kafka.ReaderConfig{
+		Brokers:  conn.brokers,
+		Dialer:   conn.dialer(),
+		GroupID:  groupID,
+		MaxBytes: 10e6,
+	}
+	if len(topics) == 1 {
+		readerConfig.Topic = conn.topicName(topics[0])
+	} else {
+		for _, topic := range topics {
+			readerConfig.GroupTopics = append(readerConfig.GroupTopics, conn.topicName(topic))
 		}
 	}
+	return newConsumer(kafka.NewReader(readerConfig), handler, consumerMetrics)
+}
+
+func newConsumer(r kafkaReader, handler MessageHandler, consumerMetrics *metrics.KafkaConsumerMetrics) *MyConsumer {
+	return &MyConsumer{
+		r:           r,
+		handler:     handler,
+		metrics:     consumerMetrics,
+		maxAttempts: defaultConsumeAttempts,
+		backoff:     defaultConsumeBackoff,
+		maxBackoff:  defaultConsumeMaxBackoff,
+	}
 }
 
-func GetReader() *MyConsumer {
-	return reader
+// Close closes the reader and leaves the consumer group.
+func (mc *MyConsumer) Close() error {
+	return mc.r.Close()
 }
 
+// ConsumeMessage runs until ctx is canceled. Offsets are committed only after
+// a message is handled, and a message already fetched is finished even if ctx
+// is canceled meanwhile, so a shutdown never loses or half-handles a message.
+// Read and commit failures are retried with backoff until they succeed. A
+// message whose handler still fails after maxAttempts is logged and skipped,
+// so one bad message or a database outage never stops the consumer for good.
 func (mc *MyConsumer) ConsumeMessage(ctx context.Context) {
-	for {
-		msgRaw, err := mc.r.ReadMessage(ctx)
+	for fetchAttempt := 1; ; fetchAttempt++ {
+		msgRaw, err := mc.r.FetchMessage(ctx)
 		if err != nil {
-			log.Logger.Errorf("read message failed, err = %s", err.Error())
-			break
+			if ctx.Err() != nil {
+				log.Logger.Info("consumer stopped")
+				return
+			}
+			mc.fail(fmt.Errorf("read message: %w", err))
+			log.Logger.Errorf("read message failed, attempt = %d, err = %s", fetchAttempt, err.Error())
+			if !mc.wait(ctx, fetchAttempt) {
+				return
+			}
+			continue
 		}
+		fetchAttempt = 0
+		mc.metrics.Fetched(msgRaw.Topic, msgRaw.Time, mc.r.Stats().Lag)
+
+		// 已取出的消息处理完再退出；重试期间收到退出信号时不提交，重启后重新消费
+		handleCtx := context.WithoutCancel(ctx)
+		if !mc.handleWithRetry(ctx, handleCtx, msgRaw) || !mc.commitWithRetry(ctx, handleCtx, msgRaw) {
+			log.Logger.Info("consumer stopped")
+			return
+		}
+		mc.failure.Store(nil)
+	}
+}
+
+// handleWithRetry returns false when ctx is canceled before the message is
+// handled or skipped.
+func (mc *MyConsumer) handleWithRetry(ctx, handleCtx context.Context, msgRaw kafka.Message) bool {
+	for attempt := 1; ; attempt++ {
+		err := mc.handleMessage(handleCtx, msgRaw)
+		if err == nil {
+			return true
+		}
+		mc.fail(fmt.Errorf("handle message: %w", err))
+		if attempt >= mc.maxAttempts {
+			// 跳过后不再重试，按日志中的位置人工补偿
+			mc.metrics.Skipped(msgRaw.Topic)
+			log.Logger.Errorf("handle message failed %d times, skipped, topic = %s, partition = %d, offset = %d, key = %s, err = %s",
+				attempt, msgRaw.Topic, msgRaw.Partition, msgRaw.Offset, string(msgRaw.Key), err.Error())
+			return true
+		}
+		log.Logger.Warnf("handle message failed, topic = %s, attempt = %d, err = %s", msgRaw.Topic, attempt, err.Error())
+		if !mc.wait(ctx, attempt) {
+			return false
+		}
+	}
+}
+
+func (mc *MyConsumer) commitWithRetry(ctx, handleCtx context.Context, msgRaw kafka.Message) bool {
+	for attempt := 1; ; attempt++ {
+		err := mc.r.CommitMessages(handleCtx, msgRaw)
+		if err == nil {
+			return true
+		}
+		mc.fail(fmt.Errorf("commit message: %w", err))
+		log.Logger.Errorf("commit message failed, attempt = %d, err = %s", attempt, err.Error())
+		if !mc.wait(ctx, attempt) {
+			return false
+		}
+	}
+}
+
+// wait sleeps before retry attempt+1, doubling the backoff each attempt. It
+// returns false if ctx is canceled first.
+func (mc *MyConsumer) wait(ctx context.Context, attempt int) bool {
+	backoff := mc.backoff << min(attempt-1, 16)
+	if backoff > mc.maxBackoff {
+		backoff = mc.maxBackoff
+	}
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (mc *MyConsumer) fail(err error) {
+	mc.failure.Store(&err)
+}
+
+// Check reports the last read, handle or commit failure until the consumer
+// makes progress again, so a consumer stuck retrying fails the health check.
+func (mc *MyConsumer) Check(ctx context.Context) error {
+	if err := mc.failure.Load(); err != nil {
+		return *err
+	}
+	return nil
+}
+
+// handleMessage continues the producer's trace, so the work done by the
+// handler shows up under the request that produced the message.
+func (mc *MyConsumer) handleMessage(ctx context.Context, msgRaw kafka.Message) error {
+	ctx = otel.GetTextMapPropagator().Extract(ctx, tracing.NewKafkaHeaderCarrier(&msgRaw.Headers))
+	ctx, span := tracing.Tracer().Start(ctx, "kafka.consume "+msgRaw.Topic,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "kafka"),
+			attribute.String("messaging.destination.name", msgRaw.Topic),
+			attribute.Int("messaging.destination.partition.id", msgRaw.Partition),
+			attribute.Int64("messaging.kafka.offset", msgRaw.Offset),
+		))
+	defer span.End()
+
+	err := mc.handler(ctx, msgRaw)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
+
+// NewOrderLogHandler writes an order status log for every order status changed message.
+func NewOrderLogHandler(orderLogDao dao.OrderLogDao) MessageHandler {
+	return func(ctx context.Context, msgRaw kafka.Message) error {
 		log.Logger.Infof("get message: %s", string(msgRaw.Value))
 		var msg types.OrderStatusChangedMessage
-		err = JSONDecode(string(msgRaw.Value), &msg)
+		err := JSONDecode(string(msgRaw.Value), &msg)
 		if err != nil {
 			log.Logger.Errorf("parse json failed, err = %s", err.Error())
-			continue
+			return nil
 		}
-		_, err = mc.orderLogDao.Create(ctx, &model.OrderStatusLog{
+		_, err = orderLogDao.Create(ctx, &model.OrderStatusLog{
 			OrderNo:       msg.OrderNo,
 			UserID:        msg.UserId,
 			CurrentStatus: msg.CurrentStatus,
 			Remark:        msg.Remark,
 			CreateTime:    time.Now(),
 		})
-		if err != nil {
-			log.Logger.Errorf("create order log failed, err = %s", err.Error())
-			break
-		}
+		return err
 	}
 }
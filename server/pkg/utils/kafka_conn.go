@@ -0,0 +1,215 @@
+package utils
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/config"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/consts"
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"github.com/segmentio/kafka-go/sasl/scram"
+)
+
+const (
+	kafkaDialTimeout      = 10 * time.Second
+	kafkaBootstrapTimeout = 30 * time.Second
+)
+
+// logical topics this service produces or consumes, created by BootstrapTopics
+var kafkaLogicalTopics = []string{
+	consts.TopicOrderCreated,
+	consts.TopicOrderStatusChanged,
+	consts.TopicOrderCanceled,
+	consts.TopicOrderUpdated,
+	consts.TopicOrderRefunded,
+}
+
+// KafkaConn holds everything needed to reach the cluster: broker list,
+// security settings and the logical -> physical topic mapping.
+type KafkaConn struct {
+	brokers   []string
+	mechanism sasl.Mechanism
+	tlsConfig *tls.Config
+	topics    map[string]config.KafkaTopic
+}
+
+func NewKafkaConn(cfg *config.KafkaConfig) (*KafkaConn, error) {
+	mechanism, err := newSASLMechanism(cfg.SASL)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig, err := newTLSConfig(cfg.TLS)
+	if err != nil {
+		return nil, err
+	}
+	return &KafkaConn{
+		brokers:   kafkaBrokers(cfg),
+		mechanism: mechanism,
+		tlsConfig: tlsConfig,
+		topics:    kafkaTopics(cfg),
+	}, nil
+}
+
+// kafkaBrokers returns the configured broker list, falling back to the legacy host/port pair.
+func kafkaBrokers(cfg *config.KafkaConfig) []string {
+	if len(cfg.Brokers) > 0 {
+		return cfg.Brokers
+	}
+	return []string{fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)}
+}
+
+// kafkaTopics fills in defaults for every logical topic: same physical name,
+// one partition and one replica unless configured otherwise.
+func kafkaTopics(cfg *config.KafkaConfig) map[string]config.KafkaTopic {
+	topics := make(map[string]config.KafkaTopic, len(kafkaLogicalTopics))
+	for _, logical := range kafkaLogicalTopics {
+		topic := config.KafkaTopic{Name: logical, Partitions: 1, ReplicationFactor: 1}
+		if configured, ok := cfg.Topics[logical]; ok && configured != nil {
+			if configured.Name != "" {
+				topic.Name = configured.Name
+			}
+			if configured.Partitions > 0 {
+				topic.Partitions = configured.Partitions
+			}
+			if configured.ReplicationFactor > 0 {
+				topic.ReplicationFactor = configured.ReplicationFactor
+			}
+		}
+		topics[logical] = topic
+	}
+	return topics
+}
+
+func newSASLMechanism(cfg *config.KafkaSASL) (sasl.Mechanism, error) {
+	if cfg == nil || cfg.Mechanism == "" {
+		return nil, nil
+	}
+	switch strings.ToUpper(cfg.Mechanism) {
+	case "PLAIN":
+		return plain.Mechanism{Username: cfg.Username, Password: cfg.Password}, nil
+	case "SCRAM-SHA-256":
+		return scram.Mechanism(scram.SHA256, cfg.Username, cfg.Password)
+	case "SCRAM-SHA-512":
+		return scram.Mechanism(scram.SHA512, cfg.Username, cfg.Password)
+	default:
+		return nil, fmt.Errorf("unsupported kafka sasl mechanism: %s", cfg.Mechanism)
+	}
+}
+
+func newTLSConfig(cfg *config.KafkaTLS) (*tls.Config, error) {
+	if cfg == nil || !cfg.Enabled {
+		return nil, nil
+	}
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: cfg.InsecureSkipVerify, // #nosec G402 -- opt-in for self-signed test clusters
+	}
+	if cfg.CAFile != "" {
+		caPem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read kafka ca file failed: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPem) {
+			return nil, errors.New("no certificate found in kafka ca file")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if cfg.CertFile != "" && cfg.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load kafka client certificate failed: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+func (c *KafkaConn) topicName(logical string) string {
+	if topic, ok := c.topics[logical]; ok {
+		return topic.Name
+	}
+	return logical
+}
+
+func (c *KafkaConn) dialer() *kafka.Dialer {
+	return &kafka.Dialer{
+		Timeout:       kafkaDialTimeout,
+		DualStack:     true,
+		SASLMechanism: c.mechanism,
+		TLS:           c.tlsConfig,
+	}
+}
+
+func (c *KafkaConn) transport() *kafka.Transport {
+	return &kafka.Transport{
+		DialTimeout: kafkaDialTimeout,
+		SASL:        c.mechanism,
+		TLS:         c.tlsConfig,
+	}
+}
+
+// Ping checks that at least one configured broker accepts connections.
+func (c *KafkaConn) Ping(ctx context.Context) error {
+	dialer := c.dialer()
+	var err error
+	for _, broker := range c.brokers {
+		var conn *kafka.Conn
+		conn, err = dialer.DialContext(ctx, "tcp", broker)
+		if err == nil {
+			return conn.Close()
+		}
+	}
+	return fmt.Errorf("no kafka broker reachable: %w", err)
+}
+
+// BootstrapTopics creates every configured topic through the cluster
+// controller. Topics that already exist are left untouched.
+func (c *KafkaConn) BootstrapTopics(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, kafkaBootstrapTimeout)
+	defer cancel()
+
+	dialer := c.dialer()
+	var conn *kafka.Conn
+	var err error
+	for _, broker := range c.brokers {
+		conn, err = dialer.DialContext(ctx, "tcp", broker)
+		if err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("dial kafka brokers %v failed: %w", c.brokers, err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	controller, err := conn.Controller()
+	if err != nil {
+		return fmt.Errorf("get kafka controller failed: %w", err)
+	}
+	controllerConn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(controller.Host, strconv.Itoa(controller.Port)))
+	if err != nil {
+		return fmt.Errorf("dial kafka controller failed: %w", err)
+	}
+	defer func() { _ = controllerConn.Close() }()
+
+	topicConfigs := make([]kafka.TopicConfig, 0, len(c.topics))
+	for _, topic := range c.topics {
+		topicConfigs = append(topicConfigs, kafka.TopicConfig{
+			Topic:             topic.Name,
+			NumPartitions:     topic.Partitions,
+			ReplicationFactor: topic.ReplicationFactor,
+		})
+	}
+	return controllerConn.CreateTopics(topicConfigs...)
+}
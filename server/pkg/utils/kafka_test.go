@@ -0,0 +1,194 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/config"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/metrics"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/consts"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/segmentio/kafka-go"
+	"go.uber.org/zap"
+)
+
+func init() {
+	logger, _ := zap.NewDevelopment()
+	log.Logger = logger.Sugar()
+}
+
+func TestMyWriter_SendMsg_BufferFull(t *testing.T) {
+	w := &MyWriter{
+		conn:  &KafkaConn{},
+		mode:  ProducerModeAsync,
+		msgCh: make(chan kafka.Message, 1),
+	}
+
+	if err := w.SendMsg(context.Background(), "order_created", "k1", "v1"); err != nil {
+		t.Errorf("Expected no error, got: %s", err.Error())
+	}
+	err := w.SendMsg(context.Background(), "order_created", "k2", "v2")
+	if !errors.Is(err, ErrProducerBufferFull) {
+		t.Errorf("Expected ErrProducerBufferFull, got: %v", err)
+	}
+}
+
+func TestMyWriter_SendMsg_AfterClose(t *testing.T) {
+	w := &MyWriter{
+		conn:   &KafkaConn{},
+		mode:   ProducerModeBatch,
+		msgCh:  make(chan kafka.Message, 1),
+		closed: true,
+	}
+
+	err := w.SendMsg(context.Background(), "order_created", "k1", "v1")
+	if !errors.Is(err, ErrProducerClosed) {
+		t.Errorf("Expected ErrProducerClosed, got: %v", err)
+	}
+}
+
+func TestMyWriter_Drain(t *testing.T) {
+	w := &MyWriter{batchSize: 3, msgCh: make(chan kafka.Message, 5)}
+	for i := 0; i < 4; i++ {
+		w.msgCh <- kafka.Message{Offset: int64(i)}
+	}
+
+	// 异步模式一次写入已缓冲的消息，不超过 batchSize，也不等待新消息
+	if batch := w.drain([]kafka.Message{<-w.msgCh}); len(batch) != 3 {
+		t.Errorf("Expected a batch of 3, got: %d", len(batch))
+	}
+	if batch := w.drain([]kafka.Message{<-w.msgCh}); len(batch) != 1 {
+		t.Errorf("Expected a batch of 1, got: %d", len(batch))
+	}
+}
+
+func TestMyWriter_CloseTwice(t *testing.T) {
+	w := &MyWriter{kafkaWriter: &kafka.Writer{}, msgCh: make(chan kafka.Message, 1)}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Errorf("Expected second Close to do nothing, got: %v", err)
+	}
+}
+
+func TestKafkaTopics_Defaults(t *testing.T) {
+	topics := kafkaTopics(&config.KafkaConfig{
+		Topics: map[string]*config.KafkaTopic{
+			consts.TopicOrderCreated: {Name: "prod.order_created", Partitions: 6},
+		},
+	})
+
+	created := topics[consts.TopicOrderCreated]
+	if created.Name != "prod.order_created" || created.Partitions != 6 || created.ReplicationFactor != 1 {
+		t.Errorf("Unexpected order_created topic config: %+v", created)
+	}
+	changed := topics[consts.TopicOrderStatusChanged]
+	if changed.Name != consts.TopicOrderStatusChanged || changed.Partitions != 1 {
+		t.Errorf("Unexpected order_status_changed topic config: %+v", changed)
+	}
+}
+
+func TestNewSASLMechanism(t *testing.T) {
+	mechanism, err := newSASLMechanism(nil)
+	if err != nil || mechanism != nil {
+		t.Errorf("Expected SASL disabled, got: %v, %v", mechanism, err)
+	}
+	mechanism, err = newSASLMechanism(&config.KafkaSASL{Mechanism: "scram-sha-512", Username: "u", Password: "p"})
+	if err != nil || mechanism == nil {
+		t.Errorf("Expected SCRAM mechanism, got: %v, %v", mechanism, err)
+	}
+	if _, err = newSASLMechanism(&config.KafkaSASL{Mechanism: "GSSAPI"}); err == nil {
+		t.Errorf("Expected error for unsupported mechanism")
+	}
+}
+
+// fakeReader 依次返回 msgs，取完后阻塞到 ctx 取消
+type fakeReader struct {
+	msgs      []kafka.Message
+	committed []int64
+}
+
+func (r *fakeReader) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	if len(r.msgs) == 0 {
+		<-ctx.Done()
+		return kafka.Message{}, ctx.Err()
+	}
+	msg := r.msgs[0]
+	r.msgs = r.msgs[1:]
+	return msg, nil
+}
+
+func (r *fakeReader) CommitMessages(_ context.Context, msgs ...kafka.Message) error {
+	for _, msg := range msgs {
+		r.committed = append(r.committed, msg.Offset)
+	}
+	return nil
+}
+
+func (r *fakeReader) Stats() kafka.ReaderStats { return kafka.ReaderStats{} }
+
+func (r *fakeReader) Close() error { return nil }
+
+func TestMyConsumer_ConsumeMessage_RetryAndSkip(t *testing.T) {
+	reader := &fakeReader{msgs: []kafka.Message{
+		{Topic: consts.TopicOrderStatusChanged, Offset: 1, Value: []byte("transient")},
+		{Topic: consts.TopicOrderStatusChanged, Offset: 2, Value: []byte("poison")},
+		{Topic: consts.TopicOrderStatusChanged, Offset: 3, Value: []byte("ok")},
+	}}
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := map[string]int{}
+	consumerMetrics := metrics.NewKafkaConsumerMetrics(prometheus.NewRegistry())
+	mc := newConsumer(reader, func(_ context.Context, msg kafka.Message) error {
+		value := string(msg.Value)
+		calls[value]++
+		switch {
+		case value == "transient" && calls[value] < 3, value == "poison":
+			return errors.New("handler failed")
+		case value == "ok":
+			defer cancel()
+		}
+		return nil
+	}, consumerMetrics)
+	mc.backoff = time.Millisecond
+
+	mc.ConsumeMessage(ctx)
+
+	if calls["transient"] != 3 || calls["poison"] != defaultConsumeAttempts || calls["ok"] != 1 {
+		t.Errorf("unexpected handler calls: %v", calls)
+	}
+	// 失败多次的消息被跳过并提交，后面的消息继续消费
+	if len(reader.committed) != 3 || reader.committed[1] != 2 || reader.committed[2] != 3 {
+		t.Errorf("committed offsets = %v, want [1 2 3]", reader.committed)
+	}
+	if got := testutil.ToFloat64(consumerMetrics.ConsumeSkippedTotal.WithLabelValues(consts.TopicOrderStatusChanged)); got != 1 {
+		t.Errorf("Expected 1 skipped message, got: %v", got)
+	}
+	if err := mc.Check(ctx); err != nil {
+		t.Errorf("Check() = %v after consumer recovered", err)
+	}
+}
+
+func TestMyConsumer_Check_Failing(t *testing.T) {
+	reader := &fakeReader{msgs: []kafka.Message{{Topic: consts.TopicOrderStatusChanged, Offset: 1}}}
+	ctx, cancel := context.WithCancel(context.Background())
+	mc := newConsumer(reader, func(context.Context, kafka.Message) error {
+		cancel()
+		return errors.New("db down")
+	}, nil)
+
+	// 重试等待中退出，消息不提交，健康检查报告失败原因
+	mc.ConsumeMessage(ctx)
+
+	if len(reader.committed) != 0 {
+		t.Errorf("committed offsets = %v, want none", reader.committed)
+	}
+	if err := mc.Check(ctx); err == nil {
+		t.Error("Check() = nil while the message is failing")
+	}
+}
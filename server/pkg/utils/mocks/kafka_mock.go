@@ -9,6 +9,7 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	kafka "github.com/segmentio/kafka-go"
 )
 
 // MockWriter is a mock of Writer interface.
@@ -47,3 +48,102 @@ func (mr *MockWriterMockRecorder) SendMsg(ctx, topic, key, value interface{}) *g
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockWriter)(nil).SendMsg), ctx, topic, key, value)
 }
+
+// SendMsgSync mocks base method.
+func (m *MockWriter) SendMsgSync(ctx context.Context, topic, key, value string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendMsgSync", ctx, topic, key, value)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsgSync indicates an expected call of SendMsgSync.
+func (mr *MockWriterMockRecorder) SendMsgSync(ctx, topic, key, value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsgSync", reflect.TypeOf((*MockWriter)(nil).SendMsgSync), ctx, topic, key, value)
+}
+
+// MockkafkaReader is a mock of kafkaReader interface.
+type MockkafkaReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockkafkaReaderMockRecorder
+}
+
+// MockkafkaReaderMockRecorder is the mock recorder for MockkafkaReader.
+type MockkafkaReaderMockRecorder struct {
+	mock *MockkafkaReader
+}
+
+// NewMockkafkaReader creates a new mock instance.
+func NewMockkafkaReader(ctrl *gomock.Controller) *MockkafkaReader {
+	mock := &MockkafkaReader{ctrl: ctrl}
+	mock.recorder = &MockkafkaReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockkafkaReader) EXPECT() *MockkafkaReaderMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockkafkaReader) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockkafkaReaderMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockkafkaReader)(nil).Close))
+}
+
+// CommitMessages mocks base method.
+func (m *MockkafkaReader) CommitMessages(ctx context.Context, msgs ...kafka.Message) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range msgs {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CommitMessages", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CommitMessages indicates an expected call of CommitMessages.
+func (mr *MockkafkaReaderMockRecorder) CommitMessages(ctx interface{}, msgs ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, msgs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CommitMessages", reflect.TypeOf((*MockkafkaReader)(nil).CommitMessages), varargs...)
+}
+
+// FetchMessage mocks base method.
+func (m *MockkafkaReader) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchMessage", ctx)
+	ret0, _ := ret[0].(kafka.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchMessage indicates an expected call of FetchMessage.
+func (mr *MockkafkaReaderMockRecorder) FetchMessage(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchMessage", reflect.TypeOf((*MockkafkaReader)(nil).FetchMessage), ctx)
+}
+
+// Stats mocks base method.
+func (m *MockkafkaReader) Stats() kafka.ReaderStats {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stats")
+	ret0, _ := ret[0].(kafka.ReaderStats)
+	return ret0
+}
+
+// Stats indicates an expected call of Stats.
+func (mr *MockkafkaReaderMockRecorder) Stats() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stats", reflect.TypeOf((*MockkafkaReader)(nil).Stats))
+}
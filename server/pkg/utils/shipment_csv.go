@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/errs"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/types"
+)
+
+var ErrInvalidShipmentCSV = errs.New(errs.CodeInvalidShipBatch, "invalid shipment csv")
+
+// 发货清单 CSV 表头，列顺序不限，多余的列忽略
+const (
+	shipmentColOrderNo    = "order_no"
+	shipmentColCarrier    = "carrier"
+	shipmentColTrackingNo = "tracking_no"
+)
+
+// ParseShipmentCSV reads a courier manifest with a header row naming the
+// order_no, carrier and tracking_no columns. Blank lines are skipped. It stops
+// reading and fails as soon as the manifest has more than maxRows rows.
+func ParseShipmentCSV(r io.Reader, maxRows int) ([]*types.BatchShipItem, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, ErrInvalidShipmentCSV.Detailf("empty file")
+	}
+	if err != nil {
+		return nil, ErrInvalidShipmentCSV.Detailf("%s", err.Error())
+	}
+
+	cols := map[string]int{shipmentColOrderNo: -1, shipmentColCarrier: -1, shipmentColTrackingNo: -1}
+	for i, name := range header {
+		// Excel 导出的 CSV 带 BOM
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\uFEFF")))
+		if _, ok := cols[name]; ok {
+			cols[name] = i
+		}
+	}
+	for _, name := range []string{shipmentColOrderNo, shipmentColTrackingNo} {
+		if cols[name] < 0 {
+			return nil, ErrInvalidShipmentCSV.Detailf("missing column %s", name)
+		}
+	}
+
+	field := func(record []string, name string) string {
+		if i := cols[name]; i >= 0 && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var items []*types.BatchShipItem
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, ErrInvalidShipmentCSV.Detailf("%s", err.Error())
+		}
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+		if len(items) >= maxRows {
+			return nil, ErrInvalidShipmentCSV.Detailf("more than %d rows", maxRows)
+		}
+		items = append(items, &types.BatchShipItem{
+			OrderNo:    field(record, shipmentColOrderNo),
+			Carrier:    field(record, shipmentColCarrier),
+			TrackingNo: field(record, shipmentColTrackingNo),
+		})
+	}
+	return items, nil
+}
@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestParseShipmentCSV(t *testing.T) {
+	input := "\uFEFFTracking_No,order_no,carrier,note\n" +
+		"SF001, ORD1 ,SF Express,fragile\n" +
+		"\n" +
+		"SF002,ORD2\n"
+	items, err := ParseShipmentCSV(strings.NewReader(input), 2)
+	if err != nil {
+		t.Fatalf("ParseShipmentCSV() error = %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(items))
+	}
+	if items[0].OrderNo != "ORD1" || items[0].Carrier != "SF Express" || items[0].TrackingNo != "SF001" {
+		t.Errorf("unexpected first item: %+v", items[0])
+	}
+	if items[1].OrderNo != "ORD2" || items[1].Carrier != "" || items[1].TrackingNo != "SF002" {
+		t.Errorf("unexpected second item: %+v", items[1])
+	}
+}
+
+func TestParseShipmentCSV_MissingColumn(t *testing.T) {
+	_, err := ParseShipmentCSV(strings.NewReader("order_no,carrier\nORD1,SF\n"), 500)
+	if !errors.Is(err, ErrInvalidShipmentCSV) {
+		t.Fatalf("expected ErrInvalidShipmentCSV, got %v", err)
+	}
+	if _, err = ParseShipmentCSV(strings.NewReader(""), 500); !errors.Is(err, ErrInvalidShipmentCSV) {
+		t.Fatalf("expected ErrInvalidShipmentCSV for empty file, got %v", err)
+	}
+}
+
+func TestParseShipmentCSV_TooManyRows(t *testing.T) {
+	input := "order_no,tracking_no\nORD1,SF001\nORD2,SF002\nORD3,SF003\n"
+	if _, err := ParseShipmentCSV(strings.NewReader(input), 2); !errors.Is(err, ErrInvalidShipmentCSV) {
+		t.Fatalf("expected ErrInvalidShipmentCSV, got %v", err)
+	}
+	// 超过行数后不再读取
+	reader := &countingReader{r: strings.NewReader(input + strings.Repeat("ORD9,SF009\n", 10000))}
+	if _, err := ParseShipmentCSV(reader, 2); !errors.Is(err, ErrInvalidShipmentCSV) {
+		t.Fatalf("expected ErrInvalidShipmentCSV, got %v", err)
+	}
+	if reader.n > 8192 {
+		t.Errorf("read %d bytes after the row limit was reached", reader.n)
+	}
+}
+
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += n
+	return n, err
+}
@@ -1,10 +1,11 @@
 package utils
 
 import (
-    "context"
-    "time"
+	"context"
+	"sync"
+	"time"
 
-    "github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/log"
 )
 
 type MyTimer interface {
@@ -14,42 +15,51 @@ type MyTimer interface {
 
 // MyTimerImpl demonstrates how to use time.Ticker for periodic tasks
 type MyTimerImpl struct {
-    interval time.Duration
-    stopChan chan struct{}
+	interval time.Duration
+	stopChan chan struct{}
+	stopOnce sync.Once
+	doneChan chan struct{}
 }
 
 // NewMyTimer creates a new ticker demo instance
 func NewMyTimer(interval time.Duration) *MyTimerImpl {
-    return &MyTimerImpl{
-        interval: interval,
-        stopChan: make(chan struct{}),
-    }
+	return &MyTimerImpl{
+		interval: interval,
+		stopChan: make(chan struct{}),
+		doneChan: make(chan struct{}),
+	}
 }
 
 // Start begins the periodic task execution
 func (t *MyTimerImpl) Start(ctx context.Context, task func()) {
-    ticker := time.NewTicker(t.interval)
-    defer ticker.Stop()
+	defer close(t.doneChan)
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
 
-    log.Logger.Infof("Ticker started with interval: %v", t.interval)
+	log.Logger.Infof("Ticker started with interval: %v", t.interval)
 
-    for {
-        select {
-        case <-ticker.C:
-            // Execute task on each tick
+	for {
+		select {
+		case <-ticker.C:
+			// Execute task on each tick
 			log.Logger.Infof("Task run at %v", time.Now())
-            task()
-        case <-t.stopChan:
-            log.Logger.Info("Ticker stopped")
-            return
-        case <-ctx.Done():
-            log.Logger.Info("Ticker stopped due to context cancellation")
-            return
-        }
-    }
+			task()
+		case <-t.stopChan:
+			log.Logger.Info("Ticker stopped")
+			return
+		case <-ctx.Done():
+			log.Logger.Info("Ticker stopped due to context cancellation")
+			return
+		}
+	}
 }
 
-// Stop stops the ticker
+// Stop stops the ticker. A task already running is not interrupted; wait on Done for it.
 func (t *MyTimerImpl) Stop() {
-    close(t.stopChan)
-}
\ No newline at end of file
+	t.stopOnce.Do(func() { close(t.stopChan) })
+}
+
+// Done is closed once Start has returned.
+func (t *MyTimerImpl) Done() <-chan struct{} {
+	return t.doneChan
+}
@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/errs"
+)
+
+// Headers carrying the signature of a carrier webhook. The signature is the
+// hex HMAC-SHA256 of "<timestamp>.<body>" keyed by the carrier's secret, and
+// the timestamp is in unix seconds.
+const (
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+)
+
+// WebhookTolerance bounds how far the signed timestamp may be from now, so a
+// captured request cannot be replayed later.
+const WebhookTolerance = 5 * time.Minute
+
+var (
+	ErrInvalidWebhookSignature = errs.New(errs.CodeInvalidSignature, "invalid webhook signature")
+	ErrWebhookExpired          = errs.New(errs.CodeWebhookExpired, "webhook timestamp outside tolerance")
+)
+
+func SignWebhook(secret, timestamp string, body []byte) string {
+	return hex.EncodeToString(webhookMAC(secret, timestamp, body))
+}
+
+func webhookMAC(secret, timestamp string, body []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// VerifyWebhookSignature checks the signature and that the timestamp is within
+// WebhookTolerance of now.
+func VerifyWebhookSignature(secret, timestamp, signature string, body []byte, now time.Time) error {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidWebhookSignature
+	}
+	if skew := now.Sub(time.Unix(ts, 0)); skew > WebhookTolerance || skew < -WebhookTolerance {
+		return ErrWebhookExpired
+	}
+	got, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(got, webhookMAC(secret, timestamp, body)) {
+		return ErrInvalidWebhookSignature
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestVerifyWebhookSignature(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	ts := strconv.FormatInt(now.Unix(), 10)
+	body := []byte(`{"tracking_no":"1Z"}`)
+	sig := SignWebhook("secret", ts, body)
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp string
+		signature string
+		body      []byte
+		now       time.Time
+		wantErr   error
+	}{
+		{"valid", "secret", ts, sig, body, now, nil},
+		{"wrong secret", "other", ts, sig, body, now, ErrInvalidWebhookSignature},
+		{"tampered body", "secret", ts, sig, []byte(`{"tracking_no":"2Z"}`), now, ErrInvalidWebhookSignature},
+		{"not hex", "secret", ts, "zz", body, now, ErrInvalidWebhookSignature},
+		{"bad timestamp", "secret", "abc", sig, body, now, ErrInvalidWebhookSignature},
+		{"replayed", "secret", ts, sig, body, now.Add(WebhookTolerance + time.Second), ErrWebhookExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyWebhookSignature(tt.secret, tt.timestamp, tt.signature, tt.body, tt.now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("VerifyWebhookSignature() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
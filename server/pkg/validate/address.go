@@ -0,0 +1,45 @@
+package validate
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/types"
+	"github.com/go-playground/validator/v10"
+)
+
+// standalone 校验不经过请求绑定的数据（如地址簿中的地址），规则与请求校验相同
+var standalone = sync.OnceValues(func() (*validator.Validate, error) {
+	v := validator.New()
+	v.SetTagName("binding")
+	return v, Register(v)
+})
+
+// NormalizeAddress trims every field and collapses its inner spaces,
+// upper-cases the country and formats the postal code the way the country
+// writes it.
+func NormalizeAddress(addr types.Address) types.Address {
+	country := strings.ToUpper(strings.TrimSpace(addr.Country))
+	return types.Address{
+		Line1:      collapseSpaces(addr.Line1),
+		Line2:      collapseSpaces(addr.Line2),
+		City:       collapseSpaces(addr.City),
+		State:      collapseSpaces(addr.State),
+		PostalCode: NormalizePostcode(country, addr.PostalCode),
+		Country:    country,
+	}
+}
+
+// ValidateAddress checks addr against the same rules as a shipping address in
+// a request body. Failures convert to field errors with FieldErrors.
+func ValidateAddress(addr types.Address) error {
+	v, err := standalone()
+	if err != nil {
+		return err
+	}
+	return v.Struct(addr)
+}
+
+func collapseSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
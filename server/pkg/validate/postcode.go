@@ -0,0 +1,123 @@
+package validate
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// postcodeFormat 国家的邮政编码格式；digits > 0 表示固定位数的纯数字编码
+type postcodeFormat struct {
+	pattern *regexp.Regexp
+	digits  int
+}
+
+func numeric(digits int) postcodeFormat {
+	return postcodeFormat{pattern: regexp.MustCompile(fmt.Sprintf(`^\d{%d}$`, digits)), digits: digits}
+}
+
+// postcodeFormats 常见收货国家的邮政编码格式，其余国家使用 genericPostcode
+var postcodeFormats = map[string]postcodeFormat{
+	"SG": numeric(6),
+	"CN": numeric(6),
+	"IN": numeric(6),
+	"VN": numeric(6),
+	"MY": numeric(5),
+	"TH": numeric(5),
+	"ID": numeric(5),
+	"KR": numeric(5),
+	"DE": numeric(5),
+	"FR": numeric(5),
+	"IT": numeric(5),
+	"ES": numeric(5),
+	"AU": numeric(4),
+	"NZ": numeric(4),
+	"PH": numeric(4),
+	"US": {pattern: regexp.MustCompile(`^\d{5}(-\d{4})?$`), digits: 5},
+	"JP": {pattern: regexp.MustCompile(`^\d{3}-?\d{4}$`), digits: 7},
+	"GB": {pattern: regexp.MustCompile(`^[A-Z]{1,2}\d[A-Z\d]? ?\d[A-Z]{2}$`)},
+	"CA": {pattern: regexp.MustCompile(`^[A-Z]\d[A-Z] ?\d[A-Z]\d$`)},
+	"NL": {pattern: regexp.MustCompile(`^\d{4} ?[A-Z]{2}$`)},
+}
+
+var genericPostcode = regexp.MustCompile(`^[A-Z\d][A-Z\d -]{1,8}[A-Z\d]$`)
+
+// noPostcodeCountries 不使用邮政编码的国家，编码可为空
+var noPostcodeCountries = map[string]bool{
+	"HK": true,
+	"MO": true,
+	"AE": true,
+	"QA": true,
+}
+
+// postcodeSeparator 分隔符在编码中的位置：去掉空格和连字符后，在末尾 tail 个字符前插入 sep；
+// length > 0 时只处理该长度的编码
+type postcodeSeparator struct {
+	sep    string
+	tail   int
+	length int
+}
+
+// postcodeSeparators 按国家的书写习惯插入分隔符，如 SW1A1AA -> SW1A 1AA
+var postcodeSeparators = map[string]postcodeSeparator{
+	"GB": {sep: " ", tail: 3},
+	"CA": {sep: " ", tail: 3, length: 6},
+	"NL": {sep: " ", tail: 2, length: 6},
+	"JP": {sep: "-", tail: 4, length: 7},
+	"US": {sep: "-", tail: 4, length: 9},
+}
+
+// ValidPostcode reports whether postcode is a valid postal code of country.
+// Letters are matched case-insensitively.
+func ValidPostcode(country, postcode string) bool {
+	postcode = strings.ToUpper(strings.TrimSpace(postcode))
+	if postcode == "" {
+		return noPostcodeCountries[country]
+	}
+	if format, ok := postcodeFormats[country]; ok {
+		return format.pattern.MatchString(postcode)
+	}
+	return genericPostcode.MatchString(postcode)
+}
+
+// NormalizePostcode upper-cases postcode, collapses its spaces and writes it
+// the way country does, e.g. "sw1a1aa" becomes "SW1A 1AA" for GB. Postcodes
+// that do not fit the country's layout are only upper-cased and trimmed.
+func NormalizePostcode(country, postcode string) string {
+	postcode = collapseSpaces(strings.ToUpper(postcode))
+	layout, ok := postcodeSeparators[country]
+	if !ok {
+		return postcode
+	}
+	compact := strings.NewReplacer(" ", "", "-", "").Replace(postcode)
+	if len(compact) <= layout.tail || (layout.length > 0 && len(compact) != layout.length) {
+		return postcode
+	}
+	return compact[:len(compact)-layout.tail] + layout.sep + compact[len(compact)-layout.tail:]
+}
+
+// PostcodeFromInt converts a postcode stored as an integer back to a string,
+// restoring the leading zeros of countries with fixed-length numeric codes.
+func PostcodeFromInt(country string, postcode int) string {
+	if postcode <= 0 {
+		return ""
+	}
+	if format, ok := postcodeFormats[country]; ok && format.digits > 0 {
+		return fmt.Sprintf("%0*d", format.digits, postcode)
+	}
+	return strconv.Itoa(postcode)
+}
+
+// validIntPostcode 校验以整数保存的邮政编码，补齐前导零后按国家格式校验；
+// 整数无法表示含字母的编码，这类国家只要求编码为正数
+func validIntPostcode(country string, postcode int64) bool {
+	if postcode <= 0 {
+		return false
+	}
+	format, ok := postcodeFormats[country]
+	if !ok || format.digits == 0 {
+		return true
+	}
+	return ValidPostcode(country, fmt.Sprintf("%0*d", format.digits, postcode))
+}
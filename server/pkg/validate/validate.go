@@ -0,0 +1,166 @@
+// Package validate registers the custom request validation rules and turns
+// validation failures into field errors.
+package validate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/errs"
+	"github.com/go-playground/validator/v10"
+	"golang.org/x/text/language"
+)
+
+// 自定义校验规则
+const (
+	TagPhone    = "phone"    // E.164 电话号码，如 +6591234567
+	TagCountry  = "country"  // ISO 3166-1 alpha-2 国家代码，如 SG
+	TagPostcode = "postcode" // postcode=<国家字段名>，按国家校验邮政编码
+	TagDistinct = "distinct" // distinct=<字段名>，列表元素的该字段不能重复；内置的 unique=<字段名> 遇到 nil 元素会 panic
+)
+
+var (
+	phonePattern   = regexp.MustCompile(`^\+[1-9]\d{6,14}$`)
+	countryPattern = regexp.MustCompile(`^[A-Z]{2}$`)
+)
+
+// Register registers the custom rules on v and names fields by their JSON
+// (or query form) names so that field errors match the request body.
+func Register(v *validator.Validate) error {
+	v.RegisterTagNameFunc(fieldName)
+	rules := map[string]validator.Func{
+		TagPhone:    validatePhone,
+		TagCountry:  validateCountry,
+		TagPostcode: validatePostcode,
+		TagDistinct: validateDistinct,
+	}
+	for tag, fn := range rules {
+		if err := v.RegisterValidation(tag, fn); err != nil {
+			return fmt.Errorf("register %s: %w", tag, err)
+		}
+	}
+	return nil
+}
+
+// fieldName 优先使用 json 标签，其次 form 标签；嵌入的结构体保留 Go 字段名，由 FieldErrors 去掉
+func fieldName(field reflect.StructField) string {
+	for _, key := range []string{"json", "form"} {
+		name, _, _ := strings.Cut(field.Tag.Get(key), ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+func validatePhone(fl validator.FieldLevel) bool {
+	return phonePattern.MatchString(fl.Field().String())
+}
+
+// IsCountry reports whether code is an assigned ISO 3166-1 alpha-2 code.
+func IsCountry(code string) bool {
+	if !countryPattern.MatchString(code) {
+		return false
+	}
+	region, err := language.ParseRegion(code)
+	// ParseRegion 会把部分代码映射为现行代码，要求原样一致；EU、ZZ 等非国家区域不通过
+	return err == nil && region.IsCountry() && region.String() == code
+}
+
+func validateCountry(fl validator.FieldLevel) bool {
+	return IsCountry(fl.Field().String())
+}
+
+// validatePostcode 按参数指定的国家字段校验邮政编码。国家缺失或不合法时由 country 规则报错，这里不重复
+func validatePostcode(fl validator.FieldLevel) bool {
+	parent := reflect.Indirect(fl.Parent())
+	countryField := parent.FieldByName(fl.Param())
+	if !countryField.IsValid() || countryField.Kind() != reflect.String {
+		return false
+	}
+	country := countryField.String()
+	if !IsCountry(country) {
+		return true
+	}
+
+	field := fl.Field()
+	switch field.Kind() {
+	case reflect.String:
+		return ValidPostcode(country, field.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return validIntPostcode(country, field.Int())
+	default:
+		return false
+	}
+}
+
+// validateDistinct 跳过 nil 元素，nil 元素由 dive,required 报错
+func validateDistinct(fl validator.FieldLevel) bool {
+	field := fl.Field()
+	if field.Kind() != reflect.Slice && field.Kind() != reflect.Array {
+		return false
+	}
+	seen := make(map[any]struct{}, field.Len())
+	for i := 0; i < field.Len(); i++ {
+		elem := reflect.Indirect(field.Index(i))
+		if !elem.IsValid() || elem.Kind() != reflect.Struct {
+			continue
+		}
+		value := elem.FieldByName(fl.Param())
+		if !value.IsValid() || !value.Comparable() {
+			return false
+		}
+		if _, ok := seen[value.Interface()]; ok {
+			return false
+		}
+		seen[value.Interface()] = struct{}{}
+	}
+	return true
+}
+
+// FieldErrors converts the validation errors of a request into field errors.
+// Fields are named by their JSON path, e.g. order_item_list[0].quantity; the
+// request struct and embedded structs, which keep their Go names, are left
+// out of the path.
+func FieldErrors(err error) ([]*errs.FieldError, bool) {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return []*errs.FieldError{errs.NewFieldError(typeErr.Field, "type", typeErr.Type.String(), false)}, true
+	}
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return nil, false
+	}
+
+	fields := make([]*errs.FieldError, 0, len(validationErrs))
+	for _, fe := range validationErrs {
+		length := false
+		switch fe.Kind() {
+		case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+			length = true
+		}
+		fields = append(fields, errs.NewFieldError(fieldPath(fe.Namespace()), fe.Tag(), fe.Param(), length))
+	}
+	return fields, true
+}
+
+// fieldPath 去掉以大写字母开头的段（请求结构体名和嵌入结构体名），JSON 字段名均为小写
+func fieldPath(namespace string) string {
+	segments := strings.Split(namespace, ".")
+	path := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		if segment == "" || unicode.IsUpper([]rune(segment)[0]) {
+			continue
+		}
+		path = append(path, segment)
+	}
+	return strings.Join(path, ".")
+}
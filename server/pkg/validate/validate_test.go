@@ -0,0 +1,215 @@
+package validate
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/types"
+	"github.com/go-playground/validator/v10"
+)
+
+func newValidator(t *testing.T) *validator.Validate {
+	v := validator.New()
+	v.SetTagName("binding")
+	if err := Register(v); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	return v
+}
+
+func validOrder() types.OrderInfo {
+	return types.OrderInfo{
+		ReceiverFirstName: "Tan",
+		ReceiverLastName:  "Ah Kow",
+		ReceiverPhone:     "+6591234567",
+		ReceiverAddress:   "1 Orchard Road",
+		ReceiverCountry:   "SG",
+		ReceiverZipCode:   238823,
+		OrderItemList:     []*types.OrderItemInfo{{ProductID: 1, Quantity: 2, Price: 1500}},
+	}
+}
+
+func TestOrderInfoValidation(t *testing.T) {
+	v := newValidator(t)
+	tests := []struct {
+		name   string
+		modify func(o *types.OrderInfo)
+		fields []string // 期望的 field:rule，为空表示校验通过
+	}{
+		{"valid", func(o *types.OrderInfo) {}, nil},
+		{"us zip with leading zero", func(o *types.OrderInfo) { o.ReceiverCountry, o.ReceiverZipCode = "US", 2134 }, nil},
+		{"alphanumeric postcode country", func(o *types.OrderInfo) { o.ReceiverCountry, o.ReceiverZipCode = "GB", 1 }, nil},
+		{"empty items", func(o *types.OrderInfo) { o.OrderItemList = nil }, []string{"order_item_list:required"}},
+		{"nil item", func(o *types.OrderInfo) { o.OrderItemList = []*types.OrderItemInfo{nil} }, []string{"order_item_list[0]:required"}},
+		{"bad quantity and price", func(o *types.OrderInfo) {
+			o.OrderItemList[0].Quantity, o.OrderItemList[0].Price = 0, -1
+		}, []string{"order_item_list[0].quantity:gt", "order_item_list[0].price:gte"}},
+		{"duplicate product", func(o *types.OrderInfo) {
+			o.OrderItemList = append(o.OrderItemList, &types.OrderItemInfo{ProductID: 1, Quantity: 1})
+		}, []string{"order_item_list:distinct"}},
+		{"missing receiver", func(o *types.OrderInfo) { o.ReceiverFirstName, o.ReceiverAddress = "", "" },
+			[]string{"receiver_first_name:required", "receiver_address:required_without_all"}},
+		{"shipping address", func(o *types.OrderInfo) {
+			o.ReceiverAddress, o.ReceiverCountry, o.ReceiverZipCode = "", "", 0
+			o.ShippingAddress = &types.Address{Line1: "1 Raffles Place", City: "Singapore", PostalCode: "048616", Country: "SG"}
+		}, nil},
+		{"saved address", func(o *types.OrderInfo) {
+			o.ReceiverAddress, o.ReceiverCountry, o.ReceiverZipCode, o.AddressID = "", "", 0, 3
+		}, nil},
+		{"invalid shipping address", func(o *types.OrderInfo) {
+			o.ShippingAddress = &types.Address{Line1: "10 Downing Street", PostalCode: "12345", Country: "GB"}
+		}, []string{"shipping_address.city:required", "shipping_address.postal_code:postcode"}},
+		{"local phone", func(o *types.OrderInfo) { o.ReceiverPhone = "91234567" }, []string{"receiver_phone:phone"}},
+		{"lowercase country", func(o *types.OrderInfo) { o.ReceiverCountry = "sg" }, []string{"receiver_country:country"}},
+		{"not a country", func(o *types.OrderInfo) { o.ReceiverCountry = "EU" }, []string{"receiver_country:country"}},
+		{"short postcode", func(o *types.OrderInfo) { o.ReceiverZipCode = 1234567 }, []string{"receiver_zip_code:postcode"}},
+		{"missing postcode", func(o *types.OrderInfo) { o.ReceiverZipCode = 0 }, []string{"receiver_zip_code:postcode"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := validOrder()
+			tt.modify(&order)
+			fields, _ := FieldErrors(v.Struct(order))
+			got := make([]string, 0, len(fields))
+			for _, f := range fields {
+				got = append(got, f.Field+":"+f.Rule)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.fields, ",") {
+				t.Errorf("field errors = %v, want %v", got, tt.fields)
+			}
+		})
+	}
+}
+
+func TestExportRequestFieldPath(t *testing.T) {
+	v := newValidator(t)
+	req := types.ExportOrderRequest{Format: "pdf"}
+	req.Limit = -1
+	fields, ok := FieldErrors(v.Struct(req))
+	if !ok || len(fields) != 2 || fields[0].Field != "limit" || fields[1].Field != "format" {
+		t.Fatalf("unexpected field errors: %+v", fields)
+	}
+}
+
+func TestEditOrderRequestValidation(t *testing.T) {
+	v := newValidator(t)
+	empty, phone := "", "+6591234567"
+	tests := []struct {
+		name   string
+		req    types.EditOrderRequest
+		fields []string
+	}{
+		{"only reason", types.EditOrderRequest{Reason: "customer called"}, nil},
+		{"clear remark", types.EditOrderRequest{Reason: "r", Remark: &empty, ReceiverPhone: &phone}, nil},
+		{"missing reason", types.EditOrderRequest{Remark: &empty}, []string{"reason:required"}},
+		// 传了空字符串的收货人按修改处理，不能为空
+		{"empty receiver", types.EditOrderRequest{Reason: "r", ReceiverFirstName: &empty}, []string{"receiver_first_name:min"}},
+		{"bad item ids", types.EditOrderRequest{Reason: "r", RemoveItemIDs: []int{3, 3, 0}},
+			[]string{"remove_item_ids:unique"}},
+		{"invalid address", types.EditOrderRequest{Reason: "r", ShippingAddress: &types.Address{Line1: "1 Raffles Place", Country: "SG"}},
+			[]string{"shipping_address.city:required", "shipping_address.postal_code:postcode"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields, _ := FieldErrors(v.Struct(tt.req))
+			got := make([]string, 0, len(fields))
+			for _, f := range fields {
+				got = append(got, f.Field+":"+f.Rule)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.fields, ",") {
+				t.Errorf("field errors = %v, want %v", got, tt.fields)
+			}
+		})
+	}
+}
+
+func TestFieldErrors_TypeMismatch(t *testing.T) {
+	var order types.OrderInfo
+	err := json.Unmarshal([]byte(`{"receiver_zip_code":"238823"}`), &order)
+	fields, ok := FieldErrors(err)
+	if !ok || len(fields) != 1 || fields[0].Field != "receiver_zip_code" || fields[0].Rule != "type" {
+		t.Fatalf("unexpected field errors: %+v", fields)
+	}
+}
+
+func TestValidPostcode(t *testing.T) {
+	tests := []struct {
+		country  string
+		postcode string
+		want     bool
+	}{
+		{"SG", "238823", true},
+		{"SG", "23882", false},
+		{"US", "02134-1234", true},
+		{"GB", "sw1a 1aa", true},
+		{"CA", "K1A 0B1", true},
+		{"JP", "100-0001", true},
+		{"NL", "1234", false},
+		{"BR", "01310-100", true},
+		{"BR", "!", false},
+		{"HK", "", true},
+		{"SG", "", false},
+	}
+	for _, tt := range tests {
+		if got := ValidPostcode(tt.country, tt.postcode); got != tt.want {
+			t.Errorf("ValidPostcode(%q, %q) = %v, want %v", tt.country, tt.postcode, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizePostcode(t *testing.T) {
+	tests := []struct {
+		country  string
+		postcode string
+		want     string
+	}{
+		{"GB", " sw1a1aa ", "SW1A 1AA"},
+		{"GB", "M1  1AE", "M1 1AE"},
+		{"CA", "k1a0b1", "K1A 0B1"},
+		{"NL", "1234ab", "1234 AB"},
+		{"JP", "1000001", "100-0001"},
+		{"US", "021341234", "02134-1234"},
+		{"US", "02134", "02134"},
+		{"SG", "018956", "018956"},
+		{"BR", "01310-100", "01310-100"},
+	}
+	for _, tt := range tests {
+		if got := NormalizePostcode(tt.country, tt.postcode); got != tt.want {
+			t.Errorf("NormalizePostcode(%q, %q) = %q, want %q", tt.country, tt.postcode, got, tt.want)
+		}
+	}
+}
+
+func TestPostcodeFromInt(t *testing.T) {
+	if got := PostcodeFromInt("SG", 18956); got != "018956" {
+		t.Errorf("PostcodeFromInt(SG) = %q, want 018956", got)
+	}
+	if got := PostcodeFromInt("GB", 1); got != "1" {
+		t.Errorf("PostcodeFromInt(GB) = %q, want 1", got)
+	}
+	if got := PostcodeFromInt("SG", 0); got != "" {
+		t.Errorf("PostcodeFromInt(SG, 0) = %q, want empty", got)
+	}
+}
+
+func TestNormalizeAddress(t *testing.T) {
+	addr := NormalizeAddress(types.Address{
+		Line1:      "  221B   Baker Street ",
+		City:       "London ",
+		PostalCode: "nw16xe",
+		Country:    " gb",
+	})
+	want := types.Address{Line1: "221B Baker Street", City: "London", PostalCode: "NW1 6XE", Country: "GB"}
+	if addr != want {
+		t.Fatalf("NormalizeAddress() = %+v, want %+v", addr, want)
+	}
+	if err := ValidateAddress(addr); err != nil {
+		t.Errorf("ValidateAddress() error = %v", err)
+	}
+
+	fields, ok := FieldErrors(ValidateAddress(types.Address{Line1: "1 Orchard Road", City: "Singapore", PostalCode: "2388", Country: "SG"}))
+	if !ok || len(fields) != 1 || fields[0].Field != "postal_code" || fields[0].Rule != "postcode" {
+		t.Errorf("unexpected field errors: %+v", fields)
+	}
+}
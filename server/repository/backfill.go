@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/consts"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/model"
+	"gorm.io/gorm"
+)
+
+// shipmentBackfillCheckpoint 包裹数据回填在 job_checkpoints 中的任务名，存在即已完成
+const shipmentBackfillCheckpoint = "shipment_carrier_backfill"
+
+// backfillShipments fixes, once, the shipments of orders shipped before
+// carrier codes were normalized on every ship path. Tracking webhooks match
+// shipments by the normalized carrier code, so it lower-cases the stored
+// codes the way utils.NormalizeCarrierCode does and gives shipped orders
+// without any shipment one holding all their items.
+func backfillShipments(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var done int64
+		err := tx.Model(&model.JobCheckpoint{}).Where("name = ?", shipmentBackfillCheckpoint).Count(&done).Error
+		if err != nil || done > 0 {
+			return err
+		}
+
+		statements := []struct {
+			sql  string
+			args []interface{}
+		}{
+			// 默认排序规则不区分大小写，用 BINARY 比较
+			{"UPDATE shipments SET carrier_code = LOWER(TRIM(carrier_code)) WHERE BINARY carrier_code <> BINARY LOWER(TRIM(carrier_code))", nil},
+			{"UPDATE orders SET carrier = LOWER(TRIM(carrier)) WHERE BINARY carrier <> BINARY LOWER(TRIM(carrier))", nil},
+			{`INSERT INTO shipments (order_no, carrier_code, tracking_no, weight, create_time, update_time)
+				SELECT o.order_no, o.carrier, o.logistics_no, 0, COALESCE(o.delivery_time, o.update_time), NOW()
+				FROM orders o
+				WHERE o.status IN ? AND o.logistics_no <> ''
+					AND NOT EXISTS (SELECT 1 FROM shipments s WHERE s.order_no = o.order_no)`,
+				[]interface{}{[]int{consts.SHIPPED, consts.DELIVERED}}},
+			// 只给整单都没有包裹商品的包裹补商品，即上一步补的包裹
+			{`INSERT INTO shipment_items (shipment_id, order_product_id, product_id, quantity, create_time)
+				SELECT s.id, p.id, p.product_id, p.quantity, NOW()
+				FROM shipments s JOIN order_products p ON p.order_no = s.order_no
+				WHERE NOT EXISTS (
+					SELECT 1 FROM shipment_items i JOIN shipments s2 ON s2.id = i.shipment_id WHERE s2.order_no = s.order_no
+				)`, nil},
+		}
+		for _, stmt := range statements {
+			if err = tx.Exec(stmt.sql, stmt.args...).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Create(&model.JobCheckpoint{Name: shipmentBackfillCheckpoint, Watermark: time.Now()}).Error
+	})
+}
@@ -1,10 +1,11 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: ./cache/order_stats_cache.go
+// Source: cache/order_stats_cache.go
 
 // Package mocks is a generated GoMock package.
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
 
 	types "github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/types"
@@ -34,17 +35,32 @@ func (m *MockIOrderStatsCache) EXPECT() *MockIOrderStatsCacheMockRecorder {
 	return m.recorder
 }
 
+// GetMerchantOrderStats mocks base method.
+func (m *MockIOrderStatsCache) GetMerchantOrderStats(ctx context.Context, merchantID int) (types.OrderStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMerchantOrderStats", ctx, merchantID)
+	ret0, _ := ret[0].(types.OrderStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMerchantOrderStats indicates an expected call of GetMerchantOrderStats.
+func (mr *MockIOrderStatsCacheMockRecorder) GetMerchantOrderStats(ctx, merchantID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMerchantOrderStats", reflect.TypeOf((*MockIOrderStatsCache)(nil).GetMerchantOrderStats), ctx, merchantID)
+}
+
 // GetOrderStats mocks base method.
-func (m *MockIOrderStatsCache) GetOrderStats() (types.OrderStats, error) {
+func (m *MockIOrderStatsCache) GetOrderStats(ctx context.Context) (types.OrderStats, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetOrderStats")
+	ret := m.ctrl.Call(m, "GetOrderStats", ctx)
 	ret0, _ := ret[0].(types.OrderStats)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetOrderStats indicates an expected call of GetOrderStats.
-func (mr *MockIOrderStatsCacheMockRecorder) GetOrderStats() *gomock.Call {
+func (mr *MockIOrderStatsCacheMockRecorder) GetOrderStats(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderStats", reflect.TypeOf((*MockIOrderStatsCache)(nil).GetOrderStats))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderStats", reflect.TypeOf((*MockIOrderStatsCache)(nil).GetOrderStats), ctx)
 }
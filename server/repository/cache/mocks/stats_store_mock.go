@@ -0,0 +1,96 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: cache/stats_store.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	types "github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/types"
+	cache "github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/cache"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockStatsStore is a mock of StatsStore interface.
+type MockStatsStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockStatsStoreMockRecorder
+}
+
+// MockStatsStoreMockRecorder is the mock recorder for MockStatsStore.
+type MockStatsStoreMockRecorder struct {
+	mock *MockStatsStore
+}
+
+// NewMockStatsStore creates a new mock instance.
+func NewMockStatsStore(ctrl *gomock.Controller) *MockStatsStore {
+	mock := &MockStatsStore{ctrl: ctrl}
+	mock.recorder = &MockStatsStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStatsStore) EXPECT() *MockStatsStoreMockRecorder {
+	return m.recorder
+}
+
+// AddOrder mocks base method.
+func (m *MockStatsStore) AddOrder(ctx context.Context, orderNo string, scopes []string, amount, userID int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddOrder", ctx, orderNo, scopes, amount, userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddOrder indicates an expected call of AddOrder.
+func (mr *MockStatsStoreMockRecorder) AddOrder(ctx, orderNo, scopes, amount, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddOrder", reflect.TypeOf((*MockStatsStore)(nil).AddOrder), ctx, orderNo, scopes, amount, userID)
+}
+
+// AdjustOrder mocks base method.
+func (m *MockStatsStore) AdjustOrder(ctx context.Context, orderNo string, scopes []string, amount int, counts bool) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdjustOrder", ctx, orderNo, scopes, amount, counts)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdjustOrder indicates an expected call of AdjustOrder.
+func (mr *MockStatsStoreMockRecorder) AdjustOrder(ctx, orderNo, scopes, amount, counts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdjustOrder", reflect.TypeOf((*MockStatsStore)(nil).AdjustOrder), ctx, orderNo, scopes, amount, counts)
+}
+
+// Get mocks base method.
+func (m *MockStatsStore) Get(ctx context.Context, scope string) (types.OrderStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, scope)
+	ret0, _ := ret[0].(types.OrderStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockStatsStoreMockRecorder) Get(ctx, scope interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockStatsStore)(nil).Get), ctx, scope)
+}
+
+// Replace mocks base method.
+func (m *MockStatsStore) Replace(ctx context.Context, snapshots map[string]cache.ScopeSnapshot) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replace", ctx, snapshots)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Replace indicates an expected call of Replace.
+func (mr *MockStatsStoreMockRecorder) Replace(ctx, snapshots interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replace", reflect.TypeOf((*MockStatsStore)(nil).Replace), ctx, snapshots)
+}
@@ -1,72 +1,232 @@
 package cache
 
 import (
+	"context"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/consts"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/types"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/utils"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/dao"
+	"github.com/segmentio/kafka-go"
+)
+
+const (
+	STATS_RECONCILE_LOCK_KEY = "order:stats:reconcile:lock"
+	STATS_RECONCILE_LOCK_EXP = 5 * time.Minute
 )
 
 type IOrderStatsCache interface {
-	GetOrderStats() (types.OrderStats, error)
+	GetOrderStats(ctx context.Context) (types.OrderStats, error)
+	GetMerchantOrderStats(ctx context.Context, merchantID int) (types.OrderStats, error)
 }
 
-type orderStatsCache struct {
-	data     *types.OrderStats
-	orderDao dao.OrderDao
+var _ IOrderStatsCache = (*OrderStatsCache)(nil)
+
+// OrderStatsCache serves the order stats from counters in the StatsStore.
+// Order paid events increment the counters, cancellations and edits adjust
+// them, and Reconcile, called after each refresh of the daily summary,
+// rebuilds them from the summary.
+//
+// When the store cannot be read the last value read is served instead, so
+// the stats degrade to stale rather than failing.
+type OrderStatsCache struct {
+	store      StatsStore
+	orderDao   dao.OrderDao
+	summaryDao dao.OrderSummaryDao
+	locker     utils.Locker
+
+	mu   sync.RWMutex
+	last map[string]types.OrderStats // 每个范围最近一次读到的值
 }
 
-var (
-	orderStatsCacheInstance IOrderStatsCache
-	orderStatsCacheSyncOnce sync.Once
-)
+func NewOrderStatsCache(store StatsStore, orderDao dao.OrderDao, summaryDao dao.OrderSummaryDao, locker utils.Locker) *OrderStatsCache {
+	return &OrderStatsCache{
+		store:      store,
+		orderDao:   orderDao,
+		summaryDao: summaryDao,
+		locker:     locker,
+		last:       make(map[string]types.OrderStats),
+	}
+}
 
-func GetOrderStatsCache() IOrderStatsCache {
-	orderStatsCacheSyncOnce.Do(func() {
-		orderStatsCache := &orderStatsCache{
-			orderDao: dao.GetOrderDao(),
-		}
-		err := orderStatsCache.loadOrderStats()
-		if err != nil {
-			panic(err)
+// GetOrderStats implements IOrderStatsCache.
+func (o *OrderStatsCache) GetOrderStats(ctx context.Context) (types.OrderStats, error) {
+	return o.get(ctx, ScopeGlobal)
+}
+
+// GetMerchantOrderStats implements IOrderStatsCache. A merchant with no
+// counted orders gets zero stats.
+func (o *OrderStatsCache) GetMerchantOrderStats(ctx context.Context, merchantID int) (types.OrderStats, error) {
+	return o.get(ctx, MerchantScope(merchantID))
+}
+
+func (o *OrderStatsCache) get(ctx context.Context, scope string) (types.OrderStats, error) {
+	stats, err := o.store.Get(ctx, scope)
+	if err != nil {
+		o.mu.RLock()
+		stale, ok := o.last[scope]
+		o.mu.RUnlock()
+		if !ok {
+			log.FromContext(ctx).Errorf("failed to read order stats of %s: %v", scope, err)
+			return types.OrderStats{}, err
 		}
-		go orderStatsCache.startLoadDataSchedule()
-		orderStatsCacheInstance = orderStatsCache
-	})
-	return orderStatsCacheInstance
+		log.FromContext(ctx).Warnf("failed to read order stats of %s, serving stale stats: %v", scope, err)
+		return stale, nil
+	}
+	withAverage(&stats)
+	o.remember(map[string]types.OrderStats{scope: stats})
+	return stats, nil
 }
 
-// GetOrderStats implements IOrderStatsCache.
-func (o *orderStatsCache) GetOrderStats() (types.OrderStats, error) {
-	if o.data == nil {
-		return o.orderDao.GetOrderStats()
+func (o *OrderStatsCache) remember(stats map[string]types.OrderStats) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for scope, s := range stats {
+		o.last[scope] = s
 	}
-	return *o.data, nil
 }
 
-func (o *orderStatsCache) startLoadDataSchedule() {
-	ticker := time.NewTicker(1 * time.Minute)
-	defer ticker.Stop()
-	for range ticker.C {
-		err := o.loadOrderStats()
-		if err != nil {
-			log.Logger.Errorf("failed to reload order stats: %v", err)
+// OnOrderPaid counts a paid order in the global and merchant stats. Failures
+// are only logged: the next reconciliation corrects the counters.
+func (o *OrderStatsCache) OnOrderPaid(ctx context.Context, orderNo string) {
+	logger := log.FromContext(ctx).With("order_no", orderNo)
+	order, err := o.orderDao.GetByOrderNo(ctx, orderNo)
+	if err != nil {
+		logger.Errorf("OnOrderPaid: get order failed, err: %s", err.Error())
+		return
+	}
+	if !slices.Contains(dao.SalesStatuses, order.Status) {
+		return
+	}
+	scopes := []string{ScopeGlobal, MerchantScope(order.MerchantID)}
+	added, err := o.store.AddOrder(ctx, orderNo, scopes, order.TotalAmount, order.UserID)
+	if err != nil {
+		logger.Errorf("OnOrderPaid: update order stats failed, err: %s", err.Error())
+		return
+	}
+	if !added {
+		logger.Infof("OnOrderPaid: order already counted")
+	}
+}
+
+// OnOrderChanged applies the change of a counted order's amount, or removes
+// the order once it is canceled. Failures are only logged: the next
+// reconciliation corrects the counters.
+func (o *OrderStatsCache) OnOrderChanged(ctx context.Context, orderNo string) {
+	logger := log.FromContext(ctx).With("order_no", orderNo)
+	order, err := o.orderDao.GetByOrderNo(ctx, orderNo)
+	if err != nil {
+		logger.Errorf("OnOrderChanged: get order failed, err: %s", err.Error())
+		return
+	}
+	scopes := []string{ScopeGlobal, MerchantScope(order.MerchantID)}
+	counts := slices.Contains(dao.SalesStatuses, order.Status)
+	if _, err = o.store.AdjustOrder(ctx, orderNo, scopes, order.TotalAmount, counts); err != nil {
+		logger.Errorf("OnOrderChanged: update order stats failed, err: %s", err.Error())
+	}
+}
+
+// Reconcile rebuilds the counters from the daily summary, so it should run
+// right after the summary is refreshed. Only the instance holding the lock
+// runs it; the others skip this round.
+func (o *OrderStatsCache) Reconcile(ctx context.Context) {
+	if err := o.locker.Lock(ctx); err != nil {
+		log.Logger.Info("Reconcile order stats: failed to acquire lock, skipping this round")
+		return
+	}
+	defer func() {
+		if err := o.locker.Unlock(ctx); err != nil {
+			log.Logger.Errorf("Reconcile order stats: failed to release lock, err: %s", err.Error())
 		}
+	}()
+
+	snapshots, err := o.loadSnapshots(ctx)
+	if err != nil {
+		log.Logger.Errorf("Reconcile order stats: load stats failed, err: %s", err.Error())
+		return
+	}
+	if err = o.store.Replace(ctx, snapshots); err != nil {
+		log.Logger.Errorf("Reconcile order stats: save stats failed, err: %s", err.Error())
+		return
 	}
+
+	stats := make(map[string]types.OrderStats, len(snapshots))
+	for scope, snapshot := range snapshots {
+		s := types.OrderStats{TotalOrders: snapshot.TotalOrders, TotalSales: snapshot.TotalSales, TotalCustomers: len(snapshot.CustomerIDs)}
+		withAverage(&s)
+		stats[scope] = s
+	}
+	o.remember(stats)
+	log.Logger.Infof("successfully reconciled order stats: %+v", stats[ScopeGlobal])
 }
 
-func (o *orderStatsCache) loadOrderStats() error {
-	stats, err := o.orderDao.GetOrderStats()
+// loadSnapshots 订单数和销售额取自日汇总表，用户无法按日累加，仍从订单表查询。
+// 全局统计为所有商家之和，用户取并集
+func (o *OrderStatsCache) loadSnapshots(ctx context.Context) (map[string]ScopeSnapshot, error) {
+	merchantStats, err := o.summaryDao.GetMerchantTotals(ctx)
+	if err != nil {
+		return nil, err
+	}
+	customers, err := o.orderDao.GetPaidCustomers(ctx)
 	if err != nil {
-		log.Logger.Errorf("failed to load order stats: %v", err)
-		return err
+		return nil, err
 	}
+
+	snapshots := make(map[string]ScopeSnapshot, len(merchantStats)+1)
+	var global ScopeSnapshot
+	globalCustomers := make(map[int]struct{})
+	for merchantID, stats := range merchantStats {
+		snapshots[MerchantScope(merchantID)] = ScopeSnapshot{
+			TotalOrders: stats.TotalOrders,
+			TotalSales:  stats.TotalSales,
+			CustomerIDs: customers[merchantID],
+		}
+		global.TotalOrders += stats.TotalOrders
+		global.TotalSales += stats.TotalSales
+		for _, userID := range customers[merchantID] {
+			globalCustomers[userID] = struct{}{}
+		}
+	}
+	for userID := range globalCustomers {
+		global.CustomerIDs = append(global.CustomerIDs, userID)
+	}
+	snapshots[ScopeGlobal] = global
+	return snapshots, nil
+}
+
+// orderStatsEvent 统计用到的订单事件字段，只有状态变更消息带 current_status
+type orderStatsEvent struct {
+	CurrentStatus *int `json:"current_status"`
+}
+
+// NewOrderStatsHandler keeps the order stats in step with the order events,
+// which are all keyed by order number: paid orders are counted, while
+// cancellations, edits and refunds adjust the orders already counted. Other
+// status changes don't affect the stats.
+func NewOrderStatsHandler(statsCache *OrderStatsCache) utils.MessageHandler {
+	return func(ctx context.Context, msgRaw kafka.Message) error {
+		var event orderStatsEvent
+		if err := utils.JSONDecode(string(msgRaw.Value), &event); err != nil {
+			log.Logger.Errorf("parse json failed, err = %s", err.Error())
+			return nil
+		}
+		orderNo := string(msgRaw.Key)
+		switch {
+		case event.CurrentStatus == nil, *event.CurrentStatus == consts.CANCELED:
+			statsCache.OnOrderChanged(ctx, orderNo)
+		case *event.CurrentStatus == consts.PAYED:
+			statsCache.OnOrderPaid(ctx, orderNo)
+		}
+		return nil
+	}
+}
+
+func withAverage(stats *types.OrderStats) {
 	if stats.TotalOrders > 0 {
 		stats.AvgSalesPerOrder = stats.TotalSales / stats.TotalOrders
 	}
-	o.data = &stats
-	log.Logger.Infof("successfully loaded order stats: %+v", stats)
-	return nil
 }
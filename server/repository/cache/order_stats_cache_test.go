@@ -0,0 +1,149 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/consts"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/types"
+	utilMocks "github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/utils/mocks"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/cache"
+	cacheMocks "github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/cache/mocks"
+	daoMocks "github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/dao/mocks"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/model"
+	"github.com/golang/mock/gomock"
+	"github.com/segmentio/kafka-go"
+	"go.uber.org/zap"
+)
+
+func init() {
+	// 初始化测试用logger
+	logger, _ := zap.NewDevelopment()
+	log.Logger = logger.Sugar()
+}
+
+func TestOrderStatsCache_GetOrderStats_Degraded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := cacheMocks.NewMockStatsStore(ctrl)
+	ctx := context.Background()
+	statsCache := cache.NewOrderStatsCache(mockStore, daoMocks.NewMockOrderDao(ctrl), daoMocks.NewMockOrderSummaryDao(ctrl), utilMocks.NewMockLocker(ctrl))
+	redisErr := errors.New("redis down")
+
+	// 从未读到过数据时返回错误
+	mockStore.EXPECT().Get(ctx, cache.ScopeGlobal).Return(types.OrderStats{}, redisErr)
+	if _, err := statsCache.GetOrderStats(ctx); !errors.Is(err, redisErr) {
+		t.Fatalf("GetOrderStats() err = %v, want %v", err, redisErr)
+	}
+
+	mockStore.EXPECT().Get(ctx, cache.ScopeGlobal).Return(types.OrderStats{TotalOrders: 4, TotalSales: 1000, TotalCustomers: 3}, nil)
+	want := types.OrderStats{TotalOrders: 4, TotalSales: 1000, TotalCustomers: 3, AvgSalesPerOrder: 250}
+	if stats, err := statsCache.GetOrderStats(ctx); err != nil || stats != want {
+		t.Fatalf("GetOrderStats() = %+v, %v, want %+v", stats, err, want)
+	}
+
+	// 之后读取失败时返回上一次的值
+	mockStore.EXPECT().Get(ctx, cache.ScopeGlobal).Return(types.OrderStats{}, redisErr)
+	if stats, err := statsCache.GetOrderStats(ctx); err != nil || stats != want {
+		t.Errorf("degraded GetOrderStats() = %+v, %v, want stale %+v", stats, err, want)
+	}
+}
+
+func TestOrderStatsCache_Reconcile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := cacheMocks.NewMockStatsStore(ctrl)
+	mockOrderDao := daoMocks.NewMockOrderDao(ctrl)
+	mockSummaryDao := daoMocks.NewMockOrderSummaryDao(ctrl)
+	mockLocker := utilMocks.NewMockLocker(ctrl)
+	ctx := context.Background()
+	statsCache := cache.NewOrderStatsCache(mockStore, mockOrderDao, mockSummaryDao, mockLocker)
+
+	mockLocker.EXPECT().Lock(ctx).Return(nil)
+	mockLocker.EXPECT().Unlock(ctx).Return(nil)
+	mockSummaryDao.EXPECT().GetMerchantTotals(ctx).Return(map[int]types.OrderStats{
+		1: {TotalOrders: 3, TotalSales: 300},
+		7: {TotalOrders: 1, TotalSales: 500},
+	}, nil)
+	mockOrderDao.EXPECT().GetPaidCustomers(ctx).Return(map[int][]int{1: {10, 11}, 7: {11}}, nil)
+	mockStore.EXPECT().Replace(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, snapshots map[string]cache.ScopeSnapshot) error {
+		global := snapshots[cache.ScopeGlobal]
+		slices.Sort(global.CustomerIDs)
+		if global.TotalOrders != 4 || global.TotalSales != 800 || !reflect.DeepEqual(global.CustomerIDs, []int{10, 11}) {
+			t.Errorf("global snapshot = %+v", global)
+		}
+		if merchant := snapshots[cache.MerchantScope(7)]; merchant.TotalOrders != 1 || !reflect.DeepEqual(merchant.CustomerIDs, []int{11}) {
+			t.Errorf("merchant snapshot = %+v", merchant)
+		}
+		return nil
+	})
+	statsCache.Reconcile(ctx)
+
+	// 对账结果可在存储不可用时提供
+	mockStore.EXPECT().Get(ctx, cache.MerchantScope(7)).Return(types.OrderStats{}, errors.New("redis down"))
+	want := types.OrderStats{TotalOrders: 1, TotalSales: 500, TotalCustomers: 1, AvgSalesPerOrder: 500}
+	if stats, err := statsCache.GetMerchantOrderStats(ctx, 7); err != nil || stats != want {
+		t.Errorf("GetMerchantOrderStats() = %+v, %v, want %+v", stats, err, want)
+	}
+}
+
+func TestOrderStatsCache_Reconcile_LockFailed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLocker := utilMocks.NewMockLocker(ctrl)
+	ctx := context.Background()
+	statsCache := cache.NewOrderStatsCache(cacheMocks.NewMockStatsStore(ctrl), daoMocks.NewMockOrderDao(ctrl), daoMocks.NewMockOrderSummaryDao(ctrl), mockLocker)
+
+	// 其他实例正在对账，本轮不查询也不写入
+	mockLocker.EXPECT().Lock(ctx).Return(errors.New("lock held"))
+	statsCache.Reconcile(ctx)
+}
+
+func TestNewOrderStatsHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := cacheMocks.NewMockStatsStore(ctrl)
+	mockOrderDao := daoMocks.NewMockOrderDao(ctrl)
+	ctx := context.Background()
+	handler := cache.NewOrderStatsHandler(cache.NewOrderStatsCache(mockStore, mockOrderDao, daoMocks.NewMockOrderSummaryDao(ctrl), utilMocks.NewMockLocker(ctrl)))
+
+	scopes := []string{cache.ScopeGlobal, cache.MerchantScope(7)}
+	mockOrderDao.EXPECT().GetByOrderNo(ctx, "Or001").Return(&model.Order{
+		OrderNo: "Or001", UserID: 10, MerchantID: 7, Status: consts.PAYED, TotalAmount: 500,
+	}, nil)
+	mockStore.EXPECT().AddOrder(ctx, "Or001", scopes, 500, 10).Return(true, nil)
+	if err := handler(ctx, kafka.Message{Key: []byte("Or001"), Value: []byte(`{"order_no":"Or001","user_id":10,"current_status":2}`)}); err != nil {
+		t.Fatalf("handler() err = %v", err)
+	}
+
+	// 其他状态变更不计入统计
+	if err := handler(ctx, kafka.Message{Key: []byte("Or001"), Value: []byte(`{"order_no":"Or001","user_id":10,"current_status":3}`)}); err != nil {
+		t.Fatalf("handler() err = %v", err)
+	}
+
+	// 修改订单后按新的总金额修正
+	mockOrderDao.EXPECT().GetByOrderNo(ctx, "Or001").Return(&model.Order{
+		OrderNo: "Or001", UserID: 10, MerchantID: 7, Status: consts.PAYED, TotalAmount: 300,
+	}, nil)
+	mockStore.EXPECT().AdjustOrder(ctx, "Or001", scopes, 300, true).Return(true, nil)
+	if err := handler(ctx, kafka.Message{Key: []byte("Or001"), Value: []byte(`{"order_no":"Or001","merchant_id":7,"total_amount":300,"refund_amount":200}`)}); err != nil {
+		t.Fatalf("handler() err = %v", err)
+	}
+
+	// 取消的订单从统计中移除
+	mockOrderDao.EXPECT().GetByOrderNo(ctx, "Or002").Return(&model.Order{
+		OrderNo: "Or002", UserID: 10, MerchantID: 7, Status: consts.CANCELED, TotalAmount: 800,
+	}, nil)
+	mockStore.EXPECT().AdjustOrder(ctx, "Or002", scopes, 800, false).Return(false, nil)
+	if err := handler(ctx, kafka.Message{Key: []byte("Or002"), Value: []byte(`{"order_no":"Or002","user_id":10,"current_status":5}`)}); err != nil {
+		t.Fatalf("handler() err = %v", err)
+	}
+}
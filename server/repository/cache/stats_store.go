@@ -0,0 +1,209 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/types"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// ScopeGlobal 全部订单的统计范围
+const ScopeGlobal = "global"
+
+// MerchantScope 商家订单的统计范围
+func MerchantScope(merchantID int) string {
+	return "merchant:" + strconv.Itoa(merchantID)
+}
+
+// ScopeSnapshot 一个统计范围的完整数据，用于对账后整体替换
+type ScopeSnapshot struct {
+	TotalOrders int
+	TotalSales  int
+	CustomerIDs []int
+}
+
+// StatsStore keeps the order counters shared by every instance.
+type StatsStore interface {
+	// Get reads the counters of one scope as a consistent snapshot.
+	Get(ctx context.Context, scope string) (types.OrderStats, error)
+	// AddOrder counts a paid order in every scope once; a redelivered order
+	// returns false and changes nothing.
+	AddOrder(ctx context.Context, orderNo string, scopes []string, amount int, userID int) (added bool, err error)
+	// AdjustOrder brings a counted order up to date: it applies the change of
+	// its amount, or removes it when it no longer counts. Orders that were
+	// never counted return false and change nothing.
+	AdjustOrder(ctx context.Context, orderNo string, scopes []string, amount int, counts bool) (adjusted bool, err error)
+	// Replace swaps in the reconciled counters of every scope.
+	Replace(ctx context.Context, snapshots map[string]ScopeSnapshot) error
+}
+
+const (
+	statsKeyPrefix    = "order:stats:"
+	statsCountedTTL   = 30 * 24 * time.Hour // 计入标记保留时间，覆盖消息重投和发货前修改订单的窗口
+	statsFieldOrders  = "total_orders"
+	statsFieldSales   = "total_sales"
+	statsSAddBatch    = 1000
+	statsTmpKeySuffix = ":tmp"
+)
+
+// addOrderScript 去重后在每个范围内累加订单数、销售额并记录用户
+// KEYS[1] 计入标记，值为已计入的销售额，之后每两个 key 为一个范围的计数 hash 和用户 set
+// ARGV[1] 销售额，ARGV[2] 用户，ARGV[3] 计入标记过期秒数
+const addOrderScript = `
+if not redis.call("set", KEYS[1], ARGV[1], "NX", "EX", ARGV[3]) then
+    return 0
+end
+for i = 2, #KEYS, 2 do
+    redis.call("hincrby", KEYS[i], "total_orders", 1)
+    redis.call("hincrby", KEYS[i], "total_sales", ARGV[1])
+    redis.call("sadd", KEYS[i + 1], ARGV[2])
+end
+return 1
+`
+
+// adjustOrderScript 按计入标记中的销售额修正已计入的订单：仍计入时补上金额差，
+// 否则减去订单数和销售额并删除标记。用户可能还有其他订单，不从用户 set 中移除，由对账修正
+// KEYS[1] 计入标记，之后每个 key 为一个范围的计数 hash
+// ARGV[1] 当前销售额，ARGV[2] 是否仍计入
+const adjustOrderScript = `
+local counted = redis.call("get", KEYS[1])
+if not counted then
+    return 0
+end
+local orders, sales = 0, tonumber(ARGV[1]) - tonumber(counted)
+if ARGV[2] == "1" then
+    redis.call("set", KEYS[1], ARGV[1], "KEEPTTL")
+else
+    orders, sales = -1, -tonumber(counted)
+    redis.call("del", KEYS[1])
+end
+if orders == 0 and sales == 0 then
+    return 0
+end
+for i = 2, #KEYS do
+    redis.call("hincrby", KEYS[i], "total_orders", orders)
+    redis.call("hincrby", KEYS[i], "total_sales", sales)
+end
+return 1
+`
+
+// RedisStatsStore is the StatsStore backed by Redis: a hash of counters and
+// a set of customer IDs per scope.
+type RedisStatsStore struct {
+	client       *goredis.Client
+	addScript    *goredis.Script
+	adjustScript *goredis.Script
+}
+
+var _ StatsStore = (*RedisStatsStore)(nil)
+
+func NewRedisStatsStore(client *goredis.Client) *RedisStatsStore {
+	return &RedisStatsStore{
+		client:       client,
+		addScript:    goredis.NewScript(addOrderScript),
+		adjustScript: goredis.NewScript(adjustOrderScript),
+	}
+}
+
+func countersKey(scope string) string  { return statsKeyPrefix + scope }
+func customersKey(scope string) string { return statsKeyPrefix + scope + ":customers" }
+func countedKey(orderNo string) string { return statsKeyPrefix + "counted:" + orderNo }
+
+func (s *RedisStatsStore) Get(ctx context.Context, scope string) (types.OrderStats, error) {
+	var counters *goredis.SliceCmd
+	var customers *goredis.IntCmd
+	_, err := s.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		counters = pipe.HMGet(ctx, countersKey(scope), statsFieldOrders, statsFieldSales)
+		customers = pipe.SCard(ctx, customersKey(scope))
+		return nil
+	})
+	if err != nil {
+		return types.OrderStats{}, err
+	}
+	values := counters.Val()
+	stats := types.OrderStats{TotalCustomers: int(customers.Val())}
+	if stats.TotalOrders, err = counterValue(values[0]); err != nil {
+		return types.OrderStats{}, err
+	}
+	if stats.TotalSales, err = counterValue(values[1]); err != nil {
+		return types.OrderStats{}, err
+	}
+	return stats, nil
+}
+
+// counterValue 解析 HMGET 的结果，字段不存在时为 0
+func counterValue(v interface{}) (int, error) {
+	if v == nil {
+		return 0, nil
+	}
+	str, ok := v.(string)
+	if !ok {
+		return 0, fmt.Errorf("unexpected counter value %v", v)
+	}
+	return strconv.Atoi(str)
+}
+
+func (s *RedisStatsStore) AddOrder(ctx context.Context, orderNo string, scopes []string, amount int, userID int) (bool, error) {
+	keys := make([]string, 0, 1+2*len(scopes))
+	keys = append(keys, countedKey(orderNo))
+	for _, scope := range scopes {
+		keys = append(keys, countersKey(scope), customersKey(scope))
+	}
+	added, err := s.addScript.Run(ctx, s.client, keys, amount, userID, int(statsCountedTTL.Seconds())).Int()
+	return added == 1, err
+}
+
+func (s *RedisStatsStore) AdjustOrder(ctx context.Context, orderNo string, scopes []string, amount int, counts bool) (bool, error) {
+	keys := make([]string, 0, 1+len(scopes))
+	keys = append(keys, countedKey(orderNo))
+	for _, scope := range scopes {
+		keys = append(keys, countersKey(scope))
+	}
+	stillCounts := 0
+	if counts {
+		stillCounts = 1
+	}
+	adjusted, err := s.adjustScript.Run(ctx, s.client, keys, amount, stillCounts).Int()
+	return adjusted == 1, err
+}
+
+// Replace builds every scope under temporary keys first and then renames
+// them in one transaction, so readers never see a half-written snapshot.
+func (s *RedisStatsStore) Replace(ctx context.Context, snapshots map[string]ScopeSnapshot) error {
+	_, err := s.client.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+		for scope, snapshot := range snapshots {
+			counters, customers := countersKey(scope)+statsTmpKeySuffix, customersKey(scope)+statsTmpKeySuffix
+			pipe.Del(ctx, counters, customers)
+			pipe.HSet(ctx, counters, statsFieldOrders, snapshot.TotalOrders, statsFieldSales, snapshot.TotalSales)
+			for start := 0; start < len(snapshot.CustomerIDs); start += statsSAddBatch {
+				end := min(start+statsSAddBatch, len(snapshot.CustomerIDs))
+				members := make([]interface{}, 0, end-start)
+				for _, id := range snapshot.CustomerIDs[start:end] {
+					members = append(members, id)
+				}
+				pipe.SAdd(ctx, customers, members...)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		for scope, snapshot := range snapshots {
+			pipe.Rename(ctx, countersKey(scope)+statsTmpKeySuffix, countersKey(scope))
+			// 空 set 不存在，无法 rename
+			if len(snapshot.CustomerIDs) == 0 {
+				pipe.Del(ctx, customersKey(scope))
+			} else {
+				pipe.Rename(ctx, customersKey(scope)+statsTmpKeySuffix, customersKey(scope))
+			}
+		}
+		return nil
+	})
+	return err
+}
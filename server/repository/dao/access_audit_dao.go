@@ -0,0 +1,25 @@
+package dao
+
+import (
+	"context"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/model"
+	"gorm.io/gorm"
+)
+
+type AccessAuditDao interface {
+	Create(ctx context.Context, auditLog *model.AccessAuditLog) (id int, err error)
+}
+
+type AccessAuditDaoImpl struct {
+	db *gorm.DB
+}
+
+func NewAccessAuditDao(db *gorm.DB) *AccessAuditDaoImpl {
+	return &AccessAuditDaoImpl{db: db}
+}
+
+func (d *AccessAuditDaoImpl) Create(ctx context.Context, auditLog *model.AccessAuditLog) (id int, err error) {
+	result := d.db.WithContext(ctx).Create(auditLog)
+	return auditLog.ID, result.Error
+}
@@ -0,0 +1,45 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// JobCheckpointDao keeps the progress of jobs that walk a table by ID.
+type JobCheckpointDao interface {
+	// GetPosition returns the last ID the job processed; ok is false before its first save.
+	GetPosition(ctx context.Context, name string) (position int, ok bool, err error)
+	SavePosition(ctx context.Context, name string, position int) error
+}
+
+type JobCheckpointDaoImpl struct {
+	db *gorm.DB
+}
+
+func NewJobCheckpointDao(db *gorm.DB) *JobCheckpointDaoImpl {
+	return &JobCheckpointDaoImpl{db: db}
+}
+
+func (d *JobCheckpointDaoImpl) GetPosition(ctx context.Context, name string) (position int, ok bool, err error) {
+	checkpoint := &model.JobCheckpoint{}
+	err = d.db.WithContext(ctx).Where("name = ?", name).First(checkpoint).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return checkpoint.Position, true, nil
+}
+
+// SavePosition also records the save time as the watermark.
+func (d *JobCheckpointDaoImpl) SavePosition(ctx context.Context, name string, position int) error {
+	return d.db.WithContext(ctx).
+		Clauses(clause.OnConflict{UpdateAll: true}).
+		Create(&model.JobCheckpoint{Name: name, Watermark: time.Now(), Position: position}).Error
+}
@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: dao/access_audit_dao.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAccessAuditDao is a mock of AccessAuditDao interface.
+type MockAccessAuditDao struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccessAuditDaoMockRecorder
+}
+
+// MockAccessAuditDaoMockRecorder is the mock recorder for MockAccessAuditDao.
+type MockAccessAuditDaoMockRecorder struct {
+	mock *MockAccessAuditDao
+}
+
+// NewMockAccessAuditDao creates a new mock instance.
+func NewMockAccessAuditDao(ctrl *gomock.Controller) *MockAccessAuditDao {
+	mock := &MockAccessAuditDao{ctrl: ctrl}
+	mock.recorder = &MockAccessAuditDaoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccessAuditDao) EXPECT() *MockAccessAuditDaoMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAccessAuditDao) Create(ctx context.Context, auditLog *model.AccessAuditLog) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, auditLog)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAccessAuditDaoMockRecorder) Create(ctx, auditLog interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAccessAuditDao)(nil).Create), ctx, auditLog)
+}
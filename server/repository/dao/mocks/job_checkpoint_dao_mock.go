@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: dao/job_checkpoint_dao.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockJobCheckpointDao is a mock of JobCheckpointDao interface.
+type MockJobCheckpointDao struct {
+	ctrl     *gomock.Controller
+	recorder *MockJobCheckpointDaoMockRecorder
+}
+
+// MockJobCheckpointDaoMockRecorder is the mock recorder for MockJobCheckpointDao.
+type MockJobCheckpointDaoMockRecorder struct {
+	mock *MockJobCheckpointDao
+}
+
+// NewMockJobCheckpointDao creates a new mock instance.
+func NewMockJobCheckpointDao(ctrl *gomock.Controller) *MockJobCheckpointDao {
+	mock := &MockJobCheckpointDao{ctrl: ctrl}
+	mock.recorder = &MockJobCheckpointDaoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJobCheckpointDao) EXPECT() *MockJobCheckpointDaoMockRecorder {
+	return m.recorder
+}
+
+// GetPosition mocks base method.
+func (m *MockJobCheckpointDao) GetPosition(ctx context.Context, name string) (int, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPosition", ctx, name)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetPosition indicates an expected call of GetPosition.
+func (mr *MockJobCheckpointDaoMockRecorder) GetPosition(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPosition", reflect.TypeOf((*MockJobCheckpointDao)(nil).GetPosition), ctx, name)
+}
+
+// SavePosition mocks base method.
+func (m *MockJobCheckpointDao) SavePosition(ctx context.Context, name string, position int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SavePosition", ctx, name, position)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SavePosition indicates an expected call of SavePosition.
+func (mr *MockJobCheckpointDaoMockRecorder) SavePosition(ctx, name, position interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SavePosition", reflect.TypeOf((*MockJobCheckpointDao)(nil).SavePosition), ctx, name, position)
+}
@@ -0,0 +1,127 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: dao/order_analytics_dao.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	dao "github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/dao"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockOrderAnalyticsDao is a mock of OrderAnalyticsDao interface.
+type MockOrderAnalyticsDao struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrderAnalyticsDaoMockRecorder
+}
+
+// MockOrderAnalyticsDaoMockRecorder is the mock recorder for MockOrderAnalyticsDao.
+type MockOrderAnalyticsDaoMockRecorder struct {
+	mock *MockOrderAnalyticsDao
+}
+
+// NewMockOrderAnalyticsDao creates a new mock instance.
+func NewMockOrderAnalyticsDao(ctrl *gomock.Controller) *MockOrderAnalyticsDao {
+	mock := &MockOrderAnalyticsDao{ctrl: ctrl}
+	mock.recorder = &MockOrderAnalyticsDaoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrderAnalyticsDao) EXPECT() *MockOrderAnalyticsDaoMockRecorder {
+	return m.recorder
+}
+
+// CountByStatus mocks base method.
+func (m *MockOrderAnalyticsDao) CountByStatus(ctx context.Context, filter dao.AnalyticsFilter) (map[int]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountByStatus", ctx, filter)
+	ret0, _ := ret[0].(map[int]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountByStatus indicates an expected call of CountByStatus.
+func (mr *MockOrderAnalyticsDaoMockRecorder) CountByStatus(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByStatus", reflect.TypeOf((*MockOrderAnalyticsDao)(nil).CountByStatus), ctx, filter)
+}
+
+// GetAvgShipSeconds mocks base method.
+func (m *MockOrderAnalyticsDao) GetAvgShipSeconds(ctx context.Context, filter dao.AnalyticsFilter) (float64, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAvgShipSeconds", ctx, filter)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAvgShipSeconds indicates an expected call of GetAvgShipSeconds.
+func (mr *MockOrderAnalyticsDaoMockRecorder) GetAvgShipSeconds(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAvgShipSeconds", reflect.TypeOf((*MockOrderAnalyticsDao)(nil).GetAvgShipSeconds), ctx, filter)
+}
+
+// GetCountrySales mocks base method.
+func (m *MockOrderAnalyticsDao) GetCountrySales(ctx context.Context, filter dao.AnalyticsFilter) ([]*dao.CountrySales, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCountrySales", ctx, filter)
+	ret0, _ := ret[0].([]*dao.CountrySales)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCountrySales indicates an expected call of GetCountrySales.
+func (mr *MockOrderAnalyticsDaoMockRecorder) GetCountrySales(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCountrySales", reflect.TypeOf((*MockOrderAnalyticsDao)(nil).GetCountrySales), ctx, filter)
+}
+
+// GetCustomerStats mocks base method.
+func (m *MockOrderAnalyticsDao) GetCustomerStats(ctx context.Context, filter dao.AnalyticsFilter) (dao.CustomerStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCustomerStats", ctx, filter)
+	ret0, _ := ret[0].(dao.CustomerStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCustomerStats indicates an expected call of GetCustomerStats.
+func (mr *MockOrderAnalyticsDaoMockRecorder) GetCustomerStats(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCustomerStats", reflect.TypeOf((*MockOrderAnalyticsDao)(nil).GetCustomerStats), ctx, filter)
+}
+
+// GetSalesSlots mocks base method.
+func (m *MockOrderAnalyticsDao) GetSalesSlots(ctx context.Context, filter dao.AnalyticsFilter) ([]*dao.SalesSlot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSalesSlots", ctx, filter)
+	ret0, _ := ret[0].([]*dao.SalesSlot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSalesSlots indicates an expected call of GetSalesSlots.
+func (mr *MockOrderAnalyticsDaoMockRecorder) GetSalesSlots(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSalesSlots", reflect.TypeOf((*MockOrderAnalyticsDao)(nil).GetSalesSlots), ctx, filter)
+}
+
+// GetTopProducts mocks base method.
+func (m *MockOrderAnalyticsDao) GetTopProducts(ctx context.Context, filter dao.AnalyticsFilter, sortBy string, limit int) ([]*dao.ProductSales, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTopProducts", ctx, filter, sortBy, limit)
+	ret0, _ := ret[0].([]*dao.ProductSales)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTopProducts indicates an expected call of GetTopProducts.
+func (mr *MockOrderAnalyticsDaoMockRecorder) GetTopProducts(ctx, filter, sortBy, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTopProducts", reflect.TypeOf((*MockOrderAnalyticsDao)(nil).GetTopProducts), ctx, filter, sortBy, limit)
+}
@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: ./dao/order_dao.go
+// Source: dao/order_dao.go
 
 // Package mocks is a generated GoMock package.
 package mocks
@@ -68,6 +68,36 @@ func (mr *MockOrderDaoMockRecorder) Create(ctx, o interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockOrderDao)(nil).Create), ctx, o)
 }
 
+// GetAfterID mocks base method.
+func (m *MockOrderDao) GetAfterID(ctx context.Context, afterID, limit int) ([]*model.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAfterID", ctx, afterID, limit)
+	ret0, _ := ret[0].([]*model.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAfterID indicates an expected call of GetAfterID.
+func (mr *MockOrderDaoMockRecorder) GetAfterID(ctx, afterID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAfterID", reflect.TypeOf((*MockOrderDao)(nil).GetAfterID), ctx, afterID, limit)
+}
+
+// GetByCheckoutNo mocks base method.
+func (m *MockOrderDao) GetByCheckoutNo(ctx context.Context, checkoutNo string) ([]*model.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByCheckoutNo", ctx, checkoutNo)
+	ret0, _ := ret[0].([]*model.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByCheckoutNo indicates an expected call of GetByCheckoutNo.
+func (mr *MockOrderDaoMockRecorder) GetByCheckoutNo(ctx, checkoutNo interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByCheckoutNo", reflect.TypeOf((*MockOrderDao)(nil).GetByCheckoutNo), ctx, checkoutNo)
+}
+
 // GetByOrderNo mocks base method.
 func (m *MockOrderDao) GetByOrderNo(ctx context.Context, orderNo string) (*model.Order, error) {
 	m.ctrl.T.Helper()
@@ -83,15 +113,31 @@ func (mr *MockOrderDaoMockRecorder) GetByOrderNo(ctx, orderNo interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByOrderNo", reflect.TypeOf((*MockOrderDao)(nil).GetByOrderNo), ctx, orderNo)
 }
 
-// GetByOrderQuery mocks base method.
-func (m *MockOrderDao) GetByOrderQuery(ctx context.Context, query dao.OrderQuery) ([]*model.Order, error) {
+// GetByOrderNos mocks base method.
+func (m *MockOrderDao) GetByOrderNos(ctx context.Context, orderNos []string) ([]*model.Order, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByOrderQuery", ctx, query)
+	ret := m.ctrl.Call(m, "GetByOrderNos", ctx, orderNos)
 	ret0, _ := ret[0].([]*model.Order)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
+// GetByOrderNos indicates an expected call of GetByOrderNos.
+func (mr *MockOrderDaoMockRecorder) GetByOrderNos(ctx, orderNos interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByOrderNos", reflect.TypeOf((*MockOrderDao)(nil).GetByOrderNos), ctx, orderNos)
+}
+
+// GetByOrderQuery mocks base method.
+func (m *MockOrderDao) GetByOrderQuery(ctx context.Context, query dao.OrderQuery) ([]*model.Order, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByOrderQuery", ctx, query)
+	ret0, _ := ret[0].([]*model.Order)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
 // GetByOrderQuery indicates an expected call of GetByOrderQuery.
 func (mr *MockOrderDaoMockRecorder) GetByOrderQuery(ctx, query interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
@@ -113,44 +159,59 @@ func (mr *MockOrderDaoMockRecorder) GetOrderStats() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderStats", reflect.TypeOf((*MockOrderDao)(nil).GetOrderStats))
 }
 
-// UpdateStatusAndConfirmTime mocks base method.
-func (m *MockOrderDao) UpdateStatusAndConfirmTime(ctx context.Context, orderNo string, status int, t time.Time) error {
+// GetPaidCustomers mocks base method.
+func (m *MockOrderDao) GetPaidCustomers(ctx context.Context) (map[int][]int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateStatusAndConfirmTime", ctx, orderNo, status, t)
+	ret := m.ctrl.Call(m, "GetPaidCustomers", ctx)
+	ret0, _ := ret[0].(map[int][]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPaidCustomers indicates an expected call of GetPaidCustomers.
+func (mr *MockOrderDaoMockRecorder) GetPaidCustomers(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaidCustomers", reflect.TypeOf((*MockOrderDao)(nil).GetPaidCustomers), ctx)
+}
+
+// ShipOrders mocks base method.
+func (m *MockOrderDao) ShipOrders(ctx context.Context, shipments []dao.ShipmentUpdate, fromStatuses []int, toStatus int, t time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ShipOrders", ctx, shipments, fromStatuses, toStatus, t)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// UpdateStatusAndConfirmTime indicates an expected call of UpdateStatusAndConfirmTime.
-func (mr *MockOrderDaoMockRecorder) UpdateStatusAndConfirmTime(ctx, orderNo, status, t interface{}) *gomock.Call {
+// ShipOrders indicates an expected call of ShipOrders.
+func (mr *MockOrderDaoMockRecorder) ShipOrders(ctx, shipments, fromStatuses, toStatus, t interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatusAndConfirmTime", reflect.TypeOf((*MockOrderDao)(nil).UpdateStatusAndConfirmTime), ctx, orderNo, status, t)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShipOrders", reflect.TypeOf((*MockOrderDao)(nil).ShipOrders), ctx, shipments, fromStatuses, toStatus, t)
 }
 
-// UpdateStatusAndPayment mocks base method.
-func (m *MockOrderDao) UpdateStatusAndPayment(ctx context.Context, orderNo string, status int, payTime time.Time) error {
+// UpdateStatusAndConfirmTime mocks base method.
+func (m *MockOrderDao) UpdateStatusAndConfirmTime(ctx context.Context, orderNo string, fromStatus, status int, t time.Time) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateStatusAndPayment", ctx, orderNo, status, payTime)
+	ret := m.ctrl.Call(m, "UpdateStatusAndConfirmTime", ctx, orderNo, fromStatus, status, t)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// UpdateStatusAndPayment indicates an expected call of UpdateStatusAndPayment.
-func (mr *MockOrderDaoMockRecorder) UpdateStatusAndPayment(ctx, orderNo, status, payTime interface{}) *gomock.Call {
+// UpdateStatusAndConfirmTime indicates an expected call of UpdateStatusAndConfirmTime.
+func (mr *MockOrderDaoMockRecorder) UpdateStatusAndConfirmTime(ctx, orderNo, fromStatus, status, t interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatusAndPayment", reflect.TypeOf((*MockOrderDao)(nil).UpdateStatusAndPayment), ctx, orderNo, status, payTime)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatusAndConfirmTime", reflect.TypeOf((*MockOrderDao)(nil).UpdateStatusAndConfirmTime), ctx, orderNo, fromStatus, status, t)
 }
 
-// UpdateStatusWithDeliveryInfo mocks base method.
-func (m *MockOrderDao) UpdateStatusWithDeliveryInfo(ctx context.Context, orderNo string, status int, t time.Time, shippingNo string) error {
+// UpdateStatusAndPayment mocks base method.
+func (m *MockOrderDao) UpdateStatusAndPayment(ctx context.Context, orderNo string, status int, payTime time.Time) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateStatusWithDeliveryInfo", ctx, orderNo, status, t, shippingNo)
+	ret := m.ctrl.Call(m, "UpdateStatusAndPayment", ctx, orderNo, status, payTime)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// UpdateStatusWithDeliveryInfo indicates an expected call of UpdateStatusWithDeliveryInfo.
-func (mr *MockOrderDaoMockRecorder) UpdateStatusWithDeliveryInfo(ctx, orderNo, status, t, shippingNo interface{}) *gomock.Call {
+// UpdateStatusAndPayment indicates an expected call of UpdateStatusAndPayment.
+func (mr *MockOrderDaoMockRecorder) UpdateStatusAndPayment(ctx, orderNo, status, payTime interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatusWithDeliveryInfo", reflect.TypeOf((*MockOrderDao)(nil).UpdateStatusWithDeliveryInfo), ctx, orderNo, status, t, shippingNo)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatusAndPayment", reflect.TypeOf((*MockOrderDao)(nil).UpdateStatusAndPayment), ctx, orderNo, status, payTime)
 }
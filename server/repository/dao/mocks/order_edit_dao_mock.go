@@ -0,0 +1,97 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: dao/order_edit_dao.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	dao "github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/dao"
+	model "github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockOrderEditDao is a mock of OrderEditDao interface.
+type MockOrderEditDao struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrderEditDaoMockRecorder
+}
+
+// MockOrderEditDaoMockRecorder is the mock recorder for MockOrderEditDao.
+type MockOrderEditDaoMockRecorder struct {
+	mock *MockOrderEditDao
+}
+
+// NewMockOrderEditDao creates a new mock instance.
+func NewMockOrderEditDao(ctrl *gomock.Controller) *MockOrderEditDao {
+	mock := &MockOrderEditDao{ctrl: ctrl}
+	mock.recorder = &MockOrderEditDaoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrderEditDao) EXPECT() *MockOrderEditDaoMockRecorder {
+	return m.recorder
+}
+
+// Edit mocks base method.
+func (m *MockOrderEditDao) Edit(ctx context.Context, orderNo string, edit dao.OrderEditor) (*model.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Edit", ctx, orderNo, edit)
+	ret0, _ := ret[0].(*model.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Edit indicates an expected call of Edit.
+func (mr *MockOrderEditDaoMockRecorder) Edit(ctx, orderNo, edit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Edit", reflect.TypeOf((*MockOrderEditDao)(nil).Edit), ctx, orderNo, edit)
+}
+
+// GetAuditLogs mocks base method.
+func (m *MockOrderEditDao) GetAuditLogs(ctx context.Context, orderNo string) ([]*model.OrderAuditLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditLogs", ctx, orderNo)
+	ret0, _ := ret[0].([]*model.OrderAuditLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuditLogs indicates an expected call of GetAuditLogs.
+func (mr *MockOrderEditDaoMockRecorder) GetAuditLogs(ctx, orderNo interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditLogs", reflect.TypeOf((*MockOrderEditDao)(nil).GetAuditLogs), ctx, orderNo)
+}
+
+// GetPendingRefunds mocks base method.
+func (m *MockOrderEditDao) GetPendingRefunds(ctx context.Context, before time.Time, limit int) ([]*model.OrderAuditLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingRefunds", ctx, before, limit)
+	ret0, _ := ret[0].([]*model.OrderAuditLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingRefunds indicates an expected call of GetPendingRefunds.
+func (mr *MockOrderEditDaoMockRecorder) GetPendingRefunds(ctx, before, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingRefunds", reflect.TypeOf((*MockOrderEditDao)(nil).GetPendingRefunds), ctx, before, limit)
+}
+
+// MarkRefundSent mocks base method.
+func (m *MockOrderEditDao) MarkRefundSent(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRefundSent", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkRefundSent indicates an expected call of MarkRefundSent.
+func (mr *MockOrderEditDaoMockRecorder) MarkRefundSent(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRefundSent", reflect.TypeOf((*MockOrderEditDao)(nil).MarkRefundSent), ctx, id)
+}
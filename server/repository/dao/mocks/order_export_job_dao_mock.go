@@ -0,0 +1,153 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: dao/order_export_job_dao.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	model "github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockOrderExportJobDao is a mock of OrderExportJobDao interface.
+type MockOrderExportJobDao struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrderExportJobDaoMockRecorder
+}
+
+// MockOrderExportJobDaoMockRecorder is the mock recorder for MockOrderExportJobDao.
+type MockOrderExportJobDaoMockRecorder struct {
+	mock *MockOrderExportJobDao
+}
+
+// NewMockOrderExportJobDao creates a new mock instance.
+func NewMockOrderExportJobDao(ctrl *gomock.Controller) *MockOrderExportJobDao {
+	mock := &MockOrderExportJobDao{ctrl: ctrl}
+	mock.recorder = &MockOrderExportJobDaoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrderExportJobDao) EXPECT() *MockOrderExportJobDaoMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockOrderExportJobDao) Create(ctx context.Context, job *model.OrderExportJob) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, job)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockOrderExportJobDaoMockRecorder) Create(ctx, job interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockOrderExportJobDao)(nil).Create), ctx, job)
+}
+
+// FailStale mocks base method.
+func (m *MockOrderExportJobDao) FailStale(ctx context.Context, createdBefore time.Time, errMsg string, finishTime time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailStale", ctx, createdBefore, errMsg, finishTime)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FailStale indicates an expected call of FailStale.
+func (mr *MockOrderExportJobDaoMockRecorder) FailStale(ctx, createdBefore, errMsg, finishTime interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailStale", reflect.TypeOf((*MockOrderExportJobDao)(nil).FailStale), ctx, createdBefore, errMsg, finishTime)
+}
+
+// GetByJobID mocks base method.
+func (m *MockOrderExportJobDao) GetByJobID(ctx context.Context, jobID string) (*model.OrderExportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByJobID", ctx, jobID)
+	ret0, _ := ret[0].(*model.OrderExportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByJobID indicates an expected call of GetByJobID.
+func (mr *MockOrderExportJobDaoMockRecorder) GetByJobID(ctx, jobID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByJobID", reflect.TypeOf((*MockOrderExportJobDao)(nil).GetByJobID), ctx, jobID)
+}
+
+// GetExpired mocks base method.
+func (m *MockOrderExportJobDao) GetExpired(ctx context.Context, now time.Time, limit int) ([]*model.OrderExportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpired", ctx, now, limit)
+	ret0, _ := ret[0].([]*model.OrderExportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpired indicates an expected call of GetExpired.
+func (mr *MockOrderExportJobDaoMockRecorder) GetExpired(ctx, now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpired", reflect.TypeOf((*MockOrderExportJobDao)(nil).GetExpired), ctx, now, limit)
+}
+
+// MarkExpired mocks base method.
+func (m *MockOrderExportJobDao) MarkExpired(ctx context.Context, jobID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkExpired", ctx, jobID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkExpired indicates an expected call of MarkExpired.
+func (mr *MockOrderExportJobDaoMockRecorder) MarkExpired(ctx, jobID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkExpired", reflect.TypeOf((*MockOrderExportJobDao)(nil).MarkExpired), ctx, jobID)
+}
+
+// MarkFailed mocks base method.
+func (m *MockOrderExportJobDao) MarkFailed(ctx context.Context, jobID, errMsg string, finishTime time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkFailed", ctx, jobID, errMsg, finishTime)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkFailed indicates an expected call of MarkFailed.
+func (mr *MockOrderExportJobDaoMockRecorder) MarkFailed(ctx, jobID, errMsg, finishTime interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkFailed", reflect.TypeOf((*MockOrderExportJobDao)(nil).MarkFailed), ctx, jobID, errMsg, finishTime)
+}
+
+// MarkRunning mocks base method.
+func (m *MockOrderExportJobDao) MarkRunning(ctx context.Context, jobID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRunning", ctx, jobID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkRunning indicates an expected call of MarkRunning.
+func (mr *MockOrderExportJobDaoMockRecorder) MarkRunning(ctx, jobID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRunning", reflect.TypeOf((*MockOrderExportJobDao)(nil).MarkRunning), ctx, jobID)
+}
+
+// MarkSucceeded mocks base method.
+func (m *MockOrderExportJobDao) MarkSucceeded(ctx context.Context, jobID string, rowCount int, finishTime, expireTime time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkSucceeded", ctx, jobID, rowCount, finishTime, expireTime)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkSucceeded indicates an expected call of MarkSucceeded.
+func (mr *MockOrderExportJobDaoMockRecorder) MarkSucceeded(ctx, jobID, rowCount, finishTime, expireTime interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkSucceeded", reflect.TypeOf((*MockOrderExportJobDao)(nil).MarkSucceeded), ctx, jobID, rowCount, finishTime, expireTime)
+}
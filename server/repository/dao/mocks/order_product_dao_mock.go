@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: ./dao/order_product_dao.go
+// Source: dao/order_product_dao.go
 
 // Package mocks is a generated GoMock package.
 package mocks
@@ -79,3 +79,18 @@ func (mr *MockOrderProductDaoMockRecorder) GetByOrderNo(ctx, orderNo interface{}
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByOrderNo", reflect.TypeOf((*MockOrderProductDao)(nil).GetByOrderNo), ctx, orderNo)
 }
+
+// GetByOrderNos mocks base method.
+func (m *MockOrderProductDao) GetByOrderNos(ctx context.Context, orderNos []string) ([]*model.OrderProduct, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByOrderNos", ctx, orderNos)
+	ret0, _ := ret[0].([]*model.OrderProduct)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByOrderNos indicates an expected call of GetByOrderNos.
+func (mr *MockOrderProductDaoMockRecorder) GetByOrderNos(ctx, orderNos interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByOrderNos", reflect.TypeOf((*MockOrderProductDao)(nil).GetByOrderNos), ctx, orderNos)
+}
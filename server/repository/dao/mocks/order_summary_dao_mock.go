@@ -0,0 +1,158 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: dao/order_summary_dao.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	types "github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/types"
+	dao "github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/dao"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockOrderSummaryDao is a mock of OrderSummaryDao interface.
+type MockOrderSummaryDao struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrderSummaryDaoMockRecorder
+}
+
+// MockOrderSummaryDaoMockRecorder is the mock recorder for MockOrderSummaryDao.
+type MockOrderSummaryDaoMockRecorder struct {
+	mock *MockOrderSummaryDao
+}
+
+// NewMockOrderSummaryDao creates a new mock instance.
+func NewMockOrderSummaryDao(ctrl *gomock.Controller) *MockOrderSummaryDao {
+	mock := &MockOrderSummaryDao{ctrl: ctrl}
+	mock.recorder = &MockOrderSummaryDaoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrderSummaryDao) EXPECT() *MockOrderSummaryDaoMockRecorder {
+	return m.recorder
+}
+
+// CountByStatus mocks base method.
+func (m *MockOrderSummaryDao) CountByStatus(ctx context.Context, filter dao.AnalyticsFilter) (map[int]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountByStatus", ctx, filter)
+	ret0, _ := ret[0].(map[int]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountByStatus indicates an expected call of CountByStatus.
+func (mr *MockOrderSummaryDaoMockRecorder) CountByStatus(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByStatus", reflect.TypeOf((*MockOrderSummaryDao)(nil).CountByStatus), ctx, filter)
+}
+
+// GetChangedDates mocks base method.
+func (m *MockOrderSummaryDao) GetChangedDates(ctx context.Context, since time.Time) ([]time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChangedDates", ctx, since)
+	ret0, _ := ret[0].([]time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChangedDates indicates an expected call of GetChangedDates.
+func (mr *MockOrderSummaryDaoMockRecorder) GetChangedDates(ctx, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChangedDates", reflect.TypeOf((*MockOrderSummaryDao)(nil).GetChangedDates), ctx, since)
+}
+
+// GetFirstOrderTime mocks base method.
+func (m *MockOrderSummaryDao) GetFirstOrderTime(ctx context.Context) (time.Time, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFirstOrderTime", ctx)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetFirstOrderTime indicates an expected call of GetFirstOrderTime.
+func (mr *MockOrderSummaryDaoMockRecorder) GetFirstOrderTime(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFirstOrderTime", reflect.TypeOf((*MockOrderSummaryDao)(nil).GetFirstOrderTime), ctx)
+}
+
+// GetMerchantTotals mocks base method.
+func (m *MockOrderSummaryDao) GetMerchantTotals(ctx context.Context) (map[int]types.OrderStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMerchantTotals", ctx)
+	ret0, _ := ret[0].(map[int]types.OrderStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMerchantTotals indicates an expected call of GetMerchantTotals.
+func (mr *MockOrderSummaryDaoMockRecorder) GetMerchantTotals(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMerchantTotals", reflect.TypeOf((*MockOrderSummaryDao)(nil).GetMerchantTotals), ctx)
+}
+
+// GetSalesDays mocks base method.
+func (m *MockOrderSummaryDao) GetSalesDays(ctx context.Context, filter dao.AnalyticsFilter) ([]*dao.SalesSlot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSalesDays", ctx, filter)
+	ret0, _ := ret[0].([]*dao.SalesSlot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSalesDays indicates an expected call of GetSalesDays.
+func (mr *MockOrderSummaryDaoMockRecorder) GetSalesDays(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSalesDays", reflect.TypeOf((*MockOrderSummaryDao)(nil).GetSalesDays), ctx, filter)
+}
+
+// GetWatermark mocks base method.
+func (m *MockOrderSummaryDao) GetWatermark(ctx context.Context) (time.Time, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWatermark", ctx)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetWatermark indicates an expected call of GetWatermark.
+func (mr *MockOrderSummaryDaoMockRecorder) GetWatermark(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWatermark", reflect.TypeOf((*MockOrderSummaryDao)(nil).GetWatermark), ctx)
+}
+
+// RebuildRange mocks base method.
+func (m *MockOrderSummaryDao) RebuildRange(ctx context.Context, start, end time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RebuildRange", ctx, start, end)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RebuildRange indicates an expected call of RebuildRange.
+func (mr *MockOrderSummaryDaoMockRecorder) RebuildRange(ctx, start, end interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RebuildRange", reflect.TypeOf((*MockOrderSummaryDao)(nil).RebuildRange), ctx, start, end)
+}
+
+// SaveWatermark mocks base method.
+func (m *MockOrderSummaryDao) SaveWatermark(ctx context.Context, watermark time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveWatermark", ctx, watermark)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveWatermark indicates an expected call of SaveWatermark.
+func (mr *MockOrderSummaryDaoMockRecorder) SaveWatermark(ctx, watermark interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveWatermark", reflect.TypeOf((*MockOrderSummaryDao)(nil).SaveWatermark), ctx, watermark)
+}
@@ -0,0 +1,68 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: dao/shipment_dao.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	dao "github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/dao"
+	model "github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockShipmentDao is a mock of ShipmentDao interface.
+type MockShipmentDao struct {
+	ctrl     *gomock.Controller
+	recorder *MockShipmentDaoMockRecorder
+}
+
+// MockShipmentDaoMockRecorder is the mock recorder for MockShipmentDao.
+type MockShipmentDaoMockRecorder struct {
+	mock *MockShipmentDao
+}
+
+// NewMockShipmentDao creates a new mock instance.
+func NewMockShipmentDao(ctrl *gomock.Controller) *MockShipmentDao {
+	mock := &MockShipmentDao{ctrl: ctrl}
+	mock.recorder = &MockShipmentDaoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockShipmentDao) EXPECT() *MockShipmentDaoMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockShipmentDao) Create(ctx context.Context, shipment *model.Shipment, plan dao.ShipmentPlanner) (*model.Order, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, shipment, plan)
+	ret0, _ := ret[0].(*model.Order)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockShipmentDaoMockRecorder) Create(ctx, shipment, plan interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockShipmentDao)(nil).Create), ctx, shipment, plan)
+}
+
+// GetByOrderNo mocks base method.
+func (m *MockShipmentDao) GetByOrderNo(ctx context.Context, orderNo string) ([]*model.Shipment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByOrderNo", ctx, orderNo)
+	ret0, _ := ret[0].([]*model.Shipment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByOrderNo indicates an expected call of GetByOrderNo.
+func (mr *MockShipmentDaoMockRecorder) GetByOrderNo(ctx, orderNo interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByOrderNo", reflect.TypeOf((*MockShipmentDao)(nil).GetByOrderNo), ctx, orderNo)
+}
@@ -0,0 +1,67 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: dao/tracking_event_dao.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockTrackingEventDao is a mock of TrackingEventDao interface.
+type MockTrackingEventDao struct {
+	ctrl     *gomock.Controller
+	recorder *MockTrackingEventDaoMockRecorder
+}
+
+// MockTrackingEventDaoMockRecorder is the mock recorder for MockTrackingEventDao.
+type MockTrackingEventDaoMockRecorder struct {
+	mock *MockTrackingEventDao
+}
+
+// NewMockTrackingEventDao creates a new mock instance.
+func NewMockTrackingEventDao(ctrl *gomock.Controller) *MockTrackingEventDao {
+	mock := &MockTrackingEventDao{ctrl: ctrl}
+	mock.recorder = &MockTrackingEventDaoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTrackingEventDao) EXPECT() *MockTrackingEventDaoMockRecorder {
+	return m.recorder
+}
+
+// GetByOrderNo mocks base method.
+func (m *MockTrackingEventDao) GetByOrderNo(ctx context.Context, orderNo string) ([]*model.TrackingEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByOrderNo", ctx, orderNo)
+	ret0, _ := ret[0].([]*model.TrackingEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByOrderNo indicates an expected call of GetByOrderNo.
+func (mr *MockTrackingEventDaoMockRecorder) GetByOrderNo(ctx, orderNo interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByOrderNo", reflect.TypeOf((*MockTrackingEventDao)(nil).GetByOrderNo), ctx, orderNo)
+}
+
+// Record mocks base method.
+func (m *MockTrackingEventDao) Record(ctx context.Context, carrierCode, trackingNo string, events []*model.TrackingEvent, deliveredStatus string, shippedStatus int) (*model.Shipment, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Record", ctx, carrierCode, trackingNo, events, deliveredStatus, shippedStatus)
+	ret0, _ := ret[0].(*model.Shipment)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Record indicates an expected call of Record.
+func (mr *MockTrackingEventDaoMockRecorder) Record(ctx, carrierCode, trackingNo, events, deliveredStatus, shippedStatus interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Record", reflect.TypeOf((*MockTrackingEventDao)(nil).Record), ctx, carrierCode, trackingNo, events, deliveredStatus, shippedStatus)
+}
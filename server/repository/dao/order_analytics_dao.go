@@ -0,0 +1,165 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/consts"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/model"
+	"gorm.io/gorm"
+)
+
+// 商品排行排序字段
+const (
+	ProductSortRevenue  = "revenue"
+	ProductSortQuantity = "quantity"
+)
+
+// SalesStatuses 计入销售额的订单状态：已支付且未取消
+var SalesStatuses = []int{consts.PAYED, consts.PARTIALLY_SHIPPED, consts.SHIPPED, consts.DELIVERED}
+
+// AnalyticsFilter 统计范围：下单时间在 [Start, End) 内，MerchantID 为 0 时不限商家
+type AnalyticsFilter struct {
+	MerchantID int
+	Start      time.Time
+	End        time.Time
+}
+
+// SalesSlot 一个 15 分钟时间片内的销售额。按时间片而不是按天汇总，
+// 调用方可以在任意时区（偏移均为 15 分钟的整数倍）下准确地按天、周、月归并。
+type SalesSlot struct {
+	SlotTime time.Time // 时间片内最早的下单时间
+	Orders   int64
+	Revenue  int64
+}
+
+type ProductSales struct {
+	ProductID   int
+	ProductName string
+	Quantity    int64
+	Revenue     int64
+	Orders      int64
+}
+
+type CountrySales struct {
+	Country   string
+	Orders    int64
+	Revenue   int64
+	Customers int64
+}
+
+type CustomerStats struct {
+	Customers       int64 // 有已支付订单的用户数
+	RepeatCustomers int64 // 其中下单两次及以上的用户数
+}
+
+type OrderAnalyticsDao interface {
+	GetSalesSlots(ctx context.Context, filter AnalyticsFilter) (slots []*SalesSlot, err error)
+	GetTopProducts(ctx context.Context, filter AnalyticsFilter, sortBy string, limit int) (products []*ProductSales, err error)
+	CountByStatus(ctx context.Context, filter AnalyticsFilter) (counts map[int]int64, err error)
+	GetAvgShipSeconds(ctx context.Context, filter AnalyticsFilter) (seconds float64, shipped int64, err error)
+	GetCustomerStats(ctx context.Context, filter AnalyticsFilter) (stats CustomerStats, err error)
+	GetCountrySales(ctx context.Context, filter AnalyticsFilter) (countries []*CountrySales, err error)
+}
+
+type OrderAnalyticsDaoImpl struct {
+	db *gorm.DB
+}
+
+func NewOrderAnalyticsDao(db *gorm.DB) *OrderAnalyticsDaoImpl {
+	return &OrderAnalyticsDaoImpl{db: db}
+}
+
+// orders 按统计范围筛选订单，列名带表名前缀以便联表
+func (d *OrderAnalyticsDaoImpl) orders(ctx context.Context, filter AnalyticsFilter) *gorm.DB {
+	db := d.db.WithContext(ctx).Model(&model.Order{}).
+		Where("orders.create_time >= ? AND orders.create_time < ?", filter.Start, filter.End)
+	if filter.MerchantID != 0 {
+		db = db.Where("orders.merchant_id = ?", filter.MerchantID)
+	}
+	return db
+}
+
+func (d *OrderAnalyticsDaoImpl) GetSalesSlots(ctx context.Context, filter AnalyticsFilter) (slots []*SalesSlot, err error) {
+	err = d.orders(ctx, filter).
+		Select("MIN(create_time) AS slot_time, COUNT(*) AS orders, SUM(total_amount) AS revenue").
+		Where("status IN ?", SalesStatuses).
+		Group("DATE_FORMAT(create_time, '%Y-%m-%d %H'), FLOOR(MINUTE(create_time) / 15)").
+		Scan(&slots).Error
+	return
+}
+
+func (d *OrderAnalyticsDaoImpl) GetTopProducts(ctx context.Context, filter AnalyticsFilter, sortBy string, limit int) (products []*ProductSales, err error) {
+	order := "revenue DESC"
+	if sortBy == ProductSortQuantity {
+		order = "quantity DESC"
+	}
+	err = d.orders(ctx, filter).
+		Joins("JOIN order_products ON order_products.order_no = orders.order_no").
+		Select([]string{
+			"order_products.product_id",
+			"MAX(order_products.product_name) AS product_name",
+			"SUM(order_products.quantity) AS quantity",
+			"SUM(order_products.total_price) AS revenue",
+			"COUNT(DISTINCT orders.order_no) AS orders",
+		}).
+		Where("orders.status IN ?", SalesStatuses).
+		Group("order_products.product_id").
+		Order(order).Order("order_products.product_id").
+		Limit(limit).
+		Scan(&products).Error
+	return
+}
+
+func (d *OrderAnalyticsDaoImpl) CountByStatus(ctx context.Context, filter AnalyticsFilter) (counts map[int]int64, err error) {
+	var rows []struct {
+		Status int
+		Count  int64
+	}
+	err = d.orders(ctx, filter).
+		Select("status, COUNT(*) AS count").
+		Group("status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	counts = make(map[int]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+// GetAvgShipSeconds 支付到首次发货的平均时长
+func (d *OrderAnalyticsDaoImpl) GetAvgShipSeconds(ctx context.Context, filter AnalyticsFilter) (seconds float64, shipped int64, err error) {
+	var row struct {
+		Seconds float64
+		Shipped int64
+	}
+	err = d.orders(ctx, filter).
+		Select("COALESCE(AVG(TIMESTAMPDIFF(SECOND, pay_time, delivery_time)), 0) AS seconds, COUNT(*) AS shipped").
+		Where("pay_time IS NOT NULL AND delivery_time IS NOT NULL").
+		Scan(&row).Error
+	return row.Seconds, row.Shipped, err
+}
+
+func (d *OrderAnalyticsDaoImpl) GetCustomerStats(ctx context.Context, filter AnalyticsFilter) (stats CustomerStats, err error) {
+	perUser := d.orders(ctx, filter).
+		Select("user_id, COUNT(*) AS orders").
+		Where("status IN ?", SalesStatuses).
+		Group("user_id")
+	err = d.db.WithContext(ctx).Table("(?) AS per_user", perUser).
+		Select("COUNT(*) AS customers, COALESCE(SUM(CASE WHEN orders > 1 THEN 1 ELSE 0 END), 0) AS repeat_customers").
+		Scan(&stats).Error
+	return
+}
+
+func (d *OrderAnalyticsDaoImpl) GetCountrySales(ctx context.Context, filter AnalyticsFilter) (countries []*CountrySales, err error) {
+	err = d.orders(ctx, filter).
+		Select("receiver_country AS country, COUNT(*) AS orders, SUM(total_amount) AS revenue, COUNT(DISTINCT user_id) AS customers").
+		Where("status IN ?", SalesStatuses).
+		Group("receiver_country").
+		Order("revenue DESC").Order("country").
+		Scan(&countries).Error
+	return
+}
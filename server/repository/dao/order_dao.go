@@ -2,13 +2,14 @@ package dao
 
 import (
 	"context"
-	"sync"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/log"
-	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/consts"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/errs"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/types"
-	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/model"
 	"gorm.io/gorm"
 )
@@ -17,29 +18,35 @@ type OrderDao interface {
 	Create(ctx context.Context, o *model.Order) (orderNo string, err error)
 	UpdateStatusAndPayment(ctx context.Context, orderNo string, status int, payTime time.Time) error
 	GetByOrderNo(ctx context.Context, orderNo string) (o *model.Order, err error)
-	GetByOrderQuery(ctx context.Context, query OrderQuery) (oList []*model.Order, err error)
-	UpdateStatusAndConfirmTime(ctx context.Context, orderNo string, status int, t time.Time) (err error)
-	UpdateStatusWithDeliveryInfo(ctx context.Context, orderNo string, status int, t time.Time, shippingNo string) (err error)
+	GetByOrderNos(ctx context.Context, orderNos []string) (oList []*model.Order, err error)
+	// GetAfterID returns up to limit orders with an ID greater than afterID, ordered by ID.
+	GetAfterID(ctx context.Context, afterID int, limit int) (oList []*model.Order, err error)
+	GetByCheckoutNo(ctx context.Context, checkoutNo string) (oList []*model.Order, err error)
+	GetByOrderQuery(ctx context.Context, query OrderQuery) (oList []*model.Order, total int64, err error)
+	UpdateStatusAndConfirmTime(ctx context.Context, orderNo string, fromStatus, status int, t time.Time) (err error)
+	ShipOrders(ctx context.Context, shipments []ShipmentUpdate, fromStatuses []int, toStatus int, t time.Time) (err error)
 	AutoConfirmShippedOrders(ctx context.Context, shippedStatus int, deliveredStatus int, daysThreshold int) (orderNos []types.OrderNoAndUserId, err error)
 	GetOrderStats() (types.OrderStats, error)
+	GetPaidCustomers(ctx context.Context) (customers map[int][]int, err error)
 }
 
-var (
-	orderOnce            sync.Once
-	orderDaoImplInstance *OrderDaoImpl
-)
+// ErrOrderStatusChanged is returned when an order is no longer in the status
+// an update expects, e.g. because it was shipped or canceled concurrently.
+var ErrOrderStatusChanged = errs.New(errs.CodeOrderStatusChanged, "order status changed concurrently")
+
+// ShipmentUpdate is the delivery info written when an order is shipped.
+type ShipmentUpdate struct {
+	OrderNo     string
+	Carrier     string
+	LogisticsNo string
+}
 
 type OrderDaoImpl struct {
 	db *gorm.DB
 }
 
-func GetOrderDao() *OrderDaoImpl {
-	orderOnce.Do(func() {
-		if orderDaoImplInstance == nil {
-			orderDaoImplInstance = &OrderDaoImpl{repository.DB}
-		}
-	})
-	return orderDaoImplInstance
+func NewOrderDao(db *gorm.DB) *OrderDaoImpl {
+	return &OrderDaoImpl{db: db}
 }
 
 func (d *OrderDaoImpl) Create(ctx context.Context, o *model.Order) (orderNo string, err error) {
@@ -57,25 +64,24 @@ func (d *OrderDaoImpl) UpdateStatusAndPayment(ctx context.Context, orderNo strin
 		}).Error
 }
 
-func (d *OrderDaoImpl) UpdateStatusAndConfirmTime(ctx context.Context, orderNo string, status int, t time.Time) (err error) {
-	return d.db.WithContext(ctx).
+// UpdateStatusAndConfirmTime moves the order from fromStatus to status. If the
+// order has left fromStatus nothing is changed and ErrOrderStatusChanged is
+// returned.
+func (d *OrderDaoImpl) UpdateStatusAndConfirmTime(ctx context.Context, orderNo string, fromStatus, status int, t time.Time) (err error) {
+	result := d.db.WithContext(ctx).
 		Model(&model.Order{}).
-		Where("order_no = ?", orderNo).
+		Where("order_no = ? AND status = ?", orderNo, fromStatus).
 		Updates(map[string]interface{}{
 			"status":       status,
 			"confirm_time": t,
-		}).Error
-}
-
-func (d *OrderDaoImpl) UpdateStatusWithDeliveryInfo(ctx context.Context, orderNo string, status int, t time.Time, shippingNo string) (err error) {
-	return d.db.WithContext(ctx).
-		Model(&model.Order{}).
-		Where("order_no = ?", orderNo).
-		Updates(map[string]interface{}{
-			"status":        status,
-			"delivery_time": t,
-			"logistics_no":  shippingNo,
-		}).Error
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrOrderStatusChanged.Detailf("%s", orderNo)
+	}
+	return nil
 }
 
 func (d *OrderDaoImpl) GetByOrderNo(ctx context.Context, orderNo string) (o *model.Order, err error) {
@@ -84,12 +90,117 @@ func (d *OrderDaoImpl) GetByOrderNo(ctx context.Context, orderNo string) (o *mod
 	return
 }
 
-func (d *OrderDaoImpl) GetByOrderQuery(ctx context.Context, query OrderQuery) (oList []*model.Order, err error) {
-	db := d.db.WithContext(ctx).Model(&model.Order{})
+func (d *OrderDaoImpl) GetByOrderNos(ctx context.Context, orderNos []string) (oList []*model.Order, err error) {
+	if len(orderNos) == 0 {
+		return nil, nil
+	}
+	err = d.db.WithContext(ctx).Where("order_no IN ?", orderNos).Find(&oList).Error
+	return
+}
+
+func (d *OrderDaoImpl) GetAfterID(ctx context.Context, afterID int, limit int) (oList []*model.Order, err error) {
+	err = d.db.WithContext(ctx).Where("id > ?", afterID).Order("id").Limit(limit).Find(&oList).Error
+	return
+}
 
-	// 根据 query 字段动态拼接条件
+// GetByCheckoutNo returns the sub-orders of one checkout. Orders placed
+// before checkouts existed have no checkout_no and are matched by order_no.
+func (d *OrderDaoImpl) GetByCheckoutNo(ctx context.Context, checkoutNo string) (oList []*model.Order, err error) {
+	err = d.db.WithContext(ctx).
+		Where("checkout_no = ? OR (order_no = ? AND (checkout_no IS NULL OR checkout_no = ''))", checkoutNo, checkoutNo).
+		Order("id").Find(&oList).Error
+	return
+}
+
+// ShipOrders ships everything not yet shipped of each order as one shipment
+// and moves the order from one of fromStatuses to toStatus, all in one
+// transaction. If any order has left fromStatuses nothing is changed and
+// ErrOrderStatusChanged is returned.
+func (d *OrderDaoImpl) ShipOrders(ctx context.Context, shipments []ShipmentUpdate, fromStatuses []int, toStatus int, t time.Time) (err error) {
+	return d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, update := range shipments {
+			shipment := &model.Shipment{
+				OrderNo:     update.OrderNo,
+				CarrierCode: update.Carrier,
+				TrackingNo:  update.LogisticsNo,
+				CreateTime:  t,
+			}
+			_, _, err := createShipment(tx, shipment, shipRemaining(fromStatuses, toStatus))
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrOrderStatusChanged.Detailf("%s", update.OrderNo)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetByOrderQuery returns one page of matching orders sorted by query.SortBy
+// (create_time when unset or unknown), descending unless query.SortAsc, with
+// id in the same direction breaking ties. total is the number of orders
+// matching the filters regardless of paging, as counted by query.CountMode.
+func (d *OrderDaoImpl) GetByOrderQuery(ctx context.Context, query OrderQuery) (oList []*model.Order, total int64, err error) {
+	filtered := func(db *gorm.DB) *gorm.DB {
+		return orderQueryFilters(db.WithContext(ctx).Model(&model.Order{}), query)
+	}
+
+	switch query.CountMode {
+	case CountModeNone:
+	case CountModeEstimate:
+		total, err = d.estimateCount(ctx, query)
+	default:
+		err = filtered(d.db).Count(&total).Error
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	sortBy := query.SortBy
+	if !IsOrderSortField(sortBy) {
+		sortBy = OrderSortCreateTime
+	}
+	direction, compare := "DESC", "<"
+	if query.SortAsc {
+		direction, compare = "ASC", ">"
+	}
+
+	db := filtered(d.db)
+	// 游标分页：(sortBy, id) 严格排在游标位置之后
+	if query.After != nil {
+		db = db.Where(fmt.Sprintf("%[1]s %[2]s ? OR (%[1]s = ? AND id %[2]s ?)", sortBy, compare),
+			query.After.Value, query.After.Value, query.After.ID)
+	}
+
+	// id 保证排序值相同时顺序稳定
+	db = db.Order(sortBy + " " + direction).Order("id " + direction)
+
+	// 分页支持
+	if query.Limit > 0 {
+		db = db.Limit(query.Limit)
+	}
+	if query.Offset > 0 && query.After == nil {
+		db = db.Offset(query.Offset)
+	}
+
+	err = db.Find(&oList).Error
+	return
+}
+
+// orderQueryFilters 根据 query 字段动态拼接条件
+func orderQueryFilters(db *gorm.DB, query OrderQuery) *gorm.DB {
+	statuses := query.Statuses
 	if query.OrderStatus != 0 {
-		db = db.Where("status = ?", query.OrderStatus)
+		statuses = append([]int{query.OrderStatus}, statuses...)
+	}
+	if len(statuses) == 1 {
+		db = db.Where("status = ?", statuses[0])
+	} else if len(statuses) > 1 {
+		db = db.Where("status IN ?", statuses)
+	}
+	if query.MerchantID != 0 {
+		db = db.Where("merchant_id = ?", query.MerchantID)
 	}
 	if query.UserID != 0 {
 		db = db.Where("user_id = ?", query.UserID)
@@ -98,6 +209,43 @@ func (d *OrderDaoImpl) GetByOrderQuery(ctx context.Context, query OrderQuery) (o
 		db = db.Where("order_no LIKE ?", "%"+query.OrderNo+"%")
 	}
 
+	// 金额范围
+	if query.MinAmount != nil {
+		db = db.Where("total_amount >= ?", *query.MinAmount)
+	}
+	if query.MaxAmount != nil {
+		db = db.Where("total_amount <= ?", *query.MaxAmount)
+	}
+
+	// 收货信息，前缀匹配以便走索引
+	if name := strings.TrimSpace(query.ReceiverName); name != "" {
+		if first, last, ok := strings.Cut(name, " "); ok {
+			db = db.Where("receiver_first_name LIKE ? AND receiver_last_name LIKE ?",
+				escapeLike(first)+"%", escapeLike(strings.TrimSpace(last))+"%")
+		} else {
+			db = db.Where("receiver_first_name LIKE ? OR receiver_last_name LIKE ?",
+				escapeLike(name)+"%", escapeLike(name)+"%")
+		}
+	}
+	if query.ReceiverPhone != "" {
+		db = db.Where("receiver_phone LIKE ?", escapeLike(query.ReceiverPhone)+"%")
+	}
+	if query.ReceiverCountry != "" {
+		db = db.Where("receiver_country = ?", query.ReceiverCountry)
+	}
+	if query.LogisticsNo != "" {
+		db = db.Where("logistics_no = ?", query.LogisticsNo)
+	}
+	if query.ProductID != 0 {
+		db = db.Where("order_no IN (?)", db.Session(&gorm.Session{NewDB: true}).
+			Model(&model.OrderProduct{}).Select("order_no").Where("product_id = ?", query.ProductID))
+	}
+	// 关键字可能在名称中间，无法走索引，通常与 UserID 一起使用以限定范围
+	if name := strings.TrimSpace(query.ProductName); name != "" {
+		db = db.Where("order_no IN (?)", db.Session(&gorm.Session{NewDB: true}).
+			Model(&model.OrderProduct{}).Select("order_no").Where("product_name LIKE ?", "%"+escapeLike(name)+"%"))
+	}
+
 	// 根据创建时间范围筛选
 	if !query.StartTime.IsZero() {
 		db = db.Where("create_time >= ?", query.StartTime)
@@ -105,24 +253,59 @@ func (d *OrderDaoImpl) GetByOrderQuery(ctx context.Context, query OrderQuery) (o
 	if !query.EndTime.IsZero() {
 		db = db.Where("create_time <= ?", query.EndTime)
 	}
+	// 支付、发货时间范围
+	if !query.PayStartTime.IsZero() {
+		db = db.Where("pay_time >= ?", query.PayStartTime)
+	}
+	if !query.PayEndTime.IsZero() {
+		db = db.Where("pay_time <= ?", query.PayEndTime)
+	}
+	if !query.DeliveryStartTime.IsZero() {
+		db = db.Where("delivery_time >= ?", query.DeliveryStartTime)
+	}
+	if !query.DeliveryEndTime.IsZero() {
+		db = db.Where("delivery_time <= ?", query.DeliveryEndTime)
+	}
+	return db
+}
 
-	// 按创建时间倒序排列
-	db = db.Order("create_time DESC")
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
 
-	// 分页支持
-	if query.Limit > 0 {
-		db = db.Limit(query.Limit)
+// escapeLike escapes the LIKE wildcards in user input.
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+type explainRow struct {
+	Rows     *int64   `gorm:"column:rows"`
+	Filtered *float64 `gorm:"column:filtered"`
+}
+
+// estimateCount reads the optimizer's row estimate from EXPLAIN instead of
+// counting, so it stays cheap for merchants with big histories.
+func (d *OrderDaoImpl) estimateCount(ctx context.Context, query OrderQuery) (int64, error) {
+	var dest []*model.Order
+	dryRun := d.db.Session(&gorm.Session{DryRun: true}).Model(&model.Order{})
+	stmt := orderQueryFilters(dryRun, query).Find(&dest).Statement
+
+	var rows []explainRow
+	err := d.db.WithContext(ctx).Raw("EXPLAIN "+stmt.SQL.String(), stmt.Vars...).Scan(&rows).Error
+	if err != nil {
+		return 0, err
 	}
-	if query.Offset > 0 {
-		db = db.Offset(query.Offset)
+	if len(rows) == 0 || rows[0].Rows == nil {
+		return 0, nil
 	}
-
-	err = db.Find(&oList).Error
-	return
+	estimate := float64(*rows[0].Rows)
+	if rows[0].Filtered != nil {
+		estimate = estimate * *rows[0].Filtered / 100
+	}
+	return int64(estimate), nil
 }
 
 // AutoConfirmShippedOrders 自动确认已发货超过指定天数的订单
-// 查询 status = shippedStatus 且 delivery_time 距离当前时间大于 daysThreshold 天的订单
+// 查询 status = shippedStatus 且确认期起点距离当前时间大于 daysThreshold 天的订单：
+// 承运商报告签收的订单从 arrived_time 起算，否则从 delivery_time 起算
 // 将它们的状态更新为 deliveredStatus，并返回更新成功的订单号列表
 func (d *OrderDaoImpl) AutoConfirmShippedOrders(ctx context.Context, shippedStatus int, deliveredStatus int, daysThreshold int) (orderNos []types.OrderNoAndUserId, err error) {
 	// 1. 计算截止时间：当前时间 - daysThreshold 天
@@ -133,8 +316,8 @@ func (d *OrderDaoImpl) AutoConfirmShippedOrders(ctx context.Context, shippedStat
 	err = d.db.WithContext(ctx).
 		Model(&model.Order{}).
 		Where("status = ?", shippedStatus).
-		Where("delivery_time IS NOT NULL").
-		Where("delivery_time <= ?", thresholdTime).
+		Where("(arrived_time IS NOT NULL AND arrived_time <= ?) OR (arrived_time IS NULL AND delivery_time IS NOT NULL AND delivery_time <= ?)",
+			thresholdTime, thresholdTime).
 		Find(&orders).Error
 	if err != nil {
 		return nil, err
@@ -180,10 +363,32 @@ func (d *OrderDaoImpl) GetOrderStats() (types.OrderStats, error) {
 			"COUNT(order_no) AS total_orders",
 			"sum(total_amount) as total_sales",
 			"count(distinct user_id) as total_customers",
-		}).Where("status in (?)", []int{consts.DELIVERED, consts.PAYED, consts.SHIPPED}).
+		}).Where("status in (?)", SalesStatuses).
 		Scan(&stats).Error
 	if err != nil {
 		log.Logger.Errorf("Failed to get order stats: %v", err)
 	}
 	return stats, err
 }
+
+// GetPaidCustomers returns the IDs of the users with counted orders, keyed
+// by merchant ID.
+func (d *OrderDaoImpl) GetPaidCustomers(ctx context.Context) (customers map[int][]int, err error) {
+	var rows []struct {
+		MerchantID int
+		UserID     int
+	}
+	err = d.db.WithContext(ctx).
+		Model(&model.Order{}).
+		Distinct("merchant_id", "user_id").
+		Where("status in (?)", SalesStatuses).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	customers = make(map[int][]int)
+	for _, row := range rows {
+		customers[row.MerchantID] = append(customers[row.MerchantID], row.UserID)
+	}
+	return customers, nil
+}
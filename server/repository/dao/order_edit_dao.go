@@ -0,0 +1,94 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/consts"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OrderEdit is the change an OrderEditor makes to an order.
+type OrderEdit struct {
+	Updates           map[string]interface{} // orders 表要修改的列
+	RemovedProductIDs []int                  // 要删除的 order_products.id
+	Audit             *model.OrderAuditLog   // 审计记录，与修改在同一事务中写入
+}
+
+// OrderEditor decides, under the order's row lock, how to edit the order.
+// products are the order's items ordered by ID.
+type OrderEditor func(order *model.Order, products []*model.OrderProduct) (*OrderEdit, error)
+
+type OrderEditDao interface {
+	Edit(ctx context.Context, orderNo string, edit OrderEditor) (order *model.Order, err error)
+	GetAuditLogs(ctx context.Context, orderNo string) (logs []*model.OrderAuditLog, err error)
+	GetPendingRefunds(ctx context.Context, before time.Time, limit int) (logs []*model.OrderAuditLog, err error)
+	MarkRefundSent(ctx context.Context, id int) error
+}
+
+type OrderEditDaoImpl struct {
+	db *gorm.DB
+}
+
+func NewOrderEditDao(db *gorm.DB) *OrderEditDaoImpl {
+	return &OrderEditDaoImpl{db: db}
+}
+
+// Edit applies the change decided by edit and stores its audit log in one
+// transaction. order is the order as it was before the change.
+func (d *OrderEditDaoImpl) Edit(ctx context.Context, orderNo string, edit OrderEditor) (order *model.Order, err error) {
+	err = d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 锁定订单行，避免与发货、状态变更并发
+		order = &model.Order{}
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("order_no = ?", orderNo).First(order).Error
+		if err != nil {
+			return err
+		}
+		var products []*model.OrderProduct
+		if err = tx.Where("order_no = ?", orderNo).Order("id").Find(&products).Error; err != nil {
+			return err
+		}
+
+		change, err := edit(order, products)
+		if err != nil {
+			return err
+		}
+		if len(change.Updates) > 0 {
+			if err = tx.Model(&model.Order{}).Where("order_no = ?", orderNo).Updates(change.Updates).Error; err != nil {
+				return err
+			}
+		}
+		if len(change.RemovedProductIDs) > 0 {
+			err = tx.Where("order_no = ? AND id IN ?", orderNo, change.RemovedProductIDs).Delete(&model.OrderProduct{}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return tx.Create(change.Audit).Error
+	})
+	return order, err
+}
+
+func (d *OrderEditDaoImpl) GetAuditLogs(ctx context.Context, orderNo string) (logs []*model.OrderAuditLog, err error) {
+	err = d.db.WithContext(ctx).Where("order_no = ?", orderNo).Order("id").Find(&logs).Error
+	return
+}
+
+// GetPendingRefunds returns the edits created before before whose refund
+// message has not been acknowledged yet, oldest first.
+func (d *OrderEditDaoImpl) GetPendingRefunds(ctx context.Context, before time.Time, limit int) (logs []*model.OrderAuditLog, err error) {
+	err = d.db.WithContext(ctx).
+		Where("refund_status = ? AND create_time < ?", consts.RefundPending, before).
+		Order("create_time, id").
+		Limit(limit).
+		Find(&logs).Error
+	return
+}
+
+func (d *OrderEditDaoImpl) MarkRefundSent(ctx context.Context, id int) error {
+	return d.db.WithContext(ctx).Model(&model.OrderAuditLog{}).
+		Where("id = ? AND refund_status = ?", id, consts.RefundPending).
+		Update("refund_status", consts.RefundSent).Error
+}
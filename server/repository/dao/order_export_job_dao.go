@@ -0,0 +1,99 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/consts"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/model"
+	"gorm.io/gorm"
+)
+
+type OrderExportJobDao interface {
+	Create(ctx context.Context, job *model.OrderExportJob) (id int, err error)
+	GetByJobID(ctx context.Context, jobID string) (job *model.OrderExportJob, err error)
+	MarkRunning(ctx context.Context, jobID string) error
+	MarkSucceeded(ctx context.Context, jobID string, rowCount int, finishTime time.Time, expireTime time.Time) error
+	MarkFailed(ctx context.Context, jobID string, errMsg string, finishTime time.Time) error
+	GetExpired(ctx context.Context, now time.Time, limit int) (jobs []*model.OrderExportJob, err error)
+	MarkExpired(ctx context.Context, jobID string) error
+	FailStale(ctx context.Context, createdBefore time.Time, errMsg string, finishTime time.Time) (count int64, err error)
+}
+
+type OrderExportJobDaoImpl struct {
+	db *gorm.DB
+}
+
+func NewOrderExportJobDao(db *gorm.DB) *OrderExportJobDaoImpl {
+	return &OrderExportJobDaoImpl{db: db}
+}
+
+func (d *OrderExportJobDaoImpl) Create(ctx context.Context, job *model.OrderExportJob) (id int, err error) {
+	result := d.db.WithContext(ctx).Create(job)
+	return job.ID, result.Error
+}
+
+func (d *OrderExportJobDaoImpl) GetByJobID(ctx context.Context, jobID string) (job *model.OrderExportJob, err error) {
+	job = &model.OrderExportJob{}
+	err = d.db.WithContext(ctx).Where("job_id = ?", jobID).First(job).Error
+	return
+}
+
+func (d *OrderExportJobDaoImpl) MarkRunning(ctx context.Context, jobID string) error {
+	return d.db.WithContext(ctx).
+		Model(&model.OrderExportJob{}).
+		Where("job_id = ?", jobID).
+		Update("status", consts.ExportJobRunning).Error
+}
+
+func (d *OrderExportJobDaoImpl) MarkSucceeded(ctx context.Context, jobID string, rowCount int, finishTime time.Time, expireTime time.Time) error {
+	return d.db.WithContext(ctx).
+		Model(&model.OrderExportJob{}).
+		Where("job_id = ?", jobID).
+		Updates(map[string]interface{}{
+			"status":      consts.ExportJobSucceeded,
+			"row_count":   rowCount,
+			"finish_time": finishTime,
+			"expire_time": expireTime,
+		}).Error
+}
+
+func (d *OrderExportJobDaoImpl) MarkFailed(ctx context.Context, jobID string, errMsg string, finishTime time.Time) error {
+	return d.db.WithContext(ctx).
+		Model(&model.OrderExportJob{}).
+		Where("job_id = ?", jobID).
+		Updates(map[string]interface{}{
+			"status":      consts.ExportJobFailed,
+			"error_msg":   errMsg,
+			"finish_time": finishTime,
+		}).Error
+}
+
+// GetExpired 查询文件已过保留期、尚未清理的任务
+func (d *OrderExportJobDaoImpl) GetExpired(ctx context.Context, now time.Time, limit int) (jobs []*model.OrderExportJob, err error) {
+	err = d.db.WithContext(ctx).
+		Where("status = ? AND expire_time <= ?", consts.ExportJobSucceeded, now).
+		Limit(limit).
+		Find(&jobs).Error
+	return
+}
+
+func (d *OrderExportJobDaoImpl) MarkExpired(ctx context.Context, jobID string) error {
+	return d.db.WithContext(ctx).
+		Model(&model.OrderExportJob{}).
+		Where("job_id = ?", jobID).
+		Update("status", consts.ExportJobExpired).Error
+}
+
+// FailStale 将创建于 createdBefore 之前仍未完成的任务标记为失败，这些任务的进程已退出
+func (d *OrderExportJobDaoImpl) FailStale(ctx context.Context, createdBefore time.Time, errMsg string, finishTime time.Time) (count int64, err error) {
+	result := d.db.WithContext(ctx).
+		Model(&model.OrderExportJob{}).
+		Where("status IN ? AND create_time < ?", []string{consts.ExportJobPending, consts.ExportJobRunning}, createdBefore).
+		Updates(map[string]interface{}{
+			"status":      consts.ExportJobFailed,
+			"error_msg":   errMsg,
+			"finish_time": finishTime,
+		})
+	return result.RowsAffected, result.Error
+}
@@ -2,9 +2,7 @@ package dao
 
 import (
 	"context"
-	"sync"
 
-	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/model"
 	"gorm.io/gorm"
 )
@@ -14,22 +12,12 @@ type OrderLogDao interface {
 	GetByOrderNo(ctx context.Context, orderNo string) (orderLogList []*model.OrderStatusLog, err error)
 }
 
-var (
-	orderLogOnce sync.Once
-	orderLogDao  *OrderLogDaoImpl
-)
-
 type OrderLogDaoImpl struct {
 	db *gorm.DB
 }
 
-func GetOrderLogDao() *OrderLogDaoImpl {
-	orderLogOnce.Do(func() {
-		if orderLogDao == nil {
-			orderLogDao = &OrderLogDaoImpl{repository.DB}
-		}
-	})
-	return orderLogDao
+func NewOrderLogDao(db *gorm.DB) *OrderLogDaoImpl {
+	return &OrderLogDaoImpl{db: db}
 }
 
 func (d *OrderLogDaoImpl) Create(ctx context.Context, orderLog *model.OrderStatusLog) (id int, err error) {
@@ -2,9 +2,7 @@ package dao
 
 import (
 	"context"
-	"sync"
 
-	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/model"
 	"gorm.io/gorm"
 )
@@ -13,24 +11,15 @@ type OrderProductDao interface {
 	Create(ctx context.Context, orderProduct *model.OrderProduct) (id int, err error)
 	CreateBatch(ctx context.Context, products []model.OrderProduct) (rows int, err error)
 	GetByOrderNo(ctx context.Context, orderNo string) (orderProductList []*model.OrderProduct, err error)
+	GetByOrderNos(ctx context.Context, orderNos []string) (orderProductList []*model.OrderProduct, err error)
 }
 
-var (
-	orderProductOnce            sync.Once
-	orderProductDaoImplInstance *OrderProductDaoImpl
-)
-
 type OrderProductDaoImpl struct {
 	db *gorm.DB
 }
 
-func GetOrderProductDao() *OrderProductDaoImpl {
-	orderProductOnce.Do(func() {
-		if orderProductDaoImplInstance == nil {
-			orderProductDaoImplInstance = &OrderProductDaoImpl{repository.DB}
-		}
-	})
-	return orderProductDaoImplInstance
+func NewOrderProductDao(db *gorm.DB) *OrderProductDaoImpl {
+	return &OrderProductDaoImpl{db: db}
 }
 
 func (d *OrderProductDaoImpl) Create(ctx context.Context, orderProduct *model.OrderProduct) (id int, err error) {
@@ -52,3 +41,12 @@ func (d *OrderProductDaoImpl) GetByOrderNo(ctx context.Context, orderNo string)
 	err = d.db.WithContext(ctx).Where("order_no = ?", orderNo).Find(&orderProductList).Error
 	return
 }
+
+// GetByOrderNos loads the products of several orders in one query.
+func (d *OrderProductDaoImpl) GetByOrderNos(ctx context.Context, orderNos []string) (orderProductList []*model.OrderProduct, err error) {
+	if len(orderNos) == 0 {
+		return nil, nil
+	}
+	err = d.db.WithContext(ctx).Where("order_no IN ?", orderNos).Order("id").Find(&orderProductList).Error
+	return
+}
@@ -2,12 +2,74 @@ package dao
 
 import "time"
 
+// 总数统计方式
+const (
+	CountModeExact    = "exact"    // COUNT(*)，精确但大结果集较慢
+	CountModeEstimate = "estimate" // 取 EXPLAIN 的估算行数，不扫表
+	CountModeNone     = "none"     // 不统计总数，用于逐批遍历
+)
+
+// 可排序字段
+const (
+	OrderSortCreateTime   = "create_time"
+	OrderSortPayTime      = "pay_time"
+	OrderSortDeliveryTime = "delivery_time"
+	OrderSortTotalAmount  = "total_amount"
+	OrderSortStatus       = "status"
+)
+
+// orderSortFields 可排序字段 -> 是否可能为 NULL。可空字段不支持游标分页，只能用偏移分页。
+var orderSortFields = map[string]bool{
+	OrderSortCreateTime:   false,
+	OrderSortPayTime:      true,
+	OrderSortDeliveryTime: true,
+	OrderSortTotalAmount:  false,
+	OrderSortStatus:       false,
+}
+
+// IsOrderSortField reports whether field can be used as OrderQuery.SortBy.
+func IsOrderSortField(field string) bool {
+	_, ok := orderSortFields[field]
+	return ok
+}
+
+// SupportsCursor reports whether listings sorted by field can use keyset pagination.
+func SupportsCursor(field string) bool {
+	nullable, ok := orderSortFields[field]
+	return ok && !nullable
+}
+
 type OrderQuery struct {
-	UserID      int       // 用户ID筛选
-	OrderStatus int       // 订单状态筛选
-	StartTime   time.Time // 创建时间开始范围
-	EndTime     time.Time // 创建时间结束范围
-	OrderNo     string    // 订单号筛选
-	Limit       int       // 分页限制
-	Offset      int       // 分页偏移
+	MerchantID        int          // 商家范围，0 表示不限
+	UserID            int          // 用户ID筛选
+	OrderStatus       int          // 订单状态筛选
+	Statuses          []int        // 订单状态多选，与 OrderStatus 同时设置时取并集
+	StartTime         time.Time    // 创建时间开始范围
+	EndTime           time.Time    // 创建时间结束范围
+	OrderNo           string       // 订单号筛选
+	MinAmount         *int         // 总金额下限（含）
+	MaxAmount         *int         // 总金额上限（含）
+	ReceiverName      string       // 收货人姓名前缀，"名 姓" 形式时分别匹配
+	ReceiverPhone     string       // 收货人电话前缀
+	ReceiverCountry   string       // 收货人国家
+	ProductID         int          // 包含该商品的订单
+	ProductName       string       // 包含名称中有该关键字的商品的订单
+	LogisticsNo       string       // 物流单号
+	PayStartTime      time.Time    // 支付时间开始范围
+	PayEndTime        time.Time    // 支付时间结束范围
+	DeliveryStartTime time.Time    // 发货时间开始范围
+	DeliveryEndTime   time.Time    // 发货时间结束范围
+	SortBy            string       // 排序字段，默认 OrderSortCreateTime
+	SortAsc           bool         // 是否升序，默认降序
+	Limit             int          // 分页限制
+	Offset            int          // 分页偏移，设置 After 时忽略
+	After             *OrderCursor // 游标分页：只返回排在该位置之后的订单
+	CountMode         string       // 总数统计方式，默认 CountModeExact
+}
+
+// OrderCursor is a position in the (SortBy, id) order of a listing: the sort
+// column value and id of the last order on the previous page.
+type OrderCursor struct {
+	Value interface{}
+	ID    int
 }
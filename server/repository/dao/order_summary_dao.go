@@ -0,0 +1,178 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/types"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SummaryCheckpoint 汇总任务在 job_checkpoints 中的任务名
+const SummaryCheckpoint = "order_daily_summary"
+
+const summaryInsertBatch = 500
+
+// OrderSummaryDao reads and rebuilds order_daily_summary. Summary dates are
+// days in the server time zone, the zone order times are stored in.
+type OrderSummaryDao interface {
+	// GetWatermark returns the order update time the summary is complete up
+	// to; ok is false until the first backfill finishes.
+	GetWatermark(ctx context.Context) (watermark time.Time, ok bool, err error)
+	SaveWatermark(ctx context.Context, watermark time.Time) error
+	// GetFirstOrderTime returns the create time of the oldest order; ok is false without orders.
+	GetFirstOrderTime(ctx context.Context) (first time.Time, ok bool, err error)
+	// GetChangedDates returns the create dates of the orders updated since the given time.
+	GetChangedDates(ctx context.Context, since time.Time) (dates []time.Time, err error)
+	// RebuildRange replaces the summary rows of the orders created in [start, end).
+	RebuildRange(ctx context.Context, start, end time.Time) error
+
+	GetSalesDays(ctx context.Context, filter AnalyticsFilter) (days []*SalesSlot, err error)
+	CountByStatus(ctx context.Context, filter AnalyticsFilter) (counts map[int]int64, err error)
+	// GetMerchantTotals returns the counted orders and sales of every merchant; customers are not summarized.
+	GetMerchantTotals(ctx context.Context) (totals map[int]types.OrderStats, err error)
+}
+
+type OrderSummaryDaoImpl struct {
+	db *gorm.DB
+}
+
+func NewOrderSummaryDao(db *gorm.DB) *OrderSummaryDaoImpl {
+	return &OrderSummaryDaoImpl{db: db}
+}
+
+func (d *OrderSummaryDaoImpl) GetWatermark(ctx context.Context) (watermark time.Time, ok bool, err error) {
+	checkpoint := &model.JobCheckpoint{}
+	err = d.db.WithContext(ctx).Where("name = ?", SummaryCheckpoint).First(checkpoint).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return checkpoint.Watermark, true, nil
+}
+
+func (d *OrderSummaryDaoImpl) SaveWatermark(ctx context.Context, watermark time.Time) error {
+	return d.db.WithContext(ctx).
+		Clauses(clause.OnConflict{UpdateAll: true}).
+		Create(&model.JobCheckpoint{Name: SummaryCheckpoint, Watermark: watermark}).Error
+}
+
+func (d *OrderSummaryDaoImpl) GetFirstOrderTime(ctx context.Context) (first time.Time, ok bool, err error) {
+	order := &model.Order{}
+	err = d.db.WithContext(ctx).Select("create_time").Order("create_time").First(order).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return order.CreateTime, true, nil
+}
+
+func (d *OrderSummaryDaoImpl) GetChangedDates(ctx context.Context, since time.Time) (dates []time.Time, err error) {
+	err = d.db.WithContext(ctx).
+		Model(&model.Order{}).
+		Where("update_time >= ?", since).
+		Distinct().
+		Order("summary_date").
+		Pluck("DATE(create_time) AS summary_date", &dates).Error
+	return
+}
+
+func (d *OrderSummaryDaoImpl) RebuildRange(ctx context.Context, start, end time.Time) error {
+	var rows []*model.OrderDailySummary
+	err := d.db.WithContext(ctx).
+		Model(&model.Order{}).
+		Select([]string{
+			"DATE(create_time) AS summary_date",
+			"merchant_id",
+			"status",
+			"receiver_country AS country",
+			"COUNT(*) AS orders",
+			"SUM(total_amount) AS gross",
+			"SUM(tax) AS tax",
+			"SUM(shipping_fee) AS shipping",
+			"SUM(refund_amount) AS refunds",
+		}).
+		Where("create_time >= ? AND create_time < ?", start, end).
+		Group("DATE(create_time), merchant_id, status, receiver_country").
+		Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	return d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("summary_date >= ? AND summary_date < ?", start, end).
+			Delete(&model.OrderDailySummary{}).Error
+		if err != nil || len(rows) == 0 {
+			return err
+		}
+		return tx.CreateInBatches(rows, summaryInsertBatch).Error
+	})
+}
+
+func (d *OrderSummaryDaoImpl) summaries(ctx context.Context, filter AnalyticsFilter) *gorm.DB {
+	db := d.db.WithContext(ctx).Model(&model.OrderDailySummary{}).
+		Where("summary_date >= ? AND summary_date < ?", filter.Start, filter.End)
+	if filter.MerchantID != 0 {
+		db = db.Where("merchant_id = ?", filter.MerchantID)
+	}
+	return db
+}
+
+// GetSalesDays returns one slot per day, starting at midnight in the server time zone.
+func (d *OrderSummaryDaoImpl) GetSalesDays(ctx context.Context, filter AnalyticsFilter) (days []*SalesSlot, err error) {
+	err = d.summaries(ctx, filter).
+		Select("summary_date AS slot_time, SUM(orders) AS orders, SUM(gross) AS revenue").
+		Where("status IN ?", SalesStatuses).
+		Group("summary_date").
+		Scan(&days).Error
+	return
+}
+
+func (d *OrderSummaryDaoImpl) CountByStatus(ctx context.Context, filter AnalyticsFilter) (counts map[int]int64, err error) {
+	var rows []struct {
+		Status int
+		Count  int64
+	}
+	err = d.summaries(ctx, filter).
+		Select("status, SUM(orders) AS count").
+		Group("status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	counts = make(map[int]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+type merchantStatsRow struct {
+	MerchantID int
+	types.OrderStats
+}
+
+func (d *OrderSummaryDaoImpl) GetMerchantTotals(ctx context.Context) (totals map[int]types.OrderStats, err error) {
+	var rows []merchantStatsRow
+	err = d.db.WithContext(ctx).
+		Model(&model.OrderDailySummary{}).
+		Select("merchant_id, SUM(orders) AS total_orders, SUM(gross) AS total_sales").
+		Where("status IN ?", SalesStatuses).
+		Group("merchant_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	totals = make(map[int]types.OrderStats, len(rows))
+	for _, row := range rows {
+		totals[row.MerchantID] = row.OrderStats
+	}
+	return totals, nil
+}
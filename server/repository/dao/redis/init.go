@@ -4,17 +4,20 @@ import (
 	"fmt"
 
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/config"
+	"github.com/redis/go-redis/extra/redisotel/v9"
 	"github.com/redis/go-redis/v9"
 )
 
-var RedisClient *redis.Client
-
-func Init() {
-	addr := fmt.Sprintf("%s:%d", config.Config.RedisConfig.Host, config.Config.RedisConfig.Port)
-	RedisClient = redis.NewClient(&redis.Options{
+func NewClient(cfg *config.RedisConfig) (*redis.Client, error) {
+	addr := fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)
+	client := redis.NewClient(&redis.Options{
 		Addr:     addr,
 		Password: "",
 		DB:       0,
 		PoolSize: 20,
 	})
+	if err := redisotel.InstrumentTracing(client); err != nil {
+		return nil, err
+	}
+	return client, nil
 }
@@ -0,0 +1,129 @@
+package dao
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ShipmentPlanner decides, under the order's row lock, which items go into a
+// new shipment and the status the order moves to. shipped maps an order
+// product ID to the quantity already shipped in earlier shipments.
+type ShipmentPlanner func(order *model.Order, products []*model.OrderProduct, shipped map[int]int) (items []*model.ShipmentItem, toStatus int, err error)
+
+type ShipmentDao interface {
+	Create(ctx context.Context, shipment *model.Shipment, plan ShipmentPlanner) (order *model.Order, toStatus int, err error)
+	GetByOrderNo(ctx context.Context, orderNo string) (shipments []*model.Shipment, err error)
+}
+
+type ShipmentDaoImpl struct {
+	db *gorm.DB
+}
+
+func NewShipmentDao(db *gorm.DB) *ShipmentDaoImpl {
+	return &ShipmentDaoImpl{db: db}
+}
+
+// Create stores the shipment with the items chosen by plan and updates the
+// order's status and latest delivery info in one transaction. order is the
+// order as it was before the update.
+func (d *ShipmentDaoImpl) Create(ctx context.Context, shipment *model.Shipment, plan ShipmentPlanner) (order *model.Order, toStatus int, err error) {
+	err = d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		order, toStatus, err = createShipment(tx, shipment, plan)
+		return err
+	})
+	return order, toStatus, err
+}
+
+func (d *ShipmentDaoImpl) GetByOrderNo(ctx context.Context, orderNo string) (shipments []*model.Shipment, err error) {
+	err = d.db.WithContext(ctx).
+		Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Where("order_no = ?", orderNo).
+		Order("id").
+		Find(&shipments).Error
+	return
+}
+
+// createShipment locks the order, lets plan pick the items and inserts the
+// shipment. It must run inside a transaction.
+func createShipment(tx *gorm.DB, shipment *model.Shipment, plan ShipmentPlanner) (*model.Order, int, error) {
+	// 锁定订单行，避免并发发货超发
+	order := &model.Order{}
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("order_no = ?", shipment.OrderNo).First(order).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var products []*model.OrderProduct
+	if err = tx.Where("order_no = ?", shipment.OrderNo).Order("id").Find(&products).Error; err != nil {
+		return nil, 0, err
+	}
+	var shippedRows []struct {
+		OrderProductID int
+		Quantity       int
+	}
+	err = tx.Model(&model.ShipmentItem{}).
+		Select("shipment_items.order_product_id, SUM(shipment_items.quantity) AS quantity").
+		Joins("JOIN shipments ON shipments.id = shipment_items.shipment_id").
+		Where("shipments.order_no = ?", shipment.OrderNo).
+		Group("shipment_items.order_product_id").
+		Scan(&shippedRows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	shipped := make(map[int]int, len(shippedRows))
+	for _, row := range shippedRows {
+		shipped[row.OrderProductID] = row.Quantity
+	}
+
+	items, toStatus, err := plan(order, products, shipped)
+	if err != nil {
+		return nil, 0, err
+	}
+	shipment.Items = items
+	if shipment.CreateTime.IsZero() {
+		shipment.CreateTime = time.Now()
+	}
+	if err = tx.Create(shipment).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// 订单上保留最近一个包裹的物流信息
+	err = tx.Model(&model.Order{}).
+		Where("order_no = ?", shipment.OrderNo).
+		Updates(map[string]interface{}{
+			"status":        toStatus,
+			"delivery_time": shipment.CreateTime,
+			"carrier":       shipment.CarrierCode,
+			"logistics_no":  shipment.TrackingNo,
+		}).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return order, toStatus, nil
+}
+
+// shipRemaining is a ShipmentPlanner that puts every item not yet shipped into
+// the shipment, for orders in one of fromStatuses.
+func shipRemaining(fromStatuses []int, toStatus int) ShipmentPlanner {
+	return func(order *model.Order, products []*model.OrderProduct, shipped map[int]int) ([]*model.ShipmentItem, int, error) {
+		if !slices.Contains(fromStatuses, order.Status) {
+			return nil, 0, ErrOrderStatusChanged.Detailf("%s", order.OrderNo)
+		}
+		items := make([]*model.ShipmentItem, 0, len(products))
+		for _, product := range products {
+			if remaining := product.Quantity - shipped[product.ID]; remaining > 0 {
+				items = append(items, &model.ShipmentItem{
+					OrderProductID: product.ID,
+					ProductID:      product.ProductID,
+					Quantity:       remaining,
+				})
+			}
+		}
+		return items, toStatus, nil
+	}
+}
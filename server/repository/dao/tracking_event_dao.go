@@ -0,0 +1,102 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TrackingEventDao interface {
+	Record(ctx context.Context, carrierCode string, trackingNo string, events []*model.TrackingEvent, deliveredStatus string, shippedStatus int) (shipment *model.Shipment, arrived bool, err error)
+	GetByOrderNo(ctx context.Context, orderNo string) (events []*model.TrackingEvent, err error)
+}
+
+type TrackingEventDaoImpl struct {
+	db *gorm.DB
+}
+
+func NewTrackingEventDao(db *gorm.DB) *TrackingEventDaoImpl {
+	return &TrackingEventDaoImpl{db: db}
+}
+
+// Record stores the events of the shipment identified by carrier and tracking
+// number, skipping events already stored. The first deliveredStatus event sets
+// the shipment's delivered time; once every shipment of an order in
+// shippedStatus is delivered, the order's arrived time is set to the latest of
+// them and arrived is true.
+func (d *TrackingEventDaoImpl) Record(ctx context.Context, carrierCode string, trackingNo string, events []*model.TrackingEvent, deliveredStatus string, shippedStatus int) (shipment *model.Shipment, arrived bool, err error) {
+	err = d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		shipment = &model.Shipment{}
+		err := tx.Where("carrier_code = ? AND tracking_no = ?", carrierCode, trackingNo).
+			Order("id DESC").
+			First(shipment).Error
+		if err != nil {
+			return err
+		}
+
+		var delivered *model.TrackingEvent
+		for _, event := range events {
+			event.ShipmentID = shipment.ID
+			event.OrderNo = shipment.OrderNo
+			if event.Status == deliveredStatus && (delivered == nil || event.EventTime.Before(delivered.EventTime)) {
+				delivered = event
+			}
+		}
+		// 承运商可能重复推送同一事件
+		if err = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&events).Error; err != nil {
+			return err
+		}
+		if delivered == nil || !shipment.DeliveredTime.IsZero() {
+			return nil
+		}
+
+		shipment.DeliveredTime = delivered.EventTime
+		err = tx.Model(&model.Shipment{}).Where("id = ?", shipment.ID).Update("delivered_time", shipment.DeliveredTime).Error
+		if err != nil {
+			return err
+		}
+		arrived, err = markOrderArrived(tx, shipment.OrderNo, shippedStatus)
+		return err
+	})
+	return shipment, arrived, err
+}
+
+// markOrderArrived sets the order's arrived time when it is fully shipped and
+// all of its shipments are delivered.
+func markOrderArrived(tx *gorm.DB, orderNo string, shippedStatus int) (bool, error) {
+	order := &model.Order{}
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("order_no = ?", orderNo).First(order).Error
+	if err != nil {
+		return false, err
+	}
+	if order.Status != shippedStatus || !order.ArrivedTime.IsZero() {
+		return false, nil
+	}
+
+	var shipments []*model.Shipment
+	if err = tx.Where("order_no = ?", orderNo).Find(&shipments).Error; err != nil {
+		return false, err
+	}
+	var arrivedTime time.Time
+	for _, s := range shipments {
+		if s.DeliveredTime.IsZero() {
+			return false, nil
+		}
+		if s.DeliveredTime.After(arrivedTime) {
+			arrivedTime = s.DeliveredTime
+		}
+	}
+	err = tx.Model(&model.Order{}).Where("order_no = ?", orderNo).Update("arrived_time", arrivedTime).Error
+	return err == nil, err
+}
+
+func (d *TrackingEventDaoImpl) GetByOrderNo(ctx context.Context, orderNo string) (events []*model.TrackingEvent, err error) {
+	err = d.db.WithContext(ctx).
+		Where("order_no = ?", orderNo).
+		Order("event_time, id").
+		Find(&events).Error
+	return
+}
@@ -1,24 +1,30 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/config"
-	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/dao/redis"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/model"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
+	gormtracing "gorm.io/plugin/opentelemetry/tracing"
 )
 
 // mockgen -source=dao/order_dao.go -destination=dao/mocks/order_dao_mock.go -package=mocks
 // mockgen -source=dao/order_product_dao.go -destination=dao/mocks/order_product_dao_mock.go -package=mocks
 // mockgen -source=dao/order_log_dao.go -destination=dao/mocks/order_log_dao_mock.go -package=mocks
-
-var (
-	DB  *gorm.DB
-	err error
-)
+// mockgen -source=dao/shipment_dao.go -destination=dao/mocks/shipment_dao_mock.go -package=mocks
+// mockgen -source=dao/tracking_event_dao.go -destination=dao/mocks/tracking_event_dao_mock.go -package=mocks
+// mockgen -source=dao/order_export_job_dao.go -destination=dao/mocks/order_export_job_dao_mock.go -package=mocks
+// mockgen -source=dao/access_audit_dao.go -destination=dao/mocks/access_audit_dao_mock.go -package=mocks
+// mockgen -source=dao/order_analytics_dao.go -destination=dao/mocks/order_analytics_dao_mock.go -package=mocks
+// mockgen -source=dao/order_summary_dao.go -destination=dao/mocks/order_summary_dao_mock.go -package=mocks
+// mockgen -source=dao/order_edit_dao.go -destination=dao/mocks/order_edit_dao_mock.go -package=mocks
+// mockgen -source=dao/job_checkpoint_dao.go -destination=dao/mocks/job_checkpoint_dao_mock.go -package=mocks
+// mockgen -source=cache/order_stats_cache.go -destination=cache/mocks/order_stats_cache_mock.go -package=mocks
+// mockgen -source=cache/stats_store.go -destination=cache/mocks/stats_store_mock.go -package=mocks
 
 type TxBeginner interface {
 	Transaction(fc func(tx *gorm.DB) error, opts ...*sql.TxOptions) error
@@ -26,34 +32,65 @@ type TxBeginner interface {
 
 var _ TxBeginner = (*gorm.DB)(nil) // Compile-time interface check
 
-func mysqlInit() {
+// NewDB opens the MySQL connection pool, migrates the order tables and runs
+// the one-off data backfills.
+func NewDB(cfg *config.MySQL) (*gorm.DB, error) {
 	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
-		config.Config.MySQLConfig.UserName,
-		config.Config.MySQLConfig.Password,
-		config.Config.MySQLConfig.Host,
-		config.Config.MySQLConfig.Port,
-		config.Config.MySQLConfig.DBName,
+		cfg.UserName,
+		cfg.Password,
+		cfg.Host,
+		cfg.Port,
+		cfg.DBName,
 	)
-	DB, err = gorm.Open(mysql.Open(dsn),
+	db, err := gorm.Open(mysql.Open(dsn),
 		&gorm.Config{
 			PrepareStmt:            true,
 			SkipDefaultTransaction: true,
 		},
 	)
 	if err != nil {
-		panic(err)
+		return nil, err
+	}
+	err = db.Use(gormtracing.NewPlugin(gormtracing.WithoutMetrics()))
+	if err != nil {
+		return nil, err
 	}
-	err = DB.AutoMigrate(
+	err = db.AutoMigrate(
 		&model.Order{},
 		&model.OrderProduct{},
 		&model.OrderStatusLog{},
+		&model.OrderExportJob{},
+		&model.Shipment{},
+		&model.ShipmentItem{},
+		&model.TrackingEvent{},
+		&model.AccessAuditLog{},
+		&model.OrderDailySummary{},
+		&model.OrderAuditLog{},
+		&model.JobCheckpoint{},
 	)
 	if err != nil {
-		panic(err)
+		return nil, err
+	}
+	if err = backfillShipments(db); err != nil {
+		return nil, err
 	}
+	return db, nil
 }
 
-func Init() {
-	mysqlInit()
-	redis.Init()
+// Ping checks that the MySQL connection pool can reach the database.
+func Ping(ctx context.Context, db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// Close closes the MySQL connection pool.
+func Close(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
 }
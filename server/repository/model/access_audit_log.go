@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// AccessAuditLog records a request refused by the authorization layer.
+type AccessAuditLog struct {
+	ID         int       `gorm:"primaryKey;autoIncrement"`
+	UserID     int       `gorm:"type:int;not null;index"`    // 请求方用户ID，服务令牌可为 0
+	Roles      string    `gorm:"type:varchar(128);not null"` // 请求方角色，逗号分隔
+	Channel    string    `gorm:"type:varchar(16);not null"`  // http / grpc
+	Resource   string    `gorm:"type:varchar(256);not null"` // HTTP 方法和路由，或 gRPC 完整方法名
+	Reason     string    `gorm:"type:varchar(256);not null"` // 拒绝原因
+	ClientIP   string    `gorm:"type:varchar(64)"`           // 客户端地址
+	RequestID  string    `gorm:"type:varchar(64)"`           // 请求ID
+	CreateTime time.Time `gorm:"autoCreateTime;index"`       // 拒绝时间
+}
+
+// TableName sets the insert table name for this struct type
+func (AccessAuditLog) TableName() string {
+	return "access_audit_logs"
+}
@@ -4,26 +4,37 @@ import "time"
 
 type Order struct {
 	ID                int       `gorm:"primaryKey;autoIncrement"`
-	OrderNo           string    `gorm:"type:varchar(64);unique;not null"` // 订单编号
-	UserID            int       `gorm:"not null"`                         // 下单用户
-	Status            int       `gorm:"not null"`                         // 订单状态 (0-无效状态，不应该有此状态； 1-创建； 2-已付款； 3-已发货； 4-已收获； 5-取消)
-	TotalAmount       int       `gorm:"type:int;not null"`                // 总金额
-	PayAmount         int       `gorm:"type:int;not null"`                // 实际支付金额
-	PayTime           time.Time `gorm:"default:null"`                     // 支付时间
-	CreateTime        time.Time `gorm:"autoCreateTime"`                   // 创建时间
-	UpdateTime        time.Time `gorm:"autoUpdateTime"`                   // 更新时间
-	ReceiverFirstName string    `gorm:"type:varchar(64)"`                 // 收货人姓名
-	ReceiverLastName  string    `gorm:"type:varchar(64)"`                 // 收货人姓名
-	ReceiverPhone     string    `gorm:"type:varchar(32)"`                 // 收货人电话
-	ReceiverAddress   string    `gorm:"type:varchar(256)"`                // 收货地址
-	ReceiverCountry   string    `gorm:"type:varchar(64)"`                 // 收货人国家
-	ReceiverZipCode   int       `gorm:"type:int"`                         // 收货人邮政编码
-	ShippingFee       int       `gorm:"type:int;not null"`                // 运费
-	Tax               int       `gorm:"type:int;not null"`                // 税
-	Remark            string    `gorm:"type:varchar(256)"`                // 备注
-	LogisticsNo       string    `gorm:"type:varchar(64)"`                 // 物流单号
-	DeliveryTime      time.Time `gorm:"default:null"`                     // 发货时间
-	ConfirmTime       time.Time `gorm:"default:null"`                     // 收货确认时间
+	OrderNo           string    `gorm:"type:varchar(64);unique;not null"`                                                                                                                             // 订单编号
+	UserID            int       `gorm:"not null;index:idx_user_create_time,priority:1"`                                                                                                               // 下单用户
+	MerchantID        int       `gorm:"not null;default:1;index:idx_merchant_create_time,priority:1"`                                                                                                 // 商家，默认 consts.DefaultMerchantID
+	CheckoutNo        string    `gorm:"type:varchar(64);index:idx_checkout_no"`                                                                                                                       // 结算单号，一次下单拆分出的各商家子订单相同；单商家时等于订单号
+	Status            int       `gorm:"not null;index:idx_status_create_time,priority:1"`                                                                                                             // 订单状态 (0-无效状态，不应该有此状态； 1-创建； 2-已付款； 3-已发货； 4-已收获； 5-取消； 6-部分发货)
+	TotalAmount       int       `gorm:"type:int;not null;index:idx_total_amount"`                                                                                                                     // 总金额
+	PayAmount         int       `gorm:"type:int;not null"`                                                                                                                                            // 实际支付金额
+	PayTime           time.Time `gorm:"default:null;index:idx_pay_time"`                                                                                                                              // 支付时间
+	CreateTime        time.Time `gorm:"autoCreateTime;index:idx_create_time;index:idx_user_create_time,priority:2;index:idx_status_create_time,priority:2;index:idx_merchant_create_time,priority:2"` // 创建时间
+	UpdateTime        time.Time `gorm:"autoUpdateTime;index:idx_update_time"`                                                                                                                         // 更新时间
+	ReceiverFirstName string    `gorm:"type:varchar(64);index:idx_receiver_first_name"`                                                                                                               // 收货人姓名
+	ReceiverLastName  string    `gorm:"type:varchar(64);index:idx_receiver_last_name"`                                                                                                                // 收货人姓名
+	ReceiverPhone     string    `gorm:"type:varchar(32);index:idx_receiver_phone"`                                                                                                                    // 收货人电话
+	ReceiverAddress   string    `gorm:"type:varchar(256)"`                                                                                                                                            // 收货地址，结构化地址的街道部分
+	ReceiverLine1     string    `gorm:"type:varchar(256)"`                                                                                                                                            // 收货地址快照：街道、门牌号；旧接口下单时为整个 receiver_address
+	ReceiverLine2     string    `gorm:"type:varchar(120)"`                                                                                                                                            // 收货地址快照：楼层、单元号等
+	ReceiverCity      string    `gorm:"type:varchar(64)"`                                                                                                                                             // 收货地址快照：城市
+	ReceiverState     string    `gorm:"type:varchar(64)"`                                                                                                                                             // 收货地址快照：州/省
+	ReceiverPostcode  string    `gorm:"type:varchar(16)"`                                                                                                                                             // 收货地址快照：邮政编码
+	ReceiverCountry   string    `gorm:"type:varchar(64);index:idx_receiver_country"`                                                                                                                  // 收货人国家
+	ReceiverZipCode   int       `gorm:"type:int"`                                                                                                                                                     // 已废弃，旧订单的邮政编码，新订单为纯数字编码时同时写入
+	AddressID         int       `gorm:"not null;default:0"`                                                                                                                                           // 下单时选择的地址簿地址，0 表示手工填写；地址在下单时复制到 Receiver* 字段，之后不随地址簿变化
+	ShippingFee       int       `gorm:"type:int;not null"`                                                                                                                                            // 运费
+	Tax               int       `gorm:"type:int;not null"`                                                                                                                                            // 税
+	Remark            string    `gorm:"type:varchar(256)"`                                                                                                                                            // 备注
+	LogisticsNo       string    `gorm:"type:varchar(64);index:idx_logistics_no"`                                                                                                                      // 物流单号
+	Carrier           string    `gorm:"type:varchar(32)"`                                                                                                                                             // 承运商
+	DeliveryTime      time.Time `gorm:"default:null;index:idx_delivery_time"`                                                                                                                         // 发货时间
+	ArrivedTime       time.Time `gorm:"default:null;index:idx_arrived_time"`                                                                                                                          // 全部包裹签收时间，由承运商回调写入
+	ConfirmTime       time.Time `gorm:"default:null"`                                                                                                                                                 // 收货确认时间
+	RefundAmount      int       `gorm:"type:int;not null;default:0"`                                                                                                                                  // 已退款金额，发货前商家删除商品时累加
 }
 
 // TableName sets the insert table name for this struct type
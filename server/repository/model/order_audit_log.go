@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// OrderAuditLog records one merchant-side edit of an order with the before
+// and after value of every changed field. It also serves as the outbox of the
+// refund the edit causes: the row is written in the edit's transaction and
+// stays pending until the refund message is acknowledged.
+type OrderAuditLog struct {
+	ID           int       `gorm:"primaryKey;autoIncrement"`
+	OrderNo      string    `gorm:"type:varchar(64);not null;index"`                                                     // 订单号
+	MerchantID   int       `gorm:"not null"`                                                                            // 订单所属商家
+	OperatorID   int       `gorm:"not null"`                                                                            // 修改订单的用户
+	Action       string    `gorm:"type:varchar(32);not null"`                                                           // 操作，目前只有 edit
+	Reason       string    `gorm:"type:varchar(256);not null"`                                                          // 修改原因
+	Changes      string    `gorm:"type:text;not null"`                                                                  // 字段修改前后的值，types.FieldChange 的 JSON 数组
+	RefundAmount int       `gorm:"type:int;not null;default:0"`                                                         // 本次修改退还的金额
+	RefundStatus string    `gorm:"type:varchar(16);not null;default:'';index:idx_refund_status_create_time,priority:1"` // 退款消息发送状态，见 consts.Refund*；没有退款时为空
+	CreateTime   time.Time `gorm:"autoCreateTime;index:idx_refund_status_create_time,priority:2"`                       // 修改时间
+}
+
+// TableName sets the insert table name for this struct type
+func (OrderAuditLog) TableName() string {
+	return "order_audit_logs"
+}
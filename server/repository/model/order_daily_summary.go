@@ -0,0 +1,38 @@
+package model
+
+import "time"
+
+// OrderDailySummary 按下单日期汇总的订单数据，日期按服务器时区划分。
+// 由汇总任务根据订单表重建，不直接写入。
+type OrderDailySummary struct {
+	ID          int       `gorm:"primaryKey;autoIncrement"`
+	SummaryDate time.Time `gorm:"type:date;not null;uniqueIndex:uk_date_merchant_status_country,priority:1"`                          // 下单日期
+	MerchantID  int       `gorm:"not null;uniqueIndex:uk_date_merchant_status_country,priority:2;index:idx_merchant_date,priority:1"` // 商家
+	Status      int       `gorm:"not null;uniqueIndex:uk_date_merchant_status_country,priority:3"`                                    // 订单当前状态
+	Country     string    `gorm:"type:varchar(64);not null;default:'';uniqueIndex:uk_date_merchant_status_country,priority:4"`        // 收货国家
+	Orders      int64     `gorm:"not null"`                                                                                           // 订单数
+	Gross       int64     `gorm:"not null"`                                                                                           // 订单总金额
+	Tax         int64     `gorm:"not null"`                                                                                           // 税
+	Shipping    int64     `gorm:"not null"`                                                                                           // 运费
+	Discounts   int64     `gorm:"not null;default:0"`                                                                                 // 优惠，订单暂无优惠数据，恒为 0
+	Refunds     int64     `gorm:"not null;default:0"`                                                                                 // 退款，发货前修改订单退还的金额
+	UpdateTime  time.Time `gorm:"autoUpdateTime"`                                                                                     // 更新时间
+}
+
+// TableName sets the insert table name for this struct type
+func (OrderDailySummary) TableName() string {
+	return "order_daily_summary"
+}
+
+// JobCheckpoint 定时任务的进度，如汇总任务已处理到的订单更新时间、搜索索引回填已处理到的订单 ID
+type JobCheckpoint struct {
+	Name       string    `gorm:"type:varchar(64);primaryKey"` // 任务名
+	Watermark  time.Time `gorm:"not null"`                    // 已处理到的时间
+	Position   int       `gorm:"not null;default:0"`          // 按 ID 遍历的任务已处理到的 ID
+	UpdateTime time.Time `gorm:"autoUpdateTime"`              // 更新时间
+}
+
+// TableName sets the insert table name for this struct type
+func (JobCheckpoint) TableName() string {
+	return "job_checkpoints"
+}
@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+type OrderExportJob struct {
+	ID         int       `gorm:"primaryKey;autoIncrement"`
+	JobID      string    `gorm:"type:varchar(64);unique;not null"`                                  // 任务编号
+	UserID     int       `gorm:"not null;index"`                                                    // 创建任务的商家
+	MerchantID int       `gorm:"not null;default:0"`                                                // 导出范围，0 表示不限商家
+	Format     string    `gorm:"type:varchar(8);not null"`                                          // 导出格式 csv / xlsx
+	Status     string    `gorm:"type:varchar(16);not null;index:idx_status_expire_time,priority:1"` // 任务状态，见 consts.ExportJob*
+	Filters    string    `gorm:"type:text"`                                                         // 筛选条件，ListOrderRequest 的 JSON
+	FileName   string    `gorm:"type:varchar(128)"`                                                 // 导出文件名
+	RowCount   int       `gorm:"not null;default:0"`                                                // 导出行数
+	ErrorMsg   string    `gorm:"type:varchar(512)"`                                                 // 失败原因
+	CreateTime time.Time `gorm:"autoCreateTime"`                                                    // 创建时间
+	FinishTime time.Time `gorm:"default:null"`                                                      // 完成时间
+	ExpireTime time.Time `gorm:"default:null;index:idx_status_expire_time,priority:2"`              // 文件过期时间
+}
+
+// TableName sets the insert table name for this struct type
+func (OrderExportJob) TableName() string {
+	return "order_export_jobs"
+}
@@ -4,14 +4,16 @@ import "time"
 
 type OrderProduct struct {
 	ID          int       `gorm:"primaryKey;autoIncrement"`
-	OrderNo     string    `gorm:"type:varchar(255);not null;index"` // 订单号
-	ProductID   int       `gorm:"not null"`                         // 商品ID
-	ProductName string    `gorm:"type:varchar(128);not null"`       // 商品名称
-	Price       int       `gorm:"type:int;not null"`                // 商品单价
-	Quantity    int       `gorm:"not null"`                         // 商品数量
-	TotalPrice  int       `gorm:"type:int;not null"`                // 商品总价
-	CreateTime  time.Time `gorm:"autoCreateTime"`                   // 创建时间
-	UpdateTime  time.Time `gorm:"autoUpdateTime"`                   // 更新时间
+	OrderNo     string    `gorm:"type:varchar(255);not null;index;index:idx_product_order_no,priority:2"` // 订单号
+	ProductID   int       `gorm:"not null;index:idx_product_order_no,priority:1"`                         // 商品ID
+	MerchantID  int       `gorm:"not null;default:1;index"`                                               // 商品所属商家
+	ProductName string    `gorm:"type:varchar(128);not null"`                                             // 商品名称
+	ImageURL    string    `gorm:"type:varchar(512)"`                                                      // 商品图片
+	Price       int       `gorm:"type:int;not null"`                                                      // 商品单价
+	Quantity    int       `gorm:"not null"`                                                               // 商品数量
+	TotalPrice  int       `gorm:"type:int;not null"`                                                      // 商品总价
+	CreateTime  time.Time `gorm:"autoCreateTime"`                                                         // 创建时间
+	UpdateTime  time.Time `gorm:"autoUpdateTime"`                                                         // 更新时间
 }
 
 // TableName sets the insert table name for this struct type
@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+// Shipment is one parcel of an order. An order may be split across several
+// shipments, each carrying some of its items.
+type Shipment struct {
+	ID            int             `gorm:"primaryKey;autoIncrement"`
+	OrderNo       string          `gorm:"type:varchar(64);not null;index"`                            // 订单编号
+	CarrierCode   string          `gorm:"type:varchar(32);index:idx_shipment_tracking_no,priority:1"` // 承运商编码，见配置 carriers
+	TrackingNo    string          `gorm:"type:varchar(64);index:idx_shipment_tracking_no,priority:2"` // 物流单号
+	Weight        int             `gorm:"type:int;not null;default:0"`                                // 包裹重量，克
+	LabelURL      string          `gorm:"type:varchar(512)"`                                          // 面单地址
+	DeliveredTime time.Time       `gorm:"default:null"`                                               // 承运商报告的签收时间
+	CreateTime    time.Time       `gorm:"autoCreateTime"`                                             // 发货时间
+	UpdateTime    time.Time       `gorm:"autoUpdateTime"`                                             // 更新时间
+	Items         []*ShipmentItem `gorm:"foreignKey:ShipmentID"`                                      // 包裹内商品
+}
+
+// TableName sets the insert table name for this struct type
+func (Shipment) TableName() string {
+	return "shipments"
+}
+
+type ShipmentItem struct {
+	ID             int       `gorm:"primaryKey;autoIncrement"`
+	ShipmentID     int       `gorm:"not null;index"` // 包裹ID
+	OrderProductID int       `gorm:"not null;index"` // 订单商品ID
+	ProductID      int       `gorm:"not null"`       // 商品ID
+	Quantity       int       `gorm:"not null"`       // 本包裹内的数量
+	CreateTime     time.Time `gorm:"autoCreateTime"` // 创建时间
+}
+
+// TableName sets the insert table name for this struct type
+func (ShipmentItem) TableName() string {
+	return "shipment_items"
+}
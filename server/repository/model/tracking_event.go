@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// TrackingEvent is one step of a shipment's tracking timeline as reported by
+// the carrier. The same event delivered twice is stored once.
+type TrackingEvent struct {
+	ID          int       `gorm:"primaryKey;autoIncrement"`
+	ShipmentID  int       `gorm:"not null;uniqueIndex:idx_tracking_event,priority:1"`                  // 包裹ID
+	OrderNo     string    `gorm:"type:varchar(64);not null;index"`                                     // 订单编号
+	Status      string    `gorm:"type:varchar(32);not null;uniqueIndex:idx_tracking_event,priority:3"` // 轨迹状态，见 consts.TrackingXxx
+	Description string    `gorm:"type:varchar(256)"`                                                   // 描述
+	Location    string    `gorm:"type:varchar(128)"`                                                   // 地点
+	EventTime   time.Time `gorm:"not null;uniqueIndex:idx_tracking_event,priority:2"`                  // 承运商记录的时间
+	CreateTime  time.Time `gorm:"autoCreateTime"`                                                      // 接收时间
+}
+
+// TableName sets the insert table name for this struct type
+func (TrackingEvent) TableName() string {
+	return "tracking_events"
+}
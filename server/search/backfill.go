@@ -0,0 +1,83 @@
+package search
+
+import (
+	"context"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/utils"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/dao"
+)
+
+const (
+	BACKFILL_LOCK_KEY      = "order:search_backfill:lock"
+	BACKFILL_LOCK_EXP_TIME = 30 * time.Minute
+
+	// BackfillCheckpoint 回填进度在 job_checkpoints 中的任务名
+	BackfillCheckpoint = "search_index_backfill"
+	backfillBatchSize  = 500
+)
+
+// Backfiller indexes the orders placed before the search index existed, or
+// while its consumer was behind, by walking the orders table by ID.
+type Backfiller struct {
+	indexer     *Indexer
+	orderDao    dao.OrderDao
+	checkpoints dao.JobCheckpointDao
+	locker      utils.Locker
+}
+
+func NewBackfiller(indexer *Indexer, orderDao dao.OrderDao, checkpoints dao.JobCheckpointDao, locker utils.Locker) *Backfiller {
+	return &Backfiller{
+		indexer:     indexer,
+		orderDao:    orderDao,
+		checkpoints: checkpoints,
+		locker:      locker,
+	}
+}
+
+// Run indexes the orders after the checkpoint and saves the checkpoint after
+// every batch, so a run that stops early resumes where it left off. Orders
+// the consumer indexes in the meantime are upserted again, which is harmless.
+// Only the instance holding the lock runs it; the others skip.
+func (b *Backfiller) Run(ctx context.Context) {
+	logger := log.FromContext(ctx)
+	if err := b.locker.Lock(ctx); err != nil {
+		logger.Info("Backfill search index: failed to acquire lock, skipping")
+		return
+	}
+	defer func() {
+		// 停止时 ctx 已取消，仍要释放锁
+		if err := b.locker.Unlock(context.WithoutCancel(ctx)); err != nil {
+			logger.Errorf("Backfill search index: failed to release lock, err: %s", err.Error())
+		}
+	}()
+
+	afterID, _, err := b.checkpoints.GetPosition(ctx, BackfillCheckpoint)
+	if err != nil {
+		logger.Errorf("Backfill search index: get checkpoint failed, err: %s", err.Error())
+		return
+	}
+	indexed := 0
+	for ctx.Err() == nil {
+		orders, err := b.orderDao.GetAfterID(ctx, afterID, backfillBatchSize)
+		if err != nil {
+			logger.Errorf("Backfill search index: query orders after %d failed, err: %s", afterID, err.Error())
+			return
+		}
+		if len(orders) == 0 {
+			break
+		}
+		if err = b.indexer.IndexOrders(ctx, orders); err != nil {
+			logger.Errorf("Backfill search index: index orders after %d failed, err: %s", afterID, err.Error())
+			return
+		}
+		afterID = orders[len(orders)-1].ID
+		indexed += len(orders)
+		if err = b.checkpoints.SavePosition(ctx, BackfillCheckpoint, afterID); err != nil {
+			logger.Errorf("Backfill search index: save checkpoint %d failed, err: %s", afterID, err.Error())
+			return
+		}
+	}
+	logger.Infof("Backfill search index: indexed %d orders, checkpoint at order %d", indexed, afterID)
+}
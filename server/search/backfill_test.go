@@ -0,0 +1,77 @@
+package search_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/log"
+	utilMocks "github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/utils/mocks"
+	daoMocks "github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/dao/mocks"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/search"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/search/mocks"
+	"github.com/golang/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func init() {
+	logger, _ := zap.NewDevelopment()
+	log.Logger = logger.Sugar()
+}
+
+func TestBackfiller_Run(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderDao := daoMocks.NewMockOrderDao(ctrl)
+	mockOrderProductDao := daoMocks.NewMockOrderProductDao(ctrl)
+	mockCheckpoints := daoMocks.NewMockJobCheckpointDao(ctrl)
+	mockLocker := utilMocks.NewMockLocker(ctrl)
+	mockIndex := mocks.NewMockOrderIndexer(ctrl)
+	ctx := context.Background()
+
+	mockLocker.EXPECT().Lock(ctx).Return(nil)
+	mockLocker.EXPECT().Unlock(gomock.Any()).Return(nil)
+	// 从上次中断的位置继续，每批之后保存进度
+	mockCheckpoints.EXPECT().GetPosition(ctx, search.BackfillCheckpoint).Return(41, true, nil)
+	mockOrderDao.EXPECT().GetAfterID(ctx, 41, gomock.Any()).Return([]*model.Order{
+		{ID: 42, OrderNo: "ORD42", ReceiverAddress: "8 Jalan Bukit", ReceiverCountry: "SG", ReceiverZipCode: 18956},
+		{ID: 45, OrderNo: "ORD45"},
+	}, nil)
+	mockOrderProductDao.EXPECT().GetByOrderNos(ctx, []string{"ORD42", "ORD45"}).Return([]*model.OrderProduct{
+		{OrderNo: "ORD42", ProductName: "Blue Mug"},
+		{OrderNo: "ORD45", ProductName: "Plate"},
+		{OrderNo: "ORD42", ProductName: "Bowl"},
+	}, nil)
+	indexed := map[string]*search.Document{}
+	mockIndex.EXPECT().IndexOrder(ctx, gomock.Any()).Times(2).DoAndReturn(func(_ context.Context, doc *search.Document) error {
+		indexed[doc.OrderNo] = doc
+		return nil
+	})
+	mockCheckpoints.EXPECT().SavePosition(ctx, search.BackfillCheckpoint, 45).Return(nil)
+	mockOrderDao.EXPECT().GetAfterID(ctx, 45, gomock.Any()).Return(nil, nil)
+
+	indexer := search.NewIndexer(mockIndex, mockOrderDao, mockOrderProductDao)
+	search.NewBackfiller(indexer, mockOrderDao, mockCheckpoints, mockLocker).Run(ctx)
+
+	// 旧订单的整数邮编按国家补齐前导零
+	if doc := indexed["ORD42"]; doc == nil || doc.ProductNames != "Blue Mug Bowl" || doc.ReceiverAddress != "8 Jalan Bukit, 018956" {
+		t.Errorf("unexpected document: %+v", doc)
+	}
+	if doc := indexed["ORD45"]; doc == nil || doc.ProductNames != "Plate" {
+		t.Errorf("unexpected document: %+v", doc)
+	}
+}
+
+func TestBackfiller_Run_LockHeld(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLocker := utilMocks.NewMockLocker(ctrl)
+	ctx := context.Background()
+
+	// 其他实例正在回填，本实例不读取进度也不写索引
+	mockLocker.EXPECT().Lock(ctx).Return(errors.New("lock held"))
+	search.NewBackfiller(nil, daoMocks.NewMockOrderDao(ctrl), daoMocks.NewMockJobCheckpointDao(ctrl), mockLocker).Run(ctx)
+}
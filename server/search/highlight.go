@@ -0,0 +1,104 @@
+package search
+
+import (
+	"html"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	highlightPre     = "<em>"
+	highlightPost    = "</em>"
+	snippetRadius    = 40 // 命中词前后保留的字符数
+	minSearchTermLen = 2  // 与 ngram_token_size 默认值一致，更短的词无法命中
+)
+
+// searchTerms splits the user input into terms, dropping the characters
+// that are operators in MySQL boolean mode.
+func searchTerms(text string) []string {
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune(`+-<>()~*"@`, r)
+	})
+	terms := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if utf8.RuneCountInString(field) >= minSearchTermLen {
+			terms = append(terms, field)
+		}
+	}
+	return terms
+}
+
+// highlight wraps every case-insensitive occurrence of terms in text with
+// <em></em>, trimmed to a snippet around the first match. The rest of the
+// text is HTML-escaped. ok is false when nothing matches.
+func highlight(text string, terms []string) (snippet string, ok bool) {
+	runes := []rune(text)
+	lower := []rune(strings.ToLower(text))
+	if len(lower) != len(runes) {
+		// 大小写转换改变了长度，退化为区分大小写匹配
+		lower = runes
+	}
+	matched := make([]bool, len(runes))
+	for _, term := range terms {
+		termRunes := []rune(strings.ToLower(term))
+		for i := 0; i+len(termRunes) <= len(lower); i++ {
+			if string(lower[i:i+len(termRunes)]) == string(termRunes) {
+				for j := i; j < i+len(termRunes); j++ {
+					matched[j] = true
+				}
+				ok = true
+			}
+		}
+	}
+	if !ok {
+		return "", false
+	}
+
+	first := 0
+	for !matched[first] {
+		first++
+	}
+	start, end := max(first-snippetRadius, 0), min(first+snippetRadius*2, len(runes))
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	for i := start; i < end; {
+		j := i
+		for j < end && matched[j] == matched[i] {
+			j++
+		}
+		if matched[i] {
+			b.WriteString(highlightPre + html.EscapeString(string(runes[i:j])) + highlightPost)
+		} else {
+			b.WriteString(html.EscapeString(string(runes[i:j])))
+		}
+		i = j
+	}
+	if end < len(runes) {
+		b.WriteString("…")
+	}
+	return b.String(), true
+}
+
+// highlights returns the highlighted snippet of every field of doc matching terms.
+func highlights(doc *Document, terms []string) map[string]string {
+	fields := map[string]string{
+		FieldOrderNo:         doc.OrderNo,
+		FieldReceiverName:    doc.ReceiverName,
+		FieldReceiverPhone:   doc.ReceiverPhone,
+		FieldReceiverAddress: doc.ReceiverAddress,
+		FieldProductNames:    doc.ProductNames,
+		FieldRemark:          doc.Remark,
+		FieldLogisticsNo:     doc.LogisticsNo,
+	}
+	highlights := make(map[string]string)
+	for name, text := range fields {
+		if snippet, ok := highlight(text, terms); ok {
+			highlights[name] = snippet
+		}
+	}
+	return highlights
+}
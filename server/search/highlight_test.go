@@ -0,0 +1,48 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSearchTerms(t *testing.T) {
+	got := searchTerms(`+陶瓷 -"mug" a (blue)*`)
+	want := []string{"陶瓷", "mug", "blue"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("searchTerms() = %v, want %v", got, want)
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	snippet, ok := highlight("Blue <Mug> and blue plate", []string{"blue"})
+	if !ok {
+		t.Fatal("expected a match")
+	}
+	want := "<em>Blue</em> &lt;Mug&gt; and <em>blue</em> plate"
+	if snippet != want {
+		t.Errorf("highlight() = %q, want %q", snippet, want)
+	}
+
+	if _, ok := highlight("plate", []string{"mug"}); ok {
+		t.Error("expected no match")
+	}
+}
+
+func TestHighlight_Snippet(t *testing.T) {
+	text := "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa match"
+	snippet, ok := highlight(text, []string{"match"})
+	if !ok {
+		t.Fatal("expected a match")
+	}
+	if []rune(snippet)[0] != '…' {
+		t.Errorf("expected the snippet to be trimmed, got %q", snippet)
+	}
+}
+
+func TestBooleanQuery(t *testing.T) {
+	got := booleanQuery([]string{"陶瓷", "mug"})
+	want := `+"陶瓷" +"mug"`
+	if got != want {
+		t.Errorf("booleanQuery() = %q, want %q", got, want)
+	}
+}
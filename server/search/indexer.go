@@ -0,0 +1,106 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/types"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/validate"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/dao"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/model"
+	"gorm.io/gorm"
+)
+
+// Indexer refreshes the search document of an order from the database. It is
+// fed by order events, so the index follows creation, payment, shipping and
+// cancellation without the order service knowing about search.
+type Indexer struct {
+	index           OrderIndexer
+	orderDao        dao.OrderDao
+	orderProductDao dao.OrderProductDao
+}
+
+func NewIndexer(index OrderIndexer, orderDao dao.OrderDao, orderProductDao dao.OrderProductDao) *Indexer {
+	return &Indexer{
+		index:           index,
+		orderDao:        orderDao,
+		orderProductDao: orderProductDao,
+	}
+}
+
+// IndexOrder reloads the order and upserts its document; an order that no
+// longer exists is removed from the index.
+func (i *Indexer) IndexOrder(ctx context.Context, orderNo string) error {
+	order, err := i.orderDao.GetByOrderNo(ctx, orderNo)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return i.index.DeleteOrder(ctx, orderNo)
+	}
+	if err != nil {
+		return err
+	}
+	products, err := i.orderProductDao.GetByOrderNo(ctx, orderNo)
+	if err != nil {
+		return err
+	}
+	return i.index.IndexOrder(ctx, NewDocument(order, products))
+}
+
+// IndexOrders upserts the documents of a batch of orders, loading their
+// products in one query.
+func (i *Indexer) IndexOrders(ctx context.Context, orders []*model.Order) error {
+	orderNos := make([]string, 0, len(orders))
+	for _, order := range orders {
+		orderNos = append(orderNos, order.OrderNo)
+	}
+	products, err := i.orderProductDao.GetByOrderNos(ctx, orderNos)
+	if err != nil {
+		return err
+	}
+	productsByOrder := make(map[string][]*model.OrderProduct, len(orders))
+	for _, product := range products {
+		productsByOrder[product.OrderNo] = append(productsByOrder[product.OrderNo], product)
+	}
+	for _, order := range orders {
+		if err = i.index.IndexOrder(ctx, NewDocument(order, productsByOrder[order.OrderNo])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func NewDocument(order *model.Order, products []*model.OrderProduct) *Document {
+	productNames := make([]string, 0, len(products))
+	for _, product := range products {
+		productNames = append(productNames, product.ProductName)
+	}
+	return &Document{
+		OrderNo:         order.OrderNo,
+		UserID:          order.UserID,
+		MerchantID:      order.MerchantID,
+		Status:          order.Status,
+		TotalAmount:     order.TotalAmount,
+		ReceiverName:    strings.TrimSpace(order.ReceiverFirstName + " " + order.ReceiverLastName),
+		ReceiverPhone:   order.ReceiverPhone,
+		ReceiverAddress: addressText(order),
+		ReceiverCountry: order.ReceiverCountry,
+		ProductNames:    strings.Join(productNames, " "),
+		Remark:          order.Remark,
+		LogisticsNo:     order.LogisticsNo,
+		CreateTime:      order.CreateTime,
+	}
+}
+
+// addressText 检索用的收货地址：街道、城市、州/省和邮政编码。国家单独作为分面，不计入
+func addressText(order *model.Order) string {
+	addr := types.Address{
+		Line1:      order.ReceiverAddress,
+		City:       order.ReceiverCity,
+		State:      order.ReceiverState,
+		PostalCode: order.ReceiverPostcode,
+	}
+	if addr.PostalCode == "" {
+		addr.PostalCode = validate.PostcodeFromInt(order.ReceiverCountry, order.ReceiverZipCode)
+	}
+	return addr.String()
+}
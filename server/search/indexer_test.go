@@ -0,0 +1,72 @@
+package search_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	daoMocks "github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/dao/mocks"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/search"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/search/mocks"
+	"github.com/golang/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestIndexer_IndexOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderDao := daoMocks.NewMockOrderDao(ctrl)
+	mockOrderProductDao := daoMocks.NewMockOrderProductDao(ctrl)
+	mockIndex := mocks.NewMockOrderIndexer(ctrl)
+
+	ctx := context.Background()
+	createTime := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	mockOrderDao.EXPECT().GetByOrderNo(ctx, "ORD1").Return(&model.Order{
+		OrderNo:           "ORD1",
+		UserID:            7,
+		Status:            2,
+		ReceiverFirstName: "Tom",
+		ReceiverLastName:  "Lee",
+		ReceiverAddress:   "1 Orchard Road, #10-01",
+		ReceiverCity:      "Singapore",
+		ReceiverPostcode:  "238823",
+		ReceiverCountry:   "SG",
+		CreateTime:        createTime,
+	}, nil)
+	mockOrderProductDao.EXPECT().GetByOrderNo(ctx, "ORD1").Return([]*model.OrderProduct{
+		{ProductName: "Blue Mug"},
+		{ProductName: "Plate"},
+	}, nil)
+	mockIndex.EXPECT().IndexOrder(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, doc *search.Document) error {
+		if doc.ReceiverName != "Tom Lee" || doc.ProductNames != "Blue Mug Plate" || doc.Status != 2 ||
+			doc.ReceiverAddress != "1 Orchard Road, #10-01, Singapore, 238823" {
+			t.Errorf("unexpected document: %+v", doc)
+		}
+		return nil
+	})
+
+	indexer := search.NewIndexer(mockIndex, mockOrderDao, mockOrderProductDao)
+	if err := indexer.IndexOrder(ctx, "ORD1"); err != nil {
+		t.Fatalf("IndexOrder() error = %v", err)
+	}
+}
+
+func TestIndexer_IndexOrder_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderDao := daoMocks.NewMockOrderDao(ctrl)
+	mockOrderProductDao := daoMocks.NewMockOrderProductDao(ctrl)
+	mockIndex := mocks.NewMockOrderIndexer(ctrl)
+
+	ctx := context.Background()
+	mockOrderDao.EXPECT().GetByOrderNo(ctx, "ORD1").Return(nil, gorm.ErrRecordNotFound)
+	mockIndex.EXPECT().DeleteOrder(ctx, "ORD1").Return(nil)
+
+	indexer := search.NewIndexer(mockIndex, mockOrderDao, mockOrderProductDao)
+	if err := indexer.IndexOrder(ctx, "ORD1"); err != nil {
+		t.Fatalf("IndexOrder() error = %v", err)
+	}
+}
@@ -0,0 +1,168 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./search.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	search "github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/search"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockOrderIndexer is a mock of OrderIndexer interface.
+type MockOrderIndexer struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrderIndexerMockRecorder
+}
+
+// MockOrderIndexerMockRecorder is the mock recorder for MockOrderIndexer.
+type MockOrderIndexerMockRecorder struct {
+	mock *MockOrderIndexer
+}
+
+// NewMockOrderIndexer creates a new mock instance.
+func NewMockOrderIndexer(ctrl *gomock.Controller) *MockOrderIndexer {
+	mock := &MockOrderIndexer{ctrl: ctrl}
+	mock.recorder = &MockOrderIndexerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrderIndexer) EXPECT() *MockOrderIndexerMockRecorder {
+	return m.recorder
+}
+
+// DeleteOrder mocks base method.
+func (m *MockOrderIndexer) DeleteOrder(ctx context.Context, orderNo string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOrder", ctx, orderNo)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOrder indicates an expected call of DeleteOrder.
+func (mr *MockOrderIndexerMockRecorder) DeleteOrder(ctx, orderNo interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOrder", reflect.TypeOf((*MockOrderIndexer)(nil).DeleteOrder), ctx, orderNo)
+}
+
+// IndexOrder mocks base method.
+func (m *MockOrderIndexer) IndexOrder(ctx context.Context, doc *search.Document) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IndexOrder", ctx, doc)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IndexOrder indicates an expected call of IndexOrder.
+func (mr *MockOrderIndexerMockRecorder) IndexOrder(ctx, doc interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IndexOrder", reflect.TypeOf((*MockOrderIndexer)(nil).IndexOrder), ctx, doc)
+}
+
+// MockOrderSearcher is a mock of OrderSearcher interface.
+type MockOrderSearcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrderSearcherMockRecorder
+}
+
+// MockOrderSearcherMockRecorder is the mock recorder for MockOrderSearcher.
+type MockOrderSearcherMockRecorder struct {
+	mock *MockOrderSearcher
+}
+
+// NewMockOrderSearcher creates a new mock instance.
+func NewMockOrderSearcher(ctrl *gomock.Controller) *MockOrderSearcher {
+	mock := &MockOrderSearcher{ctrl: ctrl}
+	mock.recorder = &MockOrderSearcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrderSearcher) EXPECT() *MockOrderSearcherMockRecorder {
+	return m.recorder
+}
+
+// Search mocks base method.
+func (m *MockOrderSearcher) Search(ctx context.Context, query search.Query) (*search.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, query)
+	ret0, _ := ret[0].(*search.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockOrderSearcherMockRecorder) Search(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockOrderSearcher)(nil).Search), ctx, query)
+}
+
+// MockOrderIndex is a mock of OrderIndex interface.
+type MockOrderIndex struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrderIndexMockRecorder
+}
+
+// MockOrderIndexMockRecorder is the mock recorder for MockOrderIndex.
+type MockOrderIndexMockRecorder struct {
+	mock *MockOrderIndex
+}
+
+// NewMockOrderIndex creates a new mock instance.
+func NewMockOrderIndex(ctrl *gomock.Controller) *MockOrderIndex {
+	mock := &MockOrderIndex{ctrl: ctrl}
+	mock.recorder = &MockOrderIndexMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrderIndex) EXPECT() *MockOrderIndexMockRecorder {
+	return m.recorder
+}
+
+// DeleteOrder mocks base method.
+func (m *MockOrderIndex) DeleteOrder(ctx context.Context, orderNo string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOrder", ctx, orderNo)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOrder indicates an expected call of DeleteOrder.
+func (mr *MockOrderIndexMockRecorder) DeleteOrder(ctx, orderNo interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOrder", reflect.TypeOf((*MockOrderIndex)(nil).DeleteOrder), ctx, orderNo)
+}
+
+// IndexOrder mocks base method.
+func (m *MockOrderIndex) IndexOrder(ctx context.Context, doc *search.Document) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IndexOrder", ctx, doc)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IndexOrder indicates an expected call of IndexOrder.
+func (mr *MockOrderIndexMockRecorder) IndexOrder(ctx, doc interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IndexOrder", reflect.TypeOf((*MockOrderIndex)(nil).IndexOrder), ctx, doc)
+}
+
+// Search mocks base method.
+func (m *MockOrderIndex) Search(ctx context.Context, query search.Query) (*search.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, query)
+	ret0, _ := ret[0].(*search.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockOrderIndexMockRecorder) Search(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockOrderIndex)(nil).Search), ctx, query)
+}
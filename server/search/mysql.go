@@ -0,0 +1,178 @@
+package search
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const fullTextColumns = "order_no, receiver_name, receiver_phone, receiver_address, product_names, remark, logistics_no"
+
+// OrderSearchDocument is the row of the MySQL search index. The FULLTEXT
+// index uses the ngram parser so fragments of names, addresses and order
+// numbers match without word boundaries, and CJK text works too.
+type OrderSearchDocument struct {
+	OrderNo         string    `gorm:"primaryKey;type:varchar(64);index:idx_order_search_ft,class:FULLTEXT,option:WITH PARSER ngram"`
+	UserID          int       `gorm:"not null"`
+	MerchantID      int       `gorm:"not null;default:1;index"`
+	Status          int       `gorm:"not null;index"`
+	TotalAmount     int       `gorm:"not null"`
+	ReceiverName    string    `gorm:"type:varchar(128);index:idx_order_search_ft,class:FULLTEXT"`
+	ReceiverPhone   string    `gorm:"type:varchar(32);index:idx_order_search_ft,class:FULLTEXT"`
+	ReceiverAddress string    `gorm:"type:varchar(512);index:idx_order_search_ft,class:FULLTEXT"`
+	ReceiverCountry string    `gorm:"type:varchar(64);index"`
+	ProductNames    string    `gorm:"type:text;index:idx_order_search_ft,class:FULLTEXT"`
+	Remark          string    `gorm:"type:varchar(256);index:idx_order_search_ft,class:FULLTEXT"`
+	LogisticsNo     string    `gorm:"type:varchar(64);index:idx_order_search_ft,class:FULLTEXT"`
+	CreateTime      time.Time `gorm:"not null;index"`
+	UpdateTime      time.Time `gorm:"autoUpdateTime"`
+}
+
+func (OrderSearchDocument) TableName() string {
+	return "order_search_documents"
+}
+
+// MySQLIndex is the built-in OrderIndex, a FULLTEXT indexed table in the order database.
+type MySQLIndex struct {
+	db *gorm.DB
+}
+
+var _ OrderIndex = (*MySQLIndex)(nil)
+
+func NewMySQLIndex(db *gorm.DB) (*MySQLIndex, error) {
+	if err := db.AutoMigrate(&OrderSearchDocument{}); err != nil {
+		return nil, err
+	}
+	return &MySQLIndex{db: db}, nil
+}
+
+func (m *MySQLIndex) IndexOrder(ctx context.Context, doc *Document) error {
+	row := &OrderSearchDocument{
+		OrderNo:         doc.OrderNo,
+		UserID:          doc.UserID,
+		MerchantID:      doc.MerchantID,
+		Status:          doc.Status,
+		TotalAmount:     doc.TotalAmount,
+		ReceiverName:    doc.ReceiverName,
+		ReceiverPhone:   doc.ReceiverPhone,
+		ReceiverAddress: doc.ReceiverAddress,
+		ReceiverCountry: doc.ReceiverCountry,
+		ProductNames:    doc.ProductNames,
+		Remark:          doc.Remark,
+		LogisticsNo:     doc.LogisticsNo,
+		CreateTime:      doc.CreateTime,
+	}
+	return m.db.WithContext(ctx).Clauses(clause.OnConflict{UpdateAll: true}).Create(row).Error
+}
+
+func (m *MySQLIndex) DeleteOrder(ctx context.Context, orderNo string) error {
+	return m.db.WithContext(ctx).Where("order_no = ?", orderNo).Delete(&OrderSearchDocument{}).Error
+}
+
+func (m *MySQLIndex) Search(ctx context.Context, query Query) (*Result, error) {
+	terms := searchTerms(query.Text)
+	if len(terms) == 0 {
+		return nil, ErrInvalidQuery.Detailf("no search term of at least %d characters", minSearchTermLen)
+	}
+	if query.Month != "" {
+		if _, err := time.Parse(MonthLayout, query.Month); err != nil {
+			return nil, ErrInvalidQuery.Detailf("month must be formatted as %s", MonthLayout)
+		}
+	}
+	against := booleanQuery(terms)
+
+	var rows []*struct {
+		OrderSearchDocument
+		Score float64
+	}
+	db := m.filtered(ctx, against, query, "").
+		Select("*, MATCH("+fullTextColumns+") AGAINST(? IN BOOLEAN MODE) AS score", against).
+		Order("score DESC").Order("create_time DESC")
+	if query.Limit > 0 {
+		db = db.Limit(query.Limit)
+	}
+	if query.Offset > 0 {
+		db = db.Offset(query.Offset)
+	}
+	if err := db.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		Hits:   make([]*Hit, 0, len(rows)),
+		Facets: make(map[string][]FacetBucket, 3),
+	}
+	if err := m.filtered(ctx, against, query, "").Count(&result.Total).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		doc := &Document{
+			OrderNo:         row.OrderNo,
+			UserID:          row.UserID,
+			MerchantID:      row.MerchantID,
+			Status:          row.Status,
+			TotalAmount:     row.TotalAmount,
+			ReceiverName:    row.ReceiverName,
+			ReceiverPhone:   row.ReceiverPhone,
+			ReceiverAddress: row.ReceiverAddress,
+			ReceiverCountry: row.ReceiverCountry,
+			ProductNames:    row.ProductNames,
+			Remark:          row.Remark,
+			LogisticsNo:     row.LogisticsNo,
+			CreateTime:      row.CreateTime,
+		}
+		result.Hits = append(result.Hits, &Hit{Document: doc, Score: row.Score, Highlights: highlights(doc, terms)})
+	}
+
+	facetExprs := map[string]string{
+		FacetStatus:  "status",
+		FacetCountry: "receiver_country",
+		FacetMonth:   "DATE_FORMAT(create_time, '%Y-%m')",
+	}
+	for facet, expr := range facetExprs {
+		var buckets []FacetBucket
+		err := m.filtered(ctx, against, query, facet).
+			Select(expr + " AS value, COUNT(*) AS count").
+			Group("value").Order("count DESC").
+			Scan(&buckets).Error
+		if err != nil {
+			return nil, err
+		}
+		result.Facets[facet] = buckets
+	}
+	return result, nil
+}
+
+// filtered applies the full-text match and the facet filters, except the
+// filter of skipFacet so a facet counts every value the user can switch to.
+func (m *MySQLIndex) filtered(ctx context.Context, against string, query Query, skipFacet string) *gorm.DB {
+	db := m.db.WithContext(ctx).Model(&OrderSearchDocument{}).
+		Where("MATCH("+fullTextColumns+") AGAINST(? IN BOOLEAN MODE)", against)
+	if query.MerchantID != 0 {
+		db = db.Where("merchant_id = ?", query.MerchantID)
+	}
+	if query.Status != 0 && skipFacet != FacetStatus {
+		db = db.Where("status = ?", query.Status)
+	}
+	if query.Country != "" && skipFacet != FacetCountry {
+		db = db.Where("receiver_country = ?", query.Country)
+	}
+	if query.Month != "" && skipFacet != FacetMonth {
+		month, _ := time.ParseInLocation(MonthLayout, query.Month, time.Local)
+		db = db.Where("create_time >= ? AND create_time < ?", month, month.AddDate(0, 1, 0))
+	}
+	return db
+}
+
+// booleanQuery requires every term, each as a phrase so the ngram parser
+// matches it as a contiguous fragment.
+func booleanQuery(terms []string) string {
+	quoted := make([]string, len(terms))
+	for i, term := range terms {
+		quoted[i] = `+"` + term + `"`
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,97 @@
+package search
+
+import (
+	"context"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/errs"
+)
+
+// mockgen -source=./search.go -destination=./mocks/search_mock.go -package=mocks
+
+// 分面字段
+const (
+	FacetStatus  = "status"
+	FacetCountry = "country"
+	FacetMonth   = "month" // 下单月份，格式 2006-01
+)
+
+// 可高亮字段
+const (
+	FieldOrderNo         = "order_no"
+	FieldReceiverName    = "receiver_name"
+	FieldReceiverPhone   = "receiver_phone"
+	FieldReceiverAddress = "receiver_address"
+	FieldProductNames    = "product_names"
+	FieldRemark          = "remark"
+	FieldLogisticsNo     = "logistics_no"
+)
+
+const MonthLayout = "2006-01"
+
+// 搜索引擎
+const EngineMySQL = "mysql"
+
+var ErrInvalidQuery = errs.New(errs.CodeInvalidSearchQuery, "invalid search query")
+
+// Document is the searchable view of one order.
+type Document struct {
+	OrderNo         string
+	UserID          int
+	MerchantID      int
+	Status          int
+	TotalAmount     int
+	ReceiverName    string
+	ReceiverPhone   string
+	ReceiverAddress string // 街道、城市、州/省和邮政编码
+	ReceiverCountry string
+	ProductNames    string // 商品名以空格拼接
+	Remark          string
+	LogisticsNo     string
+	CreateTime      time.Time
+}
+
+type Query struct {
+	Text       string // 全文检索关键词，多个词之间为 AND
+	MerchantID int    // 商家范围，0 表示不限
+	Status     int    // 订单状态筛选，0 表示不筛选
+	Country    string // 收货国家筛选
+	Month      string // 下单月份筛选，格式 2006-01
+	Limit      int
+	Offset     int
+}
+
+type Hit struct {
+	Document   *Document
+	Score      float64
+	Highlights map[string]string // 字段 -> 命中片段，命中词以 <em></em> 包裹
+}
+
+type FacetBucket struct {
+	Value string
+	Count int64
+}
+
+type Result struct {
+	Hits   []*Hit
+	Total  int64
+	Facets map[string][]FacetBucket // 每个分面的计数不受该分面自身的筛选条件影响
+}
+
+// OrderIndexer keeps the search index in sync with the orders.
+type OrderIndexer interface {
+	IndexOrder(ctx context.Context, doc *Document) error
+	DeleteOrder(ctx context.Context, orderNo string) error
+}
+
+// OrderSearcher runs full-text queries against the index.
+type OrderSearcher interface {
+	Search(ctx context.Context, query Query) (*Result, error)
+}
+
+// OrderIndex is a search engine backing order search. MySQLIndex is built in;
+// an external engine can be plugged in by implementing this interface.
+type OrderIndex interface {
+	OrderIndexer
+	OrderSearcher
+}
@@ -4,59 +4,139 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"sync"
+	"net/url"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/common/productpb"
-	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/clients"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/metrics"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/consts"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/errs"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/types"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/utils"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/cache"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/dao"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/model"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-payment-mservice/common/paymentpb"
-	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type OrderService interface {
 	CreateOrder(ctx context.Context, orderInfo types.OrderInfo, userID int) (orderNo string, err error)
 	ListOrders(ctx context.Context, req types.ListOrderRequest) (resp *types.ListOrderResponse, err error)
 	GetOrderDetail(ctx context.Context, orderNo string) (detail *types.OrderDetail, err error)
+	MerchantGetOrderDetail(ctx context.Context, orderNo string, merchantID int) (detail *types.OrderDetail, err error)
 	CustomerGetOrderDetail(ctx context.Context, orderNo string, userID int) (detail *types.OrderDetail, err error)
-	UpdateOrderStatus(ctx context.Context, orderNo string, newStatus int) (err error)
+	CustomerGetCheckout(ctx context.Context, checkoutNo string, userID int) (detail *types.CheckoutDetail, err error)
+	UpdateOrderStatus(ctx context.Context, orderNo string, newStatus int, shippingNo string) (err error)
+	CustomerConfirmOrder(ctx context.Context, orderNo string, userID int) (err error)
+	MerchantShipOrder(ctx context.Context, orderNo string, merchantID int, carrierCode, shippingNo string) (err error)
+	BatchShipOrders(ctx context.Context, req types.BatchShipRequest) (resp *types.BatchShipResponse, err error)
+	CreateShipment(ctx context.Context, orderNo string, req types.CreateShipmentRequest) (detail *types.ShipmentDetail, err error)
+	MerchantEditOrder(ctx context.Context, orderNo string, merchantID, operatorID int, req types.EditOrderRequest) (resp *types.EditOrderResponse, err error)
+	MerchantGetOrderAudits(ctx context.Context, orderNo string, merchantID int) (entries []*types.OrderAuditEntry, err error)
+	RetryPendingRefunds(ctx context.Context)
+	RecordTrackingEvents(ctx context.Context, carrierCode string, req types.TrackingWebhookRequest) (err error)
 	OrderAutoConfirm(ctx context.Context)
-	GetOrderStats(ctx context.Context) (stats types.OrderStats, err error)
+	GetOrderStats(ctx context.Context, merchantID int) (stats types.OrderStats, err error)
+	CustomerListOrders(ctx context.Context, userID int, req types.CustomerListOrderRequest) (resp *types.ListOrderResponse, err error)
+	CustomerReorder(ctx context.Context, orderNo string, userID int) (quote *types.ReorderQuote, err error)
 }
 
 type OrderServiceImpl struct {
-	lock                 sync.Mutex
 	orderDao             dao.OrderDao
 	orderStatsCache      cache.IOrderStatsCache
 	orderProductDao      dao.OrderProductDao
 	orderLogDao          dao.OrderLogDao
+	shipmentDao          dao.ShipmentDao
+	trackingEventDao     dao.TrackingEventDao
+	orderEditDao         dao.OrderEditDao
 	productServiceClient productpb.ProductServiceClient
 	paymentServiceClient paymentpb.PaymentServiceClient
+	addressBook          AddressBook
+	productMerchants     ProductMerchants
 	messageWriter        utils.Writer
 	distributedLocker    utils.Locker
-	syncMode             bool
+	refundRetryLocker    utils.Locker
+	orderMetrics         *metrics.OrderMetrics
+	carriers             utils.Carriers
+	imageHosts           []string // 商品目录图片 CDN 域名
 }
 
-func GetOrderServiceInstance() *OrderServiceImpl {
+func NewOrderService(
+	orderDao dao.OrderDao,
+	orderProductDao dao.OrderProductDao,
+	orderLogDao dao.OrderLogDao,
+	shipmentDao dao.ShipmentDao,
+	trackingEventDao dao.TrackingEventDao,
+	orderEditDao dao.OrderEditDao,
+	orderStatsCache cache.IOrderStatsCache,
+	productServiceClient productpb.ProductServiceClient,
+	paymentServiceClient paymentpb.PaymentServiceClient,
+	addressBook AddressBook,
+	productMerchants ProductMerchants,
+	messageWriter utils.Writer,
+	distributedLocker utils.Locker,
+	refundRetryLocker utils.Locker,
+	orderMetrics *metrics.OrderMetrics,
+	carriers utils.Carriers,
+	imageHosts []string,
+) *OrderServiceImpl {
 	return &OrderServiceImpl{
-		orderDao:             dao.GetOrderDao(),
-		orderStatsCache:      cache.GetOrderStatsCache(),
-		orderProductDao:      dao.GetOrderProductDao(),
-		orderLogDao:          dao.GetOrderLogDao(),
-		productServiceClient: clients.GetProductClient(),
-		paymentServiceClient: clients.GetPaymentClient(),
-		messageWriter:        utils.GetWriter(),
-		distributedLocker:    utils.GetDistributedLock(AUTO_CONFIRM_LOCK_KEY, uuid.New().String(), LOCK_EXP_TIME),
-		syncMode:             false,
+		orderDao:             orderDao,
+		orderStatsCache:      orderStatsCache,
+		orderProductDao:      orderProductDao,
+		orderLogDao:          orderLogDao,
+		shipmentDao:          shipmentDao,
+		trackingEventDao:     trackingEventDao,
+		orderEditDao:         orderEditDao,
+		productServiceClient: productServiceClient,
+		paymentServiceClient: paymentServiceClient,
+		addressBook:          addressBook,
+		productMerchants:     productMerchants,
+		messageWriter:        messageWriter,
+		distributedLocker:    distributedLocker,
+		refundRetryLocker:    refundRetryLocker,
+		orderMetrics:         orderMetrics,
+		carriers:             carriers,
+		imageHosts:           imageHosts,
 	}
 }
 
+var (
+	ErrInvalidOrderSort        = errs.New(errs.CodeInvalidSort, "invalid sort_by or sort_order")
+	ErrOrderNotFound           = errs.New(errs.CodeOrderNotFound, "order not found")
+	ErrOrderForbidden          = errs.New(errs.CodeOrderForbidden, "invalid user ID")
+	ErrInvalidStatusTransition = errs.New(errs.CodeInvalidStatusTransition, "invalid order status transition")
+	ErrInsufficientStock       = errs.New(errs.CodeInsufficientStock, "do not have enough stock")
+	ErrProductService          = errs.New(errs.CodeProductService, "product service call failed")
+	ErrPaymentService          = errs.New(errs.CodePaymentService, "payment service call failed")
+	ErrPaymentDeclined         = errs.New(errs.CodePaymentDeclined, "payment declined")
+	ErrInvalidOrderItem        = errs.New(errs.CodeInvalidOrderItem, "invalid order item")
+)
+
+// orderDaoErr 将查不到订单的 dao 错误转为 ErrOrderNotFound
+func orderDaoErr(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrOrderNotFound.Wrap(err)
+	}
+	return err
+}
+
+// orderTransitions 订单状态机：当前状态 -> 允许流转到的状态
+var orderTransitions = map[int][]int{
+	consts.CREATED:           {consts.PAYED, consts.CANCELED},
+	consts.PAYED:             {consts.PARTIALLY_SHIPPED, consts.SHIPPED},
+	consts.PARTIALLY_SHIPPED: {consts.PARTIALLY_SHIPPED, consts.SHIPPED},
+	consts.SHIPPED:           {consts.DELIVERED},
+}
+
+func canTransition(from, to int) bool {
+	return slices.Contains(orderTransitions[from], to)
+}
+
 const (
 	AUTO_CONFIRM_LOCK_KEY   = "order:auto_confirm:lock"
 	LOCK_EXP_TIME           = 10 * time.Second
@@ -64,63 +144,80 @@ const (
 )
 
 func (o *OrderServiceImpl) OrderAutoConfirm(ctx context.Context) {
-	log.Logger.Infof("Auto Confirm Order at: %v", time.Now())
+	start := time.Now()
+	logger := log.FromContext(ctx)
+	logger.Infof("Auto Confirm Order at: %v", start)
 	// 1. lock
 	lock := o.distributedLocker
 
 	err := lock.Lock(ctx)
 	if err != nil {
 		// 获取锁失败（其他实例正在处理），直接返回，等下一轮
-		log.Logger.Info("OrderAutoConfirm: failed to acquire lock, skipping this round")
+		logger.Info("OrderAutoConfirm: failed to acquire lock, skipping this round")
+		o.orderMetrics.AutoConfirmLockContention()
 		return
 	}
 
 	defer func() {
 		if unlockErr := lock.Unlock(ctx); unlockErr != nil {
-			log.Logger.Errorf("OrderAutoConfirm: failed to release lock, err: %s", unlockErr.Error())
+			logger.Errorf("OrderAutoConfirm: failed to release lock, err: %s", unlockErr.Error())
 		}
 	}()
 
 	// 2. update by status and shipped time
 	list, err := o.orderDao.AutoConfirmShippedOrders(ctx, consts.SHIPPED, consts.DELIVERED, AUTO_CONFIRM_AFTER_DAYS)
 	if err != nil {
-		log.Logger.Info("OrderAutoConfirm: failed to update order status, err: %s", err.Error())
+		logger.Errorf("OrderAutoConfirm: failed to update order status, err: %s", err.Error())
 		return
 	}
+	o.orderMetrics.AutoConfirmDone(len(list), start)
 
 	// 3. send message to mq (insert order logs)
 	for _, order := range list {
+		orderLogger := logger.With("order_no", order.OrderNo, "user_id", order.UserID)
+		o.orderMetrics.StatusTransition(getOrderStatusName(consts.SHIPPED), getOrderStatusName(consts.DELIVERED))
 		statusChangeRemark := "Shipped --> AutoConfirmed"
 		oscMsg, err := getOrderStatusChangedMsg(order.OrderNo, order.UserID, statusChangeRemark, consts.DELIVERED)
 		if err != nil {
-			log.Logger.Errorf("get order status changed msg failed, err %s", err.Error())
+			orderLogger.Errorf("get order status changed msg failed, err %s", err.Error())
 			continue
 		}
-		err = o.messageWriter.SendMsg(ctx, "order_status_changed", order.OrderNo, oscMsg)
+		err = o.messageWriter.SendMsg(ctx, consts.TopicOrderStatusChanged, order.OrderNo, oscMsg)
 		if err != nil {
-			log.Logger.Errorf("send message failed, err %s", err)
+			orderLogger.Errorf("send message failed, err %s", err)
 		}
 	}
 }
 
 func (o *OrderServiceImpl) CreateOrder(ctx context.Context, orderInfo types.OrderInfo, userID int) (orderNo string, err error) {
-	o.lock.Lock()
-	defer o.lock.Unlock()
+	logger := log.FromContext(ctx).With("user_id", userID)
+	outcome := metrics.OrderOutcomeError
+	defer func() { o.orderMetrics.OrderCreated(outcome) }()
+
+	// 0. 确定收货地址，作为快照保存在订单上，之后不随地址簿修改变化；旧字段按快照回填
+	address, err := o.resolveShippingAddress(ctx, orderInfo, userID)
+	if err != nil {
+		return "", err
+	}
+	orderInfo.ShippingAddress = &address
+	orderInfo.ReceiverAddress = address.Street()
+	orderInfo.ReceiverCountry = address.Country
+	orderInfo.ReceiverZipCode = legacyZipCode(address.PostalCode)
 
 	orderItemIds := make([]int64, len(orderInfo.OrderItemList))
 	for idx, item := range orderInfo.OrderItemList {
 		orderItemIds[idx] = int64(item.ProductID)
 	}
 
-	// log.Logger.Infof("CreateOrder: orderItemIds = %v", orderItemIds)
+	// logger.Infof("CreateOrder: orderItemIds = %v", orderItemIds)
 
 	// 1. rpc: call product service and check if all the related product's stock is enough
 	productList, err := o.productServiceClient.GetProductList(ctx, &productpb.GetProductListRequest{
 		Ids: orderItemIds,
 	})
 	if err != nil {
-		log.Logger.Errorf("CreateOrder: get product list failed, err: %s", err.Error())
-		return "", err
+		logger.Errorf("CreateOrder: get product list failed, err: %s", err.Error())
+		return "", ErrProductService.Wrap(err)
 	}
 
 	productId2StockMap := make(map[int]int)
@@ -128,56 +225,224 @@ func (o *OrderServiceImpl) CreateOrder(ctx context.Context, orderInfo types.Orde
 		productId2StockMap[int(product.Id)] = int(product.Stock)
 	}
 
-	itemTotalAmount := 0
 	for _, orderItem := range orderInfo.OrderItemList {
 		if orderItem.Quantity > productId2StockMap[orderItem.ProductID] {
-			err = fmt.Errorf("CreateOrder failed, do not have enough stock, product id: %d", orderItem.ProductID)
-			log.Logger.Errorf(err.Error())
+			err = ErrInsufficientStock.Detailf("product id: %d", orderItem.ProductID)
+			logger.Error(err.Error())
+			outcome = metrics.OrderOutcomeStockInsufficient
+			o.orderMetrics.StockInsufficient()
 			return "", err
 		}
-		itemTotalAmount += (orderItem.Price * orderItem.Quantity)
 	}
 
-	shippingFee := CalculateShippingFee(itemTotalAmount)
-	tax := CalculateTax(itemTotalAmount)
+	// 1.1 按商品目录确定商家，不采信请求中的商家；商品图片只接受商品目录 CDN 上的地址
+	productId2MerchantMap, err := o.productMerchants.GetProductMerchants(ctx, orderItemIds)
+	if err != nil {
+		logger.Errorf("CreateOrder: get product merchants failed, err: %s", err.Error())
+		return "", ErrProductService.Wrap(err)
+	}
+	for _, orderItem := range orderInfo.OrderItemList {
+		if orderItem.ImageURL != "" && !o.isCatalogImage(orderItem.ImageURL) {
+			err = ErrInvalidOrderItem.Detailf("image_url of product %d is not a catalog image", orderItem.ProductID)
+			logger.Error(err.Error())
+			return "", err
+		}
+		merchantID := productId2MerchantMap[int64(orderItem.ProductID)]
+		if merchantID <= 0 {
+			err = ErrProductService.Detailf("no merchant for product %d", orderItem.ProductID)
+			logger.Error(err.Error())
+			return "", err
+		}
+		if orderItem.MerchantID != 0 && orderItem.MerchantID != merchantID {
+			err = ErrInvalidOrderItem.Detailf("product %d is not sold by merchant %d", orderItem.ProductID, orderItem.MerchantID)
+			logger.Error(err.Error())
+			return "", err
+		}
+		orderItem.MerchantID = merchantID
+	}
 
-	// 2. local func: gen order ID
-	orderId := utils.GenerateOrderID()
+	// 2. 按商家拆分子订单，同一结算单下的子订单一起支付
+	subOrders := splitByMerchant(orderInfo.OrderItemList)
+	checkoutNo := subOrders[0].orderNo // 单商家时结算单号即订单号
+	if len(subOrders) > 1 {
+		checkoutNo = utils.GenerateCheckoutNo()
+	}
+	logger = logger.With("checkout_no", checkoutNo)
 
 	// 3. save order Info to database
-	// 3.1 save order Info
 	currentTime := time.Now()
-	_, err = o.orderDao.Create(ctx, &model.Order{
-		OrderNo:           orderId,
+	payAmount := 0
+	for _, sub := range subOrders {
+		if err = o.saveSubOrder(ctx, sub, checkoutNo, orderInfo, userID, currentTime); err != nil {
+			return "", err
+		}
+		payAmount += sub.totalAmount()
+	}
+
+	// 4. rpc: call product service and decrease stock
+	for _, orderItem := range orderInfo.OrderItemList {
+		_, _ = o.productServiceClient.UpdateStockWithCAS(ctx, &productpb.UpdateStockWithCASRequest{
+			Id:   int64(orderItem.ProductID),
+			Deta: int64(-1 * orderItem.Quantity),
+		})
+	}
+
+	// 5. rpc: call payment service and pay
+	// TODO
+	payResp, err := o.paymentServiceClient.PayOrder(ctx, &paymentpb.PayOrderRequest{
+		UserId: int32(userID),
+		Amount: int32(payAmount),
+		BizId:  checkoutNo,
+	})
+
+	// 5.2 payment failed
+	if err != nil || payResp.Code != 0 {
+		for _, sub := range subOrders {
+			_ = o.messageWriter.SendMsg(ctx, consts.TopicOrderCanceled, sub.orderNo, sub.msg)
+		}
+		outcome = metrics.OrderOutcomePaymentFailed
+		if err != nil {
+			logger.Errorf("CreateOrder: payment failed, err: %s", err.Error())
+			o.orderMetrics.PaymentFailed(metrics.PaymentFailureRPCError)
+			return "", ErrPaymentService.Wrap(err)
+		} else {
+			errMsg := payResp.ErrorMsg
+			rpcErr := ErrPaymentDeclined.Detailf("%s", *errMsg)
+			logger.Errorf("CreateOrder: payment failed, err: %s", rpcErr.Error())
+			o.orderMetrics.PaymentFailed(metrics.PaymentFailureDeclined)
+			return "", rpcErr
+		}
+	}
+
+	// 5.1 payment success: update order status
+	for _, sub := range subOrders {
+		err = o.orderDao.UpdateStatusAndPayment(ctx, sub.orderNo, consts.PAYED, time.Now())
+		if err != nil {
+			logger.Errorf("CreateOrder: update status of %s failed, err %s", sub.orderNo, err.Error())
+			return "", err
+		}
+		o.orderMetrics.StatusTransition(getOrderStatusName(consts.CREATED), getOrderStatusName(consts.PAYED))
+
+		oscMsg, err := getOrderStatusChangedMsg(sub.orderNo, userID, "Created --> Paid", 2)
+		if err != nil {
+			logger.Errorf("get order status changed msg failed, err %s", err.Error())
+		}
+		err = o.messageWriter.SendMsg(ctx, consts.TopicOrderStatusChanged, sub.orderNo, oscMsg)
+		if err != nil {
+			logger.Errorf("send message failed, err %s", err)
+		}
+	}
+	outcome = metrics.OrderOutcomeSuccess
+
+	return checkoutNo, nil
+}
+
+// subOrder 一次下单中同一商家的商品，单独计算运费和税费
+type subOrder struct {
+	orderNo     string
+	merchantID  int
+	items       []*types.OrderItemInfo
+	itemTotal   int
+	shippingFee int
+	tax         int
+	msg         string // order_created 消息，支付失败时作为 order_canceled 消息重发
+}
+
+func (s *subOrder) totalAmount() int {
+	return s.itemTotal + s.shippingFee + s.tax
+}
+
+func (s *subOrder) add(item *types.OrderItemInfo) {
+	s.items = append(s.items, item)
+	s.itemTotal += item.Price * item.Quantity
+}
+
+// price 按商品金额计算运费和税
+func (s *subOrder) price() {
+	s.shippingFee = CalculateShippingFee(s.itemTotal)
+	s.tax = CalculateTax(s.itemTotal)
+}
+
+// isCatalogImage 图片地址是否为商品目录 CDN 上的 https 地址
+func (o *OrderServiceImpl) isCatalogImage(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "https" || u.User != nil {
+		return false
+	}
+	return slices.Contains(o.imageHosts, strings.ToLower(u.Hostname()))
+}
+
+// catalogImage 返回商品目录 CDN 上的图片地址，其他地址（如早期订单中客户端提交的地址）不展示
+func (o *OrderServiceImpl) catalogImage(rawURL string) string {
+	if !o.isCatalogImage(rawURL) {
+		return ""
+	}
+	return rawURL
+}
+
+// splitByMerchant 按商家拆分商品，子订单按商家在购物车中首次出现的顺序排列。
+// items 的商家已由商品目录确定
+func splitByMerchant(items []*types.OrderItemInfo) []*subOrder {
+	var subOrders []*subOrder
+	byMerchant := make(map[int]*subOrder)
+	for _, item := range items {
+		merchantID := item.MerchantID
+		sub, ok := byMerchant[merchantID]
+		if !ok {
+			sub = &subOrder{orderNo: utils.GenerateOrderID(), merchantID: merchantID}
+			byMerchant[merchantID] = sub
+			subOrders = append(subOrders, sub)
+		}
+		sub.add(item)
+	}
+	for _, sub := range subOrders {
+		sub.price()
+	}
+	return subOrders
+}
+
+// saveSubOrder 保存子订单及其商品，并发送订单创建消息
+func (o *OrderServiceImpl) saveSubOrder(ctx context.Context, sub *subOrder, checkoutNo string, orderInfo types.OrderInfo, userID int, currentTime time.Time) error {
+	logger := log.FromContext(ctx).With("user_id", userID, "order_no", sub.orderNo, "merchant_id", sub.merchantID)
+
+	_, err := o.orderDao.Create(ctx, &model.Order{
+		OrderNo:           sub.orderNo,
+		CheckoutNo:        checkoutNo,
+		MerchantID:        sub.merchantID,
 		UserID:            userID,
 		Status:            consts.CREATED,
-		TotalAmount:       itemTotalAmount + shippingFee + tax,
+		TotalAmount:       sub.totalAmount(),
 		CreateTime:        currentTime,
 		UpdateTime:        currentTime,
 		ReceiverFirstName: orderInfo.ReceiverFirstName,
 		ReceiverLastName:  orderInfo.ReceiverLastName,
 		ReceiverPhone:     orderInfo.ReceiverPhone,
 		ReceiverAddress:   orderInfo.ReceiverAddress,
+		ReceiverLine1:     orderInfo.ShippingAddress.Line1,
+		ReceiverLine2:     orderInfo.ShippingAddress.Line2,
+		ReceiverCity:      orderInfo.ShippingAddress.City,
+		ReceiverState:     orderInfo.ShippingAddress.State,
+		ReceiverPostcode:  orderInfo.ShippingAddress.PostalCode,
 		ReceiverCountry:   orderInfo.ReceiverCountry,
 		ReceiverZipCode:   orderInfo.ReceiverZipCode,
+		AddressID:         orderInfo.AddressID,
 		Remark:            orderInfo.Remark,
-		ShippingFee:       shippingFee,
-		Tax:               tax,
+		ShippingFee:       sub.shippingFee,
+		Tax:               sub.tax,
 	})
 	if err != nil {
-		log.Logger.Errorf("CreateOrder: insert into db failed, err: %s", err.Error())
-		return "", err
+		logger.Errorf("CreateOrder: insert into db failed, err: %s", err.Error())
+		return err
 	}
 
-	// 3.2 save order items
-	orderProductModelList := make([]model.OrderProduct, len(orderInfo.OrderItemList))
-
-	// build model.orderProduct list
-	for idx, orderItem := range orderInfo.OrderItemList {
+	orderProductModelList := make([]model.OrderProduct, len(sub.items))
+	for idx, orderItem := range sub.items {
 		orderProductModelList[idx] = model.OrderProduct{
-			OrderNo:     orderId,
+			OrderNo:     sub.orderNo,
+			MerchantID:  sub.merchantID,
 			ProductID:   orderItem.ProductID,
 			ProductName: orderItem.ProductName,
+			ImageURL:    orderItem.ImageURL,
 			Price:       orderItem.Price,
 			Quantity:    orderItem.Quantity,
 			TotalPrice:  (orderItem.Price * orderItem.Quantity),
@@ -185,97 +450,55 @@ func (o *OrderServiceImpl) CreateOrder(ctx context.Context, orderInfo types.Orde
 			UpdateTime:  currentTime,
 		}
 	}
-
-	// save batch
 	_, err = o.orderProductDao.CreateBatch(ctx, orderProductModelList)
 	if err != nil {
-		log.Logger.Errorf("orderProductDao.CreateBatch: add order items failed, err %s", err.Error())
-		return "", err
-	}
-
-	orderMsg, err := getOrderMsg(orderId, orderInfo, userID)
-	if err != nil {
-		log.Logger.Errorf("getOrderMsg: json encode failed, err %s", err.Error())
-		return "", err
-	}
-	// 4. message queue: send msg -- order ID
-	err = o.messageWriter.SendMsg(ctx, "order_created", orderId, orderMsg)
-	if err != nil {
-		log.Logger.Errorf("CreateOrder: send message failed, err %s", err.Error())
-		return "", err
+		logger.Errorf("orderProductDao.CreateBatch: add order items failed, err %s", err.Error())
+		return err
 	}
 
-	oscMsg, err := getOrderStatusChangedMsg(orderId, userID, "Created", 1)
+	orderInfo.OrderItemList = sub.items
+	sub.msg, err = getOrderMsg(sub.orderNo, checkoutNo, sub.merchantID, orderInfo, userID)
 	if err != nil {
-		log.Logger.Errorf("get order status changed msg failed, err %s", err.Error())
-	}
-	err = o.messageWriter.SendMsg(ctx, "order_status_changed", orderId, oscMsg)
-	if err != nil {
-		log.Logger.Errorf("send message failed, err %s", err)
-	}
-
-	// 5. rpc: call product service and decrease stock
-	for _, orderItem := range orderInfo.OrderItemList {
-		_, _ = o.productServiceClient.UpdateStockWithCAS(ctx, &productpb.UpdateStockWithCASRequest{
-			Id:   int64(orderItem.ProductID),
-			Deta: int64(-1 * orderItem.Quantity),
-		})
-	}
-
-	// 6. rpc: call payment service and pay
-	// TODO
-	payResp, err := o.paymentServiceClient.PayOrder(ctx, &paymentpb.PayOrderRequest{
-		UserId: int32(userID),
-		Amount: int32(itemTotalAmount + shippingFee + tax),
-		BizId:  orderId,
-	})
-
-	// 6.2 payment failed
-	if err != nil || payResp.Code != 0 {
-		_ = o.messageWriter.SendMsg(ctx, "order_canceled", orderId, orderMsg)
-		if err != nil {
-			log.Logger.Errorf("CreateOrder: payment failed, err: %s", err.Error())
-			return "", err
-		} else {
-			errMsg := payResp.ErrorMsg
-			rpcErr := errors.New(*errMsg)
-			log.Logger.Errorf("CreateOrder: payment failed, err: %s", rpcErr.Error())
-			return "", rpcErr
-		}
+		logger.Errorf("getOrderMsg: json encode failed, err %s", err.Error())
+		return err
 	}
-
-	// 6.1 payment success: update order status
-	err = o.orderDao.UpdateStatusAndPayment(ctx, orderId, consts.PAYED, time.Now())
+	// message queue: send msg -- order ID
+	err = o.messageWriter.SendMsg(ctx, consts.TopicOrderCreated, sub.orderNo, sub.msg)
 	if err != nil {
-		log.Logger.Errorf("CreateOrder: update status failed, err %s", err.Error())
-		return "", err
+		logger.Errorf("CreateOrder: send message failed, err %s", err.Error())
+		return err
 	}
 
-	oscMsg, err = getOrderStatusChangedMsg(orderId, userID, "Created --> Paid", 2)
+	oscMsg, err := getOrderStatusChangedMsg(sub.orderNo, userID, "Created", 1)
 	if err != nil {
-		log.Logger.Errorf("get order status changed msg failed, err %s", err.Error())
+		logger.Errorf("get order status changed msg failed, err %s", err.Error())
 	}
-	err = o.messageWriter.SendMsg(ctx, "order_status_changed", orderId, oscMsg)
+	err = o.messageWriter.SendMsg(ctx, consts.TopicOrderStatusChanged, sub.orderNo, oscMsg)
 	if err != nil {
-		log.Logger.Errorf("send message failed, err %s", err)
+		logger.Errorf("send message failed, err %s", err)
 	}
-
-	return orderId, nil
+	return nil
 }
 
-func getOrderMsg(orderId string, orderInfo types.OrderInfo, userId int) (msg string, err error) {
+func getOrderMsg(orderId, checkoutNo string, merchantID int, orderInfo types.OrderInfo, userId int) (msg string, err error) {
 	orderMessage := types.OrderMessage{
 		UserID:            userId,
 		OrderID:           orderId,
+		MerchantID:        merchantID,
+		CheckoutNo:        checkoutNo,
 		ReceiverFirstName: orderInfo.ReceiverFirstName,
 		ReceiverLastName:  orderInfo.ReceiverLastName,
 		ReceiverPhone:     orderInfo.ReceiverPhone,
+		AddressID:         orderInfo.AddressID,
 		ReceiverAddress:   orderInfo.ReceiverAddress,
 		ReceiverCountry:   orderInfo.ReceiverCountry,
 		ReceiverZipCode:   orderInfo.ReceiverZipCode,
 		Remark:            orderInfo.Remark,
 		OrderItemList:     orderInfo.OrderItemList,
 	}
+	if orderInfo.ShippingAddress != nil {
+		orderMessage.ShippingAddress = *orderInfo.ShippingAddress
+	}
 	orderMsgJson, err := utils.JSONEncode(orderMessage)
 	return orderMsgJson, err
 }
@@ -305,29 +528,34 @@ func CalculateTax(total int) int {
 }
 
 func (o *OrderServiceImpl) ListOrders(ctx context.Context, req types.ListOrderRequest) (resp *types.ListOrderResponse, err error) {
-	// 构建查询条件
-	query := dao.OrderQuery{
-		UserID:      req.UserID,
-		OrderStatus: req.OrderStatus,
-		StartTime:   req.StartTime,
-		EndTime:     req.EndTime,
-		OrderNo:     req.OrderNo,
-		Limit:       req.Limit,
-		Offset:      req.Offset,
+	logger := log.FromContext(ctx)
+	if req.UserID != 0 {
+		logger = logger.With("user_id", req.UserID)
+	}
+
+	query, err := buildOrderQuery(req)
+	if err != nil {
+		return nil, err
 	}
 
 	// 调用 DAO 层查询订单列表
-	orders, err := o.orderDao.GetByOrderQuery(ctx, query)
+	orders, total, err := o.orderDao.GetByOrderQuery(ctx, query)
 	if err != nil {
-		log.Logger.Errorf("ListOrders: query orders failed, err: %s", err.Error())
+		logger.Errorf("ListOrders: query orders failed, err: %s", err.Error())
 		return nil, err
 	}
+	hasMore := req.Limit > 0 && len(orders) > req.Limit
+	if hasMore {
+		orders = orders[:req.Limit]
+	}
 
 	// 转换为响应格式
 	orderList := make([]*types.OrderInfoInList, len(orders))
 	for idx, order := range orders {
 		orderInfo := &types.OrderInfoInList{
 			OrderNo:           order.OrderNo,
+			CheckoutNo:        order.CheckoutNo,
+			MerchantID:        order.MerchantID,
 			ReceiverFirstName: order.ReceiverFirstName,
 			ReceiverLastName:  order.ReceiverLastName,
 			ReceiverPhone:     order.ReceiverPhone,
@@ -339,69 +567,170 @@ func (o *OrderServiceImpl) ListOrders(ctx context.Context, req types.ListOrderRe
 	}
 
 	resp = &types.ListOrderResponse{
-		Orders: orderList,
-		Total:  len(orderList),
+		Orders:         orderList,
+		Total:          int(total),
+		TotalEstimated: req.CountMode == dao.CountModeEstimate,
+		HasMore:        hasMore,
+	}
+	if hasMore && dao.SupportsCursor(query.SortBy) {
+		last := orders[len(orders)-1]
+		resp.NextCursor = utils.EncodeOrderCursor(query.SortBy, orderSortValue(last, query.SortBy), last.ID)
 	}
 
 	return resp, nil
 }
 
+// buildOrderQuery 构建查询条件，多取一条用于判断是否还有下一页
+func buildOrderQuery(req types.ListOrderRequest) (dao.OrderQuery, error) {
+	query := dao.OrderQuery{
+		MerchantID:        req.MerchantID,
+		UserID:            req.UserID,
+		OrderStatus:       req.OrderStatus,
+		Statuses:          req.Statuses,
+		StartTime:         req.StartTime,
+		EndTime:           req.EndTime,
+		OrderNo:           req.OrderNo,
+		MinAmount:         req.MinAmount,
+		MaxAmount:         req.MaxAmount,
+		ReceiverName:      req.ReceiverName,
+		ReceiverPhone:     req.ReceiverPhone,
+		ReceiverCountry:   req.ReceiverCountry,
+		ProductID:         req.ProductID,
+		ProductName:       req.ProductName,
+		LogisticsNo:       req.LogisticsNo,
+		PayStartTime:      req.PayStartTime,
+		PayEndTime:        req.PayEndTime,
+		DeliveryStartTime: req.DeliveryStartTime,
+		DeliveryEndTime:   req.DeliveryEndTime,
+		SortBy:            req.SortBy,
+		Limit:             req.Limit + 1,
+		Offset:            req.Offset,
+		CountMode:         req.CountMode,
+	}
+	if query.SortBy == "" {
+		query.SortBy = dao.OrderSortCreateTime
+	}
+	if !dao.IsOrderSortField(query.SortBy) {
+		return query, ErrInvalidOrderSort
+	}
+	switch strings.ToLower(req.SortOrder) {
+	case "", "desc":
+	case "asc":
+		query.SortAsc = true
+	default:
+		return query, ErrInvalidOrderSort
+	}
+
+	if req.Cursor != "" {
+		sortBy, value, id, err := utils.DecodeOrderCursor(req.Cursor)
+		if err != nil {
+			return query, err
+		}
+		if sortBy == "" {
+			sortBy = dao.OrderSortCreateTime
+		}
+		// 游标只对生成它的排序有效
+		if sortBy != query.SortBy || !dao.SupportsCursor(sortBy) {
+			return query, utils.ErrInvalidCursor
+		}
+		query.After = &dao.OrderCursor{Value: orderCursorValue(sortBy, value), ID: id}
+	}
+	return query, nil
+}
+
+// orderSortValue 取订单在排序字段上的值，写入游标
+func orderSortValue(order *model.Order, sortBy string) int64 {
+	switch sortBy {
+	case dao.OrderSortTotalAmount:
+		return int64(order.TotalAmount)
+	case dao.OrderSortStatus:
+		return int64(order.Status)
+	default:
+		return order.CreateTime.UnixNano()
+	}
+}
+
+// orderCursorValue 将游标中的值还原为排序字段的类型
+func orderCursorValue(sortBy string, value int64) interface{} {
+	switch sortBy {
+	case dao.OrderSortTotalAmount, dao.OrderSortStatus:
+		return value
+	default:
+		return time.Unix(0, value)
+	}
+}
+
 // GetOrderDetail 根据订单号查询订单详情
 func (o *OrderServiceImpl) GetOrderDetail(ctx context.Context, orderNo string) (detail *types.OrderDetail, err error) {
+	logger := log.FromContext(ctx).With("order_no", orderNo)
+
 	// 1. 查询订单基本信息
 	order, err := o.orderDao.GetByOrderNo(ctx, orderNo)
 	if err != nil {
-		log.Logger.Errorf("GetOrderDetail: get order failed, orderNo: %s, err: %s", orderNo, err.Error())
-		return nil, err
+		logger.Errorf("GetOrderDetail: get order failed, err: %s", err.Error())
+		return nil, orderDaoErr(err)
 	}
 
 	// 2. 查询订单商品列表
 	orderProducts, err := o.orderProductDao.GetByOrderNo(ctx, orderNo)
 	if err != nil {
-		log.Logger.Errorf("GetOrderDetail: get order products failed, orderNo: %s, err: %s", orderNo, err.Error())
+		logger.Errorf("GetOrderDetail: get order products failed, err: %s", err.Error())
 		return nil, err
 	}
 
 	// 3. 查询订单状态日志
 	orderLogs, err := o.orderLogDao.GetByOrderNo(ctx, orderNo)
 	if err != nil {
-		log.Logger.Errorf("GetOrderDetail: get order logs failed, orderNo: %s, err: %s", orderNo, err.Error())
+		logger.Errorf("GetOrderDetail: get order logs failed, err: %s", err.Error())
 		return nil, err
 	}
 
-	// 4. 转换订单商品信息
+	// 4. 查询包裹
+	shipments, err := o.shipmentDao.GetByOrderNo(ctx, orderNo)
+	if err != nil {
+		logger.Errorf("GetOrderDetail: get shipments failed, err: %s", err.Error())
+		return nil, err
+	}
+	shipmentDetails, shippedQuantities := o.toShipmentDetails(shipments, orderProducts)
+
+	// 5. 转换订单商品信息
 	orderItems := make([]*types.OrderItemDetail, 0, len(orderProducts))
 	for _, product := range orderProducts {
 		orderItem := &types.OrderItemDetail{
 			ID:          product.ID,
 			ProductID:   product.ProductID,
 			ProductName: product.ProductName,
+			ImageURL:    o.catalogImage(product.ImageURL),
 			Price:       product.Price,
 			Quantity:    product.Quantity,
 			TotalPrice:  product.TotalPrice,
 			CreateTime:  product.CreateTime,
 			UpdateTime:  product.UpdateTime,
+
+			ShippedQuantity: shippedQuantities[product.ID],
 		}
 		orderItems = append(orderItems, orderItem)
 	}
 
-	// 5. 转换订单状态日志
+	// 6. 转换订单状态日志
 	statusLogs := make([]*types.OrderStatusLogDetail, 0, len(orderLogs))
-	for _, log := range orderLogs {
+	for _, orderLog := range orderLogs {
 		statusLog := &types.OrderStatusLogDetail{
-			ID:            log.ID,
-			CurrentStatus: log.CurrentStatus,
-			StatusName:    getOrderStatusName(log.CurrentStatus),
-			Remark:        log.Remark,
-			CreateTime:    log.CreateTime,
+			ID:            orderLog.ID,
+			CurrentStatus: orderLog.CurrentStatus,
+			StatusName:    getOrderStatusName(orderLog.CurrentStatus),
+			Remark:        orderLog.Remark,
+			CreateTime:    orderLog.CreateTime,
 		}
 		statusLogs = append(statusLogs, statusLog)
 	}
 
-	// 6. 构建订单详情响应
+	// 7. 构建订单详情响应
 	detail = &types.OrderDetail{
 		// 基本订单信息
 		OrderNo:      order.OrderNo,
+		CheckoutNo:   order.CheckoutNo,
+		MerchantID:   order.MerchantID,
 		UserID:       order.UserID,
 		Status:       order.Status,
 		StatusName:   getOrderStatusName(order.Status),
@@ -413,12 +742,15 @@ func (o *OrderServiceImpl) GetOrderDetail(ctx context.Context, orderNo string) (
 		CreateTime:   order.CreateTime,
 		UpdateTime:   order.UpdateTime,
 		DeliveryTime: order.DeliveryTime,
+		ArrivedTime:  order.ArrivedTime,
 		ConfirmTime:  order.ConfirmTime,
 
 		// 收货信息
 		ReceiverFirstName: order.ReceiverFirstName,
 		ReceiverLastName:  order.ReceiverLastName,
 		ReceiverPhone:     order.ReceiverPhone,
+		AddressID:         order.AddressID,
+		ShippingAddress:   orderAddress(order),
 		ReceiverAddress:   order.ReceiverAddress,
 		ReceiverCountry:   order.ReceiverCountry,
 		ReceiverZipCode:   order.ReceiverZipCode,
@@ -426,10 +758,12 @@ func (o *OrderServiceImpl) GetOrderDetail(ctx context.Context, orderNo string) (
 		// 其他信息
 		Remark:      order.Remark,
 		LogisticsNo: order.LogisticsNo,
+		Carrier:     order.Carrier,
 
 		// 关联数据
 		OrderItems: orderItems,
 		StatusLogs: statusLogs,
+		Shipments:  shipmentDetails,
 	}
 
 	return detail, nil
@@ -448,69 +782,161 @@ func getOrderStatusName(status int) string {
 		return "Delivered"
 	case consts.CANCELED:
 		return "Canceled"
+	case consts.PARTIALLY_SHIPPED:
+		return "PartiallyShipped"
 	default:
 		return "Unknown"
 	}
 }
 
+// ownedByMerchant merchantID 为 0 时不限商家
+func ownedByMerchant(order *model.Order, merchantID int) bool {
+	return merchantID == 0 || order.MerchantID == merchantID
+}
+
+// MerchantGetOrderDetail 商家只能查看自己的订单，其他商家的订单视为不存在
+func (o *OrderServiceImpl) MerchantGetOrderDetail(ctx context.Context, orderNo string, merchantID int) (detail *types.OrderDetail, err error) {
+	detail, err = o.GetOrderDetail(ctx, orderNo)
+	if err != nil {
+		return nil, err
+	}
+	if merchantID != 0 && detail.MerchantID != merchantID {
+		log.FromContext(ctx).With("order_no", orderNo, "merchant_id", merchantID).
+			Warnf("MerchantGetOrderDetail: order belongs to merchant %d", detail.MerchantID)
+		return nil, ErrOrderNotFound
+	}
+	return detail, nil
+}
+
+// CustomerGetCheckout 查询一次下单拆分出的全部子订单
+func (o *OrderServiceImpl) CustomerGetCheckout(ctx context.Context, checkoutNo string, userId int) (detail *types.CheckoutDetail, err error) {
+	logger := log.FromContext(ctx).With("checkout_no", checkoutNo, "user_id", userId)
+	orders, err := o.orderDao.GetByCheckoutNo(ctx, checkoutNo)
+	if err != nil {
+		logger.Errorf("CustomerGetCheckout: get orders failed, err %s", err.Error())
+		return nil, err
+	}
+	if len(orders) == 0 {
+		return nil, ErrOrderNotFound.Detailf("checkout no %s", checkoutNo)
+	}
+	if orders[0].UserID != userId {
+		logger.Errorf("CustomerGetCheckout: Invalid userID, err %s", ErrOrderForbidden.Error())
+		return nil, ErrOrderForbidden
+	}
+
+	detail = &types.CheckoutDetail{
+		CheckoutNo: checkoutNo,
+		CreateTime: orders[0].CreateTime,
+		Orders:     make([]*types.OrderDetail, 0, len(orders)),
+	}
+	for _, order := range orders {
+		orderDetail, err := o.CustomerGetOrderDetail(ctx, order.OrderNo, userId)
+		if err != nil {
+			return nil, err
+		}
+		detail.TotalAmount += orderDetail.TotalAmount
+		detail.Orders = append(detail.Orders, orderDetail)
+	}
+	return detail, nil
+}
+
 func (o *OrderServiceImpl) CustomerGetOrderDetail(ctx context.Context, orderNo string, userId int) (detail *types.OrderDetail, err error) {
+	logger := log.FromContext(ctx).With("order_no", orderNo, "user_id", userId)
 	orderInfo, err := o.GetOrderDetail(ctx, orderNo)
 	if err != nil {
-		log.Logger.Errorf("CustomerGetOrderDetail: get order detail failed, err %s", err.Error())
+		logger.Errorf("CustomerGetOrderDetail: get order detail failed, err %s", err.Error())
 		return nil, err
 	}
 	if orderInfo.UserID != userId {
-		wrongUserErr := errors.New("invalid user ID")
-		log.Logger.Errorf("CustomerGetOrderDetail: Invalid userID, err %s", wrongUserErr.Error())
-		return nil, wrongUserErr
+		logger.Errorf("CustomerGetOrderDetail: Invalid userID, err %s", ErrOrderForbidden.Error())
+		return nil, ErrOrderForbidden
+	}
+
+	// 用户可查看每个包裹的物流轨迹
+	events, err := o.trackingEventDao.GetByOrderNo(ctx, orderNo)
+	if err != nil {
+		logger.Errorf("CustomerGetOrderDetail: get tracking events failed, err %s", err.Error())
+		return nil, err
 	}
+	attachTrackingEvents(orderInfo.Shipments, events)
 	return orderInfo, nil
 }
 
+// UpdateOrderStatus 发货需要承运商，使用 MerchantShipOrder
 func (o *OrderServiceImpl) UpdateOrderStatus(ctx context.Context, orderNo string, newStatus int, shippingNo string) (err error) {
-	o.lock.Lock()
-	defer o.lock.Unlock()
+	return o.updateOrderStatus(ctx, orderNo, 0, 0, newStatus, dao.ShipmentUpdate{OrderNo: orderNo, LogisticsNo: shippingNo})
+}
+
+// CustomerConfirmOrder 用户确认收到自己的订单
+func (o *OrderServiceImpl) CustomerConfirmOrder(ctx context.Context, orderNo string, userID int) (err error) {
+	return o.updateOrderStatus(ctx, orderNo, 0, userID, consts.DELIVERED, dao.ShipmentUpdate{})
+}
 
+// MerchantShipOrder 商家发出自己订单的全部商品
+func (o *OrderServiceImpl) MerchantShipOrder(ctx context.Context, orderNo string, merchantID int, carrierCode, shippingNo string) (err error) {
+	shipment := dao.ShipmentUpdate{OrderNo: orderNo, Carrier: utils.NormalizeCarrierCode(carrierCode), LogisticsNo: shippingNo}
+	return o.updateOrderStatus(ctx, orderNo, merchantID, 0, consts.SHIPPED, shipment)
+}
+
+// updateOrderStatus 变更订单状态，merchantID、userID 非 0 时只能变更该商家或该用户的订单
+func (o *OrderServiceImpl) updateOrderStatus(ctx context.Context, orderNo string, merchantID, userID int, newStatus int, shipment dao.ShipmentUpdate) (err error) {
 	orderInfo, err := o.orderDao.GetByOrderNo(ctx, orderNo)
 	if err != nil {
-		return err
+		return orderDaoErr(err)
 	}
+	if !ownedByMerchant(orderInfo, merchantID) {
+		return ErrOrderNotFound
+	}
+	if userID != 0 && orderInfo.UserID != userID {
+		return ErrOrderForbidden
+	}
+
+	logger := log.FromContext(ctx).With("order_no", orderNo, "user_id", orderInfo.UserID)
 
 	oldStatus := orderInfo.Status
-	if oldStatus != newStatus-1 {
-		statusErr := fmt.Errorf("UpdateOrderStatus: Invalid status, cur: %d, next: %d", orderInfo.Status, newStatus)
-		return statusErr
+	if !canTransition(oldStatus, newStatus) {
+		return ErrInvalidStatusTransition.Detailf("%s --> %s", getOrderStatusName(oldStatus), getOrderStatusName(newStatus))
 	}
 
 	switch newStatus {
 	case consts.DELIVERED:
-		err = o.orderDao.UpdateStatusAndConfirmTime(ctx, orderNo, newStatus, time.Now())
+		err = o.orderDao.UpdateStatusAndConfirmTime(ctx, orderNo, oldStatus, newStatus, time.Now())
 		if err != nil {
 			return err
 		}
 	case consts.SHIPPED:
-		err = o.orderDao.UpdateStatusWithDeliveryInfo(ctx, orderNo, newStatus, time.Now(), shippingNo)
+		// 物流回调按承运商和物流单号匹配包裹，没有承运商的包裹收不到物流轨迹
+		if shipment.Carrier == "" {
+			return ErrInvalidShipment.Detailf("carrier_code is required")
+		}
+		// 一次发出全部未发货商品
+		err = o.orderDao.ShipOrders(ctx, []dao.ShipmentUpdate{shipment}, shippableStatuses, consts.SHIPPED, time.Now())
 		if err != nil {
 			return err
 		}
 	default:
-		defaultErr := fmt.Errorf("UpdateOrderStatus: status no support, cur status %d", newStatus)
-		return defaultErr
+		return ErrInvalidStatusTransition.Detailf("status %d not supported", newStatus)
 	}
 
+	o.orderMetrics.StatusTransition(getOrderStatusName(oldStatus), getOrderStatusName(newStatus))
+
 	statusChangeRemark := fmt.Sprintf("%s --> %s", getOrderStatusName(oldStatus), getOrderStatusName(newStatus))
 	oscMsg, err := getOrderStatusChangedMsg(orderNo, orderInfo.UserID, statusChangeRemark, newStatus)
 	if err != nil {
-		log.Logger.Errorf("get order status changed msg failed, err %s", err.Error())
+		logger.Errorf("get order status changed msg failed, err %s", err.Error())
 	}
-	err = o.messageWriter.SendMsg(ctx, "order_status_changed", orderNo, oscMsg)
+	err = o.messageWriter.SendMsg(ctx, consts.TopicOrderStatusChanged, orderNo, oscMsg)
 	if err != nil {
-		log.Logger.Errorf("send message failed, err %s", err)
+		logger.Errorf("send message failed, err %s", err)
 	}
 
 	return nil
 }
 
-func (o *OrderServiceImpl) GetOrderStats(ctx context.Context) (stats types.OrderStats, err error) {
-	return o.orderStatsCache.GetOrderStats()
+// GetOrderStats 商家只统计自己的订单，merchantID 为 0 时统计全部
+func (o *OrderServiceImpl) GetOrderStats(ctx context.Context, merchantID int) (stats types.OrderStats, err error) {
+	if merchantID != 0 {
+		return o.orderStatsCache.GetMerchantOrderStats(ctx, merchantID)
+	}
+	return o.orderStatsCache.GetOrderStats(ctx)
 }
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/errs"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/types"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/validate"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/model"
+)
+
+var (
+	ErrAddressNotFound = errs.New(errs.CodeAddressNotFound, "saved address not found")
+	ErrInvalidAddress  = errs.New(errs.CodeInvalidAddress, "invalid shipping address")
+	ErrUserService     = errs.New(errs.CodeUserService, "user service call failed")
+)
+
+// ProductMerchants looks up the merchant selling each product in the product
+// catalog. Products missing from merchants have no known merchant.
+type ProductMerchants interface {
+	GetProductMerchants(ctx context.Context, productIDs []int64) (merchants map[int64]int, err error)
+}
+
+// AddressBook reads the addresses users saved on the user service.
+type AddressBook interface {
+	GetAddress(ctx context.Context, userID, addressID int) (addr *types.SavedAddress, ok bool, err error)
+}
+
+// resolveShippingAddress 确定订单的收货地址并规范化：地址簿中的地址优先，其次 shipping_address，
+// 最后是已废弃的 receiver_address 等字段
+func (o *OrderServiceImpl) resolveShippingAddress(ctx context.Context, orderInfo types.OrderInfo, userID int) (addr types.Address, err error) {
+	switch {
+	case orderInfo.AddressID > 0:
+		return o.getSavedAddress(ctx, orderInfo.AddressID, userID)
+	case orderInfo.ShippingAddress != nil:
+		return validate.NormalizeAddress(*orderInfo.ShippingAddress), nil
+	default:
+		return validate.NormalizeAddress(types.Address{
+			Line1:      orderInfo.ReceiverAddress,
+			PostalCode: validate.PostcodeFromInt(orderInfo.ReceiverCountry, orderInfo.ReceiverZipCode),
+			Country:    orderInfo.ReceiverCountry,
+		}), nil
+	}
+}
+
+// getSavedAddress 读取用户地址簿中的地址。地址簿的数据没有经过本服务的请求校验，规范化后按相同规则再校验
+func (o *OrderServiceImpl) getSavedAddress(ctx context.Context, addressID, userID int) (addr types.Address, err error) {
+	logger := log.FromContext(ctx).With("user_id", userID, "address_id", addressID)
+	saved, ok, err := o.addressBook.GetAddress(ctx, userID, addressID)
+	if err != nil {
+		logger.Errorf("getSavedAddress: get address failed, err: %s", err.Error())
+		return addr, ErrUserService.Wrap(err)
+	}
+	if !ok {
+		return addr, ErrAddressNotFound.Detailf("address id: %d", addressID)
+	}
+
+	addr = validate.NormalizeAddress(saved.Address)
+	if err = validate.ValidateAddress(addr); err != nil {
+		logger.Errorf("getSavedAddress: invalid saved address, err: %s", err.Error())
+		invalid := ErrInvalidAddress.Detailf("address id: %d", addressID)
+		invalid.Fields, _ = validate.FieldErrors(err)
+		return types.Address{}, invalid
+	}
+	return addr, nil
+}
+
+// legacyZipCode 纯数字的邮政编码同时写入已废弃的 receiver_zip_code，供仍读取该字段的下游使用；
+// 其余编码无法用整数表示，写 0
+func legacyZipCode(postcode string) int {
+	zipCode, err := strconv.Atoi(postcode)
+	if err != nil || zipCode < 0 {
+		return 0
+	}
+	return zipCode
+}
+
+// orderAddress 订单的收货地址快照。结构化地址之前的订单只有 receiver_address 和整数邮政编码，据此还原
+func orderAddress(order *model.Order) types.Address {
+	addr := types.Address{
+		Line1:      order.ReceiverLine1,
+		Line2:      order.ReceiverLine2,
+		City:       order.ReceiverCity,
+		State:      order.ReceiverState,
+		PostalCode: order.ReceiverPostcode,
+		Country:    order.ReceiverCountry,
+	}
+	if addr.Line1 == "" {
+		addr.Line1 = order.ReceiverAddress
+	}
+	if addr.PostalCode == "" {
+		addr.PostalCode = validate.PostcodeFromInt(order.ReceiverCountry, order.ReceiverZipCode)
+	}
+	return addr
+}
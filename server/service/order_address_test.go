@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/common/productpb"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/clients/mocks"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/consts"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/types"
+	utilMocks "github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/utils/mocks"
+	daoMocks "github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/dao/mocks"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-payment-mservice/common/paymentpb"
+	"github.com/golang/mock/gomock"
+)
+
+type fakeAddressBook map[int]*types.SavedAddress
+
+func (b fakeAddressBook) GetAddress(ctx context.Context, userID, addressID int) (*types.SavedAddress, bool, error) {
+	addr, ok := b[addressID]
+	if !ok || addr.UserID != userID {
+		return nil, false, nil
+	}
+	return addr, true, nil
+}
+
+func TestOrderServiceImpl_CreateOrder_SavedAddress(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderDao := daoMocks.NewMockOrderDao(ctrl)
+	mockOrderProductDao := daoMocks.NewMockOrderProductDao(ctrl)
+	mockProductClient := mocks.NewMockProductServiceClient(ctrl)
+	mockPaymentClient := mocks.NewMockPaymentServiceClient(ctrl)
+	mockKafkaWriter := utilMocks.NewMockWriter(ctrl)
+	ctx := context.Background()
+
+	addressBook := fakeAddressBook{7: {ID: 7, UserID: 123, Address: types.Address{
+		Line1: "10  Downing Street", City: "London", PostalCode: "sw1a2aa", Country: "GB",
+	}}}
+	orderInfo := types.OrderInfo{
+		ReceiverFirstName: "Ada",
+		ReceiverLastName:  "Lovelace",
+		ReceiverPhone:     "+442071234567",
+		AddressID:         7,
+		OrderItemList:     []*types.OrderItemInfo{{ProductID: 1, Quantity: 1, Price: 1000}},
+	}
+
+	mockProductClient.EXPECT().GetProductList(ctx, gomock.Any()).
+		Return(&productpb.GetProductListResponse{Products: []*productpb.Product{{Id: 1, Stock: 5}}}, nil)
+	mockProductClient.EXPECT().UpdateStockWithCAS(ctx, gomock.Any()).Return(&productpb.UpdateStockWithCASResponse{}, nil)
+	mockOrderDao.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, order *model.Order) (string, error) {
+		// 地址簿中的地址规范化后复制到订单上
+		if order.AddressID != 7 || order.ReceiverLine1 != "10 Downing Street" || order.ReceiverCity != "London" ||
+			order.ReceiverPostcode != "SW1A 2AA" || order.ReceiverCountry != "GB" || order.ReceiverAddress != "10 Downing Street" {
+			t.Errorf("unexpected address snapshot: %+v", order)
+		}
+		if order.ReceiverZipCode != 0 {
+			t.Errorf("alphanumeric postcode should not be written to receiver_zip_code, got %d", order.ReceiverZipCode)
+		}
+		return order.OrderNo, nil
+	})
+	mockOrderProductDao.EXPECT().CreateBatch(ctx, gomock.Any()).Return(1, nil)
+	mockKafkaWriter.EXPECT().SendMsg(ctx, consts.TopicOrderCreated, gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, _, _, msg string) error {
+		var orderMsg types.OrderMessage
+		if err := json.Unmarshal([]byte(msg), &orderMsg); err != nil {
+			t.Fatalf("decode order message: %v", err)
+		}
+		if orderMsg.AddressID != 7 || orderMsg.ShippingAddress.PostalCode != "SW1A 2AA" {
+			t.Errorf("order message should carry the address snapshot: %+v", orderMsg)
+		}
+		return nil
+	})
+	mockKafkaWriter.EXPECT().SendMsg(ctx, consts.TopicOrderStatusChanged, gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockPaymentClient.EXPECT().PayOrder(ctx, gomock.Any()).Return(&paymentpb.PayOrderResponse{Code: 0}, nil)
+	mockOrderDao.EXPECT().UpdateStatusAndPayment(ctx, gomock.Any(), consts.PAYED, gomock.Any()).Return(nil)
+
+	service := &OrderServiceImpl{
+		orderDao:             mockOrderDao,
+		orderProductDao:      mockOrderProductDao,
+		productServiceClient: mockProductClient,
+		productMerchants:     fakeProductMerchants{1: consts.DefaultMerchantID, 2: consts.DefaultMerchantID},
+		paymentServiceClient: mockPaymentClient,
+		addressBook:          addressBook,
+		messageWriter:        mockKafkaWriter,
+	}
+	if _, err := service.CreateOrder(ctx, orderInfo, 123); err != nil {
+		t.Fatalf("CreateOrder() error = %v", err)
+	}
+}
+
+func TestOrderServiceImpl_CreateOrder_SavedAddressErrors(t *testing.T) {
+	ctx := context.Background()
+	addressBook := fakeAddressBook{
+		7: {ID: 7, UserID: 123, Address: types.Address{Line1: "1 Orchard Road", City: "Singapore", PostalCode: "238823", Country: "SG"}},
+		8: {ID: 8, UserID: 123, Address: types.Address{Line1: "1 Orchard Road", City: "Singapore", PostalCode: "2388", Country: "SG"}},
+	}
+	tests := []struct {
+		name      string
+		addressID int
+		userID    int
+		want      error
+	}{
+		{"not found", 9, 123, ErrAddressNotFound},
+		{"another user's address", 7, 456, ErrAddressNotFound},
+		{"invalid saved address", 8, 123, ErrInvalidAddress},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 地址无效时在调用商品服务之前返回
+			service := &OrderServiceImpl{addressBook: addressBook}
+			_, err := service.CreateOrder(ctx, types.OrderInfo{AddressID: tt.addressID}, tt.userID)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("CreateOrder() err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrderAddress_LegacyOrder(t *testing.T) {
+	addr := orderAddress(&model.Order{ReceiverAddress: "1 Tanjong Pagar Plaza", ReceiverCountry: "SG", ReceiverZipCode: 82001})
+	want := types.Address{Line1: "1 Tanjong Pagar Plaza", PostalCode: "082001", Country: "SG"}
+	if addr != want {
+		t.Errorf("orderAddress() = %+v, want %+v", addr, want)
+	}
+}
@@ -0,0 +1,321 @@
+package service
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/consts"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/errs"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/types"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/dao"
+)
+
+// 销售趋势统计周期
+const (
+	IntervalDay   = "day"
+	IntervalWeek  = "week"
+	IntervalMonth = "month"
+)
+
+// 转化漏斗阶段
+const (
+	FunnelPlaced    = "placed"
+	FunnelPaid      = "paid"
+	FunnelShipped   = "shipped"
+	FunnelDelivered = "delivered"
+)
+
+const (
+	analyticsDateLayout    = "2006-01-02"
+	MaxAnalyticsRangeDays  = 366
+	defaultTopProductLimit = 10
+)
+
+var ErrInvalidDateRange = errs.New(errs.CodeInvalidDateRange, "invalid date range")
+
+// funnelStages 每个阶段包含已到达该阶段及之后状态的订单
+var funnelStages = []struct {
+	stage    string
+	statuses []int
+}{
+	{FunnelPlaced, []int{consts.CREATED, consts.PAYED, consts.PARTIALLY_SHIPPED, consts.SHIPPED, consts.DELIVERED, consts.CANCELED}},
+	{FunnelPaid, []int{consts.PAYED, consts.PARTIALLY_SHIPPED, consts.SHIPPED, consts.DELIVERED}},
+	{FunnelShipped, []int{consts.PARTIALLY_SHIPPED, consts.SHIPPED, consts.DELIVERED}},
+	{FunnelDelivered, []int{consts.DELIVERED}},
+}
+
+type OrderAnalyticsService interface {
+	GetSalesSeries(ctx context.Context, req types.SalesSeriesRequest) (resp *types.SalesSeriesResponse, err error)
+	GetTopProducts(ctx context.Context, req types.TopProductsRequest) (resp *types.TopProductsResponse, err error)
+	GetStatusFunnel(ctx context.Context, req types.AnalyticsRequest) (resp *types.StatusFunnelResponse, err error)
+	GetSummary(ctx context.Context, req types.AnalyticsRequest) (resp *types.AnalyticsSummary, err error)
+	GetCountryBreakdown(ctx context.Context, req types.AnalyticsRequest) (resp *types.CountryBreakdownResponse, err error)
+}
+
+// OrderAnalyticsServiceImpl reads the sales series and the status funnel
+// from the daily summary when the requested days line up with its days, and
+// from the orders table otherwise.
+type OrderAnalyticsServiceImpl struct {
+	analyticsDao dao.OrderAnalyticsDao
+	summaryDao   dao.OrderSummaryDao
+	summaryLoc   *time.Location // 日汇总表划分日期的时区
+}
+
+func NewOrderAnalyticsService(analyticsDao dao.OrderAnalyticsDao, summaryDao dao.OrderSummaryDao) *OrderAnalyticsServiceImpl {
+	return &OrderAnalyticsServiceImpl{analyticsDao: analyticsDao, summaryDao: summaryDao, summaryLoc: time.Local}
+}
+
+// analyticsFilter 将 tz 时区下的起止日期转为 [起始日 0 点, 结束日次日 0 点) 的时间范围
+func analyticsFilter(req types.AnalyticsRequest) (dao.AnalyticsFilter, *time.Location, error) {
+	loc := time.Local
+	if req.TZ != "" {
+		var err error
+		if loc, err = time.LoadLocation(req.TZ); err != nil {
+			return dao.AnalyticsFilter{}, nil, ErrInvalidDateRange.Detailf("unknown time zone %s", req.TZ)
+		}
+	}
+	start, err := time.ParseInLocation(analyticsDateLayout, req.StartDate, loc)
+	if err != nil {
+		return dao.AnalyticsFilter{}, nil, ErrInvalidDateRange.Wrap(err)
+	}
+	end, err := time.ParseInLocation(analyticsDateLayout, req.EndDate, loc)
+	if err != nil {
+		return dao.AnalyticsFilter{}, nil, ErrInvalidDateRange.Wrap(err)
+	}
+	end = end.AddDate(0, 0, 1)
+	if !end.After(start) {
+		return dao.AnalyticsFilter{}, nil, ErrInvalidDateRange.Detailf("end_date is before start_date")
+	}
+	if end.After(start.AddDate(0, 0, MaxAnalyticsRangeDays)) {
+		return dao.AnalyticsFilter{}, nil, ErrInvalidDateRange.Detailf("at most %d days", MaxAnalyticsRangeDays)
+	}
+	return dao.AnalyticsFilter{MerchantID: req.MerchantID, Start: start, End: end}, loc, nil
+}
+
+// periodStart 返回 t 所在周期的起始时间，周从周一开始
+func periodStart(t time.Time, interval string) time.Time {
+	year, month, day := t.Date()
+	switch interval {
+	case IntervalMonth:
+		return time.Date(year, month, 1, 0, 0, 0, 0, t.Location())
+	case IntervalWeek:
+		offset := (int(t.Weekday()) + 6) % 7
+		return time.Date(year, month, day-offset, 0, 0, 0, 0, t.Location())
+	default:
+		return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+	}
+}
+
+func nextPeriod(t time.Time, interval string) time.Time {
+	switch interval {
+	case IntervalMonth:
+		return t.AddDate(0, 1, 0)
+	case IntervalWeek:
+		return t.AddDate(0, 0, 7)
+	default:
+		return t.AddDate(0, 0, 1)
+	}
+}
+
+func periodLabel(t time.Time, interval string) string {
+	if interval == IntervalMonth {
+		return t.Format("2006-01")
+	}
+	return t.Format(analyticsDateLayout)
+}
+
+// useSummary 日汇总表按服务器时区的自然日统计，只有请求时区下范围内每天的 0 点
+// 也是服务器时区的 0 点，且汇总表已完成回填时才能使用
+func (s *OrderAnalyticsServiceImpl) useSummary(ctx context.Context, filter dao.AnalyticsFilter) bool {
+	for day := filter.Start; !day.After(filter.End); day = day.AddDate(0, 0, 1) {
+		if local := day.In(s.summaryLoc); local.Hour() != 0 || local.Minute() != 0 {
+			return false
+		}
+	}
+	_, ok, err := s.summaryDao.GetWatermark(ctx)
+	if err != nil {
+		log.FromContext(ctx).Warnf("useSummary: get summary watermark failed, err: %s", err.Error())
+		return false
+	}
+	return ok
+}
+
+// ratio 保留 4 位小数，分母为 0 时为 0
+func ratio(part, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(part)/float64(total)*10000) / 10000
+}
+
+// GetSalesSeries 按日、周或月统计范围内的订单数和销售额，周期按 tz 时区划分
+func (s *OrderAnalyticsServiceImpl) GetSalesSeries(ctx context.Context, req types.SalesSeriesRequest) (resp *types.SalesSeriesResponse, err error) {
+	filter, loc, err := analyticsFilter(req.AnalyticsRequest)
+	if err != nil {
+		return nil, err
+	}
+	interval := req.Interval
+	if interval == "" {
+		interval = IntervalDay
+	}
+
+	var slots []*dao.SalesSlot
+	if s.useSummary(ctx, filter) {
+		slots, err = s.summaryDao.GetSalesDays(ctx, filter)
+	} else {
+		slots, err = s.analyticsDao.GetSalesSlots(ctx, filter)
+	}
+	if err != nil {
+		log.FromContext(ctx).Errorf("GetSalesSeries: query sales failed, err: %s", err.Error())
+		return nil, err
+	}
+
+	resp = &types.SalesSeriesResponse{Interval: interval, TZ: loc.String()}
+	points := make(map[int64]*types.SalesPoint)
+	for start := periodStart(filter.Start, interval); start.Before(filter.End); start = nextPeriod(start, interval) {
+		point := &types.SalesPoint{Period: periodLabel(start, interval), Start: start}
+		points[start.Unix()] = point
+		resp.Points = append(resp.Points, point)
+	}
+	for _, slot := range slots {
+		point, ok := points[periodStart(slot.SlotTime.In(loc), interval).Unix()]
+		if !ok {
+			continue
+		}
+		point.Orders += slot.Orders
+		point.Revenue += slot.Revenue
+		resp.TotalOrders += slot.Orders
+		resp.TotalRevenue += slot.Revenue
+	}
+	return resp, nil
+}
+
+// GetTopProducts 按销售额或销量返回排名靠前的商品
+func (s *OrderAnalyticsServiceImpl) GetTopProducts(ctx context.Context, req types.TopProductsRequest) (resp *types.TopProductsResponse, err error) {
+	filter, _, err := analyticsFilter(req.AnalyticsRequest)
+	if err != nil {
+		return nil, err
+	}
+	sortBy := req.SortBy
+	if sortBy == "" {
+		sortBy = dao.ProductSortRevenue
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultTopProductLimit
+	}
+
+	products, err := s.analyticsDao.GetTopProducts(ctx, filter, sortBy, limit)
+	if err != nil {
+		log.FromContext(ctx).Errorf("GetTopProducts: query products failed, err: %s", err.Error())
+		return nil, err
+	}
+	resp = &types.TopProductsResponse{SortBy: sortBy, Products: make([]*types.ProductSales, 0, len(products))}
+	for _, product := range products {
+		resp.Products = append(resp.Products, &types.ProductSales{
+			ProductID:   product.ProductID,
+			ProductName: product.ProductName,
+			Quantity:    product.Quantity,
+			Revenue:     product.Revenue,
+			Orders:      product.Orders,
+		})
+	}
+	return resp, nil
+}
+
+// GetStatusFunnel 统计范围内下单的订单从下单到收货各阶段的转化
+func (s *OrderAnalyticsServiceImpl) GetStatusFunnel(ctx context.Context, req types.AnalyticsRequest) (resp *types.StatusFunnelResponse, err error) {
+	filter, _, err := analyticsFilter(req)
+	if err != nil {
+		return nil, err
+	}
+	var counts map[int]int64
+	if s.useSummary(ctx, filter) {
+		counts, err = s.summaryDao.CountByStatus(ctx, filter)
+	} else {
+		counts, err = s.analyticsDao.CountByStatus(ctx, filter)
+	}
+	if err != nil {
+		log.FromContext(ctx).Errorf("GetStatusFunnel: count orders failed, err: %s", err.Error())
+		return nil, err
+	}
+
+	resp = &types.StatusFunnelResponse{
+		Stages:       make([]*types.FunnelStage, 0, len(funnelStages)),
+		StatusCounts: make(map[string]int64, len(counts)),
+	}
+	for status, count := range counts {
+		resp.StatusCounts[getOrderStatusName(status)] += count
+	}
+	var placed int64
+	for _, stage := range funnelStages {
+		var reached int64
+		for _, status := range stage.statuses {
+			reached += counts[status]
+		}
+		if stage.stage == FunnelPlaced {
+			placed = reached
+		}
+		resp.Stages = append(resp.Stages, &types.FunnelStage{Stage: stage.stage, Orders: reached, Rate: ratio(reached, placed)})
+	}
+	resp.Canceled = counts[consts.CANCELED]
+	resp.CancelRate = ratio(resp.Canceled, placed)
+	return resp, nil
+}
+
+// GetSummary 复购率和平均发货时长
+func (s *OrderAnalyticsServiceImpl) GetSummary(ctx context.Context, req types.AnalyticsRequest) (resp *types.AnalyticsSummary, err error) {
+	filter, _, err := analyticsFilter(req)
+	if err != nil {
+		return nil, err
+	}
+	logger := log.FromContext(ctx)
+	customers, err := s.analyticsDao.GetCustomerStats(ctx, filter)
+	if err != nil {
+		logger.Errorf("GetSummary: query customers failed, err: %s", err.Error())
+		return nil, err
+	}
+	seconds, shipped, err := s.analyticsDao.GetAvgShipSeconds(ctx, filter)
+	if err != nil {
+		logger.Errorf("GetSummary: query ship time failed, err: %s", err.Error())
+		return nil, err
+	}
+	return &types.AnalyticsSummary{
+		Customers:          customers.Customers,
+		RepeatCustomers:    customers.RepeatCustomers,
+		RepeatCustomerRate: ratio(customers.RepeatCustomers, customers.Customers),
+		ShippedOrders:      shipped,
+		AvgTimeToShipHours: math.Round(seconds/3600*100) / 100,
+	}, nil
+}
+
+// GetCountryBreakdown 按收货国家统计订单数、销售额和用户数
+func (s *OrderAnalyticsServiceImpl) GetCountryBreakdown(ctx context.Context, req types.AnalyticsRequest) (resp *types.CountryBreakdownResponse, err error) {
+	filter, _, err := analyticsFilter(req)
+	if err != nil {
+		return nil, err
+	}
+	countries, err := s.analyticsDao.GetCountrySales(ctx, filter)
+	if err != nil {
+		log.FromContext(ctx).Errorf("GetCountryBreakdown: query countries failed, err: %s", err.Error())
+		return nil, err
+	}
+
+	var revenue int64
+	for _, country := range countries {
+		revenue += country.Revenue
+	}
+	resp = &types.CountryBreakdownResponse{Countries: make([]*types.CountrySales, 0, len(countries))}
+	for _, country := range countries {
+		resp.Countries = append(resp.Countries, &types.CountrySales{
+			Country:   country.Country,
+			Orders:    country.Orders,
+			Revenue:   country.Revenue,
+			Customers: country.Customers,
+			Share:     ratio(country.Revenue, revenue),
+		})
+	}
+	return resp, nil
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/consts"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/types"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/dao"
+	daoMocks "github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/dao/mocks"
+	"github.com/golang/mock/gomock"
+)
+
+func analyticsRequest(start, end, tz string) types.AnalyticsRequest {
+	return types.AnalyticsRequest{MerchantID: 7, StartDate: start, EndDate: end, TZ: tz}
+}
+
+// newAnalyticsService 汇总表固定按 UTC 划分日期，测试结果不受运行环境时区影响
+func newAnalyticsService(analyticsDao dao.OrderAnalyticsDao, summaryDao dao.OrderSummaryDao) *OrderAnalyticsServiceImpl {
+	service := NewOrderAnalyticsService(analyticsDao, summaryDao)
+	service.summaryLoc = time.UTC
+	return service
+}
+
+func TestOrderAnalyticsServiceImpl_GetSalesSeries_TimeZone(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAnalyticsDao := daoMocks.NewMockOrderAnalyticsDao(ctrl)
+	ctx := context.Background()
+	singapore, _ := time.LoadLocation("Asia/Singapore")
+
+	wantFilter := dao.AnalyticsFilter{
+		MerchantID: 7,
+		Start:      time.Date(2026, 3, 1, 0, 0, 0, 0, singapore),
+		End:        time.Date(2026, 3, 4, 0, 0, 0, 0, singapore),
+	}
+	mockAnalyticsDao.EXPECT().GetSalesSlots(ctx, wantFilter).Return([]*dao.SalesSlot{
+		// 3 月 1 日 UTC 17:00 在新加坡已是 3 月 2 日
+		{SlotTime: time.Date(2026, 3, 1, 17, 0, 0, 0, time.UTC), Orders: 2, Revenue: 300},
+		{SlotTime: time.Date(2026, 3, 2, 16, 15, 0, 0, time.UTC), Orders: 1, Revenue: 50},
+		{SlotTime: time.Date(2026, 2, 28, 16, 0, 0, 0, time.UTC), Orders: 1, Revenue: 10},
+	}, nil)
+
+	// 新加坡的日界不是 UTC 的 0 点，不能使用汇总表
+	service := newAnalyticsService(mockAnalyticsDao, daoMocks.NewMockOrderSummaryDao(ctrl))
+	resp, err := service.GetSalesSeries(ctx, types.SalesSeriesRequest{AnalyticsRequest: analyticsRequest("2026-03-01", "2026-03-03", "Asia/Singapore")})
+	if err != nil {
+		t.Fatalf("GetSalesSeries() error = %v", err)
+	}
+	want := []struct {
+		period  string
+		orders  int64
+		revenue int64
+	}{
+		{"2026-03-01", 1, 10},
+		{"2026-03-02", 2, 300},
+		{"2026-03-03", 1, 50},
+	}
+	if len(resp.Points) != len(want) {
+		t.Fatalf("got %d points, want %d", len(resp.Points), len(want))
+	}
+	for i, w := range want {
+		p := resp.Points[i]
+		if p.Period != w.period || p.Orders != w.orders || p.Revenue != w.revenue {
+			t.Errorf("point %d = %+v, want %+v", i, p, w)
+		}
+	}
+	if resp.TotalOrders != 4 || resp.TotalRevenue != 360 || resp.TZ != "Asia/Singapore" || resp.Interval != IntervalDay {
+		t.Errorf("unexpected totals: %+v", resp)
+	}
+}
+
+func TestOrderAnalyticsServiceImpl_GetSalesSeries_WeekAndMonth(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAnalyticsDao := daoMocks.NewMockOrderAnalyticsDao(ctrl)
+	mockSummaryDao := daoMocks.NewMockOrderSummaryDao(ctrl)
+	ctx := context.Background()
+	// 汇总表尚未回填，使用订单表
+	mockSummaryDao.EXPECT().GetWatermark(ctx).Return(time.Time{}, false, nil).Times(2)
+	mockAnalyticsDao.EXPECT().GetSalesSlots(ctx, gomock.Any()).Return([]*dao.SalesSlot{
+		{SlotTime: time.Date(2026, 1, 31, 12, 0, 0, 0, time.UTC), Orders: 1, Revenue: 100}, // 周六
+		{SlotTime: time.Date(2026, 2, 2, 12, 0, 0, 0, time.UTC), Orders: 1, Revenue: 200},  // 周一
+	}, nil).Times(2)
+
+	service := newAnalyticsService(mockAnalyticsDao, mockSummaryDao)
+	req := types.SalesSeriesRequest{AnalyticsRequest: analyticsRequest("2026-01-28", "2026-02-03", "UTC"), Interval: IntervalWeek}
+	resp, err := service.GetSalesSeries(ctx, req)
+	if err != nil {
+		t.Fatalf("GetSalesSeries() error = %v", err)
+	}
+	if len(resp.Points) != 2 || resp.Points[0].Period != "2026-01-26" || resp.Points[0].Revenue != 100 || resp.Points[1].Revenue != 200 {
+		t.Errorf("weeks should start on Monday: %+v, %+v", resp.Points[0], resp.Points[len(resp.Points)-1])
+	}
+
+	req.Interval = IntervalMonth
+	resp, err = service.GetSalesSeries(ctx, req)
+	if err != nil {
+		t.Fatalf("GetSalesSeries() error = %v", err)
+	}
+	if len(resp.Points) != 2 || resp.Points[0].Period != "2026-01" || resp.Points[1].Period != "2026-02" {
+		t.Errorf("unexpected months: %+v", resp.Points)
+	}
+}
+
+func TestOrderAnalyticsServiceImpl_GetSalesSeries_FromSummary(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSummaryDao := daoMocks.NewMockOrderSummaryDao(ctrl)
+	ctx := context.Background()
+	wantFilter := dao.AnalyticsFilter{
+		MerchantID: 7,
+		Start:      time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		End:        time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC),
+	}
+	mockSummaryDao.EXPECT().GetWatermark(ctx).Return(time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC), true, nil)
+	mockSummaryDao.EXPECT().GetSalesDays(ctx, wantFilter).Return([]*dao.SalesSlot{
+		{SlotTime: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), Orders: 3, Revenue: 450},
+	}, nil)
+
+	service := newAnalyticsService(daoMocks.NewMockOrderAnalyticsDao(ctrl), mockSummaryDao)
+	resp, err := service.GetSalesSeries(ctx, types.SalesSeriesRequest{AnalyticsRequest: analyticsRequest("2026-03-01", "2026-03-02", "UTC")})
+	if err != nil {
+		t.Fatalf("GetSalesSeries() error = %v", err)
+	}
+	if len(resp.Points) != 2 || resp.Points[0].Orders != 0 || resp.Points[1].Orders != 3 || resp.TotalRevenue != 450 {
+		t.Errorf("unexpected series: %+v, %+v", resp.Points, resp)
+	}
+}
+
+func TestAnalyticsFilter_InvalidRange(t *testing.T) {
+	tests := map[string]types.AnalyticsRequest{
+		"end before start": analyticsRequest("2026-03-02", "2026-03-01", ""),
+		"too long":         analyticsRequest("2025-01-01", "2026-03-01", ""),
+		"unknown zone":     analyticsRequest("2026-03-01", "2026-03-01", "Mars/Olympus"),
+	}
+	for name, req := range tests {
+		if _, _, err := analyticsFilter(req); !errors.Is(err, ErrInvalidDateRange) {
+			t.Errorf("%s: err = %v, want ErrInvalidDateRange", name, err)
+		}
+	}
+	if _, _, err := analyticsFilter(analyticsRequest("2025-03-01", "2026-03-01", "")); err != nil {
+		t.Errorf("366 days should be allowed, got %v", err)
+	}
+}
+
+func TestOrderAnalyticsServiceImpl_GetStatusFunnel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSummaryDao := daoMocks.NewMockOrderSummaryDao(ctrl)
+	ctx := context.Background()
+	mockSummaryDao.EXPECT().GetWatermark(ctx).Return(time.Now(), true, nil)
+	mockSummaryDao.EXPECT().CountByStatus(ctx, gomock.Any()).Return(map[int]int64{
+		consts.CREATED:   1,
+		consts.PAYED:     2,
+		consts.SHIPPED:   3,
+		consts.DELIVERED: 2,
+		consts.CANCELED:  2,
+	}, nil)
+
+	service := newAnalyticsService(daoMocks.NewMockOrderAnalyticsDao(ctrl), mockSummaryDao)
+	resp, err := service.GetStatusFunnel(ctx, analyticsRequest("2026-03-01", "2026-03-31", "UTC"))
+	if err != nil {
+		t.Fatalf("GetStatusFunnel() error = %v", err)
+	}
+	want := map[string]struct {
+		orders int64
+		rate   float64
+	}{
+		FunnelPlaced:    {10, 1},
+		FunnelPaid:      {7, 0.7},
+		FunnelShipped:   {5, 0.5},
+		FunnelDelivered: {2, 0.2},
+	}
+	for _, stage := range resp.Stages {
+		if w := want[stage.Stage]; stage.Orders != w.orders || stage.Rate != w.rate {
+			t.Errorf("stage %s = %+v, want %+v", stage.Stage, stage, w)
+		}
+	}
+	if resp.Canceled != 2 || resp.CancelRate != 0.2 || resp.StatusCounts["Shipped"] != 3 {
+		t.Errorf("unexpected funnel: %+v", resp)
+	}
+}
+
+func TestOrderAnalyticsServiceImpl_GetSummaryAndCountries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAnalyticsDao := daoMocks.NewMockOrderAnalyticsDao(ctrl)
+	ctx := context.Background()
+	mockAnalyticsDao.EXPECT().GetCustomerStats(ctx, gomock.Any()).Return(dao.CustomerStats{Customers: 8, RepeatCustomers: 2}, nil)
+	mockAnalyticsDao.EXPECT().GetAvgShipSeconds(ctx, gomock.Any()).Return(float64(27000), int64(5), nil)
+	mockAnalyticsDao.EXPECT().GetCountrySales(ctx, gomock.Any()).Return([]*dao.CountrySales{
+		{Country: "SG", Orders: 3, Revenue: 300, Customers: 2},
+		{Country: "MY", Orders: 1, Revenue: 100, Customers: 1},
+	}, nil)
+
+	service := newAnalyticsService(mockAnalyticsDao, daoMocks.NewMockOrderSummaryDao(ctrl))
+	req := analyticsRequest("2026-03-01", "2026-03-31", "")
+	summary, err := service.GetSummary(ctx, req)
+	if err != nil {
+		t.Fatalf("GetSummary() error = %v", err)
+	}
+	if summary.RepeatCustomerRate != 0.25 || summary.AvgTimeToShipHours != 7.5 || summary.ShippedOrders != 5 {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+
+	countries, err := service.GetCountryBreakdown(ctx, req)
+	if err != nil {
+		t.Fatalf("GetCountryBreakdown() error = %v", err)
+	}
+	if countries.Countries[0].Share != 0.75 || countries.Countries[1].Share != 0.25 {
+		t.Errorf("unexpected shares: %+v, %+v", countries.Countries[0], countries.Countries[1])
+	}
+}
@@ -0,0 +1,377 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/common/productpb"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/consts"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/errs"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/types"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/utils"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/validate"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/dao"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/model"
+)
+
+const auditActionEdit = "edit"
+
+const (
+	REFUND_RETRY_LOCK_KEY      = "order:refund_retry:lock"
+	REFUND_RETRY_LOCK_EXP_TIME = 5 * time.Minute
+)
+
+const (
+	// refundRetryDelay 修改提交后留给同步发送的时间，之后仍未送达的退款由重试任务补发
+	refundRetryDelay     = time.Minute
+	refundRetryBatchSize = 100
+)
+
+// editableStatuses 发货前的订单可以修改
+var editableStatuses = []int{consts.CREATED, consts.PAYED}
+
+var (
+	ErrInvalidOrderEdit = errs.New(errs.CodeInvalidOrderEdit, "invalid order edit")
+	ErrOrderNotEditable = errs.New(errs.CodeOrderNotEditable, "order cannot be edited in current status")
+)
+
+// MerchantEditOrder 商家在发货前修改订单的收货信息、备注或删除商品。删除商品后按下单时的规则重新计算
+// 运费和税，已支付的订单退还差额；每次修改记录字段修改前后的值。
+// 退款与修改在同一事务中记为待发送，提交后同步发送退款消息，失败时由 RetryPendingRefunds 补发。
+func (o *OrderServiceImpl) MerchantEditOrder(ctx context.Context, orderNo string, merchantID, operatorID int, req types.EditOrderRequest) (resp *types.EditOrderResponse, err error) {
+	logger := log.FromContext(ctx).With("order_no", orderNo, "merchant_id", merchantID, "operator_id", operatorID)
+	removeIDs := slices.Clone(req.RemoveItemIDs)
+	slices.Sort(removeIDs)
+	if len(slices.Compact(removeIDs)) != len(req.RemoveItemIDs) {
+		return nil, ErrInvalidOrderEdit.Detailf("duplicate remove_item_ids")
+	}
+
+	plan := &orderEditPlan{req: req, merchantID: merchantID, operatorID: operatorID}
+	order, err := o.orderEditDao.Edit(ctx, orderNo, plan.edit)
+	if err != nil {
+		logger.Errorf("MerchantEditOrder: edit order failed, err: %s", err.Error())
+		return nil, orderDaoErr(err)
+	}
+	logger.Infof("MerchantEditOrder: %d fields changed, refund %d", len(plan.changes), plan.refund)
+	o.onOrderEdited(ctx, order, plan)
+
+	detail, err := o.GetOrderDetail(ctx, orderNo)
+	if err != nil {
+		return nil, err
+	}
+	return &types.EditOrderResponse{
+		Order:        detail,
+		Changes:      plan.changes,
+		RefundAmount: plan.refund,
+		RefundStatus: plan.audit.RefundStatus,
+	}, nil
+}
+
+// MerchantGetOrderAudits 查询订单的修改记录，其他商家的订单视为不存在
+func (o *OrderServiceImpl) MerchantGetOrderAudits(ctx context.Context, orderNo string, merchantID int) (entries []*types.OrderAuditEntry, err error) {
+	logger := log.FromContext(ctx).With("order_no", orderNo, "merchant_id", merchantID)
+	order, err := o.orderDao.GetByOrderNo(ctx, orderNo)
+	if err != nil {
+		logger.Errorf("MerchantGetOrderAudits: get order failed, err: %s", err.Error())
+		return nil, orderDaoErr(err)
+	}
+	if !ownedByMerchant(order, merchantID) {
+		return nil, ErrOrderNotFound
+	}
+
+	logs, err := o.orderEditDao.GetAuditLogs(ctx, orderNo)
+	if err != nil {
+		logger.Errorf("MerchantGetOrderAudits: get audit logs failed, err: %s", err.Error())
+		return nil, err
+	}
+	entries = make([]*types.OrderAuditEntry, 0, len(logs))
+	for _, auditLog := range logs {
+		var changes []*types.FieldChange
+		if err = utils.JSONDecode(auditLog.Changes, &changes); err != nil {
+			logger.Warnf("MerchantGetOrderAudits: decode changes of audit log %d failed, err: %s", auditLog.ID, err.Error())
+		}
+		entries = append(entries, &types.OrderAuditEntry{
+			ID:           auditLog.ID,
+			OperatorID:   auditLog.OperatorID,
+			Action:       auditLog.Action,
+			Reason:       auditLog.Reason,
+			Changes:      changes,
+			RefundAmount: auditLog.RefundAmount,
+			RefundStatus: auditLog.RefundStatus,
+			CreateTime:   auditLog.CreateTime,
+		})
+	}
+	return entries, nil
+}
+
+// orderEditPlan 在订单行锁内根据请求计算修改内容，结果留给事务提交后使用
+type orderEditPlan struct {
+	req        types.EditOrderRequest
+	merchantID int
+	operatorID int
+
+	changes []*types.FieldChange
+	updates map[string]interface{}
+	removed []*model.OrderProduct
+	refund  int
+	audit   *model.OrderAuditLog // 事务提交后带有 ID
+}
+
+func (p *orderEditPlan) edit(order *model.Order, products []*model.OrderProduct) (*dao.OrderEdit, error) {
+	if !ownedByMerchant(order, p.merchantID) {
+		return nil, ErrOrderNotFound
+	}
+	if !slices.Contains(editableStatuses, order.Status) {
+		return nil, ErrOrderNotEditable.Detailf("%s", getOrderStatusName(order.Status))
+	}
+
+	p.changes, p.updates, p.removed, p.refund = nil, map[string]interface{}{}, nil, 0
+	p.editReceiver(order)
+	if err := p.removeItems(order, products); err != nil {
+		return nil, err
+	}
+	if len(p.changes) == 0 {
+		return nil, ErrInvalidOrderEdit.Detailf("nothing to change")
+	}
+
+	changes, err := utils.JSONEncode(p.changes)
+	if err != nil {
+		return nil, err
+	}
+	removedIDs := make([]int, 0, len(p.removed))
+	for _, product := range p.removed {
+		removedIDs = append(removedIDs, product.ID)
+	}
+	p.audit = &model.OrderAuditLog{
+		OrderNo:      order.OrderNo,
+		MerchantID:   order.MerchantID,
+		OperatorID:   p.operatorID,
+		Action:       auditActionEdit,
+		Reason:       p.req.Reason,
+		Changes:      changes,
+		RefundAmount: p.refund,
+	}
+	if p.refund > 0 {
+		p.audit.RefundStatus = consts.RefundPending
+	}
+	return &dao.OrderEdit{Updates: p.updates, RemovedProductIDs: removedIDs, Audit: p.audit}, nil
+}
+
+// set 值有变化时记录修改并更新 column，column 为空时只记录
+func (p *orderEditPlan) set(field, column string, before, after interface{}) bool {
+	if before == after {
+		return false
+	}
+	p.changes = append(p.changes, &types.FieldChange{Field: field, Before: before, After: after})
+	if column != "" {
+		p.updates[column] = after
+	}
+	return true
+}
+
+func (p *orderEditPlan) editReceiver(order *model.Order) {
+	req := p.req
+	if req.ReceiverFirstName != nil {
+		p.set("receiver_first_name", "receiver_first_name", order.ReceiverFirstName, *req.ReceiverFirstName)
+	}
+	if req.ReceiverLastName != nil {
+		p.set("receiver_last_name", "receiver_last_name", order.ReceiverLastName, *req.ReceiverLastName)
+	}
+	if req.ReceiverPhone != nil {
+		p.set("receiver_phone", "receiver_phone", order.ReceiverPhone, *req.ReceiverPhone)
+	}
+	if req.Remark != nil {
+		p.set("remark", "remark", order.Remark, *req.Remark)
+	}
+	if req.ShippingAddress == nil {
+		return
+	}
+
+	before, after := orderAddress(order), validate.NormalizeAddress(*req.ShippingAddress)
+	changed := false
+	for _, field := range []struct {
+		name, column  string
+		before, after string
+	}{
+		{"line1", "receiver_line1", before.Line1, after.Line1},
+		{"line2", "receiver_line2", before.Line2, after.Line2},
+		{"city", "receiver_city", before.City, after.City},
+		{"state", "receiver_state", before.State, after.State},
+		{"postal_code", "receiver_postcode", before.PostalCode, after.PostalCode},
+		{"country", "receiver_country", before.Country, after.Country},
+	} {
+		changed = p.set("shipping_address."+field.name, field.column, field.before, field.after) || changed
+	}
+	if !changed {
+		return
+	}
+	// 结构化地址之前的订单只有部分快照列，修改后写入完整快照，并同步已废弃的字段
+	p.updates["receiver_line1"] = after.Line1
+	p.updates["receiver_line2"] = after.Line2
+	p.updates["receiver_city"] = after.City
+	p.updates["receiver_state"] = after.State
+	p.updates["receiver_postcode"] = after.PostalCode
+	p.updates["receiver_country"] = after.Country
+	p.updates["receiver_address"] = after.Street()
+	p.updates["receiver_zip_code"] = legacyZipCode(after.PostalCode)
+}
+
+// removeItems 删除商品并按剩余商品重新计算金额。删除后不满包邮门槛时不补收运费，修改只会让订单金额减少
+func (p *orderEditPlan) removeItems(order *model.Order, products []*model.OrderProduct) error {
+	if len(p.req.RemoveItemIDs) == 0 {
+		return nil
+	}
+	sub := &subOrder{orderNo: order.OrderNo, merchantID: order.MerchantID}
+	for _, product := range products {
+		if !slices.Contains(p.req.RemoveItemIDs, product.ID) {
+			sub.add(&types.OrderItemInfo{ProductID: product.ProductID, Quantity: product.Quantity, Price: product.Price})
+			continue
+		}
+		p.removed = append(p.removed, product)
+		p.set(fmt.Sprintf("items[id=%d]", product.ID), "", &types.OrderItemInfo{
+			ProductID:   product.ProductID,
+			MerchantID:  order.MerchantID,
+			ProductName: product.ProductName,
+			ImageURL:    product.ImageURL,
+			Quantity:    product.Quantity,
+			Price:       product.Price,
+		}, nil)
+	}
+	if len(p.removed) != len(p.req.RemoveItemIDs) {
+		return ErrInvalidOrderEdit.Detailf("some items are not in the order")
+	}
+	if len(sub.items) == 0 {
+		return ErrInvalidOrderEdit.Detailf("cannot remove every item, cancel the order instead")
+	}
+
+	sub.price()
+	sub.shippingFee = min(sub.shippingFee, order.ShippingFee)
+	p.set("shipping_fee", "shipping_fee", order.ShippingFee, sub.shippingFee)
+	p.set("tax", "tax", order.Tax, sub.tax)
+	p.set("total_amount", "total_amount", order.TotalAmount, sub.totalAmount())
+	if order.Status == consts.PAYED {
+		p.refund = order.TotalAmount - sub.totalAmount()
+		p.updates["refund_amount"] = order.RefundAmount + p.refund
+	}
+	return nil
+}
+
+// onOrderEdited 修改提交后归还删除商品的库存、发送退款并通知订单已修改；失败只记录日志，未送达的退款留待重试
+func (o *OrderServiceImpl) onOrderEdited(ctx context.Context, order *model.Order, plan *orderEditPlan) {
+	logger := log.FromContext(ctx).With("order_no", order.OrderNo, "user_id", order.UserID)
+	for _, product := range plan.removed {
+		_, err := o.productServiceClient.UpdateStockWithCAS(ctx, &productpb.UpdateStockWithCASRequest{
+			Id:   int64(product.ProductID),
+			Deta: int64(product.Quantity),
+		})
+		if err != nil {
+			logger.Errorf("restore stock of product %d failed, err %s", product.ProductID, err.Error())
+		}
+	}
+
+	if plan.audit.RefundStatus == consts.RefundPending {
+		if err := o.sendRefund(ctx, order, plan.audit); err != nil {
+			logger.Errorf("send refund %d failed, left pending for retry, err %s", plan.audit.ID, err.Error())
+		}
+	}
+
+	totalAmount := order.TotalAmount
+	if amount, ok := plan.updates["total_amount"].(int); ok {
+		totalAmount = amount
+	}
+	updatedMsg, err := utils.JSONEncode(types.OrderUpdatedMessage{
+		OrderNo:      order.OrderNo,
+		MerchantID:   order.MerchantID,
+		OperatorID:   plan.operatorID,
+		TotalAmount:  totalAmount,
+		RefundAmount: plan.refund,
+		Changes:      plan.changes,
+	})
+	if err == nil {
+		err = o.messageWriter.SendMsg(ctx, consts.TopicOrderUpdated, order.OrderNo, updatedMsg)
+	}
+	if err != nil {
+		logger.Errorf("send order updated message failed, err %s", err)
+	}
+}
+
+// RetryPendingRefunds 补发修改订单后未确认送达的退款消息。只有持有锁的实例补发，其他实例跳过本轮；
+// 修改时的同步发送仍可能与补发重复，支付服务按 refund_id 去重。
+func (o *OrderServiceImpl) RetryPendingRefunds(ctx context.Context) {
+	logger := log.FromContext(ctx)
+	if err := o.refundRetryLocker.Lock(ctx); err != nil {
+		logger.Info("RetryPendingRefunds: failed to acquire lock, skipping this round")
+		return
+	}
+	defer func() {
+		if err := o.refundRetryLocker.Unlock(ctx); err != nil {
+			logger.Errorf("RetryPendingRefunds: failed to release lock, err: %s", err.Error())
+		}
+	}()
+
+	pending, err := o.orderEditDao.GetPendingRefunds(ctx, time.Now().Add(-refundRetryDelay), refundRetryBatchSize)
+	if err != nil {
+		logger.Errorf("RetryPendingRefunds: get pending refunds failed, err: %s", err.Error())
+		return
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	orderNos := make([]string, 0, len(pending))
+	for _, auditLog := range pending {
+		orderNos = append(orderNos, auditLog.OrderNo)
+	}
+	orders, err := o.orderDao.GetByOrderNos(ctx, orderNos)
+	if err != nil {
+		logger.Errorf("RetryPendingRefunds: query orders failed, err: %s", err.Error())
+		return
+	}
+	ordersByNo := make(map[string]*model.Order, len(orders))
+	for _, order := range orders {
+		ordersByNo[order.OrderNo] = order
+	}
+
+	sent := 0
+	for _, auditLog := range pending {
+		order, ok := ordersByNo[auditLog.OrderNo]
+		if !ok {
+			logger.Errorf("RetryPendingRefunds: order %s of refund %d not found", auditLog.OrderNo, auditLog.ID)
+			continue
+		}
+		if err = o.sendRefund(ctx, order, auditLog); err != nil {
+			logger.Errorf("RetryPendingRefunds: send refund %d failed, err: %s", auditLog.ID, err.Error())
+			continue
+		}
+		sent++
+	}
+	logger.Infof("RetryPendingRefunds: %d of %d pending refunds sent", sent, len(pending))
+}
+
+// sendRefund 同步发送一次修改产生的退款，送达后标记为已发送
+func (o *OrderServiceImpl) sendRefund(ctx context.Context, order *model.Order, auditLog *model.OrderAuditLog) error {
+	refundMsg, err := utils.JSONEncode(types.OrderRefundMessage{
+		RefundID:   auditLog.ID,
+		OrderNo:    order.OrderNo,
+		CheckoutNo: order.CheckoutNo,
+		UserID:     order.UserID,
+		MerchantID: order.MerchantID,
+		Amount:     auditLog.RefundAmount,
+		Reason:     auditLog.Reason,
+	})
+	if err != nil {
+		return err
+	}
+	if err = o.messageWriter.SendMsgSync(ctx, consts.TopicOrderRefunded, order.OrderNo, refundMsg); err != nil {
+		return err
+	}
+	// 标记失败时保持待发送，重试会再发一次，由 refund_id 去重
+	if err = o.orderEditDao.MarkRefundSent(ctx, auditLog.ID); err != nil {
+		return fmt.Errorf("mark refund sent: %w", err)
+	}
+	auditLog.RefundStatus = consts.RefundSent
+	return nil
+}
@@ -0,0 +1,310 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/common/productpb"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/clients/mocks"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/consts"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/types"
+	utilMocks "github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/utils/mocks"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/dao"
+	daoMocks "github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/dao/mocks"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/model"
+	"github.com/golang/mock/gomock"
+)
+
+func editProducts() []*model.OrderProduct {
+	return []*model.OrderProduct{
+		{ID: 11, OrderNo: "ORD1", ProductID: 101, ProductName: "Vase", Quantity: 1, Price: 25000, TotalPrice: 25000},
+		{ID: 12, OrderNo: "ORD1", ProductID: 102, ProductName: "Bowl", Quantity: 2, Price: 5000, TotalPrice: 10000},
+	}
+}
+
+// editOrder 已支付，商品金额 35000 满包邮门槛
+func editOrder() *model.Order {
+	return &model.Order{
+		OrderNo:          "ORD1",
+		CheckoutNo:       "CHK1",
+		UserID:           1,
+		MerchantID:       7,
+		Status:           consts.PAYED,
+		TotalAmount:      35000 + CalculateTax(35000),
+		Tax:              CalculateTax(35000),
+		ReceiverPhone:    "+6591234567",
+		ReceiverLine1:    "1 Orchard Road",
+		ReceiverCity:     "Singapore",
+		ReceiverPostcode: "238823",
+		ReceiverCountry:  "SG",
+	}
+}
+
+// runEditor 让 mock 的 Edit 在给定的订单上执行 editor，并保存其结果
+func runEditor(order *model.Order, edit **dao.OrderEdit) func(context.Context, string, dao.OrderEditor) (*model.Order, error) {
+	return func(_ context.Context, _ string, editor dao.OrderEditor) (*model.Order, error) {
+		change, err := editor(order, editProducts())
+		if err != nil {
+			return nil, err
+		}
+		change.Audit.ID = 5
+		*edit = change
+		return order, nil
+	}
+}
+
+func TestOrderServiceImpl_MerchantEditOrder_RemoveItem(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderEditDao := daoMocks.NewMockOrderEditDao(ctrl)
+	mockOrderDao := daoMocks.NewMockOrderDao(ctrl)
+	mockOrderProductDao := daoMocks.NewMockOrderProductDao(ctrl)
+	mockOrderLogDao := daoMocks.NewMockOrderLogDao(ctrl)
+	mockShipmentDao := daoMocks.NewMockShipmentDao(ctrl)
+	mockProductClient := mocks.NewMockProductServiceClient(ctrl)
+	mockKafkaWriter := utilMocks.NewMockWriter(ctrl)
+	ctx := context.Background()
+
+	order := editOrder()
+	var edit *dao.OrderEdit
+	mockOrderEditDao.EXPECT().Edit(ctx, "ORD1", gomock.Any()).DoAndReturn(runEditor(order, &edit))
+	// 删除的商品归还库存
+	mockProductClient.EXPECT().UpdateStockWithCAS(ctx, &productpb.UpdateStockWithCASRequest{Id: 102, Deta: 2}).
+		Return(&productpb.UpdateStockWithCASResponse{}, nil)
+
+	// 剩余 25000 不满包邮门槛，但不补收运费
+	wantTotal := 25000 + CalculateTax(25000)
+	wantRefund := order.TotalAmount - wantTotal
+	mockKafkaWriter.EXPECT().SendMsgSync(ctx, consts.TopicOrderRefunded, "ORD1", gomock.Any()).DoAndReturn(func(_ context.Context, _, _, msg string) error {
+		var refund types.OrderRefundMessage
+		if err := json.Unmarshal([]byte(msg), &refund); err != nil {
+			t.Fatalf("decode refund message: %v", err)
+		}
+		if refund.RefundID != 5 || refund.Amount != wantRefund || refund.CheckoutNo != "CHK1" || refund.UserID != 1 || refund.Reason != "out of stock" {
+			t.Errorf("unexpected refund message: %+v", refund)
+		}
+		return nil
+	})
+	mockOrderEditDao.EXPECT().MarkRefundSent(ctx, 5).Return(nil)
+	mockKafkaWriter.EXPECT().SendMsg(ctx, consts.TopicOrderUpdated, "ORD1", gomock.Any()).Return(nil)
+
+	mockOrderDao.EXPECT().GetByOrderNo(ctx, "ORD1").Return(order, nil)
+	mockOrderProductDao.EXPECT().GetByOrderNo(ctx, "ORD1").Return(editProducts()[:1], nil)
+	mockOrderLogDao.EXPECT().GetByOrderNo(ctx, "ORD1").Return(nil, nil)
+	mockShipmentDao.EXPECT().GetByOrderNo(ctx, "ORD1").Return(nil, nil)
+
+	service := &OrderServiceImpl{
+		orderDao:             mockOrderDao,
+		orderProductDao:      mockOrderProductDao,
+		orderLogDao:          mockOrderLogDao,
+		shipmentDao:          mockShipmentDao,
+		orderEditDao:         mockOrderEditDao,
+		productServiceClient: mockProductClient,
+		messageWriter:        mockKafkaWriter,
+	}
+	phone := "+6598765432"
+	resp, err := service.MerchantEditOrder(ctx, "ORD1", 7, 70, types.EditOrderRequest{
+		ReceiverPhone: &phone,
+		RemoveItemIDs: []int{12},
+		Reason:        "out of stock",
+	})
+	if err != nil {
+		t.Fatalf("MerchantEditOrder() error = %v", err)
+	}
+	if resp.RefundAmount != wantRefund || resp.RefundStatus != consts.RefundSent || len(resp.Changes) != 4 {
+		t.Errorf("MerchantEditOrder() = refund %d %s, changes %d, want %d sent, 4", resp.RefundAmount, resp.RefundStatus, len(resp.Changes), wantRefund)
+	}
+
+	if edit.Updates["receiver_phone"] != phone || edit.Updates["total_amount"] != wantTotal ||
+		edit.Updates["tax"] != CalculateTax(25000) || edit.Updates["refund_amount"] != wantRefund {
+		t.Errorf("unexpected updates: %v", edit.Updates)
+	}
+	if _, ok := edit.Updates["shipping_fee"]; ok {
+		t.Errorf("shipping fee should not change, updates: %v", edit.Updates)
+	}
+	if len(edit.RemovedProductIDs) != 1 || edit.RemovedProductIDs[0] != 12 {
+		t.Errorf("RemovedProductIDs = %v, want [12]", edit.RemovedProductIDs)
+	}
+
+	audit := edit.Audit
+	if audit.OperatorID != 70 || audit.MerchantID != 7 || audit.RefundAmount != wantRefund || audit.Reason != "out of stock" {
+		t.Errorf("unexpected audit log: %+v", audit)
+	}
+	var changes []*types.FieldChange
+	if err = json.Unmarshal([]byte(audit.Changes), &changes); err != nil {
+		t.Fatalf("decode audit changes: %v", err)
+	}
+	wantFields := []string{"receiver_phone", "items[id=12]", "tax", "total_amount"}
+	for i, change := range changes {
+		if change.Field != wantFields[i] {
+			t.Errorf("changes[%d].Field = %s, want %s", i, change.Field, wantFields[i])
+		}
+	}
+	if changes[0].Before != "+6591234567" || changes[0].After != phone || changes[1].After != nil {
+		t.Errorf("unexpected changes: %+v, %+v", changes[0], changes[1])
+	}
+}
+
+func TestOrderServiceImpl_MerchantEditOrder_RefundSendFailed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderEditDao := daoMocks.NewMockOrderEditDao(ctrl)
+	mockOrderDao := daoMocks.NewMockOrderDao(ctrl)
+	mockOrderProductDao := daoMocks.NewMockOrderProductDao(ctrl)
+	mockOrderLogDao := daoMocks.NewMockOrderLogDao(ctrl)
+	mockShipmentDao := daoMocks.NewMockShipmentDao(ctrl)
+	mockProductClient := mocks.NewMockProductServiceClient(ctrl)
+	mockKafkaWriter := utilMocks.NewMockWriter(ctrl)
+	ctx := context.Background()
+
+	order := editOrder()
+	var edit *dao.OrderEdit
+	mockOrderEditDao.EXPECT().Edit(ctx, "ORD1", gomock.Any()).DoAndReturn(runEditor(order, &edit))
+	mockProductClient.EXPECT().UpdateStockWithCAS(ctx, gomock.Any()).Return(&productpb.UpdateStockWithCASResponse{}, nil)
+	// 退款消息未送达时不标记已发送，留给重试任务
+	mockKafkaWriter.EXPECT().SendMsgSync(ctx, consts.TopicOrderRefunded, "ORD1", gomock.Any()).Return(errors.New("broker down"))
+	mockKafkaWriter.EXPECT().SendMsg(ctx, consts.TopicOrderUpdated, "ORD1", gomock.Any()).Return(nil)
+
+	mockOrderDao.EXPECT().GetByOrderNo(ctx, "ORD1").Return(order, nil)
+	mockOrderProductDao.EXPECT().GetByOrderNo(ctx, "ORD1").Return(editProducts()[:1], nil)
+	mockOrderLogDao.EXPECT().GetByOrderNo(ctx, "ORD1").Return(nil, nil)
+	mockShipmentDao.EXPECT().GetByOrderNo(ctx, "ORD1").Return(nil, nil)
+
+	service := &OrderServiceImpl{
+		orderDao:             mockOrderDao,
+		orderProductDao:      mockOrderProductDao,
+		orderLogDao:          mockOrderLogDao,
+		shipmentDao:          mockShipmentDao,
+		orderEditDao:         mockOrderEditDao,
+		productServiceClient: mockProductClient,
+		messageWriter:        mockKafkaWriter,
+	}
+	resp, err := service.MerchantEditOrder(ctx, "ORD1", 7, 70, types.EditOrderRequest{RemoveItemIDs: []int{12}, Reason: "out of stock"})
+	if err != nil {
+		t.Fatalf("MerchantEditOrder() error = %v", err)
+	}
+	if resp.RefundAmount == 0 || resp.RefundStatus != consts.RefundPending || edit.Audit.RefundStatus != consts.RefundPending {
+		t.Errorf("MerchantEditOrder() = refund %d %s, want pending", resp.RefundAmount, resp.RefundStatus)
+	}
+}
+
+func TestOrderServiceImpl_RetryPendingRefunds(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderEditDao := daoMocks.NewMockOrderEditDao(ctrl)
+	mockOrderDao := daoMocks.NewMockOrderDao(ctrl)
+	mockKafkaWriter := utilMocks.NewMockWriter(ctrl)
+	mockLocker := utilMocks.NewMockLocker(ctrl)
+	ctx := context.Background()
+
+	mockLocker.EXPECT().Lock(ctx).Return(nil)
+	mockLocker.EXPECT().Unlock(ctx).Return(nil)
+	pending := []*model.OrderAuditLog{
+		{ID: 5, OrderNo: "ORD1", RefundAmount: 100, RefundStatus: consts.RefundPending},
+		{ID: 6, OrderNo: "ORD2", RefundAmount: 200, RefundStatus: consts.RefundPending},
+		{ID: 7, OrderNo: "ORD3", RefundAmount: 300, RefundStatus: consts.RefundPending},
+	}
+	mockOrderEditDao.EXPECT().GetPendingRefunds(ctx, gomock.Any(), refundRetryBatchSize).Return(pending, nil)
+	mockOrderDao.EXPECT().GetByOrderNos(ctx, []string{"ORD1", "ORD2", "ORD3"}).Return([]*model.Order{
+		{OrderNo: "ORD1", CheckoutNo: "CHK1", UserID: 1},
+		{OrderNo: "ORD2", CheckoutNo: "CHK2", UserID: 2},
+	}, nil)
+	mockKafkaWriter.EXPECT().SendMsgSync(ctx, consts.TopicOrderRefunded, "ORD1", gomock.Any()).Return(nil)
+	mockOrderEditDao.EXPECT().MarkRefundSent(ctx, 5).Return(nil)
+	// 发送失败的退款保持待发送，订单不存在的退款跳过
+	mockKafkaWriter.EXPECT().SendMsgSync(ctx, consts.TopicOrderRefunded, "ORD2", gomock.Any()).Return(errors.New("broker down"))
+
+	service := &OrderServiceImpl{orderDao: mockOrderDao, orderEditDao: mockOrderEditDao, messageWriter: mockKafkaWriter, refundRetryLocker: mockLocker}
+	service.RetryPendingRefunds(ctx)
+
+	if pending[0].RefundStatus != consts.RefundSent || pending[1].RefundStatus != consts.RefundPending {
+		t.Errorf("unexpected refund status: %s, %s", pending[0].RefundStatus, pending[1].RefundStatus)
+	}
+}
+
+func TestOrderServiceImpl_RetryPendingRefunds_LockHeld(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLocker := utilMocks.NewMockLocker(ctrl)
+	ctx := context.Background()
+
+	// 其他实例正在补发，本轮不查询也不发送
+	mockLocker.EXPECT().Lock(ctx).Return(errors.New("lock held"))
+	service := &OrderServiceImpl{orderEditDao: daoMocks.NewMockOrderEditDao(ctrl), refundRetryLocker: mockLocker}
+	service.RetryPendingRefunds(ctx)
+}
+
+func TestOrderEditPlan_LegacyAddress(t *testing.T) {
+	// 结构化地址之前的未支付订单，修改地址不退款
+	order := &model.Order{OrderNo: "ORD1", MerchantID: 7, Status: consts.CREATED, ReceiverAddress: "1 Orchard Road", ReceiverCountry: "SG", ReceiverZipCode: 238823}
+	plan := &orderEditPlan{req: types.EditOrderRequest{
+		ShippingAddress: &types.Address{Line1: "1  Orchard Road", Line2: "#12-01", City: "Singapore", PostalCode: "238823", Country: "sg"},
+		Reason:          "unit number missing",
+	}, merchantID: 7}
+	edit, err := plan.edit(order, editProducts())
+	if err != nil {
+		t.Fatalf("edit() error = %v", err)
+	}
+
+	if plan.refund != 0 || len(plan.changes) != 2 || plan.changes[0].Field != "shipping_address.line2" || plan.changes[1].Field != "shipping_address.city" {
+		t.Errorf("unexpected changes: %+v", plan.changes)
+	}
+	// 未变化的街道也写入快照列
+	if edit.Updates["receiver_line1"] != "1 Orchard Road" || edit.Updates["receiver_line2"] != "#12-01" ||
+		edit.Updates["receiver_address"] != "1 Orchard Road, #12-01" || edit.Updates["receiver_zip_code"] != 238823 {
+		t.Errorf("unexpected updates: %v", edit.Updates)
+	}
+}
+
+func TestOrderServiceImpl_MerchantEditOrder_Rejected(t *testing.T) {
+	ctx := context.Background()
+	phone := "+6591234567"
+	tests := []struct {
+		name       string
+		status     int
+		merchantID int
+		req        types.EditOrderRequest
+		want       error
+	}{
+		{"shipped", consts.SHIPPED, 7, types.EditOrderRequest{RemoveItemIDs: []int{12}}, ErrOrderNotEditable},
+		{"partially shipped", consts.PARTIALLY_SHIPPED, 7, types.EditOrderRequest{RemoveItemIDs: []int{12}}, ErrOrderNotEditable},
+		{"other merchant", consts.PAYED, 8, types.EditOrderRequest{RemoveItemIDs: []int{12}}, ErrOrderNotFound},
+		{"remove every item", consts.PAYED, 7, types.EditOrderRequest{RemoveItemIDs: []int{11, 12}}, ErrInvalidOrderEdit},
+		{"item not in order", consts.PAYED, 7, types.EditOrderRequest{RemoveItemIDs: []int{12, 13}}, ErrInvalidOrderEdit},
+		{"nothing changed", consts.PAYED, 7, types.EditOrderRequest{ReceiverPhone: &phone}, ErrInvalidOrderEdit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			order := editOrder()
+			order.Status = tt.status
+			var edit *dao.OrderEdit
+			mockOrderEditDao := daoMocks.NewMockOrderEditDao(ctrl)
+			mockOrderEditDao.EXPECT().Edit(ctx, "ORD1", gomock.Any()).DoAndReturn(runEditor(order, &edit))
+
+			// 修改被拒绝时不归还库存、不发消息
+			service := &OrderServiceImpl{orderEditDao: mockOrderEditDao}
+			_, err := service.MerchantEditOrder(ctx, "ORD1", tt.merchantID, 70, tt.req)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("MerchantEditOrder() err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrderServiceImpl_MerchantEditOrder_DuplicateItems(t *testing.T) {
+	// 重复的商品在锁定订单前就被拒绝
+	service := &OrderServiceImpl{}
+	_, err := service.MerchantEditOrder(context.Background(), "ORD1", 7, 70, types.EditOrderRequest{RemoveItemIDs: []int{12, 11, 12}})
+	if !errors.Is(err, ErrInvalidOrderEdit) {
+		t.Errorf("MerchantEditOrder() err = %v, want %v", err, ErrInvalidOrderEdit)
+	}
+}
@@ -0,0 +1,502 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/export"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/consts"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/errs"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/types"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/dao"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/model"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type OrderExportService interface {
+	ExportOrders(ctx context.Context, req types.ExportOrderRequest, w io.Writer) (rows int, err error)
+	CreateExportJob(ctx context.Context, req types.ExportOrderRequest, userID int) (job *types.ExportJobInfo, err error)
+	GetExportJob(ctx context.Context, jobID string, userID int) (job *types.ExportJobInfo, err error)
+	OpenExportFile(ctx context.Context, jobID string, userID int) (file io.ReadSeekCloser, job *types.ExportJobInfo, err error)
+}
+
+var (
+	ErrExportTooLarge    = errs.New(errs.CodeExportTooLarge, "too many orders to export synchronously, use async export")
+	ErrExportJobNotFound = errs.New(errs.CodeExportJobNotFound, "export job not found")
+	ErrExportNotReady    = errs.New(errs.CodeExportNotReady, "export job has not finished")
+	ErrExportExpired     = errs.New(errs.CodeExportExpired, "export file has expired")
+)
+
+const (
+	exportTimeLayout       = "2006-01-02 15:04:05"
+	exportCleanupInterval  = 10 * time.Minute
+	exportCleanupBatchSize = 100
+
+	// exportStaleGrace 超时的任务自己记录失败所需的时间，之后仍未完成的任务视为已中断
+	exportStaleGrace = time.Minute
+)
+
+// ExportOptions tunes the export service; zero values fall back to defaults.
+type ExportOptions struct {
+	BatchSize   int           // 每批读取的订单数
+	MaxSyncRows int           // 同步导出的订单数上限，0 表示不限制
+	Workers     int           // 同时运行的异步任务数
+	Retention   time.Duration // 异步导出文件保留时长
+	JobTimeout  time.Duration // 异步任务从创建到完成的最长时间，含排队时间
+}
+
+var exportHeader = []string{
+	"order_no", "user_id", "status", "create_time", "pay_time", "delivery_time", "confirm_time",
+	"receiver_first_name", "receiver_last_name", "receiver_phone", "receiver_address", "receiver_country", "receiver_zip_code",
+	"total_amount", "pay_amount", "shipping_fee", "tax", "carrier", "logistics_no", "remark",
+	"product_id", "product_name", "price", "quantity", "item_total",
+}
+
+// OrderExportServiceImpl writes merchant order exports. Small exports are
+// streamed to the request; async jobs run in the background and leave the
+// file in the FileStore until it expires.
+type OrderExportServiceImpl struct {
+	orderDao        dao.OrderDao
+	orderProductDao dao.OrderProductDao
+	exportJobDao    dao.OrderExportJobDao
+	store           export.FileStore
+	opts            ExportOptions
+
+	workers chan struct{}
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+func NewOrderExportService(
+	orderDao dao.OrderDao,
+	orderProductDao dao.OrderProductDao,
+	exportJobDao dao.OrderExportJobDao,
+	store export.FileStore,
+	opts ExportOptions,
+) *OrderExportServiceImpl {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 500
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = 2
+	}
+	if opts.Retention <= 0 {
+		opts.Retention = 24 * time.Hour
+	}
+	if opts.JobTimeout <= 0 {
+		opts.JobTimeout = time.Hour
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &OrderExportServiceImpl{
+		orderDao:        orderDao,
+		orderProductDao: orderProductDao,
+		exportJobDao:    exportJobDao,
+		store:           store,
+		opts:            opts,
+		workers:         make(chan struct{}, opts.Workers),
+		ctx:             ctx,
+		cancel:          cancel,
+	}
+}
+
+// Start fails the jobs left unfinished by a crashed instance and runs the
+// cleanup of expired export files until Stop is called.
+func (s *OrderExportServiceImpl) Start() {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.failStaleJobs(s.ctx)
+		ticker := time.NewTicker(exportCleanupInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.failStaleJobs(s.ctx)
+				s.cleanupExpired(s.ctx)
+			case <-s.ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop cancels the running jobs and waits for them to record their failure.
+func (s *OrderExportServiceImpl) Stop(ctx context.Context) error {
+	s.cancel()
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// ExportOrders 同步导出：按筛选条件逐批读取订单及商品写入 w，不会一次加载全部结果
+func (s *OrderExportServiceImpl) ExportOrders(ctx context.Context, req types.ExportOrderRequest, w io.Writer) (rows int, err error) {
+	logger := log.FromContext(ctx)
+	format, query, err := s.buildExportQuery(req)
+	if err != nil {
+		return 0, err
+	}
+
+	if s.opts.MaxSyncRows > 0 {
+		countQuery := query
+		countQuery.Limit = 1
+		countQuery.CountMode = dao.CountModeExact
+		_, total, err := s.orderDao.GetByOrderQuery(ctx, countQuery)
+		if err != nil {
+			logger.Errorf("ExportOrders: count orders failed, err: %s", err.Error())
+			return 0, err
+		}
+		if total > int64(s.opts.MaxSyncRows) {
+			return 0, ErrExportTooLarge
+		}
+	}
+
+	rows, err = s.writeOrders(ctx, query, format, w)
+	if err != nil {
+		logger.Errorf("ExportOrders: write export failed after %d rows, err: %s", rows, err.Error())
+	}
+	return rows, err
+}
+
+// CreateExportJob 创建异步导出任务，任务在后台执行
+func (s *OrderExportServiceImpl) CreateExportJob(ctx context.Context, req types.ExportOrderRequest, userID int) (job *types.ExportJobInfo, err error) {
+	logger := log.FromContext(ctx).With("user_id", userID)
+	format, query, err := s.buildExportQuery(req)
+	if err != nil {
+		return nil, err
+	}
+	filters, err := json.Marshal(req.ListOrderRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	jobID := uuid.New().String()
+	jobModel := &model.OrderExportJob{
+		JobID:      jobID,
+		UserID:     userID,
+		MerchantID: req.MerchantID,
+		Format:     format,
+		Status:     consts.ExportJobPending,
+		Filters:    string(filters),
+		FileName:   fmt.Sprintf("orders-%s.%s", jobID, format),
+		CreateTime: time.Now(),
+	}
+	if _, err = s.exportJobDao.Create(ctx, jobModel); err != nil {
+		logger.Errorf("CreateExportJob: create job failed, err: %s", err.Error())
+		return nil, err
+	}
+
+	s.wg.Add(1)
+	go s.runJob(jobModel, query)
+
+	logger.Infof("CreateExportJob: job %s created", jobID)
+	return toExportJobInfo(jobModel), nil
+}
+
+// GetExportJob 查询导出任务状态，只能查询自己创建的任务
+func (s *OrderExportServiceImpl) GetExportJob(ctx context.Context, jobID string, userID int) (job *types.ExportJobInfo, err error) {
+	jobModel, err := s.getJob(ctx, jobID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return toExportJobInfo(jobModel), nil
+}
+
+// OpenExportFile 打开已完成任务的导出文件，调用方负责关闭
+func (s *OrderExportServiceImpl) OpenExportFile(ctx context.Context, jobID string, userID int) (file io.ReadSeekCloser, job *types.ExportJobInfo, err error) {
+	jobModel, err := s.getJob(ctx, jobID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch {
+	case jobModel.Status == consts.ExportJobExpired,
+		jobModel.Status == consts.ExportJobSucceeded && !jobModel.ExpireTime.After(time.Now()):
+		return nil, nil, ErrExportExpired
+	case jobModel.Status != consts.ExportJobSucceeded:
+		return nil, nil, ErrExportNotReady
+	}
+
+	file, err = s.store.Open(jobModel.FileName)
+	if errors.Is(err, export.ErrFileNotFound) {
+		return nil, nil, ErrExportExpired
+	}
+	if err != nil {
+		log.FromContext(ctx).Errorf("OpenExportFile: open %s failed, err: %s", jobModel.FileName, err.Error())
+		return nil, nil, err
+	}
+	return file, toExportJobInfo(jobModel), nil
+}
+
+func (s *OrderExportServiceImpl) getJob(ctx context.Context, jobID string, userID int) (*model.OrderExportJob, error) {
+	jobModel, err := s.exportJobDao.GetByJobID(ctx, jobID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrExportJobNotFound
+	}
+	if err != nil {
+		log.FromContext(ctx).Errorf("getJob: query job %s failed, err: %s", jobID, err.Error())
+		return nil, err
+	}
+	// 他人的任务按不存在处理
+	if jobModel.UserID != userID {
+		return nil, ErrExportJobNotFound
+	}
+	return jobModel, nil
+}
+
+// buildExportQuery 校验格式并构建查询条件，导出总是从头读取全部结果
+func (s *OrderExportServiceImpl) buildExportQuery(req types.ExportOrderRequest) (string, dao.OrderQuery, error) {
+	format := req.Format
+	if format == "" {
+		format = export.FormatCSV
+	}
+	if !export.IsFormat(format) {
+		return "", dao.OrderQuery{}, export.ErrUnsupportedFormat
+	}
+
+	listReq := req.ListOrderRequest
+	listReq.Cursor = ""
+	listReq.Offset = 0
+	query, err := buildOrderQuery(listReq)
+	if err != nil {
+		return "", query, err
+	}
+	query.Limit = s.opts.BatchSize
+	query.CountMode = dao.CountModeNone
+	return format, query, nil
+}
+
+// runJob 执行异步任务，排队和导出总共不超过 JobTimeout，超时的任务可由 failStaleJobs 判定为已中断
+func (s *OrderExportServiceImpl) runJob(job *model.OrderExportJob, query dao.OrderQuery) {
+	defer s.wg.Done()
+	logger := log.Logger.With("job_id", job.JobID)
+	// 任务状态在停机时也要写入
+	recordCtx := context.WithoutCancel(s.ctx)
+	ctx, cancel := context.WithDeadline(s.ctx, job.CreateTime.Add(s.opts.JobTimeout))
+	defer cancel()
+
+	select {
+	case s.workers <- struct{}{}:
+		defer func() { <-s.workers }()
+	case <-ctx.Done():
+		s.failJob(recordCtx, job.JobID, ctx.Err())
+		return
+	}
+
+	if err := s.exportJobDao.MarkRunning(recordCtx, job.JobID); err != nil {
+		logger.Errorf("runJob: mark running failed, err: %s", err.Error())
+	}
+
+	rows, err := s.writeJobFile(ctx, job, query)
+	if err != nil {
+		logger.Errorf("runJob: export failed after %d rows, err: %s", rows, err.Error())
+		if removeErr := s.store.Remove(job.FileName); removeErr != nil {
+			logger.Errorf("runJob: remove %s failed, err: %s", job.FileName, removeErr.Error())
+		}
+		s.failJob(recordCtx, job.JobID, err)
+		return
+	}
+
+	now := time.Now()
+	if err = s.exportJobDao.MarkSucceeded(recordCtx, job.JobID, rows, now, now.Add(s.opts.Retention)); err != nil {
+		logger.Errorf("runJob: mark succeeded failed, err: %s", err.Error())
+		return
+	}
+	logger.Infof("runJob: exported %d rows to %s", rows, job.FileName)
+}
+
+func (s *OrderExportServiceImpl) writeJobFile(ctx context.Context, job *model.OrderExportJob, query dao.OrderQuery) (rows int, err error) {
+	file, err := s.store.Create(job.FileName)
+	if err != nil {
+		return 0, err
+	}
+	rows, err = s.writeOrders(ctx, query, job.Format, file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return rows, err
+}
+
+func (s *OrderExportServiceImpl) failJob(ctx context.Context, jobID string, cause error) {
+	if err := s.exportJobDao.MarkFailed(ctx, jobID, cause.Error(), time.Now()); err != nil {
+		log.Logger.Errorf("failJob: mark job %s failed failed, err: %s", jobID, err.Error())
+	}
+}
+
+// writeOrders 逐批读取订单写入 w，每个订单商品一行，返回写入的数据行数
+func (s *OrderExportServiceImpl) writeOrders(ctx context.Context, query dao.OrderQuery, format string, w io.Writer) (rows int, err error) {
+	rw, err := export.NewRowWriter(format, w)
+	if err != nil {
+		return 0, err
+	}
+	if err = rw.WriteRow(exportHeader); err != nil {
+		return 0, err
+	}
+
+	for {
+		if err = ctx.Err(); err != nil {
+			return rows, err
+		}
+		orders, _, err := s.orderDao.GetByOrderQuery(ctx, query)
+		if err != nil {
+			return rows, err
+		}
+		if len(orders) == 0 {
+			break
+		}
+
+		orderNos := make([]string, len(orders))
+		for i, order := range orders {
+			orderNos[i] = order.OrderNo
+		}
+		products, err := s.orderProductDao.GetByOrderNos(ctx, orderNos)
+		if err != nil {
+			return rows, err
+		}
+		productsByOrder := make(map[string][]*model.OrderProduct, len(orders))
+		for _, product := range products {
+			productsByOrder[product.OrderNo] = append(productsByOrder[product.OrderNo], product)
+		}
+
+		for _, order := range orders {
+			for _, row := range orderExportRows(order, productsByOrder[order.OrderNo]) {
+				if err = rw.WriteRow(row); err != nil {
+					return rows, err
+				}
+				rows++
+			}
+		}
+
+		if len(orders) < query.Limit {
+			break
+		}
+		// 可用游标时按游标翻页，否则退化为偏移分页
+		last := orders[len(orders)-1]
+		if dao.SupportsCursor(query.SortBy) {
+			query.After = &dao.OrderCursor{
+				Value: orderCursorValue(query.SortBy, orderSortValue(last, query.SortBy)),
+				ID:    last.ID,
+			}
+		} else {
+			query.Offset += len(orders)
+		}
+	}
+	return rows, rw.Close()
+}
+
+// exportAddress 地址列包含街道、城市和州/省，国家和邮政编码另有单独的列
+func exportAddress(addr types.Address) string {
+	return types.Address{Line1: addr.Line1, Line2: addr.Line2, City: addr.City, State: addr.State}.String()
+}
+
+// orderExportRows 订单的每个商品一行；没有商品的订单也输出一行，商品列留空
+func orderExportRows(order *model.Order, products []*model.OrderProduct) [][]string {
+	address := orderAddress(order)
+	orderCols := []string{
+		order.OrderNo,
+		strconv.Itoa(order.UserID),
+		getOrderStatusName(order.Status),
+		formatExportTime(order.CreateTime),
+		formatExportTime(order.PayTime),
+		formatExportTime(order.DeliveryTime),
+		formatExportTime(order.ConfirmTime),
+		order.ReceiverFirstName,
+		order.ReceiverLastName,
+		order.ReceiverPhone,
+		exportAddress(address),
+		address.Country,
+		address.PostalCode,
+		strconv.Itoa(order.TotalAmount),
+		strconv.Itoa(order.PayAmount),
+		strconv.Itoa(order.ShippingFee),
+		strconv.Itoa(order.Tax),
+		order.Carrier,
+		order.LogisticsNo,
+		order.Remark,
+	}
+	if len(products) == 0 {
+		return [][]string{append(orderCols, "", "", "", "", "")}
+	}
+
+	rows := make([][]string, 0, len(products))
+	for _, product := range products {
+		row := make([]string, 0, len(exportHeader))
+		row = append(row, orderCols...)
+		row = append(row,
+			strconv.Itoa(product.ProductID),
+			product.ProductName,
+			strconv.Itoa(product.Price),
+			strconv.Itoa(product.Quantity),
+			strconv.Itoa(product.TotalPrice),
+		)
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func formatExportTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(exportTimeLayout)
+}
+
+// failStaleJobs 将超过 JobTimeout 仍未完成的任务标记为失败。正常运行的任务到时会自己记录失败，
+// 剩下的是实例崩溃时留下的任务，用户需要重新导出
+func (s *OrderExportServiceImpl) failStaleJobs(ctx context.Context) {
+	now := time.Now()
+	count, err := s.exportJobDao.FailStale(ctx, now.Add(-s.opts.JobTimeout-exportStaleGrace), "export job was interrupted, please export again", now)
+	if err != nil {
+		log.Logger.Errorf("failStaleJobs: fail stale jobs failed, err: %s", err.Error())
+		return
+	}
+	if count > 0 {
+		log.Logger.Warnf("failStaleJobs: %d interrupted jobs marked failed", count)
+	}
+}
+
+// cleanupExpired 删除过期的导出文件
+func (s *OrderExportServiceImpl) cleanupExpired(ctx context.Context) {
+	jobs, err := s.exportJobDao.GetExpired(ctx, time.Now(), exportCleanupBatchSize)
+	if err != nil {
+		log.Logger.Errorf("cleanupExpired: query expired jobs failed, err: %s", err.Error())
+		return
+	}
+	for _, job := range jobs {
+		if err = s.store.Remove(job.FileName); err != nil {
+			log.Logger.Errorf("cleanupExpired: remove %s failed, err: %s", job.FileName, err.Error())
+			continue
+		}
+		if err = s.exportJobDao.MarkExpired(ctx, job.JobID); err != nil {
+			log.Logger.Errorf("cleanupExpired: mark job %s expired failed, err: %s", job.JobID, err.Error())
+		}
+	}
+}
+
+func toExportJobInfo(job *model.OrderExportJob) *types.ExportJobInfo {
+	return &types.ExportJobInfo{
+		JobID:      job.JobID,
+		Format:     job.Format,
+		Status:     job.Status,
+		RowCount:   job.RowCount,
+		Error:      job.ErrorMsg,
+		CreateTime: job.CreateTime,
+		FinishTime: job.FinishTime,
+		ExpireTime: job.ExpireTime,
+	}
+}
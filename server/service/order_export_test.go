@@ -0,0 +1,231 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/export"
+	exportMocks "github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/export/mocks"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/consts"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/types"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/dao"
+	daoMocks "github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/dao/mocks"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/model"
+	"github.com/golang/mock/gomock"
+	"gorm.io/gorm"
+)
+
+type nopWriteCloser struct{ *bytes.Buffer }
+
+func (nopWriteCloser) Close() error { return nil }
+
+func readExportCSV(t *testing.T, data []byte) [][]string {
+	t.Helper()
+	rows, err := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xEF\xBB\xBF")))).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid csv: %v", err)
+	}
+	return rows
+}
+
+func TestOrderExportServiceImpl_ExportOrders_Batches(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderDao := daoMocks.NewMockOrderDao(ctrl)
+	mockOrderProductDao := daoMocks.NewMockOrderProductDao(ctrl)
+
+	ctx := context.Background()
+	now := time.Now()
+	first := []*model.Order{
+		{ID: 3, OrderNo: "ORD3", Status: consts.PAYED, CreateTime: now},
+		{ID: 2, OrderNo: "ORD2", Status: consts.SHIPPED, CreateTime: now.Add(-time.Hour)},
+	}
+	second := []*model.Order{
+		{ID: 1, OrderNo: "ORD1", Status: consts.CREATED, CreateTime: now.Add(-2 * time.Hour)},
+	}
+
+	gomock.InOrder(
+		mockOrderDao.EXPECT().GetByOrderQuery(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, query dao.OrderQuery) ([]*model.Order, int64, error) {
+				if query.Limit != 2 || query.CountMode != dao.CountModeNone || query.After != nil {
+					t.Errorf("unexpected first batch query: %+v", query)
+				}
+				if query.ReceiverCountry != "SG" {
+					t.Errorf("filters not passed: %+v", query)
+				}
+				return first, 0, nil
+			}),
+		mockOrderProductDao.EXPECT().GetByOrderNos(ctx, []string{"ORD3", "ORD2"}).Return([]*model.OrderProduct{
+			{OrderNo: "ORD3", ProductID: 10, ProductName: "Mug", Price: 5, Quantity: 2, TotalPrice: 10},
+			{OrderNo: "ORD3", ProductID: 11, ProductName: "Plate", Price: 8, Quantity: 1, TotalPrice: 8},
+			{OrderNo: "ORD2", ProductID: 10, ProductName: "Mug", Price: 5, Quantity: 1, TotalPrice: 5},
+		}, nil),
+		mockOrderDao.EXPECT().GetByOrderQuery(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, query dao.OrderQuery) ([]*model.Order, int64, error) {
+				if query.After == nil || query.After.ID != 2 {
+					t.Errorf("expected cursor after order 2, got %+v", query.After)
+				}
+				return second, 0, nil
+			}),
+		mockOrderProductDao.EXPECT().GetByOrderNos(ctx, []string{"ORD1"}).Return(nil, nil),
+	)
+
+	svc := NewOrderExportService(mockOrderDao, mockOrderProductDao, nil, nil, ExportOptions{BatchSize: 2})
+	var buf bytes.Buffer
+	rows, err := svc.ExportOrders(ctx, types.ExportOrderRequest{
+		ListOrderRequest: types.ListOrderRequest{ReceiverCountry: "SG"},
+	}, &buf)
+	if err != nil {
+		t.Fatalf("ExportOrders() error = %v", err)
+	}
+	if rows != 4 {
+		t.Errorf("expected 4 rows, got %d", rows)
+	}
+
+	records := readExportCSV(t, buf.Bytes())
+	if len(records) != 5 {
+		t.Fatalf("expected header and 4 rows, got %d", len(records))
+	}
+	if records[1][0] != "ORD3" || records[1][21] != "Mug" || records[2][21] != "Plate" {
+		t.Errorf("unexpected line items: %v", records[1:3])
+	}
+	if records[4][0] != "ORD1" || records[4][2] != "Created" || records[4][21] != "" {
+		t.Errorf("expected order without items to have empty item columns, got %v", records[4])
+	}
+}
+
+func TestOrderExportServiceImpl_ExportOrders_TooLarge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderDao := daoMocks.NewMockOrderDao(ctrl)
+	mockOrderProductDao := daoMocks.NewMockOrderProductDao(ctrl)
+
+	ctx := context.Background()
+	mockOrderDao.EXPECT().GetByOrderQuery(ctx, gomock.Any()).Return(nil, int64(101), nil)
+
+	svc := NewOrderExportService(mockOrderDao, mockOrderProductDao, nil, nil, ExportOptions{MaxSyncRows: 100})
+	var buf bytes.Buffer
+	_, err := svc.ExportOrders(ctx, types.ExportOrderRequest{}, &buf)
+	if !errors.Is(err, ErrExportTooLarge) {
+		t.Fatalf("expected ErrExportTooLarge, got %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected nothing written, got %d bytes", buf.Len())
+	}
+}
+
+func TestOrderExportServiceImpl_ExportOrders_InvalidFormat(t *testing.T) {
+	svc := NewOrderExportService(nil, nil, nil, nil, ExportOptions{})
+	_, err := svc.ExportOrders(context.Background(), types.ExportOrderRequest{Format: "pdf"}, &bytes.Buffer{})
+	if !errors.Is(err, export.ErrUnsupportedFormat) {
+		t.Fatalf("expected ErrUnsupportedFormat, got %v", err)
+	}
+}
+
+func TestOrderExportServiceImpl_CreateExportJob(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderDao := daoMocks.NewMockOrderDao(ctrl)
+	mockOrderProductDao := daoMocks.NewMockOrderProductDao(ctrl)
+	mockJobDao := daoMocks.NewMockOrderExportJobDao(ctrl)
+	mockStore := exportMocks.NewMockFileStore(ctrl)
+
+	ctx := context.Background()
+	var file bytes.Buffer
+	var jobID string
+	mockJobDao.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, job *model.OrderExportJob) (int, error) {
+		if job.UserID != 7 || job.Format != export.FormatCSV || job.Status != consts.ExportJobPending {
+			t.Errorf("unexpected job: %+v", job)
+		}
+		jobID = job.JobID
+		return 1, nil
+	})
+	mockJobDao.EXPECT().MarkRunning(gomock.Any(), gomock.Any()).Return(nil)
+	mockStore.EXPECT().Create(gomock.Any()).Return(nopWriteCloser{&file}, nil)
+	mockOrderDao.EXPECT().GetByOrderQuery(gomock.Any(), gomock.Any()).Return([]*model.Order{{ID: 1, OrderNo: "ORD1"}}, int64(0), nil)
+	mockOrderProductDao.EXPECT().GetByOrderNos(gomock.Any(), []string{"ORD1"}).Return(nil, nil)
+	mockJobDao.EXPECT().MarkSucceeded(gomock.Any(), gomock.Any(), 1, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, id string, _ int, finishTime, expireTime time.Time) error {
+			if id != jobID {
+				t.Errorf("expected job %s, got %s", jobID, id)
+			}
+			if expireTime.Sub(finishTime) != time.Hour {
+				t.Errorf("expected 1h retention, got %v", expireTime.Sub(finishTime))
+			}
+			return nil
+		})
+
+	svc := NewOrderExportService(mockOrderDao, mockOrderProductDao, mockJobDao, mockStore, ExportOptions{Retention: time.Hour})
+	job, err := svc.CreateExportJob(ctx, types.ExportOrderRequest{Async: true}, 7)
+	if err != nil {
+		t.Fatalf("CreateExportJob() error = %v", err)
+	}
+	if job.JobID != jobID || job.Status != consts.ExportJobPending {
+		t.Errorf("unexpected job info: %+v", job)
+	}
+	svc.wg.Wait()
+
+	if records := readExportCSV(t, file.Bytes()); len(records) != 2 || records[1][0] != "ORD1" {
+		t.Errorf("unexpected export file: %v", records)
+	}
+}
+
+func TestOrderExportServiceImpl_StartFailsStaleJobs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockJobDao := daoMocks.NewMockOrderExportJobDao(ctrl)
+	done := make(chan struct{})
+	// 启动时把超时仍未完成的任务标记为失败
+	mockJobDao.EXPECT().FailStale(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, createdBefore time.Time, _ string, _ time.Time) (int64, error) {
+			defer close(done)
+			if age := time.Since(createdBefore); age < 2*time.Hour || age > 2*time.Hour+exportStaleGrace+time.Second {
+				t.Errorf("unexpected stale cutoff: %v ago", age)
+			}
+			return 1, nil
+		})
+
+	svc := NewOrderExportService(nil, nil, mockJobDao, nil, ExportOptions{JobTimeout: 2 * time.Hour})
+	svc.Start()
+	<-done
+	if err := svc.Stop(context.Background()); err != nil {
+		t.Errorf("Stop() error = %v", err)
+	}
+}
+
+func TestOrderExportServiceImpl_OpenExportFile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockJobDao := daoMocks.NewMockOrderExportJobDao(ctrl)
+	mockStore := exportMocks.NewMockFileStore(ctrl)
+	ctx := context.Background()
+
+	mockJobDao.EXPECT().GetByJobID(ctx, "running").Return(&model.OrderExportJob{JobID: "running", UserID: 7, Status: consts.ExportJobRunning}, nil)
+	mockJobDao.EXPECT().GetByJobID(ctx, "expired").Return(&model.OrderExportJob{
+		JobID: "expired", UserID: 7, Status: consts.ExportJobSucceeded, ExpireTime: time.Now().Add(-time.Minute),
+	}, nil)
+	mockJobDao.EXPECT().GetByJobID(ctx, "other").Return(&model.OrderExportJob{JobID: "other", UserID: 8, Status: consts.ExportJobSucceeded}, nil)
+	mockJobDao.EXPECT().GetByJobID(ctx, "missing").Return(nil, gorm.ErrRecordNotFound)
+
+	svc := NewOrderExportService(nil, nil, mockJobDao, mockStore, ExportOptions{})
+	cases := map[string]error{
+		"running": ErrExportNotReady,
+		"expired": ErrExportExpired,
+		"other":   ErrExportJobNotFound,
+		"missing": ErrExportJobNotFound,
+	}
+	for jobID, want := range cases {
+		if _, _, err := svc.OpenExportFile(ctx, jobID, 7); !errors.Is(err, want) {
+			t.Errorf("OpenExportFile(%s) error = %v, want %v", jobID, err, want)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/common/productpb"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/consts"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/types"
+)
+
+// 再次购买时商品不可购买或数量减少的原因
+const (
+	ReorderReasonUnavailable       = "unavailable"        // 商品已下架
+	ReorderReasonOutOfStock        = "out_of_stock"       // 无库存
+	ReorderReasonInsufficientStock = "insufficient_stock" // 库存少于原数量，按库存购买
+)
+
+// CustomerListOrders 用户查询自己的订单，每个订单附带商品件数和缩略图
+func (o *OrderServiceImpl) CustomerListOrders(ctx context.Context, userID int, req types.CustomerListOrderRequest) (resp *types.ListOrderResponse, err error) {
+	resp, err = o.ListOrders(ctx, types.ListOrderRequest{
+		UserID:      userID,
+		Statuses:    req.Statuses,
+		ProductName: req.ProductName,
+		StartTime:   req.StartTime,
+		EndTime:     req.EndTime,
+		Limit:       req.Limit,
+		Offset:      req.Offset,
+		Cursor:      req.Cursor,
+		CountMode:   req.CountMode,
+	})
+	if err != nil || len(resp.Orders) == 0 {
+		return resp, err
+	}
+
+	orderNos := make([]string, len(resp.Orders))
+	byOrderNo := make(map[string]*types.OrderInfoInList, len(resp.Orders))
+	for idx, order := range resp.Orders {
+		orderNos[idx] = order.OrderNo
+		byOrderNo[order.OrderNo] = order
+	}
+	products, err := o.orderProductDao.GetByOrderNos(ctx, orderNos)
+	if err != nil {
+		log.FromContext(ctx).With("user_id", userID).Errorf("CustomerListOrders: get order products failed, err: %s", err.Error())
+		return nil, err
+	}
+	// 商品按 id 升序返回，第一个有图片的商品作为缩略图
+	for _, product := range products {
+		order, ok := byOrderNo[product.OrderNo]
+		if !ok {
+			continue
+		}
+		order.ItemCount += product.Quantity
+		if order.Thumbnail == "" {
+			order.Thumbnail = o.catalogImage(product.ImageURL)
+		}
+	}
+	return resp, nil
+}
+
+// CustomerReorder 按当前价格和库存重新生成原订单的购物车。下架或无库存的商品标记为不可购买，
+// 库存不足的商品按库存数量购买；运费和税费与下单时一样按商家分别计算。
+func (o *OrderServiceImpl) CustomerReorder(ctx context.Context, orderNo string, userID int) (quote *types.ReorderQuote, err error) {
+	logger := log.FromContext(ctx).With("order_no", orderNo, "user_id", userID)
+	order, err := o.orderDao.GetByOrderNo(ctx, orderNo)
+	if err != nil {
+		return nil, orderDaoErr(err)
+	}
+	if order.UserID != userID {
+		logger.Errorf("CustomerReorder: Invalid userID, err %s", ErrOrderForbidden.Error())
+		return nil, ErrOrderForbidden
+	}
+	orderProducts, err := o.orderProductDao.GetByOrderNo(ctx, orderNo)
+	if err != nil {
+		logger.Errorf("CustomerReorder: get order products failed, err: %s", err.Error())
+		return nil, err
+	}
+
+	ids := make([]int64, len(orderProducts))
+	for idx, product := range orderProducts {
+		ids[idx] = int64(product.ProductID)
+	}
+	productList, err := o.productServiceClient.GetProductList(ctx, &productpb.GetProductListRequest{Ids: ids})
+	if err != nil {
+		logger.Errorf("CustomerReorder: get product list failed, err: %s", err.Error())
+		return nil, ErrProductService.Wrap(err)
+	}
+	current := make(map[int]*productpb.Product, len(productList.Products))
+	for _, product := range productList.Products {
+		current[int(product.Id)] = product
+	}
+
+	quote = &types.ReorderQuote{
+		SourceOrderNo: orderNo,
+		Items:         make([]*types.ReorderItem, 0, len(orderProducts)),
+		OrderItemList: make([]*types.OrderItemInfo, 0, len(orderProducts)),
+	}
+	for _, orderProduct := range orderProducts {
+		merchantID := orderProduct.MerchantID
+		if merchantID == 0 {
+			merchantID = consts.DefaultMerchantID
+		}
+		item := &types.ReorderItem{
+			ProductID:     orderProduct.ProductID,
+			MerchantID:    merchantID,
+			ProductName:   orderProduct.ProductName,
+			ImageURL:      o.catalogImage(orderProduct.ImageURL),
+			OrderQuantity: orderProduct.Quantity,
+			OrderPrice:    orderProduct.Price,
+		}
+		quote.Items = append(quote.Items, item)
+
+		product, ok := current[orderProduct.ProductID]
+		switch {
+		case !ok:
+			item.Reason = ReorderReasonUnavailable
+			continue
+		case product.Stock <= 0:
+			item.Price = int(product.Price)
+			item.Reason = ReorderReasonOutOfStock
+			continue
+		case product.Stock < int64(orderProduct.Quantity):
+			item.Quantity = int(product.Stock)
+			item.Reason = ReorderReasonInsufficientStock
+		default:
+			item.Quantity = orderProduct.Quantity
+		}
+		item.Price = int(product.Price)
+		item.Available = true
+		if product.Name != "" {
+			item.ProductName = product.Name
+		}
+		quote.OrderItemList = append(quote.OrderItemList, &types.OrderItemInfo{
+			ProductID:   item.ProductID,
+			MerchantID:  item.MerchantID,
+			ProductName: item.ProductName,
+			ImageURL:    item.ImageURL,
+			Quantity:    item.Quantity,
+			Price:       item.Price,
+		})
+	}
+
+	for _, sub := range splitByMerchant(quote.OrderItemList) {
+		quote.ItemTotal += sub.itemTotal
+		quote.ShippingFee += sub.shippingFee
+		quote.Tax += sub.tax
+	}
+	quote.TotalAmount = quote.ItemTotal + quote.ShippingFee + quote.Tax
+	return quote, nil
+}
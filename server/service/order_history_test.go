@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/common/productpb"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/clients/mocks"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/consts"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/types"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/dao"
+	daoMocks "github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/dao/mocks"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/model"
+	"github.com/golang/mock/gomock"
+)
+
+func TestOrderServiceImpl_CustomerListOrders(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderDao := daoMocks.NewMockOrderDao(ctrl)
+	mockOrderProductDao := daoMocks.NewMockOrderProductDao(ctrl)
+	ctx := context.Background()
+
+	mockOrderDao.EXPECT().GetByOrderQuery(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, query dao.OrderQuery) ([]*model.Order, int64, error) {
+		if query.UserID != 123 || query.ProductName != "mug" || len(query.Statuses) != 2 {
+			t.Errorf("unexpected query: %+v", query)
+		}
+		return []*model.Order{
+			{ID: 2, OrderNo: "Or002", UserID: 123, Status: consts.SHIPPED},
+			{ID: 1, OrderNo: "Or001", UserID: 123, Status: consts.PAYED},
+		}, 2, nil
+	})
+	mockOrderProductDao.EXPECT().GetByOrderNos(ctx, []string{"Or002", "Or001"}).Return([]*model.OrderProduct{
+		{ID: 1, OrderNo: "Or001", Quantity: 2, ImageURL: "https://evil.example.net/1.png"},
+		{ID: 2, OrderNo: "Or001", Quantity: 1, ImageURL: "https://img.example.com/2.png"},
+		{ID: 3, OrderNo: "Or002", Quantity: 3},
+		{ID: 4, OrderNo: "Or002", Quantity: 1, ImageURL: "https://img.example.com/4.png"},
+	}, nil)
+
+	service := &OrderServiceImpl{orderDao: mockOrderDao, orderProductDao: mockOrderProductDao, imageHosts: []string{"img.example.com"}}
+	resp, err := service.CustomerListOrders(ctx, 123, types.CustomerListOrderRequest{
+		Statuses:    []int{consts.PAYED, consts.SHIPPED},
+		ProductName: "mug",
+		Limit:       20,
+	})
+	if err != nil {
+		t.Fatalf("CustomerListOrders() error = %v", err)
+	}
+	// 首个商品没有图片或图片不在商品目录 CDN 上时取下一个有图片的商品
+	if got := resp.Orders[0]; got.ItemCount != 4 || got.Thumbnail != "https://img.example.com/4.png" {
+		t.Errorf("Or002 = %+v", got)
+	}
+	if got := resp.Orders[1]; got.ItemCount != 3 || got.Thumbnail != "https://img.example.com/2.png" {
+		t.Errorf("Or001 = %+v", got)
+	}
+}
+
+func TestOrderServiceImpl_CustomerReorder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderDao := daoMocks.NewMockOrderDao(ctrl)
+	mockOrderProductDao := daoMocks.NewMockOrderProductDao(ctrl)
+	mockProductClient := mocks.NewMockProductServiceClient(ctrl)
+	ctx := context.Background()
+
+	mockOrderDao.EXPECT().GetByOrderNo(ctx, "Or001").Return(&model.Order{OrderNo: "Or001", UserID: 123}, nil)
+	mockOrderProductDao.EXPECT().GetByOrderNo(ctx, "Or001").Return([]*model.OrderProduct{
+		{ProductID: 1, MerchantID: 7, ProductName: "Mug", Price: 1000, Quantity: 2},
+		{ProductID: 2, MerchantID: 7, ProductName: "Bowl", Price: 2000, Quantity: 3},
+		{ProductID: 3, MerchantID: 1, ProductName: "Plate", Price: 500, Quantity: 1},
+		{ProductID: 4, MerchantID: 1, ProductName: "Vase", Price: 9000, Quantity: 1},
+	}, nil)
+	mockProductClient.EXPECT().GetProductList(ctx, &productpb.GetProductListRequest{Ids: []int64{1, 2, 3, 4}}).
+		Return(&productpb.GetProductListResponse{Products: []*productpb.Product{
+			{Id: 1, Name: "Mug v2", Price: 1200, Stock: 10},
+			{Id: 2, Price: 2000, Stock: 1},
+			{Id: 3, Price: 500, Stock: 0},
+		}}, nil)
+
+	service := &OrderServiceImpl{orderDao: mockOrderDao, orderProductDao: mockOrderProductDao, productServiceClient: mockProductClient}
+	quote, err := service.CustomerReorder(ctx, "Or001", 123)
+	if err != nil {
+		t.Fatalf("CustomerReorder() error = %v", err)
+	}
+
+	want := []struct {
+		quantity  int
+		price     int
+		available bool
+		reason    string
+	}{
+		{2, 1200, true, ""},
+		{1, 2000, true, ReorderReasonInsufficientStock},
+		{0, 500, false, ReorderReasonOutOfStock},
+		{0, 0, false, ReorderReasonUnavailable},
+	}
+	for i, w := range want {
+		item := quote.Items[i]
+		if item.Quantity != w.quantity || item.Price != w.price || item.Available != w.available || item.Reason != w.reason {
+			t.Errorf("item %d = %+v, want %+v", i, item, w)
+		}
+	}
+	if quote.Items[0].ProductName != "Mug v2" || quote.Items[0].OrderPrice != 1000 {
+		t.Errorf("item 0 should use the current name and keep the order price: %+v", quote.Items[0])
+	}
+	if len(quote.OrderItemList) != 2 {
+		t.Fatalf("got %d order items, want 2", len(quote.OrderItemList))
+	}
+	// 两件商品同属商家 7：4400 + 运费 800 + 税 396
+	if quote.ItemTotal != 4400 || quote.ShippingFee != 800 || quote.Tax != 396 || quote.TotalAmount != 5596 {
+		t.Errorf("unexpected totals: %+v", quote)
+	}
+}
+
+func TestOrderServiceImpl_CustomerReorder_Forbidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderDao := daoMocks.NewMockOrderDao(ctrl)
+	ctx := context.Background()
+	mockOrderDao.EXPECT().GetByOrderNo(ctx, "Or001").Return(&model.Order{OrderNo: "Or001", UserID: 456}, nil)
+
+	service := &OrderServiceImpl{orderDao: mockOrderDao}
+	if _, err := service.CustomerReorder(ctx, "Or001", 123); !errors.Is(err, ErrOrderForbidden) {
+		t.Errorf("CustomerReorder() err = %v, want ErrOrderForbidden", err)
+	}
+}
@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/common/productpb"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/clients/mocks"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/metrics"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/consts"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/types"
+	utilMocks "github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/utils/mocks"
+	cacheMocks "github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/cache/mocks"
+	daoMocks "github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/dao/mocks"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-payment-mservice/common/paymentpb"
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestSplitByMerchant(t *testing.T) {
+	subOrders := splitByMerchant([]*types.OrderItemInfo{
+		{ProductID: 1, MerchantID: 7, Quantity: 1, Price: 40000},
+		{ProductID: 2, MerchantID: consts.DefaultMerchantID, Quantity: 2, Price: 1000},
+		{ProductID: 3, MerchantID: 7, Quantity: 1, Price: 500},
+	})
+
+	// 子订单按商家首次出现的顺序排列，运费和税费分别计算
+	if len(subOrders) != 2 || subOrders[0].merchantID != 7 || subOrders[1].merchantID != consts.DefaultMerchantID {
+		t.Fatalf("unexpected sub orders: %+v", subOrders)
+	}
+	if subOrders[0].orderNo == subOrders[1].orderNo {
+		t.Errorf("sub orders share order no %s", subOrders[0].orderNo)
+	}
+	if len(subOrders[0].items) != 2 || subOrders[0].shippingFee != 0 || subOrders[0].totalAmount() != 40500+CalculateTax(40500) {
+		t.Errorf("merchant 7 order = %+v", subOrders[0])
+	}
+	if len(subOrders[1].items) != 1 || subOrders[1].shippingFee != 800 || subOrders[1].totalAmount() != 2000+800+CalculateTax(2000) {
+		t.Errorf("default merchant order = %+v", subOrders[1])
+	}
+}
+
+type fakeProductMerchants map[int64]int
+
+func (m fakeProductMerchants) GetProductMerchants(ctx context.Context, productIDs []int64) (map[int64]int, error) {
+	if m == nil {
+		return nil, errors.New("product service unavailable")
+	}
+	return m, nil
+}
+
+func TestOrderServiceImpl_CreateOrder_ProductMerchants(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderDao := daoMocks.NewMockOrderDao(ctrl)
+	mockOrderProductDao := daoMocks.NewMockOrderProductDao(ctrl)
+	mockProductClient := mocks.NewMockProductServiceClient(ctrl)
+	mockPaymentClient := mocks.NewMockPaymentServiceClient(ctrl)
+	mockKafkaWriter := utilMocks.NewMockWriter(ctrl)
+	ctx := context.Background()
+
+	mockProductClient.EXPECT().GetProductList(ctx, gomock.Any()).Return(&productpb.GetProductListResponse{
+		Products: []*productpb.Product{{Id: 1, Stock: 5}, {Id: 2, Stock: 5}},
+	}, nil).Times(4)
+
+	service := &OrderServiceImpl{
+		orderDao:             mockOrderDao,
+		orderProductDao:      mockOrderProductDao,
+		productServiceClient: mockProductClient,
+		paymentServiceClient: mockPaymentClient,
+		messageWriter:        mockKafkaWriter,
+		orderMetrics:         metrics.NewOrderMetrics(prometheus.NewRegistry()),
+	}
+	orderInfo := func(items ...*types.OrderItemInfo) types.OrderInfo {
+		return types.OrderInfo{ReceiverFirstName: "John", ReceiverLastName: "Doe", ReceiverCountry: "SG", OrderItemList: items}
+	}
+
+	// 查不到商品所属商家时拒绝下单，不按默认商家下单
+	for _, merchants := range []fakeProductMerchants{nil, {1: 7}} {
+		service.productMerchants = merchants
+		_, err := service.CreateOrder(ctx, orderInfo(
+			&types.OrderItemInfo{ProductID: 1, Quantity: 1, Price: 40000},
+			&types.OrderItemInfo{ProductID: 2, Quantity: 2, Price: 1000},
+		), 123)
+		if !errors.Is(err, ErrProductService) {
+			t.Errorf("CreateOrder() with merchants %v err = %v, want ErrProductService", merchants, err)
+		}
+	}
+
+	// 请求中的商家与商品目录不一致时拒绝下单，不创建订单
+	service.productMerchants = fakeProductMerchants{1: 7, 2: consts.DefaultMerchantID}
+	_, err := service.CreateOrder(ctx, orderInfo(
+		&types.OrderItemInfo{ProductID: 1, Quantity: 1, Price: 40000},
+		&types.OrderItemInfo{ProductID: 2, MerchantID: 7, Quantity: 2, Price: 1000},
+	), 123)
+	if !errors.Is(err, ErrInvalidOrderItem) {
+		t.Errorf("CreateOrder() err = %v, want ErrInvalidOrderItem", err)
+	}
+
+	// 未传商家时按商品目录确定，不同商家的商品拆分为子订单
+	var orders []*model.Order
+	mockOrderDao.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, order *model.Order) (string, error) {
+		orders = append(orders, order)
+		return order.OrderNo, nil
+	}).Times(2)
+	mockOrderProductDao.EXPECT().CreateBatch(ctx, gomock.Any()).Return(1, nil).Times(2)
+	mockKafkaWriter.EXPECT().SendMsg(ctx, consts.TopicOrderCreated, gomock.Any(), gomock.Any()).Return(nil).Times(2)
+	mockKafkaWriter.EXPECT().SendMsg(ctx, consts.TopicOrderStatusChanged, gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockProductClient.EXPECT().UpdateStockWithCAS(ctx, gomock.Any()).Return(&productpb.UpdateStockWithCASResponse{}, nil).Times(2)
+	mockPaymentClient.EXPECT().PayOrder(ctx, gomock.Any()).Return(&paymentpb.PayOrderResponse{Code: 0}, nil)
+	mockOrderDao.EXPECT().UpdateStatusAndPayment(ctx, gomock.Any(), consts.PAYED, gomock.Any()).Return(nil).Times(2)
+
+	checkoutNo, err := service.CreateOrder(ctx, orderInfo(
+		&types.OrderItemInfo{ProductID: 1, Quantity: 1, Price: 40000},
+		&types.OrderItemInfo{ProductID: 2, MerchantID: consts.DefaultMerchantID, Quantity: 2, Price: 1000},
+	), 123)
+	if err != nil {
+		t.Fatalf("CreateOrder() error = %v", err)
+	}
+	if len(orders) != 2 || orders[0].MerchantID != 7 || orders[1].MerchantID != consts.DefaultMerchantID {
+		t.Fatalf("unexpected orders: %+v", orders)
+	}
+	if orders[0].CheckoutNo != checkoutNo || orders[1].CheckoutNo != checkoutNo || !strings.HasPrefix(checkoutNo, "Co-") {
+		t.Errorf("sub orders not under checkout %s: %+v", checkoutNo, orders)
+	}
+}
+
+func TestOrderServiceImpl_MerchantGetOrderDetail_OtherMerchant(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderDao := daoMocks.NewMockOrderDao(ctrl)
+	mockOrderProductDao := daoMocks.NewMockOrderProductDao(ctrl)
+	mockOrderLogDao := daoMocks.NewMockOrderLogDao(ctrl)
+	mockShipmentDao := daoMocks.NewMockShipmentDao(ctrl)
+	ctx := context.Background()
+
+	order := &model.Order{OrderNo: "ORD1", UserID: 1, MerchantID: 2, Status: consts.PAYED}
+	mockOrderDao.EXPECT().GetByOrderNo(ctx, "ORD1").Return(order, nil).Times(2)
+	mockOrderProductDao.EXPECT().GetByOrderNo(ctx, "ORD1").Return(nil, nil).Times(2)
+	mockOrderLogDao.EXPECT().GetByOrderNo(ctx, "ORD1").Return(nil, nil).Times(2)
+	mockShipmentDao.EXPECT().GetByOrderNo(ctx, "ORD1").Return(nil, nil).Times(2)
+
+	service := &OrderServiceImpl{
+		orderDao:        mockOrderDao,
+		orderProductDao: mockOrderProductDao,
+		orderLogDao:     mockOrderLogDao,
+		shipmentDao:     mockShipmentDao,
+	}
+	if _, err := service.MerchantGetOrderDetail(ctx, "ORD1", 3); !errors.Is(err, ErrOrderNotFound) {
+		t.Errorf("another merchant's order: err = %v, want ErrOrderNotFound", err)
+	}
+	detail, err := service.MerchantGetOrderDetail(ctx, "ORD1", 2)
+	if err != nil || detail.MerchantID != 2 {
+		t.Errorf("own order: detail = %+v, err = %v", detail, err)
+	}
+}
+
+func TestOrderServiceImpl_MerchantShipOrder_OtherMerchant(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderDao := daoMocks.NewMockOrderDao(ctrl)
+	ctx := context.Background()
+	mockOrderDao.EXPECT().GetByOrderNo(ctx, "ORD1").Return(&model.Order{OrderNo: "ORD1", MerchantID: 2, Status: consts.PAYED}, nil)
+
+	service := &OrderServiceImpl{orderDao: mockOrderDao}
+	if err := service.MerchantShipOrder(ctx, "ORD1", 3, "sf", "SF001"); !errors.Is(err, ErrOrderNotFound) {
+		t.Errorf("MerchantShipOrder() error = %v, want ErrOrderNotFound", err)
+	}
+}
+
+func TestOrderServiceImpl_BatchShipOrders_OtherMerchant(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderDao := daoMocks.NewMockOrderDao(ctrl)
+	ctx := context.Background()
+	mockOrderDao.EXPECT().GetByOrderNos(ctx, []string{"ORD1"}).Return([]*model.Order{
+		{OrderNo: "ORD1", MerchantID: 2, Status: consts.PAYED},
+	}, nil)
+
+	service := &OrderServiceImpl{
+		orderDao:     mockOrderDao,
+		orderMetrics: metrics.NewOrderMetrics(prometheus.NewRegistry()),
+	}
+	resp, err := service.BatchShipOrders(ctx, types.BatchShipRequest{
+		MerchantID: 3,
+		Items:      []*types.BatchShipItem{{OrderNo: "ORD1", TrackingNo: "SF001"}},
+	})
+	if err != nil {
+		t.Fatalf("BatchShipOrders() error = %v", err)
+	}
+	if resp.Succeeded != 0 || resp.Results[0].Error != "order not found" {
+		t.Errorf("another merchant's order should not ship: %+v", resp.Results[0])
+	}
+}
+
+func TestOrderServiceImpl_GetOrderStats_Merchant(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	orderStatsCacheMock := cacheMocks.NewMockIOrderStatsCache(ctrl)
+	expectedStats := types.OrderStats{TotalOrders: 2, TotalSales: 300, TotalCustomers: 1, AvgSalesPerOrder: 150}
+	orderStatsCacheMock.EXPECT().GetMerchantOrderStats(gomock.Any(), 7).Return(expectedStats, nil)
+
+	service := &OrderServiceImpl{orderStatsCache: orderStatsCacheMock}
+	stats, err := service.GetOrderStats(context.Background(), 7)
+	if err != nil || stats != expectedStats {
+		t.Errorf("GetOrderStats() = %+v, %v", stats, err)
+	}
+}
+
+func TestOrderServiceImpl_CustomerGetCheckout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderDao := daoMocks.NewMockOrderDao(ctrl)
+	mockOrderProductDao := daoMocks.NewMockOrderProductDao(ctrl)
+	mockOrderLogDao := daoMocks.NewMockOrderLogDao(ctrl)
+	mockShipmentDao := daoMocks.NewMockShipmentDao(ctrl)
+	mockTrackingEventDao := daoMocks.NewMockTrackingEventDao(ctrl)
+	ctx := context.Background()
+
+	now := time.Now()
+	orders := []*model.Order{
+		{OrderNo: "ORD1", CheckoutNo: "CO1", UserID: 1, MerchantID: 7, TotalAmount: 100, CreateTime: now},
+		{OrderNo: "ORD2", CheckoutNo: "CO1", UserID: 1, MerchantID: 1, TotalAmount: 250, CreateTime: now},
+	}
+	mockOrderDao.EXPECT().GetByCheckoutNo(ctx, "CO1").Return(orders, nil).Times(2)
+	for _, order := range orders {
+		mockOrderDao.EXPECT().GetByOrderNo(ctx, order.OrderNo).Return(order, nil)
+		mockOrderProductDao.EXPECT().GetByOrderNo(ctx, order.OrderNo).Return(nil, nil)
+		mockOrderLogDao.EXPECT().GetByOrderNo(ctx, order.OrderNo).Return(nil, nil)
+		mockShipmentDao.EXPECT().GetByOrderNo(ctx, order.OrderNo).Return(nil, nil)
+		mockTrackingEventDao.EXPECT().GetByOrderNo(ctx, order.OrderNo).Return(nil, nil)
+	}
+
+	service := &OrderServiceImpl{
+		orderDao:         mockOrderDao,
+		orderProductDao:  mockOrderProductDao,
+		orderLogDao:      mockOrderLogDao,
+		shipmentDao:      mockShipmentDao,
+		trackingEventDao: mockTrackingEventDao,
+	}
+	detail, err := service.CustomerGetCheckout(ctx, "CO1", 1)
+	if err != nil {
+		t.Fatalf("CustomerGetCheckout() error = %v", err)
+	}
+	if detail.TotalAmount != 350 || len(detail.Orders) != 2 || detail.Orders[0].MerchantID != 7 {
+		t.Errorf("unexpected checkout: %+v", detail)
+	}
+
+	if _, err = service.CustomerGetCheckout(ctx, "CO1", 2); !errors.Is(err, ErrOrderForbidden) {
+		t.Errorf("another user's checkout: err = %v, want ErrOrderForbidden", err)
+	}
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/types"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/search"
+)
+
+type OrderSearchService interface {
+	SearchOrders(ctx context.Context, req types.OrderSearchRequest) (resp *types.OrderSearchResponse, err error)
+}
+
+type OrderSearchServiceImpl struct {
+	searcher search.OrderSearcher
+}
+
+func NewOrderSearchService(searcher search.OrderSearcher) *OrderSearchServiceImpl {
+	return &OrderSearchServiceImpl{searcher: searcher}
+}
+
+// SearchOrders 全文检索订单，返回命中高亮及状态、国家、月份分面
+func (s *OrderSearchServiceImpl) SearchOrders(ctx context.Context, req types.OrderSearchRequest) (resp *types.OrderSearchResponse, err error) {
+	result, err := s.searcher.Search(ctx, search.Query{
+		Text:       req.Q,
+		MerchantID: req.MerchantID,
+		Status:     req.Status,
+		Country:    req.Country,
+		Month:      req.Month,
+		Limit:      req.Limit,
+		Offset:     req.Offset,
+	})
+	if err != nil {
+		log.FromContext(ctx).Errorf("SearchOrders: search failed, q: %s, err: %s", req.Q, err.Error())
+		return nil, err
+	}
+
+	resp = &types.OrderSearchResponse{
+		Hits:   make([]*types.OrderSearchHit, 0, len(result.Hits)),
+		Total:  result.Total,
+		Facets: make(map[string][]types.FacetBucket, len(result.Facets)),
+	}
+	for _, hit := range result.Hits {
+		doc := hit.Document
+		resp.Hits = append(resp.Hits, &types.OrderSearchHit{
+			OrderNo:         doc.OrderNo,
+			UserID:          doc.UserID,
+			Status:          getOrderStatusName(doc.Status),
+			TotalAmount:     doc.TotalAmount,
+			ReceiverName:    doc.ReceiverName,
+			ReceiverCountry: doc.ReceiverCountry,
+			CreateTime:      doc.CreateTime,
+			Score:           hit.Score,
+			Highlights:      hit.Highlights,
+		})
+	}
+	for facet, buckets := range result.Facets {
+		respBuckets := make([]types.FacetBucket, 0, len(buckets))
+		for _, bucket := range buckets {
+			respBucket := types.FacetBucket{Value: bucket.Value, Count: bucket.Count}
+			if facet == search.FacetStatus {
+				if status, err := strconv.Atoi(bucket.Value); err == nil {
+					respBucket.Name = getOrderStatusName(status)
+				}
+			}
+			respBuckets = append(respBuckets, respBucket)
+		}
+		resp.Facets[facet] = respBuckets
+	}
+	return resp, nil
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/consts"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/types"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/search"
+	searchMocks "github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/search/mocks"
+	"github.com/golang/mock/gomock"
+)
+
+func TestOrderSearchServiceImpl_SearchOrders(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSearcher := searchMocks.NewMockOrderSearcher(ctrl)
+	ctx := context.Background()
+
+	mockSearcher.EXPECT().Search(ctx, search.Query{Text: "mug", Country: "SG", Limit: 20}).Return(&search.Result{
+		Hits: []*search.Hit{{
+			Document:   &search.Document{OrderNo: "ORD1", Status: consts.SHIPPED},
+			Score:      1.5,
+			Highlights: map[string]string{search.FieldProductNames: "Blue <em>Mug</em>"},
+		}},
+		Total: 1,
+		Facets: map[string][]search.FacetBucket{
+			search.FacetStatus:  {{Value: "3", Count: 1}},
+			search.FacetCountry: {{Value: "SG", Count: 1}},
+		},
+	}, nil)
+
+	svc := NewOrderSearchService(mockSearcher)
+	resp, err := svc.SearchOrders(ctx, types.OrderSearchRequest{Q: "mug", Country: "SG", Limit: 20})
+	if err != nil {
+		t.Fatalf("SearchOrders() error = %v", err)
+	}
+	if resp.Total != 1 || len(resp.Hits) != 1 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	if resp.Hits[0].Status != "Shipped" {
+		t.Errorf("expected status name Shipped, got %s", resp.Hits[0].Status)
+	}
+	if got := resp.Facets[search.FacetStatus][0].Name; got != "Shipped" {
+		t.Errorf("expected status facet name Shipped, got %s", got)
+	}
+	if got := resp.Facets[search.FacetCountry][0].Name; got != "" {
+		t.Errorf("expected no name on country facet, got %s", got)
+	}
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/consts"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/errs"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/types"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/utils"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/dao"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/model"
+)
+
+// 批量发货每行结果
+const (
+	ShipResultShipped = "shipped"
+	ShipResultFailed  = "failed"
+	ShipResultSkipped = "skipped"
+)
+
+const MaxBatchShipItems = 500
+
+// shippableStatuses 可以发货的订单状态，部分发货的订单发出剩余商品
+var shippableStatuses = []int{consts.PAYED, consts.PARTIALLY_SHIPPED}
+
+var (
+	ErrEmptyShipBatch    = errs.New(errs.CodeInvalidShipBatch, "no shipments in batch")
+	ErrShipBatchTooLarge = errs.New(errs.CodeInvalidShipBatch, fmt.Sprintf("too many shipments in batch, at most %d", MaxBatchShipItems))
+)
+
+// BatchShipOrders 批量发货：逐行校验订单号、物流单号和订单状态，执行合法的行并返回逐行结果。
+// allOrNothing 时任意一行不合法则全部不执行，合法行在同一事务中发货。
+func (o *OrderServiceImpl) BatchShipOrders(ctx context.Context, req types.BatchShipRequest) (resp *types.BatchShipResponse, err error) {
+	if len(req.Items) == 0 {
+		return nil, ErrEmptyShipBatch
+	}
+	if len(req.Items) > MaxBatchShipItems {
+		return nil, ErrShipBatchTooLarge
+	}
+
+	logger := log.FromContext(ctx)
+	results := make([]*types.BatchShipRowResult, len(req.Items))
+	orderNos := make([]string, 0, len(req.Items))
+	for i, item := range req.Items {
+		results[i] = &types.BatchShipRowResult{
+			Row:        i + 1,
+			OrderNo:    item.OrderNo,
+			Carrier:    item.Carrier,
+			TrackingNo: item.TrackingNo,
+		}
+		if item.OrderNo != "" {
+			orderNos = append(orderNos, item.OrderNo)
+		}
+	}
+
+	orders, err := o.orderDao.GetByOrderNos(ctx, orderNos)
+	if err != nil {
+		logger.Errorf("BatchShipOrders: query orders failed, err: %s", err.Error())
+		return nil, err
+	}
+	ordersByNo := make(map[string]*model.Order, len(orders))
+	for _, order := range orders {
+		ordersByNo[order.OrderNo] = order
+	}
+
+	// 1. 逐行校验
+	valid := make([]int, 0, len(req.Items))
+	seen := make(map[string]bool, len(req.Items))
+	for i, item := range req.Items {
+		if msg := validateShipItem(item, ordersByNo[item.OrderNo], req.MerchantID, seen); msg != "" {
+			results[i].Result, results[i].Error = ShipResultFailed, msg
+			continue
+		}
+		seen[item.OrderNo] = true
+		valid = append(valid, i)
+	}
+
+	// 2. 执行发货
+	now := time.Now()
+	shipped := make([]int, 0, len(valid))
+	switch {
+	case req.AllOrNothing && len(valid) < len(req.Items):
+		markShipSkipped(results, valid, "batch rejected because other rows failed")
+	case req.AllOrNothing:
+		shipments := make([]dao.ShipmentUpdate, 0, len(valid))
+		for _, i := range valid {
+			shipments = append(shipments, shipmentUpdate(req.Items[i]))
+		}
+		if err = o.orderDao.ShipOrders(ctx, shipments, shippableStatuses, consts.SHIPPED, now); err != nil {
+			logger.Errorf("BatchShipOrders: ship batch failed, err: %s", err.Error())
+			if !errors.Is(err, dao.ErrOrderStatusChanged) {
+				return nil, err
+			}
+			markShipFailed(results, valid, err.Error())
+			break
+		}
+		shipped = valid
+	default:
+		for _, i := range valid {
+			err := o.orderDao.ShipOrders(ctx, []dao.ShipmentUpdate{shipmentUpdate(req.Items[i])}, shippableStatuses, consts.SHIPPED, now)
+			if err != nil {
+				logger.Errorf("BatchShipOrders: ship %s failed, err: %s", req.Items[i].OrderNo, err.Error())
+				markShipFailed(results, []int{i}, err.Error())
+				continue
+			}
+			shipped = append(shipped, i)
+		}
+	}
+
+	// 3. 发送状态变更消息
+	for _, i := range shipped {
+		results[i].Result = ShipResultShipped
+		o.onOrderShipped(ctx, ordersByNo[req.Items[i].OrderNo], consts.SHIPPED)
+	}
+
+	resp = &types.BatchShipResponse{
+		Total:     len(req.Items),
+		Succeeded: len(shipped),
+		Failed:    len(req.Items) - len(shipped),
+		Results:   results,
+	}
+	logger.Infof("BatchShipOrders: %d of %d orders shipped", resp.Succeeded, resp.Total)
+	return resp, nil
+}
+
+// validateShipItem 按状态机校验一行，返回失败原因；合法时返回空串。
+// 其他商家的订单与不存在的订单返回相同的原因。
+func validateShipItem(item *types.BatchShipItem, order *model.Order, merchantID int, seen map[string]bool) string {
+	switch {
+	case item.OrderNo == "":
+		return "order_no is required"
+	case item.TrackingNo == "":
+		return "tracking_no is required"
+	case seen[item.OrderNo]:
+		return "duplicate order_no in batch"
+	case order == nil || !ownedByMerchant(order, merchantID):
+		return "order not found"
+	case order.Status == consts.SHIPPED:
+		return "order already shipped"
+	case !slices.Contains(shippableStatuses, order.Status):
+		return fmt.Sprintf("order cannot be shipped in status %s", getOrderStatusName(order.Status))
+	}
+	return ""
+}
+
+func shipmentUpdate(item *types.BatchShipItem) dao.ShipmentUpdate {
+	return dao.ShipmentUpdate{
+		OrderNo:     item.OrderNo,
+		Carrier:     utils.NormalizeCarrierCode(item.Carrier),
+		LogisticsNo: item.TrackingNo,
+	}
+}
+
+func markShipFailed(results []*types.BatchShipRowResult, rows []int, msg string) {
+	for _, i := range rows {
+		results[i].Result, results[i].Error = ShipResultFailed, msg
+	}
+}
+
+func markShipSkipped(results []*types.BatchShipRowResult, rows []int, msg string) {
+	for _, i := range rows {
+		results[i].Result, results[i].Error = ShipResultSkipped, msg
+	}
+}
+
+// onOrderShipped 记录状态流转指标并发送状态变更消息，order 为发货前的订单
+func (o *OrderServiceImpl) onOrderShipped(ctx context.Context, order *model.Order, toStatus int) {
+	logger := log.FromContext(ctx).With("order_no", order.OrderNo, "user_id", order.UserID)
+	if order.Status == toStatus {
+		// 部分发货后再发一个包裹仍未发完，状态不变
+		return
+	}
+	o.orderMetrics.StatusTransition(getOrderStatusName(order.Status), getOrderStatusName(toStatus))
+
+	statusChangeRemark := fmt.Sprintf("%s --> %s", getOrderStatusName(order.Status), getOrderStatusName(toStatus))
+	oscMsg, err := getOrderStatusChangedMsg(order.OrderNo, order.UserID, statusChangeRemark, toStatus)
+	if err != nil {
+		logger.Errorf("get order status changed msg failed, err %s", err.Error())
+	}
+	err = o.messageWriter.SendMsg(ctx, consts.TopicOrderStatusChanged, order.OrderNo, oscMsg)
+	if err != nil {
+		logger.Errorf("send message failed, err %s", err)
+	}
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/metrics"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/consts"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/types"
+	utilMocks "github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/utils/mocks"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/dao"
+	daoMocks "github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/dao/mocks"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/model"
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func batchShipOrders() []*model.Order {
+	return []*model.Order{
+		{OrderNo: "ORD1", UserID: 1, Status: consts.PAYED},
+		{OrderNo: "ORD2", UserID: 2, Status: consts.SHIPPED},
+		{OrderNo: "ORD3", UserID: 3, Status: consts.PAYED},
+	}
+}
+
+func batchShipItems() []*types.BatchShipItem {
+	return []*types.BatchShipItem{
+		{OrderNo: "ORD1", Carrier: "SF", TrackingNo: "SF001"},
+		{OrderNo: "ORD2", Carrier: "SF", TrackingNo: "SF002"},
+		{OrderNo: "ORD3", Carrier: "DHL", TrackingNo: ""},
+		{OrderNo: "ORD1", Carrier: "SF", TrackingNo: "SF003"},
+		{OrderNo: "ORD4", Carrier: "SF", TrackingNo: "SF004"},
+	}
+}
+
+func TestOrderServiceImpl_BatchShipOrders_Partial(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderDao := daoMocks.NewMockOrderDao(ctrl)
+	mockKafkaWriter := utilMocks.NewMockWriter(ctrl)
+	ctx := context.Background()
+
+	mockOrderDao.EXPECT().GetByOrderNos(ctx, []string{"ORD1", "ORD2", "ORD3", "ORD1", "ORD4"}).Return(batchShipOrders(), nil)
+	// 承运商编码规范化后写入包裹，物流回调才能匹配
+	mockOrderDao.EXPECT().ShipOrders(ctx, []dao.ShipmentUpdate{{OrderNo: "ORD1", Carrier: "sf", LogisticsNo: "SF001"}},
+		shippableStatuses, consts.SHIPPED, gomock.Any()).Return(nil)
+	mockKafkaWriter.EXPECT().SendMsg(ctx, consts.TopicOrderStatusChanged, "ORD1", gomock.Any()).Return(nil)
+
+	service := &OrderServiceImpl{
+		orderDao:      mockOrderDao,
+		messageWriter: mockKafkaWriter,
+		orderMetrics:  metrics.NewOrderMetrics(prometheus.NewRegistry()),
+	}
+	resp, err := service.BatchShipOrders(ctx, types.BatchShipRequest{Items: batchShipItems()})
+	if err != nil {
+		t.Fatalf("BatchShipOrders() error = %v", err)
+	}
+	if resp.Total != 5 || resp.Succeeded != 1 || resp.Failed != 4 {
+		t.Errorf("unexpected counts: %+v", resp)
+	}
+	wantErrors := []string{"", "order already shipped", "tracking_no is required", "duplicate order_no in batch", "order not found"}
+	for i, want := range wantErrors {
+		if resp.Results[i].Error != want {
+			t.Errorf("row %d error = %q, want %q", i+1, resp.Results[i].Error, want)
+		}
+	}
+	if resp.Results[0].Result != ShipResultShipped || resp.Results[1].Result != ShipResultFailed {
+		t.Errorf("unexpected results: %+v, %+v", resp.Results[0], resp.Results[1])
+	}
+}
+
+func TestOrderServiceImpl_BatchShipOrders_AllOrNothingRejected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderDao := daoMocks.NewMockOrderDao(ctrl)
+	ctx := context.Background()
+
+	mockOrderDao.EXPECT().GetByOrderNos(ctx, gomock.Any()).Return(batchShipOrders(), nil)
+	// 有失败行时不应执行任何发货
+	mockOrderDao.EXPECT().ShipOrders(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	service := &OrderServiceImpl{orderDao: mockOrderDao}
+	resp, err := service.BatchShipOrders(ctx, types.BatchShipRequest{AllOrNothing: true, Items: batchShipItems()})
+	if err != nil {
+		t.Fatalf("BatchShipOrders() error = %v", err)
+	}
+	if resp.Succeeded != 0 || resp.Results[0].Result != ShipResultSkipped {
+		t.Errorf("expected the valid row to be skipped, got %+v", resp.Results[0])
+	}
+}
+
+func TestOrderServiceImpl_BatchShipOrders_AllOrNothingConflict(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderDao := daoMocks.NewMockOrderDao(ctrl)
+	ctx := context.Background()
+
+	items := []*types.BatchShipItem{
+		{OrderNo: "ORD1", TrackingNo: "SF001"},
+		{OrderNo: "ORD3", TrackingNo: "SF003"},
+	}
+	mockOrderDao.EXPECT().GetByOrderNos(ctx, []string{"ORD1", "ORD3"}).Return(batchShipOrders(), nil)
+	mockOrderDao.EXPECT().ShipOrders(ctx, []dao.ShipmentUpdate{
+		{OrderNo: "ORD1", LogisticsNo: "SF001"},
+		{OrderNo: "ORD3", LogisticsNo: "SF003"},
+	}, shippableStatuses, consts.SHIPPED, gomock.Any()).Return(fmt.Errorf("%w: ORD3", dao.ErrOrderStatusChanged))
+
+	service := &OrderServiceImpl{orderDao: mockOrderDao}
+	resp, err := service.BatchShipOrders(ctx, types.BatchShipRequest{AllOrNothing: true, Items: items})
+	if err != nil {
+		t.Fatalf("BatchShipOrders() error = %v", err)
+	}
+	if resp.Succeeded != 0 || resp.Failed != 2 {
+		t.Errorf("expected the whole batch to fail, got %+v", resp)
+	}
+	for _, result := range resp.Results {
+		if result.Result != ShipResultFailed {
+			t.Errorf("expected failed, got %+v", result)
+		}
+	}
+}
+
+func TestOrderServiceImpl_BatchShipOrders_Empty(t *testing.T) {
+	service := &OrderServiceImpl{}
+	if _, err := service.BatchShipOrders(context.Background(), types.BatchShipRequest{}); err != ErrEmptyShipBatch {
+		t.Fatalf("expected ErrEmptyShipBatch, got %v", err)
+	}
+}
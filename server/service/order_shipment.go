@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"slices"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/consts"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/errs"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/types"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/utils"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/dao"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/model"
+)
+
+var (
+	ErrInvalidShipment   = errs.New(errs.CodeInvalidShipment, "invalid shipment")
+	ErrOrderNotShippable = errs.New(errs.CodeOrderNotShippable, "order cannot be shipped in current status")
+)
+
+// CreateShipment 为订单创建一个包裹。items 为空时发出全部未发货商品；
+// 发完全部商品后订单变为已发货，否则为部分发货。
+func (o *OrderServiceImpl) CreateShipment(ctx context.Context, orderNo string, req types.CreateShipmentRequest) (detail *types.ShipmentDetail, err error) {
+	logger := log.FromContext(ctx).With("order_no", orderNo)
+	if req.TrackingNo == "" {
+		return nil, ErrInvalidShipment.Detailf("tracking_no is required")
+	}
+	if req.Weight < 0 {
+		return nil, ErrInvalidShipment.Detailf("weight must not be negative")
+	}
+	for _, item := range req.Items {
+		if item == nil || item.Quantity <= 0 {
+			return nil, ErrInvalidShipment.Detailf("item quantity must be positive")
+		}
+	}
+
+	shipment := &model.Shipment{
+		OrderNo:     orderNo,
+		CarrierCode: utils.NormalizeCarrierCode(req.CarrierCode),
+		TrackingNo:  req.TrackingNo,
+		Weight:      req.Weight,
+		LabelURL:    req.LabelURL,
+	}
+	order, toStatus, err := o.shipmentDao.Create(ctx, shipment, planShipment(req.Items, req.MerchantID))
+	if err != nil {
+		logger.Errorf("CreateShipment: create shipment failed, err: %s", err.Error())
+		return nil, orderDaoErr(err)
+	}
+	logger.Infof("CreateShipment: shipment %d created, status %s", shipment.ID, getOrderStatusName(toStatus))
+	o.onOrderShipped(ctx, order, toStatus)
+
+	products, err := o.orderProductDao.GetByOrderNo(ctx, orderNo)
+	if err != nil {
+		// 包裹已创建，商品名称仅用于展示
+		logger.Warnf("CreateShipment: get order products failed, err: %s", err.Error())
+	}
+	details, _ := o.toShipmentDetails([]*model.Shipment{shipment}, products)
+	return details[0], nil
+}
+
+// planShipment 返回在订单行锁内校验包裹商品的 ShipmentPlanner，其他商家的订单视为不存在
+func planShipment(reqItems []*types.ShipmentItemRequest, merchantID int) dao.ShipmentPlanner {
+	return func(order *model.Order, products []*model.OrderProduct, shipped map[int]int) ([]*model.ShipmentItem, int, error) {
+		if !ownedByMerchant(order, merchantID) {
+			return nil, 0, ErrOrderNotFound
+		}
+		if !slices.Contains(shippableStatuses, order.Status) {
+			return nil, 0, ErrOrderNotShippable.Detailf("%s", getOrderStatusName(order.Status))
+		}
+
+		remaining := make(map[int]int, len(products))
+		productsByID := make(map[int]*model.OrderProduct, len(products))
+		left := 0
+		for _, product := range products {
+			remaining[product.ID] = product.Quantity - shipped[product.ID]
+			productsByID[product.ID] = product
+			left += remaining[product.ID]
+		}
+
+		items := make([]*model.ShipmentItem, 0, len(products))
+		if len(reqItems) == 0 {
+			for _, product := range products {
+				if remaining[product.ID] > 0 {
+					items = append(items, &model.ShipmentItem{
+						OrderProductID: product.ID,
+						ProductID:      product.ProductID,
+						Quantity:       remaining[product.ID],
+					})
+				}
+			}
+		} else {
+			for _, reqItem := range reqItems {
+				product, ok := productsByID[reqItem.OrderProductID]
+				if !ok {
+					return nil, 0, ErrInvalidShipment.Detailf("order product %d not in order", reqItem.OrderProductID)
+				}
+				if reqItem.Quantity > remaining[product.ID] {
+					return nil, 0, ErrInvalidShipment.Detailf("order product %d has only %d left to ship", product.ID, remaining[product.ID])
+				}
+				remaining[product.ID] -= reqItem.Quantity
+				items = append(items, &model.ShipmentItem{
+					OrderProductID: product.ID,
+					ProductID:      product.ProductID,
+					Quantity:       reqItem.Quantity,
+				})
+			}
+		}
+		if len(items) == 0 {
+			return nil, 0, ErrInvalidShipment.Detailf("nothing left to ship")
+		}
+
+		for _, item := range items {
+			left -= item.Quantity
+		}
+		if left > 0 {
+			return items, consts.PARTIALLY_SHIPPED, nil
+		}
+		return items, consts.SHIPPED, nil
+	}
+}
+
+// toShipmentDetails 转换包裹信息，同时返回每个订单商品的已发货数量
+func (o *OrderServiceImpl) toShipmentDetails(shipments []*model.Shipment, products []*model.OrderProduct) ([]*types.ShipmentDetail, map[int]int) {
+	productNames := make(map[int]string, len(products))
+	for _, product := range products {
+		productNames[product.ID] = product.ProductName
+	}
+
+	shippedQuantities := make(map[int]int, len(products))
+	details := make([]*types.ShipmentDetail, 0, len(shipments))
+	for _, shipment := range shipments {
+		items := make([]*types.ShipmentItemDetail, 0, len(shipment.Items))
+		for _, item := range shipment.Items {
+			shippedQuantities[item.OrderProductID] += item.Quantity
+			items = append(items, &types.ShipmentItemDetail{
+				OrderProductID: item.OrderProductID,
+				ProductID:      item.ProductID,
+				ProductName:    productNames[item.OrderProductID],
+				Quantity:       item.Quantity,
+			})
+		}
+		details = append(details, &types.ShipmentDetail{
+			ID:          shipment.ID,
+			CarrierCode: shipment.CarrierCode,
+			CarrierName: o.carriers.Name(shipment.CarrierCode),
+			TrackingNo:  shipment.TrackingNo,
+			TrackingURL: o.carriers.TrackingURL(shipment.CarrierCode, shipment.TrackingNo),
+			Weight:      shipment.Weight,
+			LabelURL:    shipment.LabelURL,
+			CreateTime:  shipment.CreateTime,
+			Items:       items,
+
+			DeliveredTime: shipment.DeliveredTime,
+		})
+	}
+	return details, shippedQuantities
+}
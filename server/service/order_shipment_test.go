@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/metrics"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/consts"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/types"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/utils"
+	utilMocks "github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/utils/mocks"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/dao"
+	daoMocks "github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/dao/mocks"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/model"
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func shipmentProducts() []*model.OrderProduct {
+	return []*model.OrderProduct{
+		{ID: 11, OrderNo: "ORD1", ProductID: 101, ProductName: "Vase", Quantity: 2},
+		{ID: 12, OrderNo: "ORD1", ProductID: 102, ProductName: "Bowl", Quantity: 1},
+	}
+}
+
+// runPlanner 让 mock 的 Create 在给定的订单和已发货数量上执行 planner
+func runPlanner(order *model.Order, shipped map[int]int) func(context.Context, *model.Shipment, dao.ShipmentPlanner) (*model.Order, int, error) {
+	return func(_ context.Context, shipment *model.Shipment, plan dao.ShipmentPlanner) (*model.Order, int, error) {
+		items, toStatus, err := plan(order, shipmentProducts(), shipped)
+		if err != nil {
+			return nil, 0, err
+		}
+		shipment.ID = 1
+		shipment.Items = items
+		return order, toStatus, nil
+	}
+}
+
+func TestOrderServiceImpl_CreateShipment_Partial(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockShipmentDao := daoMocks.NewMockShipmentDao(ctrl)
+	mockOrderProductDao := daoMocks.NewMockOrderProductDao(ctrl)
+	mockKafkaWriter := utilMocks.NewMockWriter(ctrl)
+	ctx := context.Background()
+
+	order := &model.Order{OrderNo: "ORD1", UserID: 1, Status: consts.PAYED}
+	mockShipmentDao.EXPECT().Create(ctx, gomock.Any(), gomock.Any()).DoAndReturn(runPlanner(order, nil))
+	mockKafkaWriter.EXPECT().SendMsg(ctx, consts.TopicOrderStatusChanged, "ORD1", gomock.Any()).Return(nil)
+	mockOrderProductDao.EXPECT().GetByOrderNo(ctx, "ORD1").Return(shipmentProducts(), nil)
+
+	service := &OrderServiceImpl{
+		shipmentDao:     mockShipmentDao,
+		orderProductDao: mockOrderProductDao,
+		messageWriter:   mockKafkaWriter,
+		orderMetrics:    metrics.NewOrderMetrics(prometheus.NewRegistry()),
+		carriers:        utils.Carriers{"dhl": {Name: "DHL Express", TrackingURL: "https://dhl.test/?id={tracking_no}"}},
+	}
+	detail, err := service.CreateShipment(ctx, "ORD1", types.CreateShipmentRequest{
+		CarrierCode: " DHL ",
+		TrackingNo:  "DHL 001",
+		Weight:      800,
+		Items:       []*types.ShipmentItemRequest{{OrderProductID: 11, Quantity: 1}},
+	})
+	if err != nil {
+		t.Fatalf("CreateShipment() error = %v", err)
+	}
+	if detail.CarrierCode != "dhl" || detail.CarrierName != "DHL Express" || detail.TrackingURL != "https://dhl.test/?id=DHL+001" {
+		t.Errorf("unexpected carrier info: %+v", detail)
+	}
+	if len(detail.Items) != 1 || detail.Items[0].ProductName != "Vase" || detail.Items[0].Quantity != 1 {
+		t.Errorf("unexpected items: %+v", detail.Items)
+	}
+}
+
+func TestOrderServiceImpl_CreateShipment_ShipsRemaining(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockShipmentDao := daoMocks.NewMockShipmentDao(ctrl)
+	mockOrderProductDao := daoMocks.NewMockOrderProductDao(ctrl)
+	mockKafkaWriter := utilMocks.NewMockWriter(ctrl)
+	ctx := context.Background()
+
+	order := &model.Order{OrderNo: "ORD1", UserID: 1, Status: consts.PARTIALLY_SHIPPED}
+	var toStatus int
+	mockShipmentDao.EXPECT().Create(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, shipment *model.Shipment, plan dao.ShipmentPlanner) (*model.Order, int, error) {
+			o, status, err := runPlanner(order, map[int]int{11: 1})(ctx, shipment, plan)
+			toStatus = status
+			return o, status, err
+		})
+	mockKafkaWriter.EXPECT().SendMsg(ctx, consts.TopicOrderStatusChanged, "ORD1", gomock.Any()).Return(nil)
+	mockOrderProductDao.EXPECT().GetByOrderNo(ctx, "ORD1").Return(shipmentProducts(), nil)
+
+	service := &OrderServiceImpl{
+		shipmentDao:     mockShipmentDao,
+		orderProductDao: mockOrderProductDao,
+		messageWriter:   mockKafkaWriter,
+		orderMetrics:    metrics.NewOrderMetrics(prometheus.NewRegistry()),
+	}
+	detail, err := service.CreateShipment(ctx, "ORD1", types.CreateShipmentRequest{CarrierCode: "ups", TrackingNo: "1Z001"})
+	if err != nil {
+		t.Fatalf("CreateShipment() error = %v", err)
+	}
+	if toStatus != consts.SHIPPED {
+		t.Errorf("toStatus = %d, want %d", toStatus, consts.SHIPPED)
+	}
+	if len(detail.Items) != 2 || detail.Items[0].Quantity != 1 || detail.Items[1].Quantity != 1 {
+		t.Errorf("unexpected items: %+v", detail.Items)
+	}
+	if detail.CarrierName != "ups" || detail.TrackingURL != "" {
+		t.Errorf("unconfigured carrier should fall back to its code: %+v", detail)
+	}
+}
+
+func TestOrderServiceImpl_CreateShipment_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		shipped map[int]int
+		req     types.CreateShipmentRequest
+		wantErr error
+	}{
+		{"missing tracking no", consts.PAYED, nil, types.CreateShipmentRequest{}, ErrInvalidShipment},
+		{"zero quantity", consts.PAYED, nil, types.CreateShipmentRequest{TrackingNo: "T1",
+			Items: []*types.ShipmentItemRequest{{OrderProductID: 11}}}, ErrInvalidShipment},
+		{"not in order", consts.PAYED, nil, types.CreateShipmentRequest{TrackingNo: "T1",
+			Items: []*types.ShipmentItemRequest{{OrderProductID: 99, Quantity: 1}}}, ErrInvalidShipment},
+		{"over ship", consts.PARTIALLY_SHIPPED, map[int]int{11: 1}, types.CreateShipmentRequest{TrackingNo: "T1",
+			Items: []*types.ShipmentItemRequest{{OrderProductID: 11, Quantity: 2}}}, ErrInvalidShipment},
+		{"nothing left", consts.PARTIALLY_SHIPPED, map[int]int{11: 2, 12: 1}, types.CreateShipmentRequest{TrackingNo: "T1"}, ErrInvalidShipment},
+		{"not paid", consts.CREATED, nil, types.CreateShipmentRequest{TrackingNo: "T1"}, ErrOrderNotShippable},
+		{"already shipped", consts.SHIPPED, nil, types.CreateShipmentRequest{TrackingNo: "T1"}, ErrOrderNotShippable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockShipmentDao := daoMocks.NewMockShipmentDao(ctrl)
+			ctx := context.Background()
+			order := &model.Order{OrderNo: "ORD1", UserID: 1, Status: tt.status}
+			mockShipmentDao.EXPECT().Create(ctx, gomock.Any(), gomock.Any()).DoAndReturn(runPlanner(order, tt.shipped)).AnyTimes()
+
+			service := &OrderServiceImpl{shipmentDao: mockShipmentDao}
+			_, err := service.CreateShipment(ctx, "ORD1", tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("CreateShipment() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/utils"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/dao"
+)
+
+const (
+	SUMMARY_LOCK_KEY      = "order:daily_summary:lock"
+	SUMMARY_LOCK_EXP_TIME = 30 * time.Minute
+	// 回填时每次重建的天数
+	summaryBackfillDays = 31
+	// 增量汇总时向前多查的时间，覆盖汇总开始后才提交、更新时间却更早的事务
+	summaryWatermarkOverlap = time.Minute
+)
+
+// StatsReconciler rebuilds derived stats once the daily summary is refreshed.
+type StatsReconciler interface {
+	Reconcile(ctx context.Context)
+}
+
+type OrderSummaryService interface {
+	RefreshDailySummary(ctx context.Context)
+}
+
+// OrderSummaryServiceImpl keeps order_daily_summary in step with the orders
+// table. The first run backfills every day since the oldest order; later runs
+// rebuild only the days whose orders were created or changed status since the
+// previous run.
+type OrderSummaryServiceImpl struct {
+	summaryDao      dao.OrderSummaryDao
+	locker          utils.Locker
+	statsReconciler StatsReconciler
+}
+
+func NewOrderSummaryService(summaryDao dao.OrderSummaryDao, locker utils.Locker, statsReconciler StatsReconciler) *OrderSummaryServiceImpl {
+	return &OrderSummaryServiceImpl{
+		summaryDao:      summaryDao,
+		locker:          locker,
+		statsReconciler: statsReconciler,
+	}
+}
+
+// RefreshDailySummary 在分布式锁下刷新日汇总表，完成后重新对账订单统计
+func (s *OrderSummaryServiceImpl) RefreshDailySummary(ctx context.Context) {
+	logger := log.FromContext(ctx)
+	if err := s.locker.Lock(ctx); err != nil {
+		// 获取锁失败（其他实例正在处理），直接返回，等下一轮
+		logger.Info("RefreshDailySummary: failed to acquire lock, skipping this round")
+		return
+	}
+	defer func() {
+		if unlockErr := s.locker.Unlock(ctx); unlockErr != nil {
+			logger.Errorf("RefreshDailySummary: failed to release lock, err: %s", unlockErr.Error())
+		}
+	}()
+
+	runStart := time.Now()
+	watermark, ok, err := s.summaryDao.GetWatermark(ctx)
+	if err != nil {
+		logger.Errorf("RefreshDailySummary: get watermark failed, err: %s", err.Error())
+		return
+	}
+	if ok {
+		err = s.rebuildChanged(ctx, watermark.Add(-summaryWatermarkOverlap))
+	} else {
+		err = s.backfill(ctx, runStart)
+	}
+	if err != nil {
+		logger.Errorf("RefreshDailySummary: rebuild summary failed, err: %s", err.Error())
+		return
+	}
+	if err = s.summaryDao.SaveWatermark(ctx, runStart); err != nil {
+		logger.Errorf("RefreshDailySummary: save watermark failed, err: %s", err.Error())
+		return
+	}
+	logger.Infof("RefreshDailySummary: summary refreshed in %v", time.Since(runStart))
+
+	s.statsReconciler.Reconcile(ctx)
+}
+
+// backfill 重建最早订单所在日期至今的所有汇总
+func (s *OrderSummaryServiceImpl) backfill(ctx context.Context, now time.Time) error {
+	first, ok, err := s.summaryDao.GetFirstOrderTime(ctx)
+	if err != nil || !ok {
+		return err
+	}
+	end := dayStart(now).AddDate(0, 0, 1)
+	for start := dayStart(first); start.Before(end); start = start.AddDate(0, 0, summaryBackfillDays) {
+		if err = s.summaryDao.RebuildRange(ctx, start, minTime(start.AddDate(0, 0, summaryBackfillDays), end)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rebuildChanged 重建 since 之后有订单变更的日期，连续的日期合并为一次重建
+func (s *OrderSummaryServiceImpl) rebuildChanged(ctx context.Context, since time.Time) error {
+	dates, err := s.summaryDao.GetChangedDates(ctx, since)
+	if err != nil || len(dates) == 0 {
+		return err
+	}
+	start := dayStart(dates[0])
+	end := start.AddDate(0, 0, 1)
+	for _, date := range dates[1:] {
+		day := dayStart(date)
+		if day.Equal(end) {
+			end = end.AddDate(0, 0, 1)
+			continue
+		}
+		if err = s.summaryDao.RebuildRange(ctx, start, end); err != nil {
+			return err
+		}
+		start, end = day, day.AddDate(0, 0, 1)
+	}
+	return s.summaryDao.RebuildRange(ctx, start, end)
+}
+
+// dayStart 返回 t 在服务器时区当天的 0 点，汇总表的日期按服务器时区划分
+func dayStart(t time.Time) time.Time {
+	year, month, day := t.In(time.Local).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	utilMocks "github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/utils/mocks"
+	daoMocks "github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/dao/mocks"
+	"github.com/golang/mock/gomock"
+)
+
+type countingReconciler struct {
+	calls int
+}
+
+func (r *countingReconciler) Reconcile(ctx context.Context) { r.calls++ }
+
+func localDay(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+}
+
+func TestOrderSummaryServiceImpl_RefreshDailySummary_Backfill(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSummaryDao := daoMocks.NewMockOrderSummaryDao(ctrl)
+	mockLocker := utilMocks.NewMockLocker(ctrl)
+	reconciler := &countingReconciler{}
+	ctx := context.Background()
+
+	mockLocker.EXPECT().Lock(ctx).Return(nil)
+	mockLocker.EXPECT().Unlock(ctx).Return(nil)
+	mockSummaryDao.EXPECT().GetWatermark(ctx).Return(time.Time{}, false, nil)
+	// 最早的订单在 70 天前，按 31 天分 3 段回填到今天
+	first := time.Now().AddDate(0, 0, -70)
+	mockSummaryDao.EXPECT().GetFirstOrderTime(ctx).Return(first, true, nil)
+	var ranges [][2]time.Time
+	mockSummaryDao.EXPECT().RebuildRange(ctx, gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, start, end time.Time) error {
+		ranges = append(ranges, [2]time.Time{start, end})
+		return nil
+	}).Times(3)
+	mockSummaryDao.EXPECT().SaveWatermark(ctx, gomock.Any()).Return(nil)
+
+	NewOrderSummaryService(mockSummaryDao, mockLocker, reconciler).RefreshDailySummary(ctx)
+
+	if !ranges[0][0].Equal(dayStart(first)) || !ranges[2][1].Equal(dayStart(time.Now()).AddDate(0, 0, 1)) {
+		t.Errorf("backfill should cover the first order to today, got %v", ranges)
+	}
+	for i := 1; i < len(ranges); i++ {
+		if !ranges[i][0].Equal(ranges[i-1][1]) {
+			t.Errorf("ranges %d and %d are not contiguous: %v", i-1, i, ranges)
+		}
+	}
+	if reconciler.calls != 1 {
+		t.Errorf("stats should be reconciled after the refresh, got %d calls", reconciler.calls)
+	}
+}
+
+func TestOrderSummaryServiceImpl_RefreshDailySummary_Incremental(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSummaryDao := daoMocks.NewMockOrderSummaryDao(ctrl)
+	mockLocker := utilMocks.NewMockLocker(ctrl)
+	ctx := context.Background()
+	watermark := time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local)
+
+	mockLocker.EXPECT().Lock(ctx).Return(nil)
+	mockLocker.EXPECT().Unlock(ctx).Return(nil)
+	mockSummaryDao.EXPECT().GetWatermark(ctx).Return(watermark, true, nil)
+	mockSummaryDao.EXPECT().GetChangedDates(ctx, watermark.Add(-summaryWatermarkOverlap)).Return([]time.Time{
+		localDay(2026, 2, 20), // 较早下单的订单状态变更
+		localDay(2026, 3, 9),
+		localDay(2026, 3, 10),
+	}, nil)
+	gomock.InOrder(
+		mockSummaryDao.EXPECT().RebuildRange(ctx, localDay(2026, 2, 20), localDay(2026, 2, 21)).Return(nil),
+		mockSummaryDao.EXPECT().RebuildRange(ctx, localDay(2026, 3, 9), localDay(2026, 3, 11)).Return(nil),
+	)
+	mockSummaryDao.EXPECT().SaveWatermark(ctx, gomock.Any()).Return(nil)
+
+	NewOrderSummaryService(mockSummaryDao, mockLocker, &countingReconciler{}).RefreshDailySummary(ctx)
+}
+
+func TestOrderSummaryServiceImpl_RefreshDailySummary_Failures(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSummaryDao := daoMocks.NewMockOrderSummaryDao(ctrl)
+	mockLocker := utilMocks.NewMockLocker(ctrl)
+	reconciler := &countingReconciler{}
+	ctx := context.Background()
+	service := NewOrderSummaryService(mockSummaryDao, mockLocker, reconciler)
+
+	// 其他实例正在汇总，本轮跳过
+	mockLocker.EXPECT().Lock(ctx).Return(errors.New("lock held"))
+	service.RefreshDailySummary(ctx)
+
+	// 重建失败时不推进水位，下一轮重试
+	mockLocker.EXPECT().Lock(ctx).Return(nil)
+	mockLocker.EXPECT().Unlock(ctx).Return(nil)
+	mockSummaryDao.EXPECT().GetWatermark(ctx).Return(time.Now(), true, nil)
+	mockSummaryDao.EXPECT().GetChangedDates(ctx, gomock.Any()).Return([]time.Time{localDay(2026, 3, 9)}, nil)
+	mockSummaryDao.EXPECT().RebuildRange(ctx, gomock.Any(), gomock.Any()).Return(errors.New("db error"))
+	service.RefreshDailySummary(ctx)
+
+	if reconciler.calls != 0 {
+		t.Errorf("stats should not be reconciled without a refresh, got %d calls", reconciler.calls)
+	}
+}
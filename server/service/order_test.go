@@ -11,17 +11,22 @@ import (
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/common/productpb"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/clients/mocks"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/metrics"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/consts"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/types"
 
-	// "github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/utils"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/utils"
 	utilMocks "github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/utils/mocks"
 	cacheMocks "github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/cache/mocks"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/dao"
 	daoMocks "github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/dao/mocks"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/model"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-payment-mservice/common/paymentpb"
 	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 	// "github.com/stretchr/testify/assert"
 )
 
@@ -133,9 +138,9 @@ func TestOrderServiceImpl_CreateOrder_Success(t *testing.T) {
 		orderDao:             mockOrderDao,
 		orderProductDao:      mockOrderProductDao,
 		productServiceClient: mockProductClient,
+		productMerchants:     fakeProductMerchants{1: consts.DefaultMerchantID, 2: consts.DefaultMerchantID},
 		paymentServiceClient: mockPaymentClient,
 		messageWriter:        mockKafkaWriter,
-		syncMode:             true,
 	}
 
 	// Now we can actually test the CreateOrder method
@@ -194,13 +199,12 @@ func TestOrderServiceImpl_CreateOrder_GetProductListError(t *testing.T) {
 		productServiceClient: mockProductClient,
 		paymentServiceClient: mockPaymentClient,
 		messageWriter:        mockKafkaWriter,
-		syncMode:             true,
 	}
 
 	// Test the CreateOrder method
 	orderNo, err := service.CreateOrder(ctx, orderInfo, 123)
-	if err == nil {
-		t.Errorf("Expected error, got nil")
+	if !errors.Is(err, ErrProductService) {
+		t.Errorf("Expected ErrProductService, got: %v", err)
 	}
 	if orderNo != "" {
 		t.Errorf("Expected empty orderNo, got: %s", orderNo)
@@ -254,23 +258,71 @@ func TestOrderServiceImpl_CreateOrder_InsufficientStock(t *testing.T) {
 		Times(1)
 
 	// Create service instance with mocks
+	orderMetrics := metrics.NewOrderMetrics(prometheus.NewRegistry())
 	service := &OrderServiceImpl{
 		orderDao:             mockOrderDao,
 		orderProductDao:      mockOrderProductDao,
 		productServiceClient: mockProductClient,
 		paymentServiceClient: mockPaymentClient,
 		messageWriter:        mockKafkaWriter,
-		syncMode:             true,
+		orderMetrics:         orderMetrics,
 	}
 
 	// Test the CreateOrder method
 	orderNo, err := service.CreateOrder(ctx, orderInfo, 123)
-	if err == nil {
-		t.Errorf("Expected error, got nil")
+	if !errors.Is(err, ErrInsufficientStock) {
+		t.Errorf("Expected ErrInsufficientStock, got: %v", err)
 	}
 	if orderNo != "" {
 		t.Errorf("Expected empty orderNo, got: %s", orderNo)
 	}
+	if got := testutil.ToFloat64(orderMetrics.StockInsufficientTotal); got != 1 {
+		t.Errorf("Expected 1 stock insufficient rejection, got: %v", got)
+	}
+	if got := testutil.ToFloat64(orderMetrics.OrdersCreatedTotal.WithLabelValues(metrics.OrderOutcomeStockInsufficient)); got != 1 {
+		t.Errorf("Expected 1 stock_insufficient outcome, got: %v", got)
+	}
+}
+
+func TestOrderServiceImpl_CreateOrder_ImageURL(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProductClient := mocks.NewMockProductServiceClient(ctrl)
+	ctx := context.TODO()
+
+	mockProductClient.EXPECT().GetProductList(ctx, gomock.Any()).Return(&productpb.GetProductListResponse{
+		Products: []*productpb.Product{{Id: 1, Stock: 5}},
+	}, nil).Times(3)
+
+	service := &OrderServiceImpl{
+		productServiceClient: mockProductClient,
+		productMerchants:     fakeProductMerchants{1: consts.DefaultMerchantID, 2: consts.DefaultMerchantID},
+		orderMetrics:         metrics.NewOrderMetrics(prometheus.NewRegistry()),
+		imageHosts:           []string{"cdn.ceramicraft.com"},
+	}
+
+	// 商品图片只接受商品目录 CDN 上的 https 地址，不创建订单
+	for _, imageURL := range []string{
+		"https://evil.example.com/1.png",
+		"http://cdn.ceramicraft.com/1.png",
+		"https://user@cdn.ceramicraft.com/1.png",
+	} {
+		orderInfo := types.OrderInfo{
+			ReceiverFirstName: "John",
+			ReceiverLastName:  "Doe",
+			ReceiverCountry:   "SG",
+			OrderItemList: []*types.OrderItemInfo{
+				{ProductID: 1, ImageURL: imageURL, Quantity: 1, Price: 1000},
+			},
+		}
+		if _, err := service.CreateOrder(ctx, orderInfo, 123); !errors.Is(err, ErrInvalidOrderItem) {
+			t.Errorf("CreateOrder(%s) err = %v, want ErrInvalidOrderItem", imageURL, err)
+		}
+	}
+	if !service.isCatalogImage("https://CDN.ceramicraft.com/products/1.png") {
+		t.Errorf("Expected catalog CDN image to be accepted")
+	}
 }
 
 func TestOrderServiceImpl_CreateOrder_OrderDaoCreateError(t *testing.T) {
@@ -330,9 +382,9 @@ func TestOrderServiceImpl_CreateOrder_OrderDaoCreateError(t *testing.T) {
 		orderDao:             mockOrderDao,
 		orderProductDao:      mockOrderProductDao,
 		productServiceClient: mockProductClient,
+		productMerchants:     fakeProductMerchants{1: consts.DefaultMerchantID, 2: consts.DefaultMerchantID},
 		paymentServiceClient: mockPaymentClient,
 		messageWriter:        mockKafkaWriter,
-		syncMode:             true,
 	}
 
 	// Test the CreateOrder method
@@ -379,13 +431,12 @@ func TestOrderServiceImpl_ListOrders_Success(t *testing.T) {
 			Status:            consts.PAYED,
 		},
 	}
-	mockOrderDao.EXPECT().GetByOrderQuery(ctx, gomock.Any()).Return(orders, nil)
+	mockOrderDao.EXPECT().GetByOrderQuery(ctx, gomock.Any()).Return(orders, int64(2), nil)
 
 	service := &OrderServiceImpl{
 		orderDao:        mockOrderDao,
 		orderProductDao: mockOrderProductDao,
 		orderLogDao:     mockOrderLogDao,
-		syncMode:        true,
 	}
 	resp, err := service.ListOrders(ctx, req)
 	if err != nil {
@@ -409,13 +460,12 @@ func TestOrderServiceImpl_ListOrders_DaoError(t *testing.T) {
 
 	ctx := context.Background()
 	req := types.ListOrderRequest{UserID: 123}
-	mockOrderDao.EXPECT().GetByOrderQuery(ctx, gomock.Any()).Return(nil, errors.New("db error"))
+	mockOrderDao.EXPECT().GetByOrderQuery(ctx, gomock.Any()).Return(nil, int64(0), errors.New("db error"))
 
 	service := &OrderServiceImpl{
 		orderDao:        mockOrderDao,
 		orderProductDao: mockOrderProductDao,
 		orderLogDao:     mockOrderLogDao,
-		syncMode:        true,
 	}
 	resp, err := service.ListOrders(ctx, req)
 	if err == nil {
@@ -426,6 +476,123 @@ func TestOrderServiceImpl_ListOrders_DaoError(t *testing.T) {
 	}
 }
 
+func TestOrderServiceImpl_ListOrders_CursorAndTotal(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderDao := daoMocks.NewMockOrderDao(ctrl)
+
+	ctx := context.Background()
+	after := time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)
+	req := types.ListOrderRequest{
+		Limit:     2,
+		Cursor:    utils.EncodeOrderCursor(dao.OrderSortCreateTime, after.UnixNano(), 50),
+		CountMode: dao.CountModeEstimate,
+	}
+	orders := []*model.Order{
+		{ID: 49, OrderNo: "order49", CreateTime: after, Status: consts.PAYED},
+		{ID: 48, OrderNo: "order48", CreateTime: after.Add(-time.Minute), Status: consts.PAYED},
+		{ID: 47, OrderNo: "order47", CreateTime: after.Add(-2 * time.Minute), Status: consts.PAYED},
+	}
+	mockOrderDao.EXPECT().GetByOrderQuery(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, query dao.OrderQuery) ([]*model.Order, int64, error) {
+			if query.After == nil || query.After.ID != 50 || !query.After.Value.(time.Time).Equal(after) {
+				t.Errorf("Unexpected cursor: %+v", query.After)
+			}
+			if query.Limit != 3 || query.CountMode != dao.CountModeEstimate {
+				t.Errorf("Unexpected query: %+v", query)
+			}
+			return orders, int64(120), nil
+		})
+
+	service := &OrderServiceImpl{orderDao: mockOrderDao}
+	resp, err := service.ListOrders(ctx, req)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(resp.Orders) != 2 || resp.Total != 120 || !resp.TotalEstimated || !resp.HasMore {
+		t.Errorf("Unexpected resp: %+v", resp)
+	}
+	sortBy, value, id, err := utils.DecodeOrderCursor(resp.NextCursor)
+	if err != nil || sortBy != dao.OrderSortCreateTime || id != 48 || value != orders[1].CreateTime.UnixNano() {
+		t.Errorf("Expected next cursor at order48, got: %s, %d, %d, %v", sortBy, value, id, err)
+	}
+}
+
+func TestOrderServiceImpl_ListOrders_AdvancedFilters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderDao := daoMocks.NewMockOrderDao(ctrl)
+
+	ctx := context.Background()
+	minAmount := 1000
+	req := types.ListOrderRequest{
+		Statuses:        []int{consts.PAYED, consts.SHIPPED},
+		MinAmount:       &minAmount,
+		ReceiverCountry: "SG",
+		ProductID:       7,
+		SortBy:          dao.OrderSortTotalAmount,
+		SortOrder:       "asc",
+		Limit:           1,
+	}
+	orders := []*model.Order{
+		{ID: 3, OrderNo: "order3", TotalAmount: 1200, Status: consts.PAYED},
+		{ID: 8, OrderNo: "order8", TotalAmount: 1500, Status: consts.SHIPPED},
+	}
+	mockOrderDao.EXPECT().GetByOrderQuery(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, query dao.OrderQuery) ([]*model.Order, int64, error) {
+			if len(query.Statuses) != 2 || query.MinAmount == nil || *query.MinAmount != 1000 ||
+				query.ReceiverCountry != "SG" || query.ProductID != 7 ||
+				query.SortBy != dao.OrderSortTotalAmount || !query.SortAsc {
+				t.Errorf("Unexpected query: %+v", query)
+			}
+			return orders, int64(2), nil
+		})
+
+	service := &OrderServiceImpl{orderDao: mockOrderDao}
+	resp, err := service.ListOrders(ctx, req)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	sortBy, value, id, err := utils.DecodeOrderCursor(resp.NextCursor)
+	if err != nil || sortBy != dao.OrderSortTotalAmount || value != 1200 || id != 3 {
+		t.Errorf("Expected next cursor at order3, got: %s, %d, %d, %v", sortBy, value, id, err)
+	}
+}
+
+func TestOrderServiceImpl_ListOrders_InvalidSort(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderDao := daoMocks.NewMockOrderDao(ctrl)
+	service := &OrderServiceImpl{orderDao: mockOrderDao}
+
+	_, err := service.ListOrders(context.Background(), types.ListOrderRequest{Limit: 20, SortBy: "receiver_address"})
+	if !errors.Is(err, ErrInvalidOrderSort) {
+		t.Errorf("Expected ErrInvalidOrderSort, got: %v", err)
+	}
+	// 游标与排序字段不一致
+	cursor := utils.EncodeOrderCursor(dao.OrderSortCreateTime, time.Now().UnixNano(), 1)
+	_, err = service.ListOrders(context.Background(), types.ListOrderRequest{Limit: 20, SortBy: dao.OrderSortTotalAmount, Cursor: cursor})
+	if !errors.Is(err, utils.ErrInvalidCursor) {
+		t.Errorf("Expected ErrInvalidCursor, got: %v", err)
+	}
+}
+
+func TestOrderServiceImpl_ListOrders_InvalidCursor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderDao := daoMocks.NewMockOrderDao(ctrl)
+
+	service := &OrderServiceImpl{orderDao: mockOrderDao}
+	_, err := service.ListOrders(context.Background(), types.ListOrderRequest{Limit: 20, Cursor: "bad"})
+	if !errors.Is(err, utils.ErrInvalidCursor) {
+		t.Errorf("Expected ErrInvalidCursor, got: %v", err)
+	}
+}
+
 func TestOrderServiceImpl_GetOrderDetail_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -433,6 +600,7 @@ func TestOrderServiceImpl_GetOrderDetail_Success(t *testing.T) {
 	mockOrderDao := daoMocks.NewMockOrderDao(ctrl)
 	mockOrderProductDao := daoMocks.NewMockOrderProductDao(ctrl)
 	mockOrderLogDao := daoMocks.NewMockOrderLogDao(ctrl)
+	mockShipmentDao := daoMocks.NewMockShipmentDao(ctrl)
 
 	ctx := context.Background()
 	orderNo := "order1"
@@ -468,12 +636,13 @@ func TestOrderServiceImpl_GetOrderDetail_Success(t *testing.T) {
 	mockOrderDao.EXPECT().GetByOrderNo(ctx, orderNo).Return(order, nil)
 	mockOrderProductDao.EXPECT().GetByOrderNo(ctx, orderNo).Return(products, nil)
 	mockOrderLogDao.EXPECT().GetByOrderNo(ctx, orderNo).Return(logs, nil)
+	mockShipmentDao.EXPECT().GetByOrderNo(ctx, orderNo).Return(nil, nil)
 
 	service := &OrderServiceImpl{
 		orderDao:        mockOrderDao,
 		orderProductDao: mockOrderProductDao,
 		orderLogDao:     mockOrderLogDao,
-		syncMode:        true,
+		shipmentDao:     mockShipmentDao,
 	}
 	detail, err := service.GetOrderDetail(ctx, orderNo)
 	if err != nil {
@@ -500,17 +669,16 @@ func TestOrderServiceImpl_GetOrderDetail_OrderNotFound(t *testing.T) {
 
 	ctx := context.Background()
 	orderNo := "order1"
-	mockOrderDao.EXPECT().GetByOrderNo(ctx, orderNo).Return(nil, errors.New("not found"))
+	mockOrderDao.EXPECT().GetByOrderNo(ctx, orderNo).Return(nil, gorm.ErrRecordNotFound)
 
 	service := &OrderServiceImpl{
 		orderDao:        mockOrderDao,
 		orderProductDao: mockOrderProductDao,
 		orderLogDao:     mockOrderLogDao,
-		syncMode:        true,
 	}
 	detail, err := service.GetOrderDetail(ctx, orderNo)
-	if err == nil {
-		t.Errorf("Expected error, got nil")
+	if !errors.Is(err, ErrOrderNotFound) {
+		t.Errorf("Expected ErrOrderNotFound, got: %v", err)
 	}
 	if detail != nil {
 		t.Errorf("Expected nil detail, got: %v", detail)
@@ -535,7 +703,6 @@ func TestOrderServiceImpl_GetOrderDetail_ProductError(t *testing.T) {
 		orderDao:        mockOrderDao,
 		orderProductDao: mockOrderProductDao,
 		orderLogDao:     mockOrderLogDao,
-		syncMode:        true,
 	}
 	detail, err := service.GetOrderDetail(ctx, orderNo)
 	if err == nil {
@@ -566,7 +733,6 @@ func TestOrderServiceImpl_GetOrderDetail_LogError(t *testing.T) {
 		orderDao:        mockOrderDao,
 		orderProductDao: mockOrderProductDao,
 		orderLogDao:     mockOrderLogDao,
-		syncMode:        true,
 	}
 	detail, err := service.GetOrderDetail(ctx, orderNo)
 	if err == nil {
@@ -640,9 +806,9 @@ func TestOrderServiceImpl_CreateOrder_OrderProductDaoCreateError(t *testing.T) {
 		orderDao:             mockOrderDao,
 		orderProductDao:      mockOrderProductDao,
 		productServiceClient: mockProductClient,
+		productMerchants:     fakeProductMerchants{1: consts.DefaultMerchantID, 2: consts.DefaultMerchantID},
 		paymentServiceClient: mockPaymentClient,
 		messageWriter:        mockKafkaWriter,
-		syncMode:             true,
 	}
 
 	// Test the CreateOrder method
@@ -724,9 +890,9 @@ func TestOrderServiceImpl_CreateOrder_KafkaOrderCreatedError(t *testing.T) {
 		orderDao:             mockOrderDao,
 		orderProductDao:      mockOrderProductDao,
 		productServiceClient: mockProductClient,
+		productMerchants:     fakeProductMerchants{1: consts.DefaultMerchantID, 2: consts.DefaultMerchantID},
 		paymentServiceClient: mockPaymentClient,
 		messageWriter:        mockKafkaWriter,
-		syncMode:             true,
 	}
 
 	// Test the CreateOrder method
@@ -832,9 +998,9 @@ func TestOrderServiceImpl_CreateOrder_PaymentFailed(t *testing.T) {
 		orderDao:             mockOrderDao,
 		orderProductDao:      mockOrderProductDao,
 		productServiceClient: mockProductClient,
+		productMerchants:     fakeProductMerchants{1: consts.DefaultMerchantID, 2: consts.DefaultMerchantID},
 		paymentServiceClient: mockPaymentClient,
 		messageWriter:        mockKafkaWriter,
-		syncMode:             true,
 	}
 
 	// Test the CreateOrder method
@@ -854,6 +1020,8 @@ func TestOrderServiceImpl_CustomerGetOrderDetail_Success(t *testing.T) {
 	mockOrderDao := daoMocks.NewMockOrderDao(ctrl)
 	mockOrderProductDao := daoMocks.NewMockOrderProductDao(ctrl)
 	mockOrderLogDao := daoMocks.NewMockOrderLogDao(ctrl)
+	mockShipmentDao := daoMocks.NewMockShipmentDao(ctrl)
+	mockTrackingEventDao := daoMocks.NewMockTrackingEventDao(ctrl)
 
 	ctx := context.Background()
 	orderNo := "order1"
@@ -900,12 +1068,15 @@ func TestOrderServiceImpl_CustomerGetOrderDetail_Success(t *testing.T) {
 	mockOrderDao.EXPECT().GetByOrderNo(ctx, orderNo).Return(order, nil)
 	mockOrderProductDao.EXPECT().GetByOrderNo(ctx, orderNo).Return(products, nil)
 	mockOrderLogDao.EXPECT().GetByOrderNo(ctx, orderNo).Return(logs, nil)
+	mockShipmentDao.EXPECT().GetByOrderNo(ctx, orderNo).Return(nil, nil)
+	mockTrackingEventDao.EXPECT().GetByOrderNo(ctx, orderNo).Return(nil, nil)
 
 	service := &OrderServiceImpl{
-		orderDao:        mockOrderDao,
-		orderProductDao: mockOrderProductDao,
-		orderLogDao:     mockOrderLogDao,
-		syncMode:        true,
+		orderDao:         mockOrderDao,
+		orderProductDao:  mockOrderProductDao,
+		orderLogDao:      mockOrderLogDao,
+		shipmentDao:      mockShipmentDao,
+		trackingEventDao: mockTrackingEventDao,
 	}
 	detail, err := service.CustomerGetOrderDetail(ctx, orderNo, userID)
 	if err != nil {
@@ -932,6 +1103,7 @@ func TestOrderServiceImpl_CustomerGetOrderDetail_WrongUser(t *testing.T) {
 	mockOrderDao := daoMocks.NewMockOrderDao(ctrl)
 	mockOrderProductDao := daoMocks.NewMockOrderProductDao(ctrl)
 	mockOrderLogDao := daoMocks.NewMockOrderLogDao(ctrl)
+	mockShipmentDao := daoMocks.NewMockShipmentDao(ctrl)
 
 	ctx := context.Background()
 	orderNo := "order1"
@@ -981,12 +1153,13 @@ func TestOrderServiceImpl_CustomerGetOrderDetail_WrongUser(t *testing.T) {
 	mockOrderDao.EXPECT().GetByOrderNo(ctx, orderNo).Return(order, nil)
 	mockOrderProductDao.EXPECT().GetByOrderNo(ctx, orderNo).Return(products, nil)
 	mockOrderLogDao.EXPECT().GetByOrderNo(ctx, orderNo).Return(logs, nil)
+	mockShipmentDao.EXPECT().GetByOrderNo(ctx, orderNo).Return(nil, nil)
 
 	service := &OrderServiceImpl{
 		orderDao:        mockOrderDao,
 		orderProductDao: mockOrderProductDao,
 		orderLogDao:     mockOrderLogDao,
-		syncMode:        true,
+		shipmentDao:     mockShipmentDao,
 	}
 
 	// 用户456尝试访问用户123的订单
@@ -1019,7 +1192,6 @@ func TestOrderServiceImpl_CustomerGetOrderDetail_OrderNotFound(t *testing.T) {
 		orderDao:        mockOrderDao,
 		orderProductDao: mockOrderProductDao,
 		orderLogDao:     mockOrderLogDao,
-		syncMode:        true,
 	}
 	detail, err := service.CustomerGetOrderDetail(ctx, orderNo, userID)
 	if err == nil {
@@ -1049,7 +1221,6 @@ func TestOrderServiceImpl_CustomerGetOrderDetail_ProductError(t *testing.T) {
 		orderDao:        mockOrderDao,
 		orderProductDao: mockOrderProductDao,
 		orderLogDao:     mockOrderLogDao,
-		syncMode:        true,
 	}
 	detail, err := service.CustomerGetOrderDetail(ctx, orderNo, userID)
 	if err == nil {
@@ -1081,7 +1252,6 @@ func TestOrderServiceImpl_CustomerGetOrderDetail_LogError(t *testing.T) {
 		orderDao:        mockOrderDao,
 		orderProductDao: mockOrderProductDao,
 		orderLogDao:     mockOrderLogDao,
-		syncMode:        true,
 	}
 	detail, err := service.CustomerGetOrderDetail(ctx, orderNo, userID)
 	if err == nil {
@@ -1092,8 +1262,8 @@ func TestOrderServiceImpl_CustomerGetOrderDetail_LogError(t *testing.T) {
 	}
 }
 
-// TestOrderServiceImpl_UpdateOrderStatus_ToShipped_Success tests successful update to shipped status
-func TestOrderServiceImpl_UpdateOrderStatus_ToShipped_Success(t *testing.T) {
+// TestOrderServiceImpl_MerchantShipOrder_Success tests successful update to shipped status
+func TestOrderServiceImpl_MerchantShipOrder_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -1110,7 +1280,8 @@ func TestOrderServiceImpl_UpdateOrderStatus_ToShipped_Success(t *testing.T) {
 		OrderNo: orderNo,
 		Status:  int(consts.PAYED),
 	}, nil)
-	mockOrderDao.EXPECT().UpdateStatusWithDeliveryInfo(ctx, orderNo, newStatus, gomock.Any(), logisticsInfo).Return(nil)
+	mockOrderDao.EXPECT().ShipOrders(ctx, []dao.ShipmentUpdate{{OrderNo: orderNo, Carrier: "sf", LogisticsNo: logisticsInfo}},
+		[]int{consts.PAYED, consts.PARTIALLY_SHIPPED}, newStatus, gomock.Any()).Return(nil)
 
 	// Mock successful Kafka message
 	mockMessageWriter.EXPECT().SendMsg(ctx, "order_status_changed", gomock.Any(), gomock.Any()).Return(nil)
@@ -1118,15 +1289,30 @@ func TestOrderServiceImpl_UpdateOrderStatus_ToShipped_Success(t *testing.T) {
 	service := &OrderServiceImpl{
 		orderDao:      mockOrderDao,
 		messageWriter: mockMessageWriter,
-		syncMode:      true,
 	}
 
-	err := service.UpdateOrderStatus(ctx, orderNo, newStatus, logisticsInfo)
+	err := service.MerchantShipOrder(ctx, orderNo, 0, " SF ", logisticsInfo)
 	if err != nil {
 		t.Errorf("Expected no error, got: %v", err)
 	}
 }
 
+// TestOrderServiceImpl_UpdateOrderStatus_ToShipped_NoCarrier tests that shipping requires a carrier
+func TestOrderServiceImpl_UpdateOrderStatus_ToShipped_NoCarrier(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderDao := daoMocks.NewMockOrderDao(ctrl)
+	ctx := context.Background()
+	mockOrderDao.EXPECT().GetByOrderNo(ctx, "TEST001").Return(&model.Order{OrderNo: "TEST001", Status: consts.PAYED}, nil)
+
+	service := &OrderServiceImpl{orderDao: mockOrderDao}
+	err := service.UpdateOrderStatus(ctx, "TEST001", consts.SHIPPED, "SF12345")
+	if !errors.Is(err, ErrInvalidShipment) {
+		t.Errorf("UpdateOrderStatus() error = %v, want ErrInvalidShipment", err)
+	}
+}
+
 // TestOrderServiceImpl_UpdateOrderStatus_ToDelivered_Success tests successful update to delivered status
 func TestOrderServiceImpl_UpdateOrderStatus_ToDelivered_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
@@ -1144,7 +1330,7 @@ func TestOrderServiceImpl_UpdateOrderStatus_ToDelivered_Success(t *testing.T) {
 		OrderNo: orderNo,
 		Status:  int(consts.SHIPPED),
 	}, nil)
-	mockOrderDao.EXPECT().UpdateStatusAndConfirmTime(ctx, orderNo, newStatus, gomock.Any()).Return(nil)
+	mockOrderDao.EXPECT().UpdateStatusAndConfirmTime(ctx, orderNo, consts.SHIPPED, newStatus, gomock.Any()).Return(nil)
 
 	// Mock successful Kafka message
 	mockMessageWriter.EXPECT().SendMsg(ctx, "order_status_changed", gomock.Any(), gomock.Any()).Return(nil)
@@ -1152,7 +1338,6 @@ func TestOrderServiceImpl_UpdateOrderStatus_ToDelivered_Success(t *testing.T) {
 	service := &OrderServiceImpl{
 		orderDao:      mockOrderDao,
 		messageWriter: mockMessageWriter,
-		syncMode:      true,
 	}
 
 	err := service.UpdateOrderStatus(ctx, orderNo, newStatus, "")
@@ -1161,6 +1346,36 @@ func TestOrderServiceImpl_UpdateOrderStatus_ToDelivered_Success(t *testing.T) {
 	}
 }
 
+// TestOrderServiceImpl_CustomerConfirmOrder tests that users can only confirm their own orders
+func TestOrderServiceImpl_CustomerConfirmOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderDao := daoMocks.NewMockOrderDao(ctrl)
+	mockMessageWriter := utilMocks.NewMockWriter(ctrl)
+	ctx := context.Background()
+
+	mockOrderDao.EXPECT().GetByOrderNo(ctx, "TEST002").Return(&model.Order{
+		OrderNo: "TEST002",
+		UserID:  1,
+		Status:  consts.SHIPPED,
+	}, nil).Times(2)
+	mockOrderDao.EXPECT().UpdateStatusAndConfirmTime(ctx, "TEST002", consts.SHIPPED, consts.DELIVERED, gomock.Any()).Return(nil)
+	mockMessageWriter.EXPECT().SendMsg(ctx, consts.TopicOrderStatusChanged, "TEST002", gomock.Any()).Return(nil)
+
+	service := &OrderServiceImpl{
+		orderDao:      mockOrderDao,
+		messageWriter: mockMessageWriter,
+	}
+
+	if err := service.CustomerConfirmOrder(ctx, "TEST002", 2); !errors.Is(err, ErrOrderForbidden) {
+		t.Errorf("another user's order: err = %v, want ErrOrderForbidden", err)
+	}
+	if err := service.CustomerConfirmOrder(ctx, "TEST002", 1); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+}
+
 // TestOrderServiceImpl_UpdateOrderStatus_OrderNotFound tests order not found scenario
 func TestOrderServiceImpl_UpdateOrderStatus_OrderNotFound(t *testing.T) {
 	ctrl := gomock.NewController(t)
@@ -1176,7 +1391,6 @@ func TestOrderServiceImpl_UpdateOrderStatus_OrderNotFound(t *testing.T) {
 
 	service := &OrderServiceImpl{
 		orderDao: mockOrderDao,
-		syncMode: true,
 	}
 
 	err := service.UpdateOrderStatus(ctx, orderNo, newStatus, "")
@@ -1204,12 +1418,11 @@ func TestOrderServiceImpl_UpdateOrderStatus_InvalidStatusTransition(t *testing.T
 
 	service := &OrderServiceImpl{
 		orderDao: mockOrderDao,
-		syncMode: true,
 	}
 
 	err := service.UpdateOrderStatus(ctx, orderNo, newStatus, "")
-	if err == nil {
-		t.Errorf("Expected error for invalid status transition, got nil")
+	if !errors.Is(err, ErrInvalidStatusTransition) {
+		t.Errorf("Expected ErrInvalidStatusTransition, got: %v", err)
 	}
 }
 
@@ -1231,7 +1444,6 @@ func TestOrderServiceImpl_UpdateOrderStatus_UnsupportedStatus(t *testing.T) {
 
 	service := &OrderServiceImpl{
 		orderDao: mockOrderDao,
-		syncMode: true,
 	}
 
 	err := service.UpdateOrderStatus(ctx, orderNo, newStatus, "")
@@ -1255,11 +1467,10 @@ func TestOrderServiceImpl_UpdateOrderStatus_DaoUpdateError(t *testing.T) {
 		OrderNo: orderNo,
 		Status:  int(consts.SHIPPED),
 	}, nil)
-	mockOrderDao.EXPECT().UpdateStatusAndConfirmTime(ctx, orderNo, newStatus, gomock.Any()).Return(errors.New("database error"))
+	mockOrderDao.EXPECT().UpdateStatusAndConfirmTime(ctx, orderNo, consts.SHIPPED, newStatus, gomock.Any()).Return(errors.New("database error"))
 
 	service := &OrderServiceImpl{
 		orderDao: mockOrderDao,
-		syncMode: true,
 	}
 
 	err := service.UpdateOrderStatus(ctx, orderNo, newStatus, "")
@@ -1307,7 +1518,6 @@ func TestOrderServiceImpl_OrderAutoConfirm_Success(t *testing.T) {
 		orderDao:          mockOrderDao,
 		messageWriter:     mockKafkaWriter,
 		distributedLocker: mockLocker,
-		syncMode:          true,
 	}
 
 	// Execute
@@ -1328,13 +1538,18 @@ func TestOrderServiceImpl_OrderAutoConfirm_LockFailed(t *testing.T) {
 	// Unlock should NOT be called if lock acquisition fails
 	mockLocker.EXPECT().Unlock(ctx).Times(0)
 
+	orderMetrics := metrics.NewOrderMetrics(prometheus.NewRegistry())
 	service := &OrderServiceImpl{
 		distributedLocker: mockLocker,
-		syncMode:          true,
+		orderMetrics:      orderMetrics,
 	}
 
 	// Execute - should return gracefully without error
 	service.OrderAutoConfirm(ctx)
+
+	if got := testutil.ToFloat64(orderMetrics.AutoConfirmLockContentionTotal); got != 1 {
+		t.Errorf("Expected 1 lock contention, got: %v", got)
+	}
 }
 
 // TestOrderServiceImpl_OrderAutoConfirm_DaoError tests DAO error during auto-confirm
@@ -1360,7 +1575,6 @@ func TestOrderServiceImpl_OrderAutoConfirm_DaoError(t *testing.T) {
 	service := &OrderServiceImpl{
 		orderDao:          mockOrderDao,
 		distributedLocker: mockLocker,
-		syncMode:          true,
 	}
 
 	// Execute - should handle error gracefully
@@ -1395,7 +1609,6 @@ func TestOrderServiceImpl_OrderAutoConfirm_NoOrders(t *testing.T) {
 		orderDao:          mockOrderDao,
 		messageWriter:     mockKafkaWriter,
 		distributedLocker: mockLocker,
-		syncMode:          true,
 	}
 
 	// Execute
@@ -1436,7 +1649,6 @@ func TestOrderServiceImpl_OrderAutoConfirm_MessageSendError(t *testing.T) {
 		orderDao:          mockOrderDao,
 		messageWriter:     mockKafkaWriter,
 		distributedLocker: mockLocker,
-		syncMode:          true,
 	}
 
 	// Execute - should log error but continue
@@ -1478,7 +1690,6 @@ func TestOrderServiceImpl_OrderAutoConfirm_UnlockError(t *testing.T) {
 		orderDao:          mockOrderDao,
 		messageWriter:     mockKafkaWriter,
 		distributedLocker: mockLocker,
-		syncMode:          true,
 	}
 
 	// Execute - should log error but not panic
@@ -1525,7 +1736,6 @@ func TestOrderServiceImpl_OrderAutoConfirm_MultipleOrders(t *testing.T) {
 		orderDao:          mockOrderDao,
 		messageWriter:     mockKafkaWriter,
 		distributedLocker: mockLocker,
-		syncMode:          true,
 	}
 
 	// Execute
@@ -1580,7 +1790,6 @@ func TestOrderServiceImpl_OrderAutoConfirm_PartialMessageFailure(t *testing.T) {
 		orderDao:          mockOrderDao,
 		messageWriter:     mockKafkaWriter,
 		distributedLocker: mockLocker,
-		syncMode:          true,
 	}
 
 	// Execute - should continue processing all orders despite one failure
@@ -1599,13 +1808,13 @@ func TestOrderServiceImpl_GetOrderStats_Success(t *testing.T) {
 		AvgSalesPerOrder: 10,
 	}
 
-	orderStatsCacheMock.EXPECT().GetOrderStats().Return(expectedStats, nil)
+	orderStatsCacheMock.EXPECT().GetOrderStats(gomock.Any()).Return(expectedStats, nil)
 
 	service := &OrderServiceImpl{
 		orderStatsCache: orderStatsCacheMock,
 	}
 
-	stats, err := service.GetOrderStats(ctx)
+	stats, err := service.GetOrderStats(ctx, 0)
 	if err != nil {
 		t.Errorf("Expected no error, got: %s", err.Error())
 	}
@@ -1623,13 +1832,13 @@ func TestOrderServiceImpl_GetOrderStats_Error(t *testing.T) {
 	ctx := context.TODO()
 	expectedError := errors.New("cache error")
 
-	orderStatsCacheMock.EXPECT().GetOrderStats().Return(types.OrderStats{}, expectedError)
+	orderStatsCacheMock.EXPECT().GetOrderStats(gomock.Any()).Return(types.OrderStats{}, expectedError)
 
 	service := &OrderServiceImpl{
 		orderStatsCache: orderStatsCacheMock,
 	}
 
-	stats, err := service.GetOrderStats(ctx)
+	stats, err := service.GetOrderStats(ctx, 0)
 	if err == nil {
 		t.Errorf("Expected error, got nil")
 	}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/consts"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/errs"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/types"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/utils"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/model"
+	"gorm.io/gorm"
+)
+
+const MaxTrackingEvents = 100
+
+var (
+	ErrInvalidTrackingEvent = errs.New(errs.CodeInvalidTrackingEvent, "invalid tracking event")
+	ErrShipmentNotFound     = errs.New(errs.CodeShipmentNotFound, "shipment not found")
+)
+
+var trackingStatuses = []string{
+	consts.TrackingInTransit,
+	consts.TrackingOutForDelivery,
+	consts.TrackingDelivered,
+	consts.TrackingException,
+}
+
+// RecordTrackingEvents 保存承运商推送的物流轨迹。包裹签收后记录签收时间，
+// 订单全部包裹签收后自动确认收货的等待期从签收时间起算。
+func (o *OrderServiceImpl) RecordTrackingEvents(ctx context.Context, carrierCode string, req types.TrackingWebhookRequest) (err error) {
+	carrierCode = utils.NormalizeCarrierCode(carrierCode)
+	logger := log.FromContext(ctx).With("carrier", carrierCode, "tracking_no", req.TrackingNo)
+	if req.TrackingNo == "" {
+		return ErrInvalidTrackingEvent.Detailf("tracking_no is required")
+	}
+	if len(req.Events) == 0 || len(req.Events) > MaxTrackingEvents {
+		return ErrInvalidTrackingEvent.Detailf("expected 1 to %d events", MaxTrackingEvents)
+	}
+
+	events := make([]*model.TrackingEvent, 0, len(req.Events))
+	for _, event := range req.Events {
+		if event == nil || !slices.Contains(trackingStatuses, event.Status) {
+			return ErrInvalidTrackingEvent.Detailf("status must be one of %v", trackingStatuses)
+		}
+		if event.Time.IsZero() {
+			return ErrInvalidTrackingEvent.Detailf("time is required")
+		}
+		events = append(events, &model.TrackingEvent{
+			Status:      event.Status,
+			Description: event.Description,
+			Location:    event.Location,
+			EventTime:   event.Time,
+		})
+	}
+
+	shipment, arrived, err := o.trackingEventDao.Record(ctx, carrierCode, req.TrackingNo, events, consts.TrackingDelivered, consts.SHIPPED)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrShipmentNotFound
+	}
+	if err != nil {
+		logger.Errorf("RecordTrackingEvents: record events failed, err: %s", err.Error())
+		return err
+	}
+	logger.Infof("RecordTrackingEvents: %d events recorded for order %s", len(events), shipment.OrderNo)
+	if arrived {
+		logger.Infof("RecordTrackingEvents: all parcels of order %s delivered, confirm window starts now", shipment.OrderNo)
+	}
+	return nil
+}
+
+// attachTrackingEvents 将物流轨迹按包裹挂到包裹详情上
+func attachTrackingEvents(shipments []*types.ShipmentDetail, events []*model.TrackingEvent) {
+	byShipment := make(map[int][]*types.TrackingEventDetail, len(shipments))
+	for _, event := range events {
+		byShipment[event.ShipmentID] = append(byShipment[event.ShipmentID], &types.TrackingEventDetail{
+			Status:      event.Status,
+			Description: event.Description,
+			Location:    event.Location,
+			EventTime:   event.EventTime,
+		})
+	}
+	for _, shipment := range shipments {
+		shipment.TrackingEvents = byShipment[shipment.ID]
+	}
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/consts"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/pkg/types"
+	daoMocks "github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/dao/mocks"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/repository/model"
+	"github.com/golang/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestOrderServiceImpl_RecordTrackingEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTrackingEventDao := daoMocks.NewMockTrackingEventDao(ctrl)
+	ctx := context.Background()
+	deliveredAt := time.Date(2026, 10, 1, 9, 30, 0, 0, time.UTC)
+
+	mockTrackingEventDao.EXPECT().
+		Record(ctx, "dhl", "DHL001", gomock.Any(), consts.TrackingDelivered, consts.SHIPPED).
+		DoAndReturn(func(_ context.Context, _, _ string, events []*model.TrackingEvent, _ string, _ int) (*model.Shipment, bool, error) {
+			if len(events) != 2 || events[1].Status != consts.TrackingDelivered || !events[1].EventTime.Equal(deliveredAt) {
+				t.Errorf("unexpected events: %+v", events)
+			}
+			return &model.Shipment{ID: 1, OrderNo: "ORD1", DeliveredTime: deliveredAt}, true, nil
+		})
+
+	service := &OrderServiceImpl{trackingEventDao: mockTrackingEventDao}
+	err := service.RecordTrackingEvents(ctx, "DHL", types.TrackingWebhookRequest{
+		TrackingNo: "DHL001",
+		Events: []*types.TrackingEventRequest{
+			{Status: consts.TrackingInTransit, Location: "Singapore", Time: deliveredAt.Add(-time.Hour)},
+			{Status: consts.TrackingDelivered, Location: "Singapore", Time: deliveredAt},
+		},
+	})
+	if err != nil {
+		t.Fatalf("RecordTrackingEvents() error = %v", err)
+	}
+}
+
+func TestOrderServiceImpl_RecordTrackingEvents_Errors(t *testing.T) {
+	now := time.Now()
+	valid := []*types.TrackingEventRequest{{Status: consts.TrackingInTransit, Time: now}}
+	tests := []struct {
+		name    string
+		req     types.TrackingWebhookRequest
+		daoErr  error
+		wantErr error
+	}{
+		{"missing tracking no", types.TrackingWebhookRequest{Events: valid}, nil, ErrInvalidTrackingEvent},
+		{"no events", types.TrackingWebhookRequest{TrackingNo: "T1"}, nil, ErrInvalidTrackingEvent},
+		{"unknown status", types.TrackingWebhookRequest{TrackingNo: "T1",
+			Events: []*types.TrackingEventRequest{{Status: "lost", Time: now}}}, nil, ErrInvalidTrackingEvent},
+		{"missing time", types.TrackingWebhookRequest{TrackingNo: "T1",
+			Events: []*types.TrackingEventRequest{{Status: consts.TrackingDelivered}}}, nil, ErrInvalidTrackingEvent},
+		{"unknown shipment", types.TrackingWebhookRequest{TrackingNo: "T1", Events: valid}, gorm.ErrRecordNotFound, ErrShipmentNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockTrackingEventDao := daoMocks.NewMockTrackingEventDao(ctrl)
+			if tt.daoErr != nil {
+				mockTrackingEventDao.EXPECT().Record(gomock.Any(), "ups", "T1", gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, false, tt.daoErr)
+			}
+			service := &OrderServiceImpl{trackingEventDao: mockTrackingEventDao}
+			err := service.RecordTrackingEvents(context.Background(), "ups", tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("RecordTrackingEvents() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestOrderServiceImpl_CustomerGetOrderDetail_TrackingTimeline(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderDao := daoMocks.NewMockOrderDao(ctrl)
+	mockOrderProductDao := daoMocks.NewMockOrderProductDao(ctrl)
+	mockOrderLogDao := daoMocks.NewMockOrderLogDao(ctrl)
+	mockShipmentDao := daoMocks.NewMockShipmentDao(ctrl)
+	mockTrackingEventDao := daoMocks.NewMockTrackingEventDao(ctrl)
+	ctx := context.Background()
+	deliveredAt := time.Date(2026, 10, 1, 9, 30, 0, 0, time.UTC)
+
+	mockOrderDao.EXPECT().GetByOrderNo(ctx, "ORD1").Return(&model.Order{OrderNo: "ORD1", UserID: 7, Status: consts.SHIPPED, ArrivedTime: deliveredAt}, nil)
+	mockOrderProductDao.EXPECT().GetByOrderNo(ctx, "ORD1").Return(nil, nil)
+	mockOrderLogDao.EXPECT().GetByOrderNo(ctx, "ORD1").Return(nil, nil)
+	mockShipmentDao.EXPECT().GetByOrderNo(ctx, "ORD1").Return([]*model.Shipment{
+		{ID: 1, OrderNo: "ORD1", CarrierCode: "dhl", TrackingNo: "DHL001", DeliveredTime: deliveredAt},
+		{ID: 2, OrderNo: "ORD1", CarrierCode: "ups", TrackingNo: "1Z001"},
+	}, nil)
+	mockTrackingEventDao.EXPECT().GetByOrderNo(ctx, "ORD1").Return([]*model.TrackingEvent{
+		{ShipmentID: 1, Status: consts.TrackingInTransit, EventTime: deliveredAt.Add(-time.Hour)},
+		{ShipmentID: 2, Status: consts.TrackingInTransit, EventTime: deliveredAt.Add(-time.Minute)},
+		{ShipmentID: 1, Status: consts.TrackingDelivered, EventTime: deliveredAt},
+	}, nil)
+
+	service := &OrderServiceImpl{
+		orderDao:         mockOrderDao,
+		orderProductDao:  mockOrderProductDao,
+		orderLogDao:      mockOrderLogDao,
+		shipmentDao:      mockShipmentDao,
+		trackingEventDao: mockTrackingEventDao,
+	}
+	detail, err := service.CustomerGetOrderDetail(ctx, "ORD1", 7)
+	if err != nil {
+		t.Fatalf("CustomerGetOrderDetail() error = %v", err)
+	}
+	if !detail.ArrivedTime.Equal(deliveredAt) || !detail.Shipments[0].DeliveredTime.Equal(deliveredAt) {
+		t.Errorf("delivered time not shown: %v, %v", detail.ArrivedTime, detail.Shipments[0].DeliveredTime)
+	}
+	first, second := detail.Shipments[0].TrackingEvents, detail.Shipments[1].TrackingEvents
+	if len(first) != 2 || first[1].Status != consts.TrackingDelivered || len(second) != 1 {
+		t.Errorf("unexpected timeline: %+v, %+v", first, second)
+	}
+}
@@ -0,0 +1,45 @@
+package tracing
+
+import (
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// KafkaHeaderCarrier adapts kafka message headers to a TextMapCarrier so the
+// trace context travels with each message.
+type KafkaHeaderCarrier struct {
+	headers *[]kafka.Header
+}
+
+var _ propagation.TextMapCarrier = (*KafkaHeaderCarrier)(nil)
+
+func NewKafkaHeaderCarrier(headers *[]kafka.Header) *KafkaHeaderCarrier {
+	return &KafkaHeaderCarrier{headers: headers}
+}
+
+func (c *KafkaHeaderCarrier) Get(key string) string {
+	for _, h := range *c.headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+func (c *KafkaHeaderCarrier) Set(key, value string) {
+	for i, h := range *c.headers {
+		if h.Key == key {
+			(*c.headers)[i].Value = []byte(value)
+			return
+		}
+	}
+	*c.headers = append(*c.headers, kafka.Header{Key: key, Value: []byte(value)})
+}
+
+func (c *KafkaHeaderCarrier) Keys() []string {
+	keys := make([]string, 0, len(*c.headers))
+	for _, h := range *c.headers {
+		keys = append(keys, h.Key)
+	}
+	return keys
+}
@@ -0,0 +1,102 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/config"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server/log"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+
+	defaultServiceName  = "ceramicraft-order-mservice"
+	instrumentationName = "github.com/NUS-ISS-Agile-Team/ceramicraft-order-mservice/server"
+)
+
+var (
+	ServiceName    = defaultServiceName
+	tracerProvider *sdktrace.TracerProvider
+)
+
+// Init installs the global tracer provider and the W3C trace context propagator.
+// When tracing is disabled the no-op provider stays in place, so instrumented
+// code keeps working without exporting anything. It returns an error when the
+// exporter can't be created.
+func Init(cfg *config.TracingConfig) error {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+	if cfg == nil || !cfg.Enabled {
+		log.Logger.Info("tracing: disabled")
+		return nil
+	}
+	if cfg.ServiceName != "" {
+		ServiceName = cfg.ServiceName
+	}
+
+	exporter, err := newExporter(cfg)
+	if err != nil {
+		return fmt.Errorf("create tracing exporter failed: %w", err)
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(ServiceName),
+	))
+	if err != nil {
+		return fmt.Errorf("build tracing resource failed: %w", err)
+	}
+	sampleRatio := cfg.SampleRatio
+	if sampleRatio <= 0 {
+		sampleRatio = 1
+	}
+	tracerProvider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+	)
+	otel.SetTracerProvider(tracerProvider)
+	log.Logger.Infof("tracing: enabled, exporter %s, sample ratio %v", cfg.Exporter, sampleRatio)
+	return nil
+}
+
+func newExporter(cfg *config.TracingConfig) (sdktrace.SpanExporter, error) {
+	switch cfg.Exporter {
+	case ExporterOTLP:
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.Endpoint)}
+		if cfg.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		return otlptracegrpc.New(context.Background(), opts...)
+	case ExporterStdout, "":
+		return stdouttrace.New(stdouttrace.WithPrettyPrint())
+	default:
+		return nil, fmt.Errorf("unsupported tracing exporter: %s", cfg.Exporter)
+	}
+}
+
+// Shutdown flushes pending spans and stops the exporter.
+func Shutdown(ctx context.Context) {
+	if tracerProvider == nil {
+		return
+	}
+	if err := tracerProvider.Shutdown(ctx); err != nil {
+		log.Logger.Errorf("tracing: shutdown failed, err %s", err.Error())
+	}
+}
+
+// Tracer returns the tracer used for spans created by this service's own code.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}